@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
+	"log"
 	"os"
+	"strconv"
 	"time"
 
 	_ "socialmeli/docs"
 	"socialmeli/internal/http"
+	"socialmeli/internal/mail"
+	"socialmeli/internal/payment"
 	"socialmeli/internal/service"
 	"socialmeli/internal/store"
 
@@ -48,7 +53,148 @@ func main() {
 
 	as := service.NewAuthService(st)
 
-	r := http.NewRouter(us, ps, as)
+	// feed materializado: fan-out dos posts por TIMELINE_WORKERS workers
+	tl := service.NewTimeline(st, envInt("TIMELINE_POPULAR_FOLLOWERS", service.DefaultPopularSeller))
+	if err := tl.Start(envInt("TIMELINE_WORKERS", 4), envInt("TIMELINE_QUEUE", 1024)); err != nil {
+		panic(err)
+	}
+	// o MemoryStore nasce com posts do seed; no Postgres só quando pedido (primeiro deploy)
+	if dsn == "" || os.Getenv("TIMELINE_REBUILD") == "true" {
+		n, err := tl.Rebuild()
+		if err != nil {
+			panic(err)
+		}
+		log.Printf("timeline: %d follows reconstruídos", n)
+	}
+	go tl.RunExpiry(context.Background(), envDuration("TIMELINE_EXPIRY_INTERVAL", 24*time.Hour), log.Printf)
+	us.SetTimeline(tl)
+	ps.SetTimeline(tl)
+
+	// eventos para o /ws
+	bus := service.NewEventBus()
+	us.SetEventBus(bus)
+
+	// caixa de notificações (novas também chegam pelo /ws)
+	notes := service.NewNotificationService(st)
+	notes.SetEventBus(bus)
+	us.SetNotifications(notes)
+	ps.SetNotifications(notes)
+
+	// histórico de preços e alertas de queda; PRICE_ALERT_INTERVAL pega as promoções
+	// agendadas que começam sem ninguém mexer no post (0 desliga)
+	prices := service.NewPriceService(st)
+	prices.SetNotifications(notes)
+	ps.SetPrices(prices)
+	if every := envDuration("PRICE_ALERT_INTERVAL", time.Minute); every > 0 {
+		go prices.Run(context.Background(), every, log.Printf)
+	}
+
+	// webhooks dos vendedores: o worker confere a fila a cada WEBHOOK_POLL_INTERVAL
+	// (ou logo que algo é enfileirado)
+	hooks := service.NewWebhookService(st)
+	hooks.SetRetry(envInt("WEBHOOK_MAX_ATTEMPTS", service.DefaultWebhookAttempts), envDuration("WEBHOOK_BACKOFF", service.DefaultWebhookBackoff))
+	// destinos internos (localhost, redes privadas) só com WEBHOOK_ALLOW_PRIVATE=true
+	hooks.SetAllowPrivate(envBool("WEBHOOK_ALLOW_PRIVATE", false))
+	us.SetWebhooks(hooks)
+	ps.SetWebhooks(hooks)
+	go hooks.Run(context.Background(), envDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second))
+
+	// PROMO_START_INTERVAL: promoções agendadas que começaram são anunciadas aos
+	// seguidores, no stream do feed e nos webhooks (0 desliga)
+	if every := envDuration("PROMO_START_INTERVAL", time.Minute); every > 0 {
+		go ps.RunScheduledPromos(context.Background(), every, log.Printf)
+	}
+
+	// perguntas e respostas nos posts
+	comments := service.NewCommentService(st)
+	comments.SetNotifications(notes)
+	// carrinho, checkout e pedidos
+	orders := service.NewOrderService(st)
+	// pagamentos: PAYMENT_PROVIDER escolhe o provedor; vazio deixa os pagamentos
+	// desligados (503). Por enquanto só existe o falso (PAYMENT_PROVIDER=fake), que
+	// aprova qualquer token e serve só para desenvolvimento. Pagamentos com confirmação
+	// atrasada são aprovados depois de PAYMENT_FAKE_CONFIRM_AFTER e o callback assinado
+	// vai direto para o serviço (o provedor real faria POST em /payments/callback).
+	switch provider := envString("PAYMENT_PROVIDER", ""); provider {
+	case "":
+		log.Printf("pagamentos: PAYMENT_PROVIDER não configurado, pagamentos desligados")
+	case "fake":
+		secret := envString("PAYMENT_WEBHOOK_SECRET", "")
+		if secret == "" {
+			log.Fatalf("pagamentos: PAYMENT_WEBHOOK_SECRET é obrigatório")
+		}
+		log.Printf("⚠️ pagamentos: provedor falso, todo token é aprovado")
+		gateway := payment.NewFake(secret)
+		gateway.SetCallback(func(body []byte, signature string) {
+			if err := orders.HandlePaymentCallback(body, signature); err != nil {
+				log.Printf("pagamentos: callback: %v", err)
+			}
+		}, envDuration("PAYMENT_FAKE_CONFIRM_AFTER", 5*time.Second))
+		orders.SetPaymentGateway(gateway)
+	default:
+		log.Fatalf("pagamentos: PAYMENT_PROVIDER desconhecido: %q", provider)
+	}
+	coupons := service.NewCouponService(st)
+	orders.SetCoupons(coupons)
+
+	// cotações para o ?currency= das listagens; EXCHANGE_RATES_FILE (CSV moeda,cotação)
+	// é importado na subida e a tabela pode ser mantida depois em /admin/exchange-rates
+	// com o header X-Admin-Token = ADMIN_TOKEN (vazio desliga as rotas /admin)
+	currencies := service.NewCurrencyService(st)
+	if path := envString("EXCHANGE_RATES_FILE", ""); path != "" {
+		if f, err := os.Open(path); err != nil {
+			log.Printf("cotações: %v", err)
+		} else {
+			if rates, err := currencies.Import(f); err != nil {
+				log.Printf("cotações: %s: %v", path, err)
+			} else {
+				log.Printf("cotações: %d moedas importadas de %s", len(rates), path)
+			}
+			f.Close()
+		}
+	}
+
+	// resumo semanal por e-mail; em desenvolvimento os e-mails viram arquivos .eml em MAIL_DROP_DIR
+	// (DIGEST_CHECK_INTERVAL=0 desliga)
+	mailer := mail.NewFileMailer(envString("MAIL_DROP_DIR", "./mail"), envString("MAIL_FROM", "SocialMeli <noreply@socialmeli.local>"))
+	digests := service.NewDigestService(st, mailer, envDuration("DIGEST_PERIOD", service.DefaultDigestPeriod))
+	if every := envDuration("DIGEST_CHECK_INTERVAL", time.Hour); every > 0 {
+		go digests.Run(context.Background(), every, log.Printf)
+	}
+
+	blobs, err := store.NewBlobStoreFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	ups := service.NewUploadService(st, blobs)
+	ups.SetQuota(service.Quota{
+		MaxCount: envInt("UPLOAD_QUOTA_COUNT", service.DefaultQuota.MaxCount),
+		MaxBytes: int64(envInt("UPLOAD_QUOTA_BYTES", int(service.DefaultQuota.MaxBytes))),
+	})
+	// coleta periodica de uploads orfaos (UPLOAD_GC_INTERVAL=0 desliga)
+	gcEvery := envDuration("UPLOAD_GC_INTERVAL", time.Hour)
+	gcGrace := envDuration("UPLOAD_GC_GRACE", 24*time.Hour)
+	if gcEvery > 0 {
+		go ups.RunCollector(context.Background(), gcEvery, gcGrace, log.Printf)
+	}
+
+	// pedacos de uploads via URL assinada: ficam no blob store e os offsets no store,
+	// entao cada PUT pode cair numa instancia diferente; parciais abandonados somem
+	// depois de UPLOAD_STAGING_TTL
+	staging := service.NewChunkStager(st, blobs)
+	stagingTTL := envDuration("UPLOAD_STAGING_TTL", 6*time.Hour)
+	go func() {
+		for range time.Tick(time.Hour) {
+			if n, err := staging.Sweep(time.Now().Add(-stagingTTL)); err != nil {
+				log.Printf("upload staging: %v", err)
+			} else if n > 0 {
+				log.Printf("upload staging: %d uploads parciais removidos", n)
+			}
+		}
+	}()
+
+	r := http.NewRouter(us, ps, as, http.WithBlobStore(blobs), http.WithUploadService(ups), http.WithChunkStager(staging), http.WithEventBus(bus), http.WithNotificationService(notes), http.WithWebhookService(hooks), http.WithCommentService(comments), http.WithOrderService(orders), http.WithCouponService(coupons), http.WithPriceService(prices), http.WithCurrencyService(currencies), http.WithAdminToken(envString("ADMIN_TOKEN", "")))
 
 	r.SetTrustedProxies(nil)
 
@@ -58,9 +204,10 @@ func main() {
 			"http://127.0.0.1:5173",
 		},
 		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "Content-Range"},
 		ExposeHeaders: []string{
 			"Content-Length",
+			"Range",
 		},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -75,3 +222,46 @@ func main() {
 
 	_ = r.Run(":" + port)
 }
+
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		panic(name + ": " + err.Error())
+	}
+	return d
+}
+
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		panic(name + ": " + err.Error())
+	}
+	return n
+}
+
+func envBool(name string, def bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		panic(name + ": " + err.Error())
+	}
+	return b
+}
+
+func envString(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,55 @@
+// Comando administrativo: apaga uploads sem referencia (posts, avatares e itens
+// de pedidos) ha mais que o periodo de carencia e informa os bytes recuperados.
+// A carencia conta de quando uma coleta (este comando ou o worker da API) viu o
+// upload sem uso pela primeira vez.
+//
+//	DATABASE_URL=... go run ./cmd/uploadgc -grace 24h -dry-run
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+)
+
+func main() {
+	grace := flag.Duration("grace", 24*time.Hour, "tempo minimo sem referencia para um upload ser apagado")
+	dryRun := flag.Bool("dry-run", false, "apenas lista o que seria apagado")
+	flag.Parse()
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		fmt.Fprintln(os.Stderr, "DATABASE_URL obrigatoria (o MemoryStore nao e compartilhado entre processos)")
+		os.Exit(2)
+	}
+	st, err := store.NewSQLStore(dsn)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "store:", err)
+		os.Exit(1)
+	}
+	blobs, err := store.NewBlobStoreFromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "blob store:", err)
+		os.Exit(1)
+	}
+
+	rep, err := service.NewUploadService(st, blobs).CollectOrphans(*grace, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "coleta:", err)
+		os.Exit(1)
+	}
+
+	prefix := ""
+	if *dryRun {
+		prefix = "(dry-run) "
+	}
+	fmt.Printf("%sanalisados: %d, apagados: %d, falhas: %d, bytes recuperados: %d\n",
+		prefix, rep.Scanned, rep.Deleted, rep.Failed, rep.ReclaimedBytes)
+	if rep.Failed > 0 {
+		os.Exit(1)
+	}
+}
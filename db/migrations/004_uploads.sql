@@ -0,0 +1,14 @@
+-- Registro dos uploads para coleta de arquivos orfaos.
+-- A contagem de referencias e calculada (posts.image_url / users.avatar_url), nao armazenada.
+CREATE TABLE IF NOT EXISTS uploads (
+  id         TEXT PRIMARY KEY,
+  owner_id   INT NOT NULL,
+  url        TEXT NOT NULL,
+  blob_keys  TEXT NOT NULL,
+  size       BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploads_created ON uploads(created_at);
+CREATE INDEX IF NOT EXISTS idx_posts_image_url ON posts(image_url);
+CREATE INDEX IF NOT EXISTS idx_users_avatar_url ON users(avatar_url);
@@ -0,0 +1,18 @@
+-- Uploads enderecados por conteudo (SHA-256) e compartilhados entre donos.
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS hash TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_uploads_hash ON uploads(hash) WHERE hash IS NOT NULL;
+
+-- Donos de cada upload (cotas por conta). Um mesmo arquivo pode ter varios donos.
+CREATE TABLE IF NOT EXISTS upload_owners (
+  upload_id  TEXT NOT NULL REFERENCES uploads(id) ON DELETE CASCADE,
+  user_id    INT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (upload_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_owners_user ON upload_owners(user_id);
+
+-- uploads anteriores: o owner_id original vira dono
+INSERT INTO upload_owners (upload_id, user_id, created_at)
+SELECT id, owner_id, created_at FROM uploads
+ON CONFLICT DO NOTHING;
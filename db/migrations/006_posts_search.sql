@@ -0,0 +1,28 @@
+-- Busca textual de produtos (GET /products/search).
+-- Config 'simple' + unaccent: sem stemming, so minusculas e sem acento, igual a
+-- normalizacao feita em Go (domain.SearchTerms) e no indice em memoria.
+-- unaccent nao e IMMUTABLE, por isso o vetor e mantido por trigger e nao por coluna gerada.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION posts_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('simple', unaccent(COALESCE(NEW.product_name, ''))), 'A') ||
+    setweight(to_tsvector('simple', unaccent(COALESCE(NEW.brand, ''))), 'B') ||
+    setweight(to_tsvector('simple', unaccent(COALESCE(NEW.type, '') || ' ' || COALESCE(NEW.color, ''))), 'C') ||
+    setweight(to_tsvector('simple', unaccent(COALESCE(NEW.notes, ''))), 'D');
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_posts_search_vector ON posts;
+CREATE TRIGGER trg_posts_search_vector
+  BEFORE INSERT OR UPDATE OF product_name, brand, type, color, notes ON posts
+  FOR EACH ROW EXECUTE FUNCTION posts_search_vector_update();
+
+-- backfill (o trigger recalcula o vetor)
+UPDATE posts SET product_name = product_name WHERE search_vector IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING GIN (search_vector);
@@ -0,0 +1,12 @@
+-- Busca de usuarios por nome (GET /users/search e /users/typeahead).
+-- unaccent nao e IMMUTABLE; o wrapper abaixo fixa o dicionario e pode ser usado em indice.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+  SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- trigramas atendem LIKE 'termo%' e LIKE '% termo%' (prefixo de qualquer palavra)
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm
+  ON users USING GIN (immutable_unaccent(LOWER(name)) gin_trgm_ops);
@@ -0,0 +1,18 @@
+-- Feed materializado: cada post entra na timeline dos seguidores quando e publicado
+-- (fan-out na escrita). Vendedores muito populares ficam de fora e sao lidos na hora.
+CREATE TABLE IF NOT EXISTS timeline (
+  user_id   INT NOT NULL,
+  post_id   INT NOT NULL,
+  seller_id INT NOT NULL,
+  date      TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, post_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_timeline_user_date ON timeline(user_id, date DESC);
+CREATE INDEX IF NOT EXISTS idx_timeline_user_seller ON timeline(user_id, seller_id);
+CREATE INDEX IF NOT EXISTS idx_timeline_date ON timeline(date);
+
+-- fan-out e PopularSellers consultam seguidores por vendedor
+CREATE INDEX IF NOT EXISTS idx_follows_seller ON follows(seller_id);
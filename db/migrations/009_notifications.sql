@@ -0,0 +1,25 @@
+-- Caixa de notificacoes (GET /notifications) e preferencias por tipo.
+CREATE TABLE IF NOT EXISTS notifications (
+  id         SERIAL PRIMARY KEY,
+  user_id    INT NOT NULL,
+  type       TEXT NOT NULL,
+  actor_id   INT NOT NULL,
+  post_id    INT,
+  message    TEXT NOT NULL,
+  read_at    TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- sem linha = tipo ligado
+CREATE TABLE IF NOT EXISTS notification_prefs (
+  user_id INT NOT NULL,
+  type    TEXT NOT NULL,
+  enabled BOOLEAN NOT NULL,
+  PRIMARY KEY (user_id, type),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+-- Resumo semanal por e-mail: quando cada usuario recebeu (ou teve processado) o ultimo,
+-- para o proximo cobrir so o que veio depois e nao repetir envios.
+CREATE TABLE IF NOT EXISTS digests (
+  user_id INT PRIMARY KEY,
+  sent_at TIMESTAMP NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,32 @@
+-- Webhooks dos vendedores: cada evento assinado vira uma entrega, tentada pelo worker
+-- com backoff exponencial ate dar certo ou ir para a dead-letter (status 'dead').
+CREATE TABLE IF NOT EXISTS webhooks (
+  id         SERIAL PRIMARY KEY,
+  user_id    INT NOT NULL,
+  url        TEXT NOT NULL,
+  events     TEXT NOT NULL, -- separados por virgula
+  secret     TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user ON webhooks(user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id              SERIAL PRIMARY KEY,
+  webhook_id      INT NOT NULL,
+  event           TEXT NOT NULL,
+  payload         TEXT NOT NULL,
+  status          TEXT NOT NULL,
+  attempts        INT NOT NULL DEFAULT 0,
+  last_status     INT NOT NULL DEFAULT 0,
+  last_error      TEXT NOT NULL DEFAULT '',
+  next_attempt_at TIMESTAMP NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id DESC);
+-- o worker so olha as pendentes
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
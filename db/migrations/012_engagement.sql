@@ -0,0 +1,22 @@
+-- Likes (publicos, so a contagem aparece) e favoritos (lista privada de cada usuario).
+-- Apagar o post apaga os dois.
+CREATE TABLE IF NOT EXISTS post_likes (
+  post_id    INT NOT NULL,
+  user_id    INT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (post_id, user_id),
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS post_favorites (
+  user_id    INT NOT NULL,
+  post_id    INT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, post_id),
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_favorites_user_date ON post_favorites(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_post_favorites_post ON post_favorites(post_id);
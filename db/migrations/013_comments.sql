@@ -0,0 +1,20 @@
+-- Perguntas e respostas nos posts. Respostas tem um nivel so (parent_id aponta
+-- sempre para uma pergunta); apagar a pergunta ou o post apaga tudo abaixo.
+CREATE TABLE IF NOT EXISTS comments (
+  id         SERIAL PRIMARY KEY,
+  post_id    INT NOT NULL,
+  user_id    INT NOT NULL,
+  parent_id  INT NULL,
+  text       VARCHAR(500) NOT NULL,
+  is_answer  BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  edited_at  TIMESTAMP NULL,
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_post_top ON comments(post_id, id DESC) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments(parent_id, id);
+-- no maximo uma resposta marcada por pergunta
+CREATE UNIQUE INDEX IF NOT EXISTS idx_comments_one_answer ON comments(parent_id) WHERE is_answer;
@@ -0,0 +1,19 @@
+-- Avaliacoes de vendedores: uma por comprador e vendedor (editar troca nota e texto),
+-- com resposta publica opcional do vendedor.
+CREATE TABLE IF NOT EXISTS reviews (
+  id         SERIAL PRIMARY KEY,
+  seller_id  INT NOT NULL,
+  buyer_id   INT NOT NULL,
+  rating     SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  text       VARCHAR(500) NOT NULL,
+  reply      VARCHAR(500) NOT NULL DEFAULT '',
+  replied_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE (seller_id, buyer_id),
+  CHECK (seller_id <> buyer_id),
+  FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (buyer_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_seller_updated ON reviews(seller_id, updated_at DESC, id DESC);
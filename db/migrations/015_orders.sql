@@ -0,0 +1,47 @@
+-- Carrinho (so post e quantidade; o preco e lido do post na hora) e pedidos.
+-- Os itens do pedido copiam produto e precos do checkout, entao nao dependem do post.
+CREATE TABLE IF NOT EXISTS cart_items (
+  user_id  INT NOT NULL,
+  post_id  INT NOT NULL,
+  quantity INT NOT NULL CHECK (quantity BETWEEN 1 AND 99),
+  added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (user_id, post_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+  id         SERIAL PRIMARY KEY,
+  buyer_id   INT NOT NULL,
+  seller_id  INT NOT NULL,
+  status     VARCHAR(16) NOT NULL,
+  total      NUMERIC(12,2) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (buyer_id) REFERENCES users(id),
+  FOREIGN KEY (seller_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_buyer ON orders(buyer_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_seller ON orders(seller_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS order_items (
+  order_id     INT NOT NULL,
+  line         INT NOT NULL,
+  post_id      INT NOT NULL,
+  product_id   INT NOT NULL,
+  product_name TEXT NOT NULL,
+  type         TEXT NOT NULL,
+  brand        TEXT NOT NULL,
+  color        TEXT NOT NULL,
+  notes        TEXT NOT NULL DEFAULT '',
+  image_url    TEXT NOT NULL DEFAULT '',
+  price        NUMERIC(12,2) NOT NULL,
+  has_promo    BOOLEAN NOT NULL,
+  discount     NUMERIC(12,2) NOT NULL,
+  unit_price   NUMERIC(12,2) NOT NULL,
+  quantity     INT NOT NULL,
+  subtotal     NUMERIC(12,2) NOT NULL,
+  PRIMARY KEY (order_id, line),
+  FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
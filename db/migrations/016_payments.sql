@@ -0,0 +1,11 @@
+-- Pagamento de cada pedido no provedor e os callbacks ja processados (o provedor
+-- pode reentregar o mesmo evento).
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_status VARCHAR(16) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_orders_payment ON orders(payment_id) WHERE payment_id <> '';
+
+CREATE TABLE IF NOT EXISTS payment_events (
+  event_id    TEXT PRIMARY KEY,
+  received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
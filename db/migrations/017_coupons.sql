@@ -0,0 +1,33 @@
+-- Cupons dos vendedores. uses conta os usos totais; coupon_uses, os de cada usuario.
+-- Os dois sao atualizados na mesma transacao, com o cupom travado (FOR UPDATE).
+CREATE TABLE IF NOT EXISTS coupons (
+  id                SERIAL PRIMARY KEY,
+  seller_id         INT NOT NULL,
+  code              VARCHAR(20) NOT NULL UNIQUE,
+  kind              VARCHAR(10) NOT NULL,
+  value             NUMERIC(12,2) NOT NULL,
+  min_order         NUMERIC(12,2) NOT NULL DEFAULT 0,
+  expires_at        TIMESTAMP NULL,
+  max_uses          INT NOT NULL DEFAULT 0,
+  max_uses_per_user INT NOT NULL DEFAULT 0,
+  uses              INT NOT NULL DEFAULT 0,
+  created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (seller_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupons_seller ON coupons(seller_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS coupon_uses (
+  coupon_id INT NOT NULL,
+  user_id   INT NOT NULL,
+  uses      INT NOT NULL,
+  PRIMARY KEY (coupon_id, user_id),
+  FOREIGN KEY (coupon_id) REFERENCES coupons(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- desconto do cupom no pedido (o codigo fica copiado, como os itens)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_code VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount NUMERIC(12,2) NOT NULL DEFAULT 0;
+UPDATE orders SET subtotal = total WHERE subtotal = 0 AND discount = 0;
@@ -0,0 +1,5 @@
+-- Janela opcional das promoções: fora dela o post volta ao preço cheio.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS promo_starts_at TIMESTAMP;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS promo_ends_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_posts_promo_window ON posts(user_id, promo_ends_at) WHERE has_promo;
@@ -0,0 +1,30 @@
+-- Histórico de preços: cada mudança guarda a configuração completa do post
+-- (a linha do tempo do preço final sai daqui, incluindo início e fim das promoções).
+CREATE TABLE IF NOT EXISTS post_price_changes (
+  id              SERIAL PRIMARY KEY,
+  post_id         INT NOT NULL,
+  price           NUMERIC(12,2) NOT NULL,
+  has_promo       BOOLEAN NOT NULL,
+  discount        NUMERIC(12,2) NOT NULL DEFAULT 0,
+  promo_starts_at TIMESTAMP NULL,
+  promo_ends_at   TIMESTAMP NULL,
+  changed_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_price_changes_post ON post_price_changes(post_id, changed_at);
+
+-- Alertas de queda de preço: um por (usuário, post); triggered_at NULL = pendente.
+CREATE TABLE IF NOT EXISTS price_alerts (
+  id           SERIAL PRIMARY KEY,
+  user_id      INT NOT NULL,
+  post_id      INT NOT NULL,
+  threshold    NUMERIC(12,2) NOT NULL,
+  created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+  triggered_at TIMESTAMP NULL,
+  UNIQUE (user_id, post_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_alerts_pending ON price_alerts(post_id) WHERE triggered_at IS NULL;
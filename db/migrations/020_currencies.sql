@@ -0,0 +1,10 @@
+-- Moeda dos preços (ISO 4217). Posts e pedidos existentes ficam em reais.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+
+-- Cotações: quanto vale 1 unidade de currency em BRL (a moeda base não entra).
+CREATE TABLE IF NOT EXISTS exchange_rates (
+  currency   CHAR(3) PRIMARY KEY,
+  rate       NUMERIC(18,8) NOT NULL CHECK (rate > 0),
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
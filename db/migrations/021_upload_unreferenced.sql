@@ -0,0 +1,6 @@
+-- Carência da coleta de uploads: conta de quando a imagem ficou sem uso, não do upload.
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS unreferenced_since TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_uploads_unreferenced ON uploads(unreferenced_since) WHERE unreferenced_since IS NOT NULL;
+
+-- itens de pedidos guardam a imagem do produto na compra e contam como referência
+CREATE INDEX IF NOT EXISTS idx_order_items_image_url ON order_items(image_url);
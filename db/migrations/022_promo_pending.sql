@@ -0,0 +1,4 @@
+-- Promoções agendadas ainda não anunciadas: o job avisa seguidores, stream e webhooks
+-- quando a janela abre e desliga a marca.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS promo_pending BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_posts_promo_pending ON posts(promo_starts_at) WHERE promo_pending;
@@ -0,0 +1,12 @@
+-- Pedaços de uploads retomáveis. O conteúdo fica no blob store (blob_key); a tabela
+-- guarda os offsets para qualquer instância continuar o upload. A chave primária
+-- impede dois pedaços no mesmo offset quando retries chegam em instâncias diferentes.
+CREATE TABLE IF NOT EXISTS upload_parts (
+  upload_id  TEXT NOT NULL,
+  start_at   BIGINT NOT NULL,
+  size       BIGINT NOT NULL,
+  blob_key   TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (upload_id, start_at)
+);
+CREATE INDEX IF NOT EXISTS idx_upload_parts_created ON upload_parts(created_at);
@@ -0,0 +1,2 @@
+-- Moeda do valor fixo e do pedido mínimo dos cupons. Cupons existentes ficam em reais.
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
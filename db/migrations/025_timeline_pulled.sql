@@ -0,0 +1,7 @@
+-- Vendedores cujos posts não passam pelo fan-out (populares) e entram no feed por leitura.
+-- Fica gravado para que, depois de um restart, quem deixou de ser popular tenha os
+-- posts recentes copiados para a timeline antes de parar de ser lido na hora.
+CREATE TABLE IF NOT EXISTS timeline_pulled (
+  seller_id  INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+-- A coleta marca o upload antes de apagar os arquivos e só remove o registro no fim:
+-- enquanto marcado, o mesmo conteúdo não é reaproveitado por um novo envio.
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS collecting_since TIMESTAMP;
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxCommentLen é o limite de um comentário (bem maior que o dos campos do produto).
+const MaxCommentLen = 500
+
+var (
+	ErrMaxLen500          = errors.New("O comprimento não pode exceder 500 caracteres.")
+	ErrCommentReplyDepth  = errors.New("Só é possível responder a perguntas, não a respostas.")
+	ErrCommentForbidden   = errors.New("Você não pode alterar um comentário que não é seu.")
+	ErrCommentAnswerOwner = errors.New("Só o vendedor pode marcar a resposta.")
+	ErrCommentNotReply    = errors.New("Só respostas podem ser marcadas como resposta do vendedor.")
+)
+
+// além do texto dos produtos, comentários aceitam pontuação comum e quebras de linha
+var allowedCommentText = regexp.MustCompile(`^[A-Za-zÀ-ÿ0-9 \n.,;:!?()'"/%$+\-]+$`)
+
+// Comment é uma pergunta (ParentID 0) ou resposta em um post. Respostas só têm um nível.
+type Comment struct {
+	ID       int    `json:"id"`
+	PostID   int    `json:"post_id"`
+	UserID   int    `json:"user_id"`
+	UserName string `json:"user_name"`
+	ParentID int    `json:"parent_id,omitempty"`
+	Text     string `json:"text"`
+	// IsAnswer marca a resposta que o vendedor escolheu para a pergunta (uma por pergunta).
+	IsAnswer bool `json:"is_answer"`
+	// Answered indica, numa pergunta, que ela já tem resposta marcada.
+	Answered bool `json:"answered"`
+	// BySeller indica que o autor é o dono do post.
+	BySeller  bool       `json:"by_seller"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	Replies   []Comment  `json:"replies,omitempty"`
+}
+
+// NormalizeCommentText tira espaços das pontas e padroniza quebras de linha.
+func NormalizeCommentText(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+}
+
+func ValidateCommentText(s string) error {
+	return validateText(s, MaxCommentLen, ErrMaxLen500, allowedCommentText)
+}
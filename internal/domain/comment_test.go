@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCommentText(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr error
+	}{
+		{"pergunta com pontuação e quebra de linha", "Serve no pé 42?\nTem outra cor!", nil},
+		{"vazio", "", ErrFieldEmpty},
+		{"no limite", strings.Repeat("a", MaxCommentLen), nil},
+		{"acima do limite", strings.Repeat("a", MaxCommentLen+1), ErrMaxLen500},
+		{"caractere não permitido", "oi <b>", ErrSpecialChars},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCommentText(NormalizeCommentText(tt.s)); err != tt.wantErr {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCouponCode      = errors.New("Código de cupom inválido (3 a 20 letras, números, - ou _).")
+	ErrCouponCodeTaken = errors.New("Já existe um cupom com esse código.")
+	ErrCouponNotSeller = errors.New("Só vendedores podem criar cupons.")
+	ErrCouponKind      = errors.New("Tipo de cupom inválido (percent ou fixed).")
+	ErrCouponValue     = errors.New("Valor do cupom inválido.")
+	ErrCouponLimits    = errors.New("Limites do cupom não podem ser negativos.")
+	ErrCouponExpiry    = errors.New("A validade do cupom precisa estar no futuro.")
+	ErrCouponExpired   = errors.New("Cupom expirado.")
+	ErrCouponMinOrder  = errors.New("O valor dos produtos do vendedor não atinge o mínimo do cupom.")
+	ErrCouponNoItems   = errors.New("O cupom não vale para nenhum produto do carrinho.")
+	ErrCouponExhausted = errors.New("Cupom esgotado.")
+	ErrCouponUserLimit = errors.New("Você já usou este cupom o máximo de vezes.")
+	ErrCouponCurrency  = errors.New("O cupom é de outra moeda que o carrinho.")
+)
+
+// Tipos de desconto de um cupom.
+const (
+	CouponPercent = "percent" // Percent é o percentual (ex.: 10 = 10%)
+	CouponFixed   = "fixed"   // Amount é o valor abatido, limitado ao subtotal
+)
+
+var couponCode = regexp.MustCompile(`^[A-Z0-9_-]{3,20}$`)
+
+// Coupon é um código de desconto de um vendedor. Vale só para os produtos dele no
+// carrinho. Limites 0 = sem limite; ExpiresAt nil = não expira. Currency é a moeda
+// de Amount e MinOrder: cupom fixo ou com mínimo só vale em carrinho nessa moeda.
+//
+// No JSON Percent e Amount saem num único campo value, conforme Kind.
+type Coupon struct {
+	ID             int        `json:"id"`
+	SellerID       int        `json:"seller_id"`
+	Code           string     `json:"code"`
+	Kind           string     `json:"kind"`
+	Percent        float64    `json:"-"` // só CouponPercent
+	Amount         Money      `json:"-"` // só CouponFixed
+	MinOrder       Money      `json:"min_order"`
+	Currency       string     `json:"currency"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	MaxUses        int        `json:"max_uses"`
+	MaxUsesPerUser int        `json:"max_uses_per_user"`
+	Uses           int        `json:"uses"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// couponJSON é o Coupon sem os métodos de JSON, para não recursar.
+type couponJSON Coupon
+
+func (c Coupon) MarshalJSON() ([]byte, error) {
+	out := struct {
+		couponJSON
+		Value any `json:"value"`
+	}{couponJSON: couponJSON(c), Value: c.Percent}
+	if c.Kind == CouponFixed {
+		out.Value = c.Amount
+	}
+	return json.Marshal(out)
+}
+
+func (c *Coupon) UnmarshalJSON(b []byte) error {
+	var in struct {
+		couponJSON
+		Value json.Number `json:"value"`
+	}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	*c = Coupon(in.couponJSON)
+	return c.SetValue(in.Value.String())
+}
+
+// SetValue lê o value da API conforme Kind: percentual em percent e valor exato
+// (sem passar por float) em fixed. Com Kind desconhecido não faz nada; quem
+// recusa é ValidateCoupon.
+func (c *Coupon) SetValue(v string) error {
+	c.Percent, c.Amount = 0, 0
+	if v == "" {
+		return nil
+	}
+	switch c.Kind {
+	case CouponPercent:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return ErrCouponValue
+		}
+		c.Percent = f
+	case CouponFixed:
+		m, err := ParseMoney(v)
+		if err != nil {
+			return ErrCouponValue
+		}
+		c.Amount = m
+	}
+	return nil
+}
+
+// NormalizeCouponCode deixa o código em maiúsculas e sem espaços nas pontas:
+// "promo10" e "PROMO10" são o mesmo cupom.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidateCoupon confere um cupom novo (código já normalizado).
+func ValidateCoupon(c Coupon, now time.Time) error {
+	if !couponCode.MatchString(c.Code) {
+		return ErrCouponCode
+	}
+	switch c.Kind {
+	case CouponPercent:
+		if c.Percent <= 0 || c.Percent > 100 {
+			return ErrCouponValue
+		}
+	case CouponFixed:
+		if c.Amount <= 0 {
+			return ErrCouponValue
+		}
+	default:
+		return ErrCouponKind
+	}
+	if c.MinOrder < 0 || c.MaxUses < 0 || c.MaxUsesPerUser < 0 {
+		return ErrCouponLimits
+	}
+	if c.ExpiresAt != nil && !c.ExpiresAt.After(now) {
+		return ErrCouponExpiry
+	}
+	return nil
+}
+
+// AppliesTo diz se o cupom vale num carrinho em currency. Percentual sem mínimo
+// vale em qualquer moeda; os demais comparam valores e precisam da mesma moeda.
+func (c Coupon) AppliesTo(currency string) bool {
+	if c.Kind == CouponPercent && c.MinOrder == 0 {
+		return true
+	}
+	return c.Currency == currency
+}
+
+// Discount é quanto o cupom abate de subtotal (nunca mais que subtotal).
+func (c Coupon) Discount(subtotal Money) Money {
+	if c.Kind == CouponPercent {
+		return subtotal.Percent(c.Percent)
+	}
+	return c.Amount.Min(subtotal)
+}
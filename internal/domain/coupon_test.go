@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestValidateCoupon(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	ok := Coupon{Code: "PROMO10", Kind: CouponPercent, Percent: 10}
+
+	cases := []struct {
+		edit func(c *Coupon)
+		want error
+	}{
+		{func(c *Coupon) {}, nil},
+		{func(c *Coupon) { c.Code = "P1" }, ErrCouponCode},
+		{func(c *Coupon) { c.Code = "PROMO 10" }, ErrCouponCode},
+		{func(c *Coupon) { c.Kind = "bogo" }, ErrCouponKind},
+		{func(c *Coupon) { c.Percent = 101 }, ErrCouponValue},
+		{func(c *Coupon) { c.Kind, c.Percent = CouponFixed, 0 }, ErrCouponValue},
+		{func(c *Coupon) { c.MaxUsesPerUser = -1 }, ErrCouponLimits},
+		{func(c *Coupon) { c.ExpiresAt = &past }, ErrCouponExpiry},
+		{func(c *Coupon) { c.ExpiresAt = &future }, nil},
+	}
+	for i, tc := range cases {
+		c := ok
+		tc.edit(&c)
+		if err := ValidateCoupon(c, now); err != tc.want {
+			t.Fatalf("case %d: expected %v, got %v", i, tc.want, err)
+		}
+	}
+	if NormalizeCouponCode("  promo10 ") != "PROMO10" {
+		t.Fatalf("code not normalized")
+	}
+}
+
+func TestCoupon_Discount(t *testing.T) {
+	pct := Coupon{Kind: CouponPercent, Percent: 15}
+	if d := pct.Discount(33_33); d != 5_00 {
+		t.Fatalf("expected 5, got %v", d)
+	}
+	fixed := Coupon{Kind: CouponFixed, Amount: 50_00}
+	if d := fixed.Discount(120_00); d != 50_00 {
+		t.Fatalf("expected 50, got %v", d)
+	}
+	if d := fixed.Discount(30_00); d != 30_00 {
+		t.Fatalf("fixed discount must stop at the subtotal, got %v", d)
+	}
+}
+
+func TestCoupon_JSONValue(t *testing.T) {
+	for _, tc := range []struct {
+		json string
+		want Coupon
+	}{
+		{`{"code":"P","kind":"percent","value":12.5}`, Coupon{Code: "P", Kind: CouponPercent, Percent: 12.5}},
+		{`{"code":"F","kind":"fixed","value":19.99}`, Coupon{Code: "F", Kind: CouponFixed, Amount: 19_99}},
+	} {
+		var c Coupon
+		if err := json.Unmarshal([]byte(tc.json), &c); err != nil || c != tc.want {
+			t.Fatalf("%s: got %+v %v", tc.json, c, err)
+		}
+		out, _ := json.Marshal(c)
+		var back map[string]any
+		_ = json.Unmarshal(out, &back)
+		var in map[string]any
+		_ = json.Unmarshal([]byte(tc.json), &in)
+		if back["value"] != in["value"] || back["kind"] != in["kind"] {
+			t.Fatalf("round trip changed value: %s", out)
+		}
+	}
+	var c Coupon
+	if err := json.Unmarshal([]byte(`{"kind":"fixed","value":"x"}`), &c); err == nil {
+		t.Fatalf("expected error for invalid value")
+	}
+}
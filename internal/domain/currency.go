@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BaseCurrency é a moeda dos posts sem moeda informada e a referência das cotações.
+const BaseCurrency = "BRL"
+
+var (
+	ErrCurrency         = errors.New("Moeda inválida: use um código ISO 4217 (ex.: BRL, ARS, MXN).")
+	ErrExchangeRate     = errors.New("A cotação precisa ser maior que zero.")
+	ErrExchangeRateBase = errors.New("A moeda base não tem cotação (vale sempre 1).")
+	ErrNoExchangeRate   = errors.New("Sem cotação cadastrada para a moeda")
+)
+
+// iso4217 são os códigos ISO 4217 de moedas em circulação. Ficam de fora fundos
+// (BOV, CLF, MXV...), metais e códigos técnicos (XAU, XDR, XXX...).
+var iso4217 = func() map[string]bool {
+	codes := `AED AFN ALL AMD AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BRL
+		BSD BTN BWP BYN BZD CAD CDF CHF CLP CNY COP CRC CUP CVE CZK DJF DKK DOP DZD EGP
+		ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR
+		IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL
+		LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR
+		NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD
+		SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX
+		USD UYU UZS VED VES VND VUV WST XAF XCD XCG XOF XPF YER ZAR ZMW ZWG`
+	m := map[string]bool{}
+	for _, c := range strings.Fields(codes) {
+		m[c] = true
+	}
+	return m
+}()
+
+// NormalizeCurrency deixa o código em maiúsculas e sem espaços: "ars" = "ARS".
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidateCurrency confere um código já normalizado.
+func ValidateCurrency(code string) error {
+	if !iso4217[code] {
+		return ErrCurrency
+	}
+	return nil
+}
+
+// FormatPrice escreve o valor para leitura, com a moeda na frente e vírgula
+// decimal: "R$ 80,00" em reais, "ARS 80,00" nas demais. Sem moeda vale a base.
+func FormatPrice(m Money, currency string) string {
+	prefix := NormalizeCurrency(currency)
+	switch prefix {
+	case "", BaseCurrency:
+		prefix = "R$"
+	}
+	return prefix + " " + strings.Replace(m.String(), ".", ",", 1)
+}
+
+// ExchangeRate diz quanto vale 1 unidade de Currency em BaseCurrency
+// (ex.: ARS 0.0055 = 1 peso argentino vale R$ 0,0055).
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MaxRateDecimals é a precisão guardada das cotações (NUMERIC(18,8)).
+const MaxRateDecimals = 8
+
+// ValidateExchangeRate confere uma cotação com moeda já normalizada.
+func ValidateExchangeRate(r ExchangeRate) error {
+	if err := ValidateCurrency(r.Currency); err != nil {
+		return err
+	}
+	if r.Currency == BaseCurrency {
+		return ErrExchangeRateBase
+	}
+	if !(r.Rate > 0) || r.Rate >= 1e10 || math.IsInf(r.Rate, 0) {
+		return ErrExchangeRate
+	}
+	return nil
+}
+
+// RoundRate corta a cotação na precisão guardada, para memória e banco darem
+// o mesmo resultado.
+func RoundRate(rate float64) float64 {
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(rate, 'f', MaxRateDecimals, 64), 64)
+	return f
+}
+
+// Rates é a tabela de cotações pronta para converter valores.
+type Rates map[string]*big.Rat
+
+// NewRates monta a tabela; a moeda base entra com cotação 1.
+func NewRates(list []ExchangeRate) Rates {
+	r := Rates{BaseCurrency: big.NewRat(1, 1)}
+	for _, x := range list {
+		if v, ok := new(big.Rat).SetString(strconv.FormatFloat(x.Rate, 'f', -1, 64)); ok && v.Sign() > 0 {
+			r[x.Currency] = v
+		}
+	}
+	return r
+}
+
+// Convert passa m de from para to pela moeda base, com um único arredondamento
+// no fim (mesma regra de Money).
+func (r Rates) Convert(m Money, from, to string) (Money, error) {
+	if from == to {
+		return m, nil
+	}
+	rf, ok := r[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNoExchangeRate, from)
+	}
+	rt, ok := r[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNoExchangeRate, to)
+	}
+	v := new(big.Rat).SetInt64(int64(m))
+	v.Mul(v, rf).Quo(v, rt)
+	c := roundQuo(v.Num(), v.Denom())
+	if !c.IsInt64() {
+		return 0, ErrMoney
+	}
+	return Money(c.Int64()), nil
+}
+
+// ConvertedPrice são os preços do post na moeda pedida, só para exibição.
+type ConvertedPrice struct {
+	Currency   string `json:"currency"`
+	Price      Money  `json:"price"`
+	FinalPrice Money  `json:"final_price"`
+}
+
+// ConvertPost preenche p.Converted com Price e FinalPrice em to; os valores
+// originais não mudam.
+func (r Rates) ConvertPost(p Post, to string) (Post, error) {
+	price, err := r.Convert(p.Price, p.Currency, to)
+	if err != nil {
+		return Post{}, err
+	}
+	final, err := r.Convert(p.FinalPrice, p.Currency, to)
+	if err != nil {
+		return Post{}, err
+	}
+	p.Converted = &ConvertedPrice{Currency: to, Price: price, FinalPrice: final}
+	return p, nil
+}
+
+// SortExchangeRates ordena por código de moeda.
+func SortExchangeRates(rates []ExchangeRate) {
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Currency < rates[j].Currency })
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateCurrency(t *testing.T) {
+	for _, code := range []string{"BRL", "ARS", "MXN", "USD", "EUR"} {
+		if err := ValidateCurrency(code); err != nil {
+			t.Fatalf("%s: unexpected %v", code, err)
+		}
+	}
+	// minúsculas passam por NormalizeCurrency antes; fundos e códigos técnicos ficam de fora
+	for _, code := range []string{"", "brl", "REAL", "XXX", "XAU", "MXV", "ZZZ"} {
+		if err := ValidateCurrency(code); err != ErrCurrency {
+			t.Fatalf("%q: expected ErrCurrency, got %v", code, err)
+		}
+	}
+	if NormalizeCurrency(" ars ") != "ARS" {
+		t.Fatalf("NormalizeCurrency must trim and upper-case")
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	for _, c := range []struct {
+		m        Money
+		currency string
+		want     string
+	}{{80_00, "BRL", "R$ 80,00"}, {80_00, "", "R$ 80,00"}, {1234_50, "ARS", "ARS 1234,50"}, {5, "mxn", "MXN 0,05"}} {
+		if got := FormatPrice(c.m, c.currency); got != c.want {
+			t.Fatalf("FormatPrice(%d, %q) = %q, want %q", c.m, c.currency, got, c.want)
+		}
+	}
+}
+
+func TestValidateExchangeRate(t *testing.T) {
+	if err := ValidateExchangeRate(ExchangeRate{Currency: "ARS", Rate: 0.0055}); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if err := ValidateExchangeRate(ExchangeRate{Currency: "BRL", Rate: 1}); err != ErrExchangeRateBase {
+		t.Fatalf("expected ErrExchangeRateBase, got %v", err)
+	}
+	for _, rate := range []float64{0, -1, 1e10} {
+		if err := ValidateExchangeRate(ExchangeRate{Currency: "MXN", Rate: rate}); err != ErrExchangeRate {
+			t.Fatalf("%v: expected ErrExchangeRate, got %v", rate, err)
+		}
+	}
+	if RoundRate(0.123456789) != 0.12345679 {
+		t.Fatalf("RoundRate must keep %d decimals", MaxRateDecimals)
+	}
+}
+
+func TestRates_Convert(t *testing.T) {
+	rates := NewRates([]ExchangeRate{{Currency: "ARS", Rate: 0.0055}, {Currency: "MXN", Rate: 0.3}})
+	cases := []struct {
+		m        Money
+		from, to string
+		want     Money
+	}{
+		{1000_00, "ARS", "BRL", 5_50},
+		{100_00, "BRL", "ARS", 18181_82}, // 18181,8181...
+		{1000_00, "ARS", "MXN", 18_33},   // passa pelo real com um só arredondamento
+		{99_99, "MXN", "MXN", 99_99},
+	}
+	for _, tc := range cases {
+		got, err := rates.Convert(tc.m, tc.from, tc.to)
+		if err != nil || got != tc.want {
+			t.Fatalf("Convert(%v %s->%s) = %v, %v; want %v", tc.m, tc.from, tc.to, got, err, tc.want)
+		}
+	}
+	if _, err := rates.Convert(10_00, "BRL", "USD"); !errors.Is(err, ErrNoExchangeRate) {
+		t.Fatalf("expected ErrNoExchangeRate, got %v", err)
+	}
+}
+
+func TestRates_ConvertPost(t *testing.T) {
+	rates := NewRates([]ExchangeRate{{Currency: "MXN", Rate: 0.3}})
+	p := Post{PostID: 1, Price: 100_00, FinalPrice: 90_00, Currency: "BRL"}
+	got, err := rates.ConvertPost(p, "MXN")
+	if err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if got.Price != 100_00 || got.Currency != "BRL" {
+		t.Fatalf("original values must stay, got %+v", got)
+	}
+	c := got.Converted
+	if c == nil || c.Currency != "MXN" || c.Price != 333_33 || c.FinalPrice != 300_00 {
+		t.Fatalf("unexpected converted: %+v", c)
+	}
+	if p.Converted != nil {
+		t.Fatalf("input post must not change")
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrFeedWindow = errors.New("days deve estar entre 1 e 90")
+	ErrFeedSince  = errors.New("Use since ou days, não ambos")
+	ErrFeedCursor = errors.New("cursor inválido")
+)
+
+const (
+	DefaultFeedDays = 14
+	MaxFeedDays     = 90
+)
+
+// Pesos do modo ranked: recência domina, desconto e engajamento do vendedor desempatam.
+const (
+	feedWeightRecency    = 0.6
+	feedWeightDiscount   = 0.25
+	feedWeightEngagement = 0.15
+
+	// meia-vida da recência: um post de 3 dias vale metade de um de hoje
+	feedRecencyHalfLife = 72 * time.Hour
+)
+
+// ValidateFeedDays aceita 1..90 dias.
+func ValidateFeedDays(days int) error {
+	if days < 1 || days > MaxFeedDays {
+		return ErrFeedWindow
+	}
+	return nil
+}
+
+// FeedScore pontua um post entre 0 e 1 para o feed ranked.
+// followers é o número de seguidores do vendedor (1000+ conta como engajamento máximo).
+func FeedScore(p Post, followers int, now time.Time) float64 {
+	age := now.Sub(p.Date)
+	if age < 0 {
+		age = 0
+	}
+	recency := math.Exp2(-float64(age) / float64(feedRecencyHalfLife))
+
+	discount := 0.0
+	if p.HasPromo {
+		discount = math.Min(p.Discount, 100) / 100
+	}
+
+	engagement := math.Min(1, math.Log10(1+float64(followers))/3)
+
+	return feedWeightRecency*recency + feedWeightDiscount*discount + feedWeightEngagement*engagement
+}
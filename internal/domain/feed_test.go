@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFeedScore(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	fresh := Post{Date: now}
+	old := Post{Date: now.Add(-72 * time.Hour)}
+	promo := Post{Date: now, HasPromo: true, Discount: 50}
+
+	if FeedScore(fresh, 0, now) <= FeedScore(old, 0, now) {
+		t.Fatalf("recent post must score higher")
+	}
+	if FeedScore(promo, 0, now) <= FeedScore(fresh, 0, now) {
+		t.Fatalf("discount must add to the score")
+	}
+	if FeedScore(fresh, 1000, now) <= FeedScore(fresh, 10, now) {
+		t.Fatalf("seller engagement must add to the score")
+	}
+	if s := FeedScore(Post{Date: now, HasPromo: true, Discount: 100}, 1_000_000, now); s > 1.0000001 {
+		t.Fatalf("score must be at most 1, got %f", s)
+	}
+	// meia-vida: 3 dias depois a parte de recência cai pela metade
+	if got := FeedScore(old, 0, now); got < 0.29 || got > 0.31 {
+		t.Fatalf("expected ~0.3, got %f", got)
+	}
+}
+
+func TestValidateFeedDays(t *testing.T) {
+	if ValidateFeedDays(1) != nil || ValidateFeedDays(90) != nil {
+		t.Fatalf("1 and 90 must be valid")
+	}
+	if ValidateFeedDays(0) != ErrFeedWindow || ValidateFeedDays(91) != ErrFeedWindow {
+		t.Fatalf("expected ErrFeedWindow")
+	}
+}
@@ -0,0 +1,264 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFilter = errors.New("Filtro inválido")
+	ErrPriceRange    = errors.New("min_price não pode ser maior que max_price")
+)
+
+const maxFilterValues = 20
+
+// PostFilter restringe listagens de posts. Campos vazios não filtram.
+// Valores de uma mesma lista são alternativas (OU); campos diferentes se somam (E).
+// brand, type e color comparam sem diferenciar maiúsculas.
+type PostFilter struct {
+	Categories []int
+	Brands     []string
+	Types      []string
+	Colors     []string
+	// faixa sobre FinalPrice (preço já com desconto); nil = sem limite
+	MinPrice  *Money
+	MaxPrice  *Money
+	PromoOnly bool
+}
+
+// PostQuery seleciona os posts de uma listagem: de quais vendedores, desde quando
+// (zero = sem limite) e com qual filtro.
+type PostQuery struct {
+	SellerIDs []int
+	Since     time.Time
+	Filter    PostFilter
+}
+
+// filterValues junta parâmetros repetidos e separados por vírgula (?brand=a,b&brand=c).
+func filterValues(q map[string][]string, key string) []string {
+	out := []string{}
+	for _, raw := range q[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+func invalidFilter(key string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidFilter, key)
+}
+
+func parseTextFilter(q map[string][]string, key string, max int) ([]string, error) {
+	vals := filterValues(q, key)
+	if len(vals) > maxFilterValues {
+		return nil, invalidFilter(key)
+	}
+	for _, v := range vals {
+		if len([]rune(v)) > max || !allowedText.MatchString(v) {
+			return nil, invalidFilter(key)
+		}
+	}
+	return vals, nil
+}
+
+func parsePriceFilter(q map[string][]string, key string) (*Money, error) {
+	vals := filterValues(q, key)
+	if len(vals) == 0 {
+		return nil, nil
+	}
+	v, err := ParseMoney(vals[0])
+	if len(vals) > 1 || err != nil || v < 0 || v > MaxPrice {
+		return nil, invalidFilter(key)
+	}
+	return &v, nil
+}
+
+// ParsePostFilter lê o filtro dos query params: category, brand, type, color,
+// min_price, max_price e promo (true/false).
+func ParsePostFilter(q map[string][]string) (PostFilter, error) {
+	var f PostFilter
+	var err error
+
+	cats := filterValues(q, "category")
+	if len(cats) > maxFilterValues {
+		return PostFilter{}, invalidFilter("category")
+	}
+	for _, c := range cats {
+		n, err := strconv.Atoi(c)
+		if err != nil || n <= 0 {
+			return PostFilter{}, invalidFilter("category")
+		}
+		f.Categories = append(f.Categories, n)
+	}
+
+	if f.Brands, err = parseTextFilter(q, "brand", 25); err != nil {
+		return PostFilter{}, err
+	}
+	if f.Types, err = parseTextFilter(q, "type", 15); err != nil {
+		return PostFilter{}, err
+	}
+	if f.Colors, err = parseTextFilter(q, "color", 15); err != nil {
+		return PostFilter{}, err
+	}
+
+	if f.MinPrice, err = parsePriceFilter(q, "min_price"); err != nil {
+		return PostFilter{}, err
+	}
+	if f.MaxPrice, err = parsePriceFilter(q, "max_price"); err != nil {
+		return PostFilter{}, err
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return PostFilter{}, ErrPriceRange
+	}
+
+	if promo := filterValues(q, "promo"); len(promo) > 0 {
+		b, err := strconv.ParseBool(promo[0])
+		if len(promo) > 1 || err != nil {
+			return PostFilter{}, invalidFilter("promo")
+		}
+		f.PromoOnly = b
+	}
+	return f, nil
+}
+
+func containsFold(vals []string, s string) bool {
+	for _, v := range vals {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match diz se o post passa no filtro.
+func (f PostFilter) Match(p Post) bool {
+	if len(f.Categories) > 0 {
+		found := false
+		for _, c := range f.Categories {
+			if c == p.Category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Brands) > 0 && !containsFold(f.Brands, p.Product.Brand) {
+		return false
+	}
+	if len(f.Types) > 0 && !containsFold(f.Types, p.Product.Type) {
+		return false
+	}
+	if len(f.Colors) > 0 && !containsFold(f.Colors, p.Product.Color) {
+		return false
+	}
+	if f.MinPrice != nil && p.FinalPrice < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && p.FinalPrice > *f.MaxPrice {
+		return false
+	}
+	if f.PromoOnly && !p.HasPromo {
+		return false
+	}
+	return true
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type CategoryFacet struct {
+	Category int `json:"category"`
+	Count    int `json:"count"`
+}
+
+// PriceBucket conta posts com Min <= FinalPrice < Max (Max nil = sem teto).
+type PriceBucket struct {
+	Min   Money  `json:"min"`
+	Max   *Money `json:"max,omitempty"`
+	Count int    `json:"count"`
+}
+
+// PostFacets resume os resultados de uma listagem filtrada.
+type PostFacets struct {
+	Brands     []FacetCount    `json:"brands"`
+	Categories []CategoryFacet `json:"categories"`
+	Prices     []PriceBucket   `json:"prices"`
+}
+
+// PriceBucketEdges são os limites das faixas de preço das facetas.
+var PriceBucketEdges = []Money{50_00, 100_00, 500_00, 1000_00, 5000_00}
+
+// PriceBucketIndex devolve a faixa (0..len(PriceBucketEdges)) de um preço.
+func PriceBucketIndex(price Money) int {
+	for i, edge := range PriceBucketEdges {
+		if price < edge {
+			return i
+		}
+	}
+	return len(PriceBucketEdges)
+}
+
+// NewPriceBuckets monta todas as faixas a partir das contagens por índice
+// (faixas vazias também aparecem, para a UI ficar estável).
+func NewPriceBuckets(counts map[int]int) []PriceBucket {
+	out := make([]PriceBucket, 0, len(PriceBucketEdges)+1)
+	lo := Money(0)
+	for i := 0; i <= len(PriceBucketEdges); i++ {
+		b := PriceBucket{Min: lo, Count: counts[i]}
+		if i < len(PriceBucketEdges) {
+			hi := PriceBucketEdges[i]
+			b.Max = &hi
+			lo = hi
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// SortFacets ordena por contagem (desc) e depois pelo valor.
+func SortFacets(f *PostFacets) {
+	sort.Slice(f.Brands, func(i, j int) bool {
+		if f.Brands[i].Count != f.Brands[j].Count {
+			return f.Brands[i].Count > f.Brands[j].Count
+		}
+		return f.Brands[i].Value < f.Brands[j].Value
+	})
+	sort.Slice(f.Categories, func(i, j int) bool {
+		if f.Categories[i].Count != f.Categories[j].Count {
+			return f.Categories[i].Count > f.Categories[j].Count
+		}
+		return f.Categories[i].Category < f.Categories[j].Category
+	})
+}
+
+// ComputeFacets calcula as facetas de uma lista já filtrada.
+func ComputeFacets(posts []Post) PostFacets {
+	brands := map[string]int{}
+	cats := map[int]int{}
+	prices := map[int]int{}
+	for _, p := range posts {
+		brands[p.Product.Brand]++
+		cats[p.Category]++
+		prices[PriceBucketIndex(p.FinalPrice)]++
+	}
+	f := PostFacets{Brands: []FacetCount{}, Categories: []CategoryFacet{}, Prices: NewPriceBuckets(prices)}
+	for b, n := range brands {
+		f.Brands = append(f.Brands, FacetCount{Value: b, Count: n})
+	}
+	for c, n := range cats {
+		f.Categories = append(f.Categories, CategoryFacet{Category: c, Count: n})
+	}
+	SortFacets(&f)
+	return f
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePostFilter(t *testing.T) {
+	f, err := ParsePostFilter(map[string][]string{
+		"category":  {"1,2", "3"},
+		"brand":     {"Nike, Adidas"},
+		"color":     {"Preto"},
+		"min_price": {"10.50"},
+		"max_price": {"200"},
+		"promo":     {"true"},
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(f.Categories) != 3 || len(f.Brands) != 2 || f.Brands[1] != "Adidas" || len(f.Colors) != 1 {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+	if *f.MinPrice != 10_50 || *f.MaxPrice != 200_00 || !f.PromoOnly {
+		t.Fatalf("unexpected price/promo: %+v", f)
+	}
+
+	empty, err := ParsePostFilter(map[string][]string{})
+	if err != nil || empty.MinPrice != nil || len(empty.Brands) != 0 {
+		t.Fatalf("expected empty filter, got %+v %v", empty, err)
+	}
+}
+
+func TestParsePostFilter_Invalid(t *testing.T) {
+	cases := []map[string][]string{
+		{"category": {"0"}},
+		{"category": {"abc"}},
+		{"brand": {"Nike$"}},
+		{"type": {"um tipo longo demais"}},
+		{"min_price": {"-1"}},
+		{"max_price": {"x"}},
+		{"promo": {"talvez"}},
+	}
+	for _, q := range cases {
+		if _, err := ParsePostFilter(q); !errors.Is(err, ErrInvalidFilter) {
+			t.Fatalf("%v: expected ErrInvalidFilter, got %v", q, err)
+		}
+	}
+	if _, err := ParsePostFilter(map[string][]string{"min_price": {"20"}, "max_price": {"10"}}); !errors.Is(err, ErrPriceRange) {
+		t.Fatalf("expected ErrPriceRange, got %v", err)
+	}
+}
+
+func TestPostFilter_Match(t *testing.T) {
+	p := Post{Category: 2, Product: Product{Brand: "Nike", Type: "Tenis", Color: "Preto"}, FinalPrice: 90_00, HasPromo: true}
+	min, max := Money(50_00), Money(100_00)
+
+	if !(PostFilter{}).Match(p) {
+		t.Fatalf("empty filter must match")
+	}
+	if !(PostFilter{Brands: []string{"nike"}, Categories: []int{1, 2}, MinPrice: &min, MaxPrice: &max, PromoOnly: true}).Match(p) {
+		t.Fatalf("expected match")
+	}
+	if (PostFilter{Colors: []string{"Branco"}}).Match(p) {
+		t.Fatalf("color must not match")
+	}
+	if (PostFilter{MaxPrice: &min}).Match(p) {
+		t.Fatalf("price above max must not match")
+	}
+}
+
+func TestComputeFacets(t *testing.T) {
+	posts := []Post{
+		{Category: 1, Product: Product{Brand: "B"}, FinalPrice: 10_00},
+		{Category: 1, Product: Product{Brand: "A"}, FinalPrice: 75_00},
+		{Category: 2, Product: Product{Brand: "A"}, FinalPrice: 7000_00},
+	}
+	f := ComputeFacets(posts)
+	if len(f.Brands) != 2 || f.Brands[0].Value != "A" || f.Brands[0].Count != 2 {
+		t.Fatalf("unexpected brands: %+v", f.Brands)
+	}
+	if f.Categories[0].Category != 1 || f.Categories[0].Count != 2 {
+		t.Fatalf("unexpected categories: %+v", f.Categories)
+	}
+	if len(f.Prices) != len(PriceBucketEdges)+1 {
+		t.Fatalf("expected all buckets, got %d", len(f.Prices))
+	}
+	if f.Prices[0].Count != 1 || f.Prices[1].Count != 1 || f.Prices[5].Count != 1 || f.Prices[5].Max != nil {
+		t.Fatalf("unexpected prices: %+v", f.Prices)
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "strings"
+
+// ImageSet agrupa as URLs dos tamanhos normalizados de uma imagem enviada.
+type ImageSet struct {
+	Thumb  string `json:"thumb"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
+}
+
+var imageSizes = []string{"thumb", "medium", "large"}
+
+// ImageVariantName monta o nome de um tamanho: <base>_<size><ext>.
+func ImageVariantName(base, size, ext string) string {
+	return base + "_" + size + ext
+}
+
+// ImageVariants deriva as URLs de todos os tamanhos a partir da URL de qualquer um deles.
+// URLs externas (fora da convenção <base>_<size><ext>) retornam ok=false.
+func ImageVariants(url string) (ImageSet, bool) {
+	dot := strings.LastIndex(url, ".")
+	if dot < 0 || strings.Contains(url[dot:], "/") {
+		return ImageSet{}, false
+	}
+	stem, ext := url[:dot], url[dot:]
+	for _, size := range imageSizes {
+		if base, ok := strings.CutSuffix(stem, "_"+size); ok && base != "" {
+			return ImageSet{
+				Thumb:  ImageVariantName(base, "thumb", ext),
+				Medium: ImageVariantName(base, "medium", ext),
+				Large:  ImageVariantName(base, "large", ext),
+			}, true
+		}
+	}
+	return ImageSet{}, false
+}
+
+// CanonicalImageURL normaliza para o tamanho large; é essa URL que posts e avatares guardam.
+func CanonicalImageURL(url string) string {
+	if set, ok := ImageVariants(url); ok {
+		return set.Large
+	}
+	return url
+}
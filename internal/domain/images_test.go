@@ -0,0 +1,31 @@
+package domain
+
+import "testing"
+
+func TestImageVariants(t *testing.T) {
+	set, ok := ImageVariants("/static/products/12-99_large.jpg")
+	if !ok {
+		t.Fatalf("expected ok")
+	}
+	if set.Thumb != "/static/products/12-99_thumb.jpg" || set.Medium != "/static/products/12-99_medium.jpg" || set.Large != "/static/products/12-99_large.jpg" {
+		t.Fatalf("unexpected set: %+v", set)
+	}
+
+	if _, ok := ImageVariants("/static/products/12-99_thumb.png"); !ok {
+		t.Fatalf("expected ok for thumb url")
+	}
+	for _, u := range []string{"", "https://cdn.example.com/foto.jpg", "/static/products/12-99.png", "/static/a_large/x"} {
+		if _, ok := ImageVariants(u); ok {
+			t.Fatalf("expected ok=false for %q", u)
+		}
+	}
+}
+
+func TestCanonicalImageURL(t *testing.T) {
+	if got := CanonicalImageURL("/static/products/1-2_thumb.png"); got != "/static/products/1-2_large.png" {
+		t.Fatalf("unexpected canonical url: %s", got)
+	}
+	if got := CanonicalImageURL("https://cdn.example.com/x.jpg"); got != "https://cdn.example.com/x.jpg" {
+		t.Fatalf("external url must be kept, got %s", got)
+	}
+}
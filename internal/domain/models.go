@@ -16,7 +16,8 @@ type Product struct {
 	Color       string `json:"color"`
 	Notes       string `json:"notes"`
 
-	ImageURL string `json:"image_url,omitempty"`
+	ImageURL string    `json:"image_url,omitempty"`
+	Images   *ImageSet `json:"images,omitempty"`
 }
 
 type Post struct {
@@ -26,10 +27,26 @@ type Post struct {
 	DateStr  string    `json:"date"`
 	Product  Product   `json:"product"`
 	Category int       `json:"category"`
-	Price    float64   `json:"price"`
+	Price    Money     `json:"price"`
+	// código ISO 4217 de Price e FinalPrice (vazio no store = BaseCurrency)
+	Currency string `json:"currency"`
 
 	HasPromo bool    `json:"has_promo"`
 	Discount float64 `json:"discount"`
+	// janela opcional da promoção (nil = desde sempre / sem fim)
+	PromoStartsAt *time.Time `json:"promo_starts_at,omitempty"`
+	PromoEndsAt   *time.Time `json:"promo_ends_at,omitempty"`
 
-	FinalPrice float64 `json:"final_price"`
+	FinalPrice Money `json:"final_price"`
+	// preços convertidos para a moeda pedida na listagem (?currency=)
+	Converted *ConvertedPrice `json:"converted,omitempty"`
+
+	LikesCount     int `json:"likes_count"`
+	FavoritesCount int `json:"favorites_count"`
+}
+
+// PostCounts são os contadores de engajamento de um post.
+type PostCounts struct {
+	Likes     int
+	Favorites int
 }
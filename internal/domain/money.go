@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+var ErrMoney = errors.New("Valor monetário inválido.")
+
+// Money é um valor em centavos. Preços, totais e descontos em dinheiro passam por
+// aqui para as contas serem exatas; percentuais continuam float64.
+//
+// Arredondamento: sempre para o centavo mais próximo, com meio centavo indo para
+// longe do zero (0,005 vira 0,01). Vale na leitura (JSON, banco, query string) e
+// nas contas com percentual.
+//
+// No JSON sai como número com até 2 casas (100.5, 89.9), igual ao float64 de antes;
+// na entrada aceita número ou string decimal ("89.90").
+type Money int64
+
+// Cents monta um valor a partir dos centavos.
+func Cents(c int64) Money { return Money(c) }
+
+// MoneyFromFloat arredonda f para centavos a partir do decimal mais curto que o
+// representa (1.005 conta como 1,005 e vira 1,01). Texto deve ir por ParseMoney.
+func MoneyFromFloat(f float64) Money {
+	m, _ := ParseMoney(strconv.FormatFloat(f, 'f', -1, 64))
+	return m
+}
+
+// ParseMoney lê um decimal ("19.9", "19.90", "1e3") sem passar por float.
+func ParseMoney(s string) (Money, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrMoney
+	}
+	return moneyFromRat(r)
+}
+
+var (
+	ratHundred = big.NewInt(100)
+	maxCents   = big.NewInt(math.MaxInt64)
+)
+
+func moneyFromRat(r *big.Rat) (Money, error) {
+	if r == nil {
+		return 0, ErrMoney
+	}
+	num := new(big.Int).Mul(r.Num(), ratHundred)
+	c := roundQuo(num, r.Denom())
+	if c.CmpAbs(maxCents) > 0 {
+		return 0, ErrMoney
+	}
+	return Money(c.Int64()), nil
+}
+
+// roundQuo divide arredondando o meio para longe do zero (den > 0).
+func roundQuo(num, den *big.Int) *big.Int {
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Cents devolve o valor em centavos.
+func (m Money) Cents() int64 { return int64(m) }
+
+// Float64 é para exibição (JSON). Não use o resultado em contas.
+func (m Money) Float64() float64 { return float64(m) / 100 }
+
+// String formata com 2 casas e ponto: "1234.50", "-0.05".
+func (m Money) String() string {
+	sign, c := "", int64(m)
+	if c < 0 {
+		sign, c = "-", -c
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, c/100, c%100)
+}
+
+// Mul multiplica por uma quantidade (exato).
+func (m Money) Mul(q int) Money { return m * Money(q) }
+
+// Percent é pct% de m, arredondado. O percentual é considerado com 2 casas
+// (12.345% vira 12.35%), o que deixa a conta toda em inteiros.
+func (m Money) Percent(pct float64) Money {
+	bp := int64(math.Round(pct * 100)) // pontos-base
+	return Money(roundQuo(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(bp)), big.NewInt(10000)).Int64())
+}
+
+// Min devolve o menor dos dois.
+func (m Money) Min(o Money) Money {
+	if o < m {
+		return o
+	}
+	return m
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(m.Float64(), 'f', -1, 64)), nil
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value grava como decimal em texto; a coluna NUMERIC(12,2) guarda exato.
+func (m Money) Value() (driver.Value, error) { return m.String(), nil }
+
+// Scan lê NUMERIC (o driver entrega texto) e, por compatibilidade, números.
+func (m *Money) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case string:
+		*m, err = ParseMoney(v)
+	case []byte:
+		*m, err = ParseMoney(string(v))
+	case float64:
+		*m = MoneyFromFloat(v)
+	case int64:
+		*m = Money(v * 100)
+	default:
+		return fmt.Errorf("%w: %T", ErrMoney, src)
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Money
+	}{
+		{"19.9", 19_90},
+		{"19.90", 19_90},
+		{"0.1", 10},
+		{"1.005", 1_01}, // meio centavo sobe
+		{"1.0049", 1_00},
+		{"-1.005", -1_01}, // e desce no negativo (longe do zero)
+		{"1e3", 1000_00},
+		{"10000000", MaxPrice},
+	}
+	for _, tc := range cases {
+		got, err := ParseMoney(tc.in)
+		if err != nil || got != tc.want {
+			t.Fatalf("ParseMoney(%q) = %v, %v; want %v", tc.in, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "abc", "1,50", "1e30"} {
+		if _, err := ParseMoney(bad); err != ErrMoney {
+			t.Fatalf("ParseMoney(%q): expected ErrMoney, got %v", bad, err)
+		}
+	}
+	if MoneyFromFloat(1.005) != 1_01 || MoneyFromFloat(0.1+0.2) != 30 {
+		t.Fatalf("MoneyFromFloat must round the shortest decimal")
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	// 0.1 + 0.2 em float dá 0.30000000000000004
+	if Money(10)+Money(20) != 30 {
+		t.Fatalf("sum must be exact")
+	}
+	if got := Money(33_33).Percent(15); got != 5_00 { // 4.9995 -> 5.00
+		t.Fatalf("expected 5.00, got %v", got)
+	}
+	if got := Money(1_01).Percent(50); got != 51 { // 0.505 -> 0.51
+		t.Fatalf("expected 0.51, got %v", got)
+	}
+	if got := Money(100_00).Percent(12.345); got != 12_35 { // percentual com 2 casas
+		t.Fatalf("expected 12.35, got %v", got)
+	}
+	if got := FinalPrice(19_99, 10, true); got != 17_99 { // 17.991
+		t.Fatalf("expected 17.99, got %v", got)
+	}
+	if got := LineTotal(19_99, 3); got != 59_97 {
+		t.Fatalf("expected 59.97, got %v", got)
+	}
+	if Money(-5).String() != "-0.05" || Money(1234_50).String() != "1234.50" {
+		t.Fatalf("unexpected String: %s %s", Money(-5), Money(1234_50))
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	// sai igual ao float64 de antes
+	b, _ := json.Marshal(struct {
+		A, B, C Money
+	}{100_50, 100_00, 5})
+	if string(b) != `{"A":100.5,"B":100,"C":0.05}` {
+		t.Fatalf("unexpected JSON %s", b)
+	}
+
+	var v struct {
+		Price  Money  `json:"price"`
+		Text   Money  `json:"text"`
+		Absent Money  `json:"absent"`
+		Ptr    *Money `json:"ptr"`
+	}
+	if err := json.Unmarshal([]byte(`{"price":89.9,"text":"19.99","absent":null,"ptr":0.3}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.Price != 89_90 || v.Text != 19_99 || v.Absent != 0 || v.Ptr == nil || *v.Ptr != 30 {
+		t.Fatalf("unexpected %+v", v)
+	}
+	if err := json.Unmarshal([]byte(`{"price":"x"}`), &v); err == nil {
+		t.Fatalf("expected error for invalid price")
+	}
+	if err := json.Unmarshal([]byte(`{"price":true}`), &v); err == nil {
+		t.Fatalf("expected error for non-number price")
+	}
+}
+
+func TestMoney_SQL(t *testing.T) {
+	if v, _ := Money(1234_50).Value(); v != "1234.50" {
+		t.Fatalf("unexpected Value %v", v)
+	}
+	var m Money
+	for _, src := range []any{"89.90", []byte("89.90"), 89.9} {
+		if err := m.Scan(src); err != nil || m != 89_90 {
+			t.Fatalf("Scan(%v) = %v, %v", src, m, err)
+		}
+	}
+	if err := m.Scan(int64(3)); err != nil || m != 3_00 {
+		t.Fatalf("Scan(int64) = %v, %v", m, err)
+	}
+	if err := m.Scan(true); err == nil {
+		t.Fatalf("expected error for bool")
+	}
+}
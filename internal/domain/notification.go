@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotificationType = errors.New("Tipo de notificação inválido")
+	ErrNotificationIDs  = errors.New("Informe ids ou all")
+)
+
+// Tipos de notificação (também as chaves das preferências).
+const (
+	NotificationNewFollower  = "new_follower"  // alguém passou a seguir o usuário
+	NotificationPromoStarted = "promo_started" // vendedor seguido publicou uma promoção
+	NotificationWeeklyDigest = "weekly_digest" // resumo semanal das promoções, por e-mail
+	NotificationPriceDrop    = "price_drop"    // post com alerta de preço ficou abaixo do valor pedido
+	NotificationPostLiked    = "post_liked"    // alguém curtiu um post do usuário
+	NotificationComment      = "post_comment"  // pergunta num post do usuário ou resposta a uma pergunta dele
+)
+
+// NotificationTypes lista os tipos na ordem em que foram criados.
+var NotificationTypes = []string{NotificationNewFollower, NotificationPromoStarted, NotificationWeeklyDigest, NotificationPriceDrop, NotificationPostLiked, NotificationComment}
+
+// Notification é uma entrada da caixa de entrada de UserID. ActorID é quem causou
+// (o novo seguidor, o vendedor da promoção).
+type Notification struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Type      string    `json:"type"`
+	ActorID   int       `json:"actor_id"`
+	PostID    int       `json:"post_id,omitempty"`
+	Message   string    `json:"message"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ValidateNotificationType(t string) error {
+	for _, k := range NotificationTypes {
+		if k == t {
+			return nil
+		}
+	}
+	return ErrNotificationType
+}
+
+// NotificationPrefs diz, por tipo, se o usuário quer receber. Tipos ausentes valem true.
+type NotificationPrefs map[string]bool
+
+// WithDefaults devolve as preferências de todos os tipos conhecidos.
+func (p NotificationPrefs) WithDefaults() NotificationPrefs {
+	out := NotificationPrefs{}
+	for _, t := range NotificationTypes {
+		enabled, ok := p[t]
+		out[t] = !ok || enabled
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrCartQuantity    = errors.New("A quantidade deve ser de 1 a 99.")
+	ErrCartEmpty       = errors.New("O carrinho está vazio.")
+	ErrCartOwnPost     = errors.New("Você não pode comprar o próprio produto.")
+	ErrCartCurrency    = errors.New("O carrinho só aceita produtos na mesma moeda.")
+	ErrCartChanged     = errors.New("O carrinho mudou durante o checkout; confira e tente de novo.")
+	ErrOrderStatus     = errors.New("Status de pedido inválido.")
+	ErrOrderTransition = errors.New("Mudança de status não permitida para este pedido.")
+)
+
+// MaxCartQuantity limita a quantidade de um mesmo post no carrinho.
+const MaxCartQuantity = 99
+
+// Estados de um pedido.
+const (
+	OrderPending   = "pending"   // criado no checkout, aguardando pagamento
+	OrderPaid      = "paid"      // pago, aguardando envio
+	OrderShipped   = "shipped"   // enviado pelo vendedor
+	OrderDelivered = "delivered" // entregue (final)
+	OrderCancelled = "cancelled" // cancelado antes do envio (final)
+)
+
+// OrderStatuses lista os estados na ordem do ciclo de vida.
+var OrderStatuses = []string{OrderPending, OrderPaid, OrderShipped, OrderDelivered, OrderCancelled}
+
+// CartLine é o que fica gravado do carrinho: só o post e a quantidade. Preço e
+// produto são lidos do post na hora.
+type CartLine struct {
+	PostID   int       `json:"post_id"`
+	Quantity int       `json:"quantity"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// CartItem é uma linha do carrinho já precificada com o FinalPrice atual do post.
+type CartItem struct {
+	PostID    int     `json:"post_id"`
+	SellerID  int     `json:"seller_id"`
+	Product   Product `json:"product"`
+	UnitPrice Money   `json:"unit_price"`
+	Quantity  int     `json:"quantity"`
+	Subtotal  Money   `json:"subtotal"`
+}
+
+// Cart é o carrinho precificado. Com cupom, Discount sai de Subtotal em Total.
+// Todos os itens têm a mesma moeda (Currency; vazio com o carrinho vazio).
+type Cart struct {
+	Items      []CartItem `json:"items"`
+	Count      int        `json:"count"` // soma das quantidades
+	Currency   string     `json:"currency,omitempty"`
+	Subtotal   Money      `json:"subtotal"`
+	CouponCode string     `json:"coupon_code,omitempty"`
+	Discount   Money      `json:"discount"`
+	Total      Money      `json:"total"`
+}
+
+// OrderItem guarda uma cópia do produto e dos preços no momento do checkout:
+// editar ou apagar o post depois não muda o pedido.
+type OrderItem struct {
+	PostID    int     `json:"post_id"`
+	Product   Product `json:"product"`
+	Price     Money   `json:"price"`
+	HasPromo  bool    `json:"has_promo"`
+	Discount  float64 `json:"discount"` // percentual da promoção
+	UnitPrice Money   `json:"unit_price"`
+	Quantity  int     `json:"quantity"`
+	Subtotal  Money   `json:"subtotal"`
+}
+
+// Order é a compra de BuyerID com um único vendedor (o checkout separa o carrinho
+// por vendedor).
+type Order struct {
+	ID       int         `json:"id"`
+	BuyerID  int         `json:"buyer_id"`
+	SellerID int         `json:"seller_id"`
+	Status   string      `json:"status"`
+	Items    []OrderItem `json:"items"`
+	// Subtotal soma os itens; Discount vem do cupom (CouponCode) e Total é o cobrado.
+	Subtotal   Money  `json:"subtotal"`
+	CouponCode string `json:"coupon_code,omitempty"`
+	Discount   Money  `json:"discount"`
+	Total      Money  `json:"total"`
+	Currency   string `json:"currency"`
+	// PaymentID e PaymentStatus vêm do provedor de pagamentos (vazios até a
+	// primeira tentativa de pagar).
+	PaymentID     string    `json:"payment_id,omitempty"`
+	PaymentStatus string    `json:"payment_status,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// OrderFilter escolhe os pedidos de um comprador ou de um vendedor; Status vazio = todos.
+type OrderFilter struct {
+	BuyerID  int
+	SellerID int
+	Status   string
+}
+
+// LineTotal multiplica preço e quantidade.
+func LineTotal(unitPrice Money, quantity int) Money {
+	return unitPrice.Mul(quantity)
+}
+
+func ValidateCartQuantity(q int) error {
+	if q < 1 || q > MaxCartQuantity {
+		return ErrCartQuantity
+	}
+	return nil
+}
+
+// ValidateOrderStatus aceita vazio (sem filtro) ou um dos OrderStatuses.
+func ValidateOrderStatus(s string) error {
+	if s == "" {
+		return nil
+	}
+	for _, k := range OrderStatuses {
+		if k == s {
+			return nil
+		}
+	}
+	return ErrOrderStatus
+}
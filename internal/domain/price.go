@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// PriceLowWindow é a janela do "menor preço recente" mostrado no histórico.
+const PriceLowWindow = 30 * 24 * time.Hour
+
+var (
+	ErrPriceAlertThreshold = errors.New("O preço do alerta precisa ser maior que zero.")
+	ErrPriceAlertBelow     = errors.New("O preço atual já está abaixo do alerta.")
+)
+
+// PriceChange é uma mudança de preço ou de promoção feita pelo vendedor, com a
+// configuração completa do post a partir de ChangedAt.
+type PriceChange struct {
+	PostID        int        `json:"post_id"`
+	Price         Money      `json:"price"`
+	HasPromo      bool       `json:"has_promo"`
+	Discount      float64    `json:"discount"`
+	PromoStartsAt *time.Time `json:"promo_starts_at,omitempty"`
+	PromoEndsAt   *time.Time `json:"promo_ends_at,omitempty"`
+	ChangedAt     time.Time  `json:"changed_at"`
+}
+
+// PriceChangeOf registra a configuração atual de p (HasPromo é o configurado,
+// não o avaliado agora).
+func PriceChangeOf(p Post, at time.Time) PriceChange {
+	return PriceChange{
+		PostID: p.PostID, Price: p.Price, HasPromo: p.HasPromo, Discount: p.Discount,
+		PromoStartsAt: p.PromoStartsAt, PromoEndsAt: p.PromoEndsAt, ChangedAt: at,
+	}
+}
+
+func (c PriceChange) post() Post {
+	return Post{PostID: c.PostID, Price: c.Price, HasPromo: c.HasPromo, Discount: c.Discount,
+		PromoStartsAt: c.PromoStartsAt, PromoEndsAt: c.PromoEndsAt}
+}
+
+// PricePoint é o preço que valia a partir de At (até o próximo ponto).
+type PricePoint struct {
+	At         time.Time `json:"at"`
+	Price      Money     `json:"price"`
+	HasPromo   bool      `json:"has_promo"`
+	Discount   float64   `json:"discount"`
+	FinalPrice Money     `json:"final_price"`
+}
+
+// PriceHistory é a linha do tempo do preço de um post.
+type PriceHistory struct {
+	PostID     int          `json:"post_id"`
+	FinalPrice Money        `json:"final_price"`
+	Lowest30d  Money        `json:"lowest_30d"`
+	Points     []PricePoint `json:"points"`
+}
+
+// PriceTimeline transforma as mudanças (em qualquer ordem) nos preços que valeram até
+// until: além de cada mudança, o início e o fim das janelas de promoção também
+// mudam o preço. Pontos que não mudam nada são omitidos.
+func PriceTimeline(changes []PriceChange, until time.Time) []PricePoint {
+	changes = append([]PriceChange(nil), changes...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+
+	out := []PricePoint{}
+	for i, c := range changes {
+		end := until
+		if i+1 < len(changes) {
+			end = changes[i+1].ChangedAt
+		}
+		instants := []time.Time{c.ChangedAt}
+		for _, t := range []*time.Time{c.PromoStartsAt, c.PromoEndsAt} {
+			if c.HasPromo && t != nil && t.After(c.ChangedAt) && t.Before(end) && !t.After(until) {
+				instants = append(instants, *t)
+			}
+		}
+		sort.Slice(instants, func(a, b int) bool { return instants[a].Before(instants[b]) })
+		for _, at := range instants {
+			if at.After(until) {
+				continue
+			}
+			p := c.post().AtTime(at)
+			pt := PricePoint{At: at, Price: p.Price, HasPromo: p.HasPromo, FinalPrice: p.FinalPrice}
+			if p.HasPromo {
+				pt.Discount = p.Discount
+			}
+			if n := len(out); n > 0 && samePrice(out[n-1], pt) {
+				continue
+			}
+			out = append(out, pt)
+		}
+	}
+	return out
+}
+
+func samePrice(a, b PricePoint) bool {
+	return a.Price == b.Price && a.HasPromo == b.HasPromo && a.Discount == b.Discount
+}
+
+// LowestSince é o menor FinalPrice que valeu em algum momento desde since
+// (inclui o ponto em vigor em since). Sem pontos devolve 0.
+func LowestSince(points []PricePoint, since time.Time) Money {
+	var lowest Money
+	found := false
+	for i, p := range points {
+		if i+1 < len(points) && !points[i+1].At.After(since) {
+			continue // substituído antes de since
+		}
+		if !found || p.FinalPrice < lowest {
+			lowest, found = p.FinalPrice, true
+		}
+	}
+	return lowest
+}
+
+// PriceAlert avisa UserID quando o FinalPrice do post ficar abaixo de Threshold.
+// Dispara uma vez; assinar de novo rearma.
+type PriceAlert struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	PostID      int        `json:"post_id"`
+	Threshold   Money      `json:"threshold"`
+	CreatedAt   time.Time  `json:"created_at"`
+	TriggeredAt *time.Time `json:"triggered_at,omitempty"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriceTimeline(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	start, end := day(5), day(8)
+	changes := []PriceChange{
+		// fora de ordem de propósito
+		{Price: 100_00, HasPromo: true, Discount: 20, PromoStartsAt: &start, PromoEndsAt: &end, ChangedAt: day(3)},
+		{Price: 120_00, ChangedAt: day(1)},
+		{Price: 100_00, ChangedAt: day(10)}, // mesmo preço que já valia: some
+	}
+	points := PriceTimeline(changes, day(20))
+	want := []struct {
+		at    time.Time
+		final Money
+		promo bool
+	}{
+		{day(1), 120_00, false},
+		{day(3), 100_00, false},
+		{day(5), 80_00, true},
+		{day(8), 100_00, false},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("expected %d points, got %+v", len(want), points)
+	}
+	for i, w := range want {
+		if !points[i].At.Equal(w.at) || points[i].FinalPrice != w.final || points[i].HasPromo != w.promo {
+			t.Fatalf("point %d: unexpected %+v", i, points[i])
+		}
+	}
+	if points[1].Discount != 0 || points[2].Discount != 20 {
+		t.Fatalf("discount only shows while the promo runs: %+v", points)
+	}
+
+	// até o dia 6 o fim da promoção ainda não aconteceu
+	if got := PriceTimeline(changes, day(6)); len(got) != 3 {
+		t.Fatalf("expected 3 points until day 6, got %+v", got)
+	}
+
+	if low := LowestSince(points, day(9)); low != 100_00 {
+		t.Fatalf("expected 100 since day 9, got %v", low)
+	}
+	if low := LowestSince(points, day(6)); low != 80_00 {
+		t.Fatalf("point in effect at since counts, got %v", low)
+	}
+	if low := LowestSince(nil, day(1)); low != 0 {
+		t.Fatalf("expected 0 without points, got %v", low)
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDiscountMin    = errors.New("Desconto deve ser maior que zero")
+	ErrDiscountMax    = errors.New("Desconto deve ser menor ou igual a 100")
+	ErrPromoWindow    = errors.New("O fim da promoção precisa ser depois do início.")
+	ErrPromoEnded     = errors.New("O fim da promoção precisa estar no futuro.")
+	ErrPromoForbidden = errors.New("Você não pode alterar uma publicação que não é sua.")
+)
+
+// PromoSchedule é a promoção configurada num post. Sem datas vale desde já e
+// não termina; Discount 0 tira a promoção.
+type PromoSchedule struct {
+	Discount float64    `json:"discount"`
+	StartsAt *time.Time `json:"promo_starts_at"`
+	EndsAt   *time.Time `json:"promo_ends_at"`
+}
+
+// UTC devolve a promoção com a janela em UTC, como ela fica gravada.
+func (p PromoSchedule) UTC() PromoSchedule {
+	p.StartsAt, p.EndsAt = UTCTime(p.StartsAt), UTCTime(p.EndsAt)
+	return p
+}
+
+// UTCTime converte para UTC o instante opcional (nil continua nil).
+func UTCTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// ValidateDiscount aceita descontos em percentual de (0, 100].
+func ValidateDiscount(discount float64) error {
+	if discount <= 0 {
+		return ErrDiscountMin
+	}
+	if discount > 100 {
+		return ErrDiscountMax
+	}
+	return nil
+}
+
+// ValidatePromoSchedule confere desconto e janela de uma promoção nova.
+func ValidatePromoSchedule(p PromoSchedule, now time.Time) error {
+	if err := ValidateDiscount(p.Discount); err != nil {
+		return err
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return ErrPromoWindow
+	}
+	if p.EndsAt != nil && !p.EndsAt.After(now) {
+		return ErrPromoEnded
+	}
+	return nil
+}
+
+// FinalPrice aplica o desconto (percentual) quando a promoção vale, arredondando
+// como Money.Percent.
+func FinalPrice(price Money, discount float64, promo bool) Money {
+	if !promo {
+		return price
+	}
+	return price.Percent(100 - discount)
+}
+
+// PromoActiveAt diz se a promoção configurada no post vale em now
+// (início incluso, fim excluso).
+func (p Post) PromoActiveAt(now time.Time) bool {
+	if !p.HasPromo {
+		return false
+	}
+	if p.PromoStartsAt != nil && now.Before(*p.PromoStartsAt) {
+		return false
+	}
+	return p.PromoEndsAt == nil || now.Before(*p.PromoEndsAt)
+}
+
+// PromoScheduledAt diz se a promoção configurada só começa depois de now, ou seja,
+// ainda não foi anunciada aos seguidores.
+func (p Post) PromoScheduledAt(now time.Time) bool {
+	return p.HasPromo && p.PromoStartsAt != nil && now.Before(*p.PromoStartsAt)
+}
+
+// AtTime devolve o post como visto em now: HasPromo e FinalPrice passam a refletir
+// se a promoção vale agora. Discount e as datas ficam, para mostrar o agendamento.
+func (p Post) AtTime(now time.Time) Post {
+	p.HasPromo = p.PromoActiveAt(now)
+	p.FinalPrice = FinalPrice(p.Price, p.Discount, p.HasPromo)
+	return p
+}
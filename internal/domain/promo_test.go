@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPost_AtTime(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+	p := Post{Price: 200_00, HasPromo: true, Discount: 25, PromoStartsAt: &start, PromoEndsAt: &end}
+
+	cases := []struct {
+		at     time.Time
+		active bool
+		final  Money
+	}{
+		{start.Add(-time.Second), false, 200_00},
+		{start, true, 150_00},
+		{end.Add(-time.Second), true, 150_00},
+		{end, false, 200_00},
+	}
+	for _, tc := range cases {
+		got := p.AtTime(tc.at)
+		if got.HasPromo != tc.active || got.FinalPrice != tc.final || got.Discount != 25 {
+			t.Fatalf("at %v: unexpected %+v", tc.at, got)
+		}
+	}
+
+	open := Post{Price: 100_00, HasPromo: true, Discount: 10}
+	if got := open.AtTime(start); !got.HasPromo || got.FinalPrice != 90_00 {
+		t.Fatalf("promo without dates must always apply: %+v", got)
+	}
+	if got := (Post{Price: 100_00, Discount: 10}).AtTime(start); got.HasPromo || got.FinalPrice != 100_00 {
+		t.Fatalf("no promo: %+v", got)
+	}
+}
+
+func TestValidatePromoSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	past, future, later := now.Add(-time.Hour), now.Add(time.Hour), now.Add(2*time.Hour)
+
+	cases := []struct {
+		p    PromoSchedule
+		want error
+	}{
+		{PromoSchedule{Discount: 10}, nil},
+		{PromoSchedule{Discount: 0}, ErrDiscountMin},
+		{PromoSchedule{Discount: 101}, ErrDiscountMax},
+		{PromoSchedule{Discount: 10, StartsAt: &later, EndsAt: &future}, ErrPromoWindow},
+		{PromoSchedule{Discount: 10, EndsAt: &past}, ErrPromoEnded},
+		{PromoSchedule{Discount: 10, StartsAt: &past, EndsAt: &future}, nil},
+	}
+	for i, tc := range cases {
+		if err := ValidatePromoSchedule(tc.p, now); err != tc.want {
+			t.Fatalf("case %d: expected %v, got %v", i, tc.want, err)
+		}
+	}
+}
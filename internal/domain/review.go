@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrReviewRating    = errors.New("A nota deve ser de 1 a 5.")
+	ErrReviewSelf      = errors.New("Você não pode avaliar a si mesmo.")
+	ErrReviewNotSeller = errors.New("Só vendedores podem ser avaliados.")
+	ErrReviewForbidden = errors.New("Só o vendedor avaliado pode responder.")
+)
+
+// Review é a avaliação de um comprador para um vendedor (uma por par; editar
+// substitui nota e texto). Reply é a resposta pública do vendedor.
+type Review struct {
+	ID        int        `json:"id"`
+	SellerID  int        `json:"seller_id"`
+	BuyerID   int        `json:"buyer_id"`
+	BuyerName string     `json:"buyer_name"`
+	Rating    int        `json:"rating"`
+	Text      string     `json:"text"`
+	Reply     string     `json:"reply,omitempty"`
+	RepliedAt *time.Time `json:"replied_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// RatingSummary resume as avaliações de um vendedor. Histogram tem sempre as
+// chaves 1 a 5; Average é 0 sem avaliações.
+type RatingSummary struct {
+	Average   float64     `json:"average"`
+	Count     int         `json:"count"`
+	Histogram map[int]int `json:"histogram"`
+}
+
+// NewRatingSummary monta o resumo a partir da contagem por nota.
+func NewRatingSummary(histogram map[int]int) RatingSummary {
+	r := RatingSummary{Histogram: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	sum := 0
+	for stars, n := range histogram {
+		if stars < 1 || stars > 5 {
+			continue
+		}
+		r.Histogram[stars] = n
+		r.Count += n
+		sum += stars * n
+	}
+	if r.Count > 0 {
+		// uma casa decimal, como aparece no perfil
+		r.Average = math.Round(float64(sum)/float64(r.Count)*10) / 10
+	}
+	return r
+}
+
+func ValidateRating(stars int) error {
+	if stars < 1 || stars > 5 {
+		return ErrReviewRating
+	}
+	return nil
+}
+
+// ValidateReviewText usa as mesmas regras (e limite) dos comentários.
+func ValidateReviewText(s string) error {
+	return ValidateCommentText(s)
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestNewRatingSummary(t *testing.T) {
+	empty := NewRatingSummary(nil)
+	if empty.Count != 0 || empty.Average != 0 || len(empty.Histogram) != 5 {
+		t.Fatalf("unexpected empty summary %+v", empty)
+	}
+
+	r := NewRatingSummary(map[int]int{5: 2, 4: 1})
+	if r.Count != 3 || r.Average != 4.7 || r.Histogram[5] != 2 || r.Histogram[1] != 0 {
+		t.Fatalf("unexpected summary %+v", r)
+	}
+}
+
+func TestValidateRating(t *testing.T) {
+	for _, stars := range []int{0, 6, -1} {
+		if err := ValidateRating(stars); err != ErrReviewRating {
+			t.Fatalf("%d: expected ErrReviewRating, got %v", stars, err)
+		}
+	}
+	if err := ValidateRating(3); err != nil {
+		t.Fatalf("unexpected %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrSearchQueryEmpty   = errors.New("Parâmetro obrigatório: q")
+	ErrSearchQueryTooLong = errors.New("A busca não pode exceder 100 caracteres.")
+)
+
+const maxSearchTerms = 8
+
+// Pesos de relevância por campo, os mesmos do ts_rank do Postgres (A, B, C, D).
+const (
+	SearchWeightName  = 1.0 // product_name
+	SearchWeightBrand = 0.4 // brand
+	SearchWeightKind  = 0.2 // type e color
+	SearchWeightNotes = 0.1 // notes
+)
+
+// foldAccent mapeia as letras acentuadas do Latin-1 (o que os validadores aceitam) para ASCII.
+var foldAccent = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y',
+}
+
+// SearchTerms normaliza o texto (minúsculas, sem acento) e quebra em termos.
+// Qualquer coisa que não seja letra ou número separa termos.
+func SearchTerms(s string) []string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if f, ok := foldAccent[r]; ok {
+			r = f
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// ParseSearchQuery valida q e devolve os termos (sem repetição, no máximo 8).
+func ParseSearchQuery(q string) ([]string, error) {
+	if len([]rune(q)) > 100 {
+		return nil, ErrSearchQueryTooLong
+	}
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, t := range SearchTerms(q) {
+		if seen[t] || len(terms) == maxSearchTerms {
+			continue
+		}
+		seen[t] = true
+		terms = append(terms, t)
+	}
+	if len(terms) == 0 {
+		return nil, ErrSearchQueryEmpty
+	}
+	return terms, nil
+}
+
+// UserSummary é o usuário como aparece na busca e no typeahead.
+type UserSummary struct {
+	ID        int    `json:"user_id"`
+	Name      string `json:"user_name"`
+	IsSeller  bool   `json:"is_seller"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// NameMatches diz se cada termo é prefixo de alguma palavra do nome.
+// Termos devem vir de SearchTerms/ParseSearchQuery.
+func NameMatches(name string, terms []string) bool {
+	words := SearchTerms(name)
+	for _, t := range terms {
+		found := false
+		for _, w := range words {
+			if strings.HasPrefix(w, t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := SearchTerms("Tênis  CORRIDA-Açaí 42")
+	want := []string{"tenis", "corrida", "acai", "42"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := SearchTerms("%$&"); len(got) != 0 {
+		t.Fatalf("expected no terms, got %v", got)
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	terms, err := ParseSearchQuery("café Café preto")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !reflect.DeepEqual(terms, []string{"cafe", "preto"}) {
+		t.Fatalf("unexpected terms %v", terms)
+	}
+
+	if _, err := ParseSearchQuery("  !! "); !errors.Is(err, ErrSearchQueryEmpty) {
+		t.Fatalf("expected ErrSearchQueryEmpty, got %v", err)
+	}
+	if _, err := ParseSearchQuery(strings.Repeat("a", 101)); !errors.Is(err, ErrSearchQueryTooLong) {
+		t.Fatalf("expected ErrSearchQueryTooLong, got %v", err)
+	}
+}
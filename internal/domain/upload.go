@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Upload registra um arquivo enviado (todas as variantes de uma imagem).
+// Os blobs são endereçados pelo SHA-256 do conteúdo original (Hash), então o mesmo
+// arquivo enviado várias vezes, por qualquer usuário, vira um único Upload.
+// RefCount não é gravado: é calculado pelo store contando posts, avatares e itens
+// de pedidos que apontam para URL, então nunca fica dessincronizado.
+type Upload struct {
+	ID      string   `json:"id"`
+	Hash    string   `json:"hash,omitempty"`
+	OwnerID int      `json:"owner_id"`
+	URL     string   `json:"url"`
+	Keys    []string `json:"keys"`
+	Size    int64    `json:"size"`
+	// CreatedAt é renovado a cada novo envio do mesmo conteúdo (reinicia a carência da coleta)
+	CreatedAt time.Time `json:"created_at"`
+	// UnreferencedSince é quando a coleta viu o upload sem referências pela primeira
+	// vez (nil enquanto está em uso); a carência conta a partir daqui.
+	UnreferencedSince *time.Time `json:"unreferenced_since,omitempty"`
+	// CollectingSince é quando a coleta começou a apagar os arquivos; a partir daí o
+	// upload não é mais reaproveitado e o registro some quando os arquivos saírem.
+	CollectingSince *time.Time `json:"collecting_since,omitempty"`
+	RefCount        int        `json:"ref_count"`
+}
+
+// UploadPart é um pedaço recebido de um upload retomável: Size bytes a partir de
+// Start, guardados no blob store em Key.
+type UploadPart struct {
+	UploadID  string    `json:"upload_id"`
+	Start     int64     `json:"start"`
+	Size      int64     `json:"size"`
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StorageUsage é o consumo de uploads de uma conta frente à cota.
+type StorageUsage struct {
+	Count    int   `json:"count"`
+	Bytes    int64 `json:"bytes"`
+	MaxCount int   `json:"max_count"`
+	MaxBytes int64 `json:"max_bytes"`
+}
@@ -34,13 +34,17 @@ func ValidateID(id int) error {
 }
 
 func ValidateTextRequired(s string, max int, maxErr error) error {
+	return validateText(s, max, maxErr, allowedText)
+}
+
+func validateText(s string, max int, maxErr error, allowed *regexp.Regexp) error {
 	if s == "" {
 		return ErrFieldEmpty
 	}
 	if len([]rune(s)) > max {
 		return maxErr
 	}
-	if !allowedText.MatchString(s) {
+	if !allowed.MatchString(s) {
 		return ErrSpecialChars
 	}
 	return nil
@@ -59,11 +63,14 @@ func ValidateNotesOptional(s string) error {
 	return nil
 }
 
-func ValidatePrice(price float64) error {
+// MaxPrice é o maior preço aceito por produto.
+const MaxPrice = Money(10_000_000 * 100)
+
+func ValidatePrice(price Money) error {
 	if price == 0 {
 		return ErrPriceEmpty
 	}
-	if price > 10_000_000 {
+	if price > MaxPrice {
 		return ErrPriceMax
 	}
 	return nil
@@ -140,7 +140,7 @@ func TestValidateNotesOptional(t *testing.T) {
 func TestValidatePrice(t *testing.T) {
 	tests := []struct {
 		name    string
-		price   float64
+		price   Money
 		wantErr error
 	}{
 		{
@@ -150,17 +150,17 @@ func TestValidatePrice(t *testing.T) {
 		},
 		{
 			name:    "preço maior que 10_000_000 retorna ErrPriceMax",
-			price:   10_000_000.01,
+			price:   10_000_000_01,
 			wantErr: ErrPriceMax,
 		},
 		{
 			name:    "preço exatamente no limite é válido",
-			price:   10_000_000,
+			price:   10_000_000_00,
 			wantErr: nil,
 		},
 		{
 			name:    "preço positivo dentro do limite é válido",
-			price:   123.45,
+			price:   123_45,
 			wantErr: nil,
 		},
 	}
@@ -169,7 +169,7 @@ func TestValidatePrice(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			err := ValidatePrice(tt.price)
 			if err != tt.wantErr {
-				t.Fatalf("ValidatePrice(%f) error = %v, want %v", tt.price, err, tt.wantErr)
+				t.Fatalf("ValidatePrice(%s) error = %v, want %v", tt.price, err, tt.wantErr)
 			}
 		})
 	}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrWebhookURL    = errors.New("URL do webhook inválida (use http ou https)")
+	ErrWebhookEvents = errors.New("Evento de webhook inválido")
+	ErrWebhookStatus = errors.New("Status de entrega inválido")
+	ErrWebhookLimit  = errors.New("Limite de webhooks por usuário atingido")
+	ErrNotDead       = errors.New("Só entregas na dead-letter podem ser reenviadas")
+)
+
+// MaxWebhooksPerUser limita as assinaturas de cada usuário.
+const MaxWebhooksPerUser = 10
+
+// Eventos que um webhook pode assinar. Todos se referem ao dono do webhook.
+const (
+	WebhookFollowerNew = "follower.new" // alguém passou a seguir o dono
+	WebhookPostCreated = "post.created" // o dono publicou um post
+	WebhookPostUpdated = "post.updated" // um post do dono foi alterado
+	WebhookPostDeleted = "post.deleted" // o dono apagou um post
+)
+
+// WebhookEvents lista os eventos na ordem em que foram criados.
+var WebhookEvents = []string{WebhookFollowerNew, WebhookPostCreated, WebhookPostUpdated, WebhookPostDeleted}
+
+// Estados de uma entrega.
+const (
+	DeliveryPending   = "pending"   // aguardando (primeira tentativa ou retry)
+	DeliverySucceeded = "succeeded" // o receptor respondeu 2xx
+	DeliveryDead      = "dead"      // esgotou as tentativas: fica na dead-letter
+)
+
+// Webhook é a assinatura de um usuário: cada evento assinado é enviado por POST
+// para URL, assinado com Secret (HMAC-SHA256). O segredo só aparece na criação.
+type Webhook struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Subscribes diz se o webhook assina o evento.
+func (w Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery é um evento a entregar para um webhook e o histórico das tentativas.
+type WebhookDelivery struct {
+	ID            int             `json:"id"`
+	WebhookID     int             `json:"webhook_id"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastStatus    int             `json:"last_status_code,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+func ValidateWebhook(rawURL string, events []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(rawURL) > 2048 {
+		return ErrWebhookURL
+	}
+	if len(events) == 0 {
+		return ErrWebhookEvents
+	}
+	for _, e := range events {
+		if !knownWebhookEvent(e) {
+			return ErrWebhookEvents
+		}
+	}
+	return nil
+}
+
+func knownWebhookEvent(e string) bool {
+	for _, k := range WebhookEvents {
+		if k == e {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidateDeliveryStatus(s string) error {
+	switch s {
+	case "", DeliveryPending, DeliverySucceeded, DeliveryDead:
+		return nil
+	}
+	return ErrWebhookStatus
+}
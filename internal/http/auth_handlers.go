@@ -61,18 +61,27 @@ func (h *AuthHandlers) Login(c *gin.Context) {
 
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		auth := c.GetHeader("Authorization")
-		if auth == "" || !strings.HasPrefix(strings.ToLower(auth), "bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		uid, ok := bearerUserID(c)
+		if !ok {
 			return
 		}
-		tokenStr := strings.TrimSpace(auth[7:])
-		claims, err := ParseToken(tokenStr)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
-		}
-		c.Set("auth_user_id", claims.Sub)
+		c.Set("auth_user_id", uid)
 		c.Next()
 	}
 }
+
+// bearerUserID valida o header Authorization; sem token válido já responde 401.
+// O token nunca vai na query string: o log de acesso grava a URL inteira.
+func bearerUserID(c *gin.Context) (int, bool) {
+	auth := c.GetHeader("Authorization")
+	if auth == "" || !strings.HasPrefix(strings.ToLower(auth), "bearer ") {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return 0, false
+	}
+	claims, err := ParseToken(strings.TrimSpace(auth[7:]))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	return claims.Sub, true
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"math"
+	"net/http"
+
+	"socialmeli/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandlers struct {
+	cs *service.CommentService
+}
+
+func NewCommentHandlers(cs *service.CommentService) *CommentHandlers {
+	return &CommentHandlers{cs: cs}
+}
+
+func (h *CommentHandlers) available(c *gin.Context) bool {
+	if h.cs == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "comentários indisponíveis"})
+		return false
+	}
+	return true
+}
+
+// authedComment devolve usuário logado, post e comentário da rota.
+func (h *CommentHandlers) authedComment(c *gin.Context) (uid, postID, commentID int, ok bool) {
+	if !h.available(c) {
+		return 0, 0, 0, false
+	}
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return 0, 0, 0, false
+	}
+	if postID, ok = intParam(c, "postId"); !ok {
+		return 0, 0, 0, false
+	}
+	if commentID, ok = intParam(c, "commentId"); !ok {
+		return 0, 0, 0, false
+	}
+	return uidAny.(int), postID, commentID, true
+}
+
+// List godoc
+// @Summary Perguntas e respostas de um post
+// @Description Perguntas da mais recente para a mais antiga, cada uma com as respostas em ordem cronológica.
+// @Tags comments
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Perguntas por página (padrão 20, máx 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/comments [get]
+func (h *CommentHandlers) List(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+	items, total, err := h.cs.List(postID, page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"comments": items,
+		"meta": PageMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+type commentRequest struct {
+	Text     string `json:"text"`
+	ParentID int    `json:"parent_id"`
+}
+
+// Create godoc
+// @Summary Pergunta ou responde em um post
+// @Description Sem parent_id é uma pergunta; com parent_id, uma resposta (só um nível).
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 201 {object} domain.Comment
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/comments [post]
+func (h *CommentHandlers) Create(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	cm, err := h.cs.Add(uidAny.(int), postID, req.ParentID, req.Text)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, cm)
+}
+
+// Edit godoc
+// @Summary Edita um comentário (só o autor)
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Param commentId path int true "ID do comentário"
+// @Success 200 {object} domain.Comment
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/comments/{commentId} [patch]
+func (h *CommentHandlers) Edit(c *gin.Context) {
+	uid, postID, commentID, ok := h.authedComment(c)
+	if !ok {
+		return
+	}
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	cm, err := h.cs.Edit(uid, postID, commentID, req.Text)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cm)
+}
+
+// Delete godoc
+// @Summary Apaga um comentário (autor ou dono do post)
+// @Description Apagar uma pergunta apaga as respostas.
+// @Tags comments
+// @Param postId path int true "ID da publicacao"
+// @Param commentId path int true "ID do comentário"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/comments/{commentId} [delete]
+func (h *CommentHandlers) Delete(c *gin.Context) {
+	uid, postID, commentID, ok := h.authedComment(c)
+	if !ok {
+		return
+	}
+	if err := h.cs.Delete(uid, postID, commentID); err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Answer godoc
+// @Summary Marca (PUT) ou desmarca (DELETE) a resposta do vendedor
+// @Description Só o dono do post. Uma resposta marcada por pergunta; marcar outra substitui.
+// @Tags comments
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Param commentId path int true "ID da resposta"
+// @Success 200 {object} domain.Comment
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/comments/{commentId}/answer [put]
+// @Router /products/{postId}/comments/{commentId}/answer [delete]
+func (h *CommentHandlers) Answer(c *gin.Context) {
+	uid, postID, commentID, ok := h.authedComment(c)
+	if !ok {
+		return
+	}
+	cm, err := h.cs.MarkAnswer(uid, postID, commentID, c.Request.Method == http.MethodPut)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cm)
+}
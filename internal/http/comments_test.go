@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Buyer"}, {ID: 2, Name: "Seller", IsSeller: true}})
+	postID, err := st.AddPost(domain.Post{UserID: 2})
+	require.NoError(t, err)
+
+	h := NewCommentHandlers(service.NewCommentService(st))
+	r := gin.New()
+	auth := func(uid int, fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", uid); fn(c) }
+	}
+	r.GET("/products/:postId/comments", h.List)
+	r.POST("/products/:postId/comments", auth(1, h.Create))
+	r.POST("/seller/products/:postId/comments", auth(2, h.Create))
+	r.PATCH("/products/:postId/comments/:commentId", auth(1, h.Edit))
+	r.DELETE("/products/:postId/comments/:commentId", auth(1, h.Delete))
+	r.PUT("/products/:postId/comments/:commentId/answer", auth(2, h.Answer))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	base := "/products/" + strconv.Itoa(postID) + "/comments"
+
+	w := do(http.MethodPost, base, `{"text":"Tem 42?"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var q domain.Comment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &q))
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, base, `{"text":""}`).Code)
+	w = do(http.MethodPost, "/seller"+base, `{"text":"Tem sim","parent_id":`+strconv.Itoa(q.ID)+`}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var a domain.Comment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &a))
+	require.True(t, a.BySeller)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPut, base+"/"+strconv.Itoa(a.ID)+"/answer", "").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, base+"/"+strconv.Itoa(q.ID), `{"text":"Tem 43?"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPatch, base+"/"+strconv.Itoa(a.ID), `{"text":"hack"}`).Code)
+
+	w = do(http.MethodGet, base+"?limit=1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Comments []domain.Comment `json:"comments"`
+		Meta     PageMeta         `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Comments, 1)
+	require.Equal(t, "Tem 43?", page.Comments[0].Text)
+	require.True(t, page.Comments[0].Answered)
+	require.Len(t, page.Comments[0].Replies, 1)
+	require.Equal(t, 1, page.Meta.Total)
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, base+"/"+strconv.Itoa(q.ID), "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/products/99/comments", "").Code)
+}
+
+func TestCommentHandlers_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/products/:postId/comments", NewCommentHandlers(nil).List)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/1/comments", nil))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
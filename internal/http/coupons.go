@@ -0,0 +1,103 @@
+package http
+
+import (
+	"net/http"
+
+	"socialmeli/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CouponHandlers struct {
+	coupons *service.CouponService
+}
+
+func NewCouponHandlers(coupons *service.CouponService) *CouponHandlers {
+	return &CouponHandlers{coupons: coupons}
+}
+
+// authedCoupons devolve o usuário logado; sem serviço configurado responde 503.
+func (h *CouponHandlers) authedCoupons(c *gin.Context) (int, bool) {
+	if h.coupons == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "cupons indisponíveis"})
+		return 0, false
+	}
+	uid, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return 0, false
+	}
+	return uid.(int), true
+}
+
+// Create godoc
+// @Summary Cria um cupom do vendedor logado
+// @Description kind percent (value = %) ou fixed (value = valor abatido). Limites 0 = sem limite.
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param body body service.CouponInput true "Cupom"
+// @Success 201 {object} domain.Coupon
+// @Failure 400 {object} map[string]string
+// @Router /coupons [post]
+func (h *CouponHandlers) Create(c *gin.Context) {
+	uid, ok := h.authedCoupons(c)
+	if !ok {
+		return
+	}
+	var req service.CouponInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	coupon, err := h.coupons.Create(uid, req)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, coupon)
+}
+
+// List godoc
+// @Summary Cupons do vendedor logado
+// @Description Mais recentes primeiro, com quantos usos cada um já teve.
+// @Tags coupons
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /coupons [get]
+func (h *CouponHandlers) List(c *gin.Context) {
+	uid, ok := h.authedCoupons(c)
+	if !ok {
+		return
+	}
+	coupons, err := h.coupons.List(uid)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"coupons": coupons})
+}
+
+// Delete godoc
+// @Summary Remove um cupom do vendedor logado
+// @Description Pedidos que já usaram o cupom mantêm o código e o desconto.
+// @Tags coupons
+// @Param id path int true "Cupom"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /coupons/{id} [delete]
+func (h *CouponHandlers) Delete(c *gin.Context) {
+	uid, ok := h.authedCoupons(c)
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.coupons.Delete(uid, id); err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
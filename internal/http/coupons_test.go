@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCouponHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Buyer"}, {ID: 2, Name: "Seller", IsSeller: true}})
+	postID, err := st.AddPost(domain.Post{UserID: 2, Price: 100_00, FinalPrice: 100_00, Product: domain.Product{ProductName: "Fone"}})
+	require.NoError(t, err)
+
+	cs := service.NewCouponService(st)
+	orders := service.NewOrderService(st)
+	orders.SetCoupons(cs)
+	ch := NewCouponHandlers(cs)
+	oh := NewOrderHandlers(orders)
+	r := gin.New()
+	auth := func(uid int, fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", uid); fn(c) }
+	}
+	r.POST("/coupons", auth(2, ch.Create))
+	r.GET("/coupons", auth(2, ch.List))
+	r.DELETE("/coupons/:id", auth(2, ch.Delete))
+	r.POST("/buyer/coupons", auth(1, ch.Create))
+	r.POST("/cart/items", auth(1, oh.AddToCart))
+	r.GET("/cart", auth(1, oh.Cart))
+	r.POST("/cart/checkout", auth(1, oh.Checkout))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/buyer/coupons", `{"code":"X10","kind":"percent","value":10}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/coupons", `{"code":"X10","kind":"percent","value":150}`).Code)
+	w := do(http.MethodPost, "/coupons", `{"code":"fone10","kind":"percent","value":10,"max_uses":1}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var coupon domain.Coupon
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &coupon))
+	require.Equal(t, "FONE10", coupon.Code)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/cart/items", `{"post_id":`+strconv.Itoa(postID)+`}`).Code)
+	w = do(http.MethodGet, "/cart?coupon=fone10", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var cart domain.Cart
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cart))
+	require.Equal(t, domain.Money(10_00), cart.Discount)
+	require.Equal(t, domain.Money(90_00), cart.Total)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/cart?coupon=NOPE", "").Code)
+
+	w = do(http.MethodPost, "/cart/checkout", `{"coupon":"FONE10"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var placed struct {
+		Orders []domain.Order `json:"orders"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &placed))
+	require.Equal(t, domain.Money(90_00), placed.Orders[0].Total)
+	require.Equal(t, "FONE10", placed.Orders[0].CouponCode)
+
+	w = do(http.MethodGet, "/coupons", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Coupons []domain.Coupon `json:"coupons"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Coupons, 1)
+	require.Equal(t, 1, list.Coupons[0].Uses)
+
+	path := "/coupons/" + strconv.Itoa(coupon.ID)
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, path, "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, path, "").Code)
+}
+
+func TestCouponHandlers_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewCouponHandlers(nil)
+	r := gin.New()
+	r.GET("/coupons", h.List)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coupons", nil))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
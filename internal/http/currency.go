@@ -0,0 +1,177 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware libera a rota só para quem mandar o token de administração no
+// header X-Admin-Token. Sem token configurado as rotas ficam desligadas (503).
+func AdminMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "administração desabilitada"})
+			return
+		}
+		got := c.GetHeader("X-Admin-Token")
+		if got == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token de administração ausente"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "token de administração inválido"})
+			return
+		}
+		c.Next()
+	}
+}
+
+type CurrencyHandlers struct {
+	currencies *service.CurrencyService
+}
+
+func NewCurrencyHandlers(currencies *service.CurrencyService) *CurrencyHandlers {
+	return &CurrencyHandlers{currencies: currencies}
+}
+
+func (h *CurrencyHandlers) available(c *gin.Context) bool {
+	if h.currencies == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "cotações indisponíveis"})
+		return false
+	}
+	return true
+}
+
+// Rates godoc
+// @Summary Tabela de cotações
+// @Description Quanto vale 1 unidade de cada moeda em BRL (a moeda base vale sempre 1 e não aparece).
+// @Tags currencies
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /exchange-rates [get]
+func (h *CurrencyHandlers) Rates(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	rates, err := h.currencies.Rates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"base": domain.BaseCurrency, "rates": rates})
+}
+
+type setRatesRequest struct {
+	Rates []domain.ExchangeRate `json:"rates"`
+}
+
+// SetRates godoc
+// @Summary Cria ou atualiza cotações (admin)
+// @Description Grava tudo ou nada; moedas fora da lista continuam como estão. Exige X-Admin-Token.
+// @Tags currencies
+// @Accept json
+// @Produce json
+// @Param body body setRatesRequest true "Cotações"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/exchange-rates [put]
+func (h *CurrencyHandlers) SetRates(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req setRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	rates, err := h.currencies.SetRates(req.Rates)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"base": domain.BaseCurrency, "rates": rates})
+}
+
+// maxRatesFile limita o CSV importado; uma linha por moeda cabe com folga.
+const maxRatesFile = 64 << 10
+
+// Import godoc
+// @Summary Importa cotações de um CSV (admin)
+// @Description Corpo text/csv com linhas moeda,cotação (cabeçalho "currency,rate" e linhas com # são ignorados). Grava tudo ou nada. Exige X-Admin-Token.
+// @Tags currencies
+// @Accept plain
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /admin/exchange-rates/import [post]
+func (h *CurrencyHandlers) Import(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxRatesFile)
+	rates, err := h.currencies.Import(body)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"base": domain.BaseCurrency, "rates": rates})
+}
+
+// Delete godoc
+// @Summary Remove a cotação de uma moeda (admin)
+// @Description Listagens pedidas nessa moeda (ou com posts nela) passam a responder 400 até a cotação voltar. Exige X-Admin-Token.
+// @Tags currencies
+// @Param currency path string true "Código ISO 4217"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /admin/exchange-rates/{currency} [delete]
+func (h *CurrencyHandlers) Delete(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	if err := h.currencies.Delete(c.Param("currency")); err != nil {
+		if errors.Is(err, store.ErrExchangeRateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		badRequest(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ProductHandlers) convertPosts(c *gin.Context, posts []domain.Post) ([]domain.Post, bool) {
+	return convertPosts(c, h.currencies, posts)
+}
+
+// convertPosts atende ?currency= das listagens: devolve os posts com o bloco
+// converted preenchido. Sem o parâmetro devolve os posts como vieram.
+func convertPosts(c *gin.Context, currencies *service.CurrencyService, posts []domain.Post) ([]domain.Post, bool) {
+	to := c.Query("currency")
+	if to == "" {
+		return posts, true
+	}
+	if currencies == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "conversão de moeda indisponível"})
+		return nil, false
+	}
+	out, err := currencies.ConvertPosts(posts, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrCurrency) || errors.Is(err, domain.ErrNoExchangeRate) {
+			badRequest(c, err)
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return nil, false
+	}
+	return out, true
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyHandlers_Admin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := NewCurrencyHandlers(service.NewCurrencyService(store.NewMemoryStore()))
+	r := gin.New()
+	r.GET("/exchange-rates", h.Rates)
+	admin := r.Group("/admin", AdminMiddleware("s3cret"))
+	admin.PUT("/exchange-rates", h.SetRates)
+	admin.POST("/exchange-rates/import", h.Import)
+	admin.DELETE("/exchange-rates/:currency", h.Delete)
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"rates":[{"currency":"ARS","rate":0.0055}]}`
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPut, "/admin/exchange-rates", "", body).Code)
+	require.Equal(t, http.StatusForbidden, do(http.MethodPut, "/admin/exchange-rates", "errado", body).Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPut, "/admin/exchange-rates", "s3cret", body).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/admin/exchange-rates", "s3cret", `{"rates":[{"currency":"XYZ","rate":1}]}`).Code)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/admin/exchange-rates/import", "s3cret", "MXN;0.3\n").Code)
+	w := do(http.MethodPost, "/admin/exchange-rates/import", "s3cret", "currency,rate\nMXN,0.3\n")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = do(http.MethodGet, "/exchange-rates", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Base  string                `json:"base"`
+		Rates []domain.ExchangeRate `json:"rates"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, "BRL", list.Base)
+	require.Len(t, list.Rates, 2)
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/admin/exchange-rates/ars", "s3cret", "").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/admin/exchange-rates/ARS", "s3cret", "").Code)
+}
+
+func TestAdminMiddleware_NoToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.PUT("/admin/exchange-rates", AdminMiddleware(""), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/exchange-rates", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestProductHandlers_SearchCurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 2, Name: "Seller", IsSeller: true}})
+	_, err := st.AddPost(domain.Post{UserID: 2, Price: 1000_00, FinalPrice: 1000_00, Currency: "ARS", Product: domain.Product{ProductName: "Fone"}})
+	require.NoError(t, err)
+	require.NoError(t, st.SetExchangeRates([]domain.ExchangeRate{{Currency: "ARS", Rate: 0.0055}}))
+
+	h := NewProductHandlers(service.NewProductService(st))
+	r := gin.New()
+	r.GET("/products/search", h.Search)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// sem serviço de cotações só o ?currency= fica indisponível
+	require.Equal(t, http.StatusOK, get("/products/search?q=fone").Code)
+	require.Equal(t, http.StatusServiceUnavailable, get("/products/search?q=fone&currency=BRL").Code)
+
+	h.currencies = service.NewCurrencyService(st)
+	w := get("/products/search?q=fone&currency=brl")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Posts []domain.Post `json:"posts"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Posts, 1)
+	require.Equal(t, "ARS", body.Posts[0].Currency)
+	require.Equal(t, domain.Money(1000_00), body.Posts[0].Price)
+	require.NotNil(t, body.Posts[0].Converted)
+	require.Equal(t, domain.ConvertedPrice{Currency: "BRL", Price: 5_50, FinalPrice: 5_50}, *body.Posts[0].Converted)
+
+	require.Equal(t, http.StatusBadRequest, get("/products/search?q=fone&currency=REAL").Code)
+	require.Equal(t, http.StatusBadRequest, get("/products/search?q=fone&currency=MXN").Code)
+}
+
+func TestProfileHandlers_MyPostsCurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 2, Name: "Seller", IsSeller: true}})
+	_, err := st.AddPost(domain.Post{UserID: 2, Price: 1000_00, FinalPrice: 1000_00, Currency: "ARS", Product: domain.Product{ProductName: "Fone"}})
+	require.NoError(t, err)
+	require.NoError(t, st.SetExchangeRates([]domain.ExchangeRate{{Currency: "ARS", Rate: 0.0055}}))
+
+	h := NewProfileHandlers(service.NewUserService(st))
+	h.currencies = service.NewCurrencyService(st)
+	r := gin.New()
+	r.GET("/users/me/posts", func(c *gin.Context) { c.Set("auth_user_id", 2) }, h.MyPosts)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/users/me/posts?currency=BRL")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Posts []domain.Post `json:"posts"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Posts, 1)
+	require.NotNil(t, body.Posts[0].Converted)
+	require.Equal(t, domain.ConvertedPrice{Currency: "BRL", Price: 5_50, FinalPrice: 5_50}, *body.Posts[0].Converted)
+
+	require.Equal(t, http.StatusBadRequest, get("/users/me/posts?currency=MXN").Code)
+	w = get("/users/me/posts")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "converted")
+}
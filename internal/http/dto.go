@@ -1,5 +1,7 @@
 package http
 
+import "socialmeli/internal/domain"
+
 type FollowersCountResponse struct {
 	UserID         int    `json:"userId"`
 	UserName       string `json:"userName"`
@@ -30,8 +32,9 @@ type PublishResponse struct {
 }
 
 type FollowedPostsResponse struct {
-	UserID int `json:"user_id"`
-	Posts  any `json:"posts"`
+	UserID int                `json:"user_id"`
+	Posts  any                `json:"posts"`
+	Facets *domain.PostFacets `json:"facets,omitempty"`
 }
 
 type PromoCountResponse struct {
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// react trata like/unlike e favoritar/desfavoritar: mesma rota, POST liga e DELETE desliga.
+func (h *ProductHandlers) react(c *gin.Context, set func(userID, postID int, on bool) (int, int, error)) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	likes, favorites, err := set(uidAny.(int), postID, c.Request.Method == http.MethodPost)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post_id": postID, "likes_count": likes, "favorites_count": favorites})
+}
+
+// Like godoc
+// @Summary Curte (POST) ou descurte (DELETE) um post
+// @Description Idempotente. Devolve os contadores atualizados.
+// @Tags products
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/like [post]
+// @Router /products/{postId}/like [delete]
+func (h *ProductHandlers) Like(c *gin.Context) {
+	h.react(c, func(userID, postID int, on bool) (int, int, error) {
+		counts, err := h.ps.SetLike(userID, postID, on)
+		return counts.Likes, counts.Favorites, err
+	})
+}
+
+// Favorite godoc
+// @Summary Adiciona (POST) ou remove (DELETE) um post dos favoritos
+// @Description A lista de favoritos é privada; só a contagem aparece nos posts.
+// @Tags products
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/favorite [post]
+// @Router /products/{postId}/favorite [delete]
+func (h *ProductHandlers) Favorite(c *gin.Context) {
+	h.react(c, func(userID, postID int, on bool) (int, int, error) {
+		counts, err := h.ps.SetFavorite(userID, postID, on)
+		return counts.Likes, counts.Favorites, err
+	})
+}
+
+// MyFavorites godoc
+// @Summary Favoritos do usuário logado
+// @Description Do favoritado mais recentemente para o mais antigo.
+// @Tags users
+// @Produce json
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Param currency query string false "Moeda ISO 4217 para exibir os preços (bloco converted)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/me/favorites [get]
+func (h *ProductHandlers) MyFavorites(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+	posts, total, err := h.ps.Favorites(uidAny.(int), page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	if posts, ok = h.convertPosts(c, posts); !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"posts": posts,
+		"meta":  PageMeta{Page: page, Limit: limit, Total: total, TotalPages: (total + limit - 1) / limit},
+	})
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngagementRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	seller, err := st.CreateAccount("Seller", "seller@example.com", "hash", true)
+	require.NoError(t, err)
+	buyer, err := st.CreateAccount("Buyer", "buyer@example.com", "hash", false)
+	require.NoError(t, err)
+	postID, err := st.AddPost(domain.Post{UserID: seller.ID, Date: time.Now(), Price: 10_00})
+	require.NoError(t, err)
+
+	r := NewRouter(service.NewUserService(st), service.NewProductService(st), service.NewAuthService(st))
+	token, err := MakeToken(buyer.ID, time.Hour)
+	require.NoError(t, err)
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	post := "/products/" + strconv.Itoa(postID)
+	w := do(http.MethodPost, post+"/like")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"post_id":`+strconv.Itoa(postID)+`,"likes_count":1,"favorites_count":0}`, w.Body.String())
+	require.Equal(t, http.StatusOK, do(http.MethodPost, post+"/like").Code) // idempotente
+	require.Equal(t, http.StatusOK, do(http.MethodPost, post+"/favorite").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/products/999/like").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/products/abc/like").Code)
+
+	w = do(http.MethodGet, "/users/me/favorites")
+	require.Equal(t, http.StatusOK, w.Code)
+	var favs struct {
+		Posts []domain.Post `json:"posts"`
+		Meta  PageMeta      `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &favs))
+	require.Len(t, favs.Posts, 1)
+	require.Equal(t, 1, favs.Posts[0].LikesCount)
+	require.Equal(t, 1, favs.Posts[0].FavoritesCount)
+	require.Equal(t, 1, favs.Meta.Total)
+
+	w = do(http.MethodDelete, post+"/like")
+	require.JSONEq(t, `{"post_id":`+strconv.Itoa(postID)+`,"likes_count":0,"favorites_count":1}`, w.Body.String())
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, post+"/favorite").Code)
+	require.NoError(t, json.Unmarshal(do(http.MethodGet, "/users/me/favorites").Body.Bytes(), &favs))
+	require.Empty(t, favs.Posts)
+
+	// sem token
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, post+"/like", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"socialmeli/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedStreamHeartbeat mantém a conexão viva em proxies que derrubam conexões ociosas.
+var feedStreamHeartbeat = 15 * time.Second
+
+// feedStreamRetry é o intervalo de reconexão sugerido ao EventSource (ms).
+const feedStreamRetry = 3000
+
+// lastEventID lê o Last-Event-ID do header (reconexão do EventSource) ou de
+// ?last_event_id= (primeira conexão, quando o navegador não deixa mandar header).
+func lastEventID(c *gin.Context) (uint64, bool) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro inválido: Last-Event-ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeFeedEvent(w gin.ResponseWriter, ev service.FeedEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+func writeFeedControl(w gin.ResponseWriter, event string) {
+	fmt.Fprintf(w, "event: %s\ndata: {}\n\n", event)
+	w.Flush()
+}
+
+// FeedStream godoc
+// @Summary Stream do feed (SSE)
+// @Description Envia, como Server-Sent Events, posts novos (post/promo) e apagados (deleted) de quem o usuário segue.
+// @Description Reconectando com Last-Event-ID os eventos perdidos são reenviados; se não houver mais histórico chega um evento reset (recarregue o feed).
+// @Description Se a conexão não consumir rápido o bastante recebe overflow e é encerrada.
+// @Tags products
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "Último evento recebido"
+// @Param last_event_id query string false "Mesmo que o header, para a primeira conexão"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 400 {object} map[string]string "Parâmetro inválido"
+// @Router /products/feed/stream [get]
+func (h *ProductHandlers) FeedStream(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	lastID, ok := lastEventID(c)
+	if !ok {
+		return
+	}
+	sub, err := h.ps.SubscribeFeed(uidAny.(int), lastID)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	defer sub.Close()
+
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx não pode segurar o stream
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", feedStreamRetry)
+	w.Flush()
+
+	if sub.Reset {
+		writeFeedControl(w, "reset")
+	}
+	for _, ev := range sub.Backlog {
+		if err := writeFeedEvent(w, ev); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(feedStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			w.Flush()
+		case ev, ok := <-sub.Events():
+			if !ok {
+				if sub.Lagged() {
+					writeFeedControl(w, "overflow")
+				}
+				return
+			}
+			if err := writeFeedEvent(w, ev); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type sseEvent struct {
+	id, event, data string
+}
+
+// readSSE devolve os eventos (ignorando comentários e o retry inicial) num canal.
+func readSSE(body *bufio.Reader) <-chan sseEvent {
+	out := make(chan sseEvent, 16)
+	go func() {
+		defer close(out)
+		var ev sseEvent
+		for {
+			line, err := body.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "":
+				if ev.event != "" {
+					out <- ev
+				}
+				ev = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				ev.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				ev.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return out
+}
+
+func nextSSE(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		require.True(t, ok, "stream closed")
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timeout waiting for event")
+		return sseEvent{}
+	}
+}
+
+func TestFeedStream_PushAndResume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	buyer, err := st.CreateAccount("Buyer", "buyer@example.com", "hash", false)
+	require.NoError(t, err)
+	seller, err := st.CreateAccount("Seller", "seller@example.com", "hash", true)
+	require.NoError(t, err)
+	require.NoError(t, st.Follow(buyer.ID, seller.ID))
+
+	ps := service.NewProductService(st)
+	srv := httptest.NewServer(NewRouter(service.NewUserService(st), ps, service.NewAuthService(st)))
+	defer srv.Close()
+	token, err := MakeToken(buyer.ID, time.Hour)
+	require.NoError(t, err)
+
+	open := func(lastID string) (*http.Response, <-chan sseEvent, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/products/feed/stream", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp, readSSE(bufio.NewReader(resp.Body)), cancel
+	}
+
+	publish := func(promo bool) {
+		p := service.PublishPayload{
+			UserID: seller.ID, Date: time.Now().Format("02-01-2006"), Category: 1, Price: 100_00,
+			Product: domain.Product{ProductID: 1, ProductName: "Cadeira", Type: "Gamer", Brand: "Racer", Color: "Preto"},
+		}
+		if promo {
+			p.HasPromo, p.Discount = true, 10
+		}
+		_, err := ps.Publish(p)
+		require.NoError(t, err)
+	}
+
+	resp, events, cancel := open("")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Eventually(t, func() bool { return ps.Hub().Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+	publish(false)
+	first := nextSSE(t, events)
+	require.Equal(t, service.FeedEventPost, first.event)
+	require.Contains(t, first.data, `"product_name":"Cadeira"`)
+	cancel()
+	resp.Body.Close()
+	require.Eventually(t, func() bool { return ps.Hub().Subscribers() == 0 }, time.Second, 10*time.Millisecond)
+
+	// publicado enquanto desconectado: volta no replay
+	publish(true)
+	resp, events, cancel = open(first.id)
+	defer cancel()
+	defer resp.Body.Close()
+	missed := nextSSE(t, events)
+	require.Equal(t, service.FeedEventPromo, missed.event)
+	require.NotEqual(t, first.id, missed.id)
+}
+
+func TestFeedStream_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewProductHandlers(&productServiceMock{})
+	r := gin.New()
+	r.GET("/stream", func(c *gin.Context) { c.Set("auth_user_id", 1); h.FeedStream(c) })
+	r.GET("/stream-noauth", h.FeedStream)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream-noauth", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream?last_event_id=abc", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// id desconhecido: o stream começa com reset (o contexto já cancelado encerra o handler)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream?last_event_id=42", nil).WithContext(ctx))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "event: reset")
+}
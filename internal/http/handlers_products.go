@@ -2,10 +2,7 @@ package http
 
 import (
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	"socialmeli/internal/domain"
@@ -17,17 +14,29 @@ import (
 type ProductService interface {
 	Publish(service.PublishPayload) (int, error)
 	FollowedLastTwoWeeks(userID int, order string) ([]domain.Post, error)
+	FollowedFiltered(userID int, order string, f domain.PostFilter) ([]domain.Post, domain.PostFacets, error)
 	PromoCount(userID int) (domain.User, int, error)
 	PromoList(userID int) (domain.User, []domain.Post, error)
+	PromoListFiltered(userID int, f domain.PostFilter) (domain.User, []domain.Post, domain.PostFacets, error)
 	DeleteMyPost(userID, postID int) error
+	SchedulePromo(userID, postID int, promo domain.PromoSchedule) (domain.Post, error)
+	EndPromo(userID, postID int) (domain.Post, error)
+	Search(q string, page, limit int) ([]domain.Post, int, error)
+	Feed(req service.FeedRequest) (service.FeedPage, error)
+	SubscribeFeed(userID int, lastEventID uint64) (*service.FeedSubscription, error)
+	SetLike(userID, postID int, liked bool) (domain.PostCounts, error)
+	SetFavorite(userID, postID int, favorite bool) (domain.PostCounts, error)
+	Favorites(userID, page, limit int) ([]domain.Post, int, error)
 }
 
 type ProductHandlers struct {
-	ps ProductService
+	ps         ProductService
+	uploads    *service.UploadService
+	currencies *service.CurrencyService
 }
 
 func NewProductHandlers(ps ProductService) *ProductHandlers {
-	return &ProductHandlers{ps: ps}
+	return &ProductHandlers{ps: ps, uploads: defaultUploadService()}
 }
 
 // Publish godoc
@@ -87,11 +96,19 @@ func (h *ProductHandlers) PromoPublish(c *gin.Context) {
 
 // FollowedLastTwoWeeks godoc
 // @Summary Lista produtos dos usuários seguidos
-// @Description Retorna produtos publicados nos últimos 14 dias pelos usuários seguidos
+// @Description Retorna produtos publicados nos últimos 14 dias pelos usuários seguidos, com filtros opcionais e facetas (marcas, categorias e faixas de preço dos resultados)
 // @Tags products
 // @Produce json
 // @Param userId path int true "ID do usuário"
 // @Param order query string false "Ordenação" Enums(date_asc,date_desc)
+// @Param category query string false "Categorias (separadas por vírgula)"
+// @Param brand query string false "Marcas (separadas por vírgula)"
+// @Param type query string false "Tipos (separados por vírgula)"
+// @Param color query string false "Cores (separadas por vírgula)"
+// @Param min_price query number false "Preço final mínimo"
+// @Param max_price query number false "Preço final máximo"
+// @Param promo query bool false "Somente promoções"
+// @Param currency query string false "Moeda ISO 4217 para exibir os preços (bloco converted)"
 // @Success 200 {object} FollowedPostsResponse
 // @Failure 400 {object} map[string]string "Parâmetro inválido"
 // @Router /products/followed/{userId}/list [get]
@@ -102,19 +119,106 @@ func (h *ProductHandlers) FollowedLastTwoWeeks(c *gin.Context) {
 		return
 	}
 
+	filter, err := domain.ParsePostFilter(c.Request.URL.Query())
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
 	order := c.Query("order")
-	posts, err2 := h.ps.FollowedLastTwoWeeks(userID, order)
+	posts, facets, err2 := h.ps.FollowedFiltered(userID, order, filter)
 	if err2 != nil {
 		badRequest(c, err2)
 		return
 	}
+	posts, ok := h.convertPosts(c, posts)
+	if !ok {
+		return
+	}
 
 	c.JSON(http.StatusOK, FollowedPostsResponse{
 		UserID: userID,
 		Posts:  posts,
+		Facets: &facets,
 	})
 }
 
+// Feed godoc
+// @Summary Feed de quem o usuário logado segue
+// @Description Janela configurável (days, padrão 14, ou since dd-MM-aaaa), paginação por cursor e modo ranked (recência, desconto e engajamento do vendedor). Aceita os mesmos filtros da listagem de seguidos.
+// @Tags products
+// @Produce json
+// @Param days query int false "Janela em dias (1 a 90)"
+// @Param since query string false "Data inicial (dd-MM-aaaa)"
+// @Param ranked query bool false "Ordena por relevância em vez de data"
+// @Param cursor query string false "next_cursor da página anterior"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Param currency query string false "Moeda ISO 4217 para exibir os preços (bloco converted)"
+// @Success 200 {object} service.FeedPage
+// @Failure 400 {object} map[string]string "Parâmetro inválido"
+// @Router /products/feed [get]
+func (h *ProductHandlers) Feed(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	_, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	req := service.FeedRequest{UserID: uidAny.(int), Limit: limit, Cursor: c.Query("cursor")}
+	if d := c.Query("days"); d != "" {
+		days, err := strconv.Atoi(d)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro inválido: days"})
+			return
+		}
+		if err := domain.ValidateFeedDays(days); err != nil {
+			badRequest(c, err)
+			return
+		}
+		req.Days = days
+	}
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		if req.Days != 0 {
+			badRequest(c, domain.ErrFeedSince)
+			return
+		}
+		since, err := time.Parse("02-01-2006", sinceStr)
+		if err != nil {
+			badRequest(c, service.ErrDateFormat)
+			return
+		}
+		req.Since = since
+	}
+	if r := c.Query("ranked"); r != "" {
+		ranked, err := strconv.ParseBool(r)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro inválido: ranked"})
+			return
+		}
+		req.Ranked = ranked
+	}
+	filter, err := domain.ParsePostFilter(c.Request.URL.Query())
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	req.Filter = filter
+
+	page, err := h.ps.Feed(req)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	if page.Posts, ok = h.convertPosts(c, page.Posts); !ok {
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
 // PromoCount godoc
 // @Summary Conta produtos em promoção
 // @Description Retorna a quantidade de produtos em promoção de um usuário
@@ -146,10 +250,17 @@ func (h *ProductHandlers) PromoCount(c *gin.Context) {
 
 // PromoList godoc
 // @Summary Lista produtos em promoção
-// @Description Retorna a lista de produtos em promoção de um usuário
+// @Description Retorna a lista de produtos em promoção de um usuário, com os mesmos filtros e facetas do feed
 // @Tags products
 // @Produce json
 // @Param user_id query int true "ID do usuário"
+// @Param category query string false "Categorias (separadas por vírgula)"
+// @Param brand query string false "Marcas (separadas por vírgula)"
+// @Param type query string false "Tipos (separados por vírgula)"
+// @Param color query string false "Cores (separadas por vírgula)"
+// @Param min_price query number false "Preço final mínimo"
+// @Param max_price query number false "Preço final máximo"
+// @Param currency query string false "Moeda ISO 4217 para exibir os preços (bloco converted)"
 // @Success 200 {object} PromoListResponse
 // @Failure 400 {object} map[string]string "Parâmetro inválido"
 // @Router /products/promo-pub/list [get]
@@ -164,30 +275,77 @@ func (h *ProductHandlers) PromoList(c *gin.Context) {
 		return
 	}
 
-	u, posts, err2 := h.ps.PromoList(userID)
+	filter, err := domain.ParsePostFilter(c.Request.URL.Query())
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	u, posts, facets, err2 := h.ps.PromoListFiltered(userID, filter)
 	if err2 != nil {
 		badRequest(c, err2)
 		return
 	}
 
 	postsPage, meta := paginateSlice(posts, page, limit)
+	postsPage, ok = h.convertPosts(c, postsPage)
+	if !ok {
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"user_id":   u.ID,
 		"user_name": u.Name,
 		"posts":     postsPage,
 		"meta":      meta,
+		"facets":    facets,
+	})
+}
+
+// Search godoc
+// @Summary Busca produtos
+// @Description Busca textual em nome, marca, tipo, cor e notas (sem diferenciar acentos/maiúsculas; termos casam por prefixo). Resultados por relevância.
+// @Tags products
+// @Produce json
+// @Param q query string true "Termos da busca"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Param currency query string false "Moeda ISO 4217 para exibir os preços (bloco converted)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Parâmetro inválido"
+// @Router /products/search [get]
+func (h *ProductHandlers) Search(c *gin.Context) {
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	q := c.Query("q")
+	posts, total, err := h.ps.Search(q, page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	if posts, ok = h.convertPosts(c, posts); !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query": q,
+		"posts": posts,
+		"meta":  PageMeta{Page: page, Limit: limit, Total: total, TotalPages: (total + limit - 1) / limit},
 	})
 }
 
 // UploadProductImage godoc
 // @Summary Upload de imagem do produto
-// @Description Faz upload (multipart) e devolve a URL para usar em product.image_url
+// @Description Faz upload (multipart), valida o conteúdo real (jpg, png, gif ou webp), remove metadados e gera os tamanhos thumb, medium e large (webp é guardado no tamanho original). image_url aponta para o large.
 // @Tags products
 // @Accept multipart/form-data
 // @Produce json
 // @Param image formData file true "Arquivo da imagem"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Cota de armazenamento excedida"
 // @Router /products/me/image [post]
 func (h *ProductHandlers) UploadProductImage(c *gin.Context) {
 	// precisa estar autenticado
@@ -199,44 +357,24 @@ func (h *ProductHandlers) UploadProductImage(c *gin.Context) {
 	uid := uidAny.(int)
 
 	file, err := c.FormFile("image")
-	// valida tamanho (max 4MB)
-	if file.Size > 4<<20 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "imagem muito grande (max 4MB)"})
-		return
-	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "arquivo 'image' ausente"})
 		return
 	}
-
-	uploadDir := "uploads/products"
-	if err := os.MkdirAll(uploadDir, 0o755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao criar pasta"})
-		return
-	}
-
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext == "" {
-		ext = ".jpg"
-	}
-	switch ext {
-	case ".jpg", ".jpeg", ".png", ".webp":
-		// ok
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "formato inválido (use jpg, png ou webp)"})
+	// valida tamanho (max 4MB)
+	if file.Size > 4<<20 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "imagem muito grande (max 4MB)"})
 		return
 	}
-	// nome unico: <userId>-<timestamp>.<ext>
-	name := strconv.Itoa(uid) + "-" + strconv.FormatInt(time.Now().UnixNano(), 10) + ext
-	localPath := filepath.Join(uploadDir, name)
 
-	if err := c.SaveUploadedFile(file, localPath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao salvar"})
+	// arquivos endereçados pelo SHA-256: reenviar a mesma imagem reaproveita os arquivos
+	images, err := saveImage(h.uploads, uid, file, 4<<20)
+	if err != nil {
+		imageError(c, err)
 		return
 	}
 
-	url := "/static/products/" + name
-	c.JSON(http.StatusOK, gin.H{"image_url": url})
+	c.JSON(http.StatusOK, gin.H{"image_url": images.Large, "images": images})
 }
 
 // DeleteMyPost godoc
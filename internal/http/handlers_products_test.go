@@ -20,6 +20,82 @@ type productServiceMock struct {
 	PromoCountFn           func(userID int) (domain.User, int, error)
 	PromoListFn            func(userID int) (domain.User, []domain.Post, error)
 	DeleteMyPostFn         func(userID, postID int) error
+	SearchFn               func(q string, page, limit int) ([]domain.Post, int, error)
+	FollowedFilteredFn     func(userID int, order string, f domain.PostFilter) ([]domain.Post, domain.PostFacets, error)
+	PromoListFilteredFn    func(userID int, f domain.PostFilter) (domain.User, []domain.Post, domain.PostFacets, error)
+	FeedFn                 func(req service.FeedRequest) (service.FeedPage, error)
+	SubscribeFeedFn        func(userID int, lastEventID uint64) (*service.FeedSubscription, error)
+	SetLikeFn              func(userID, postID int, liked bool) (domain.PostCounts, error)
+	SetFavoriteFn          func(userID, postID int, favorite bool) (domain.PostCounts, error)
+	FavoritesFn            func(userID, page, limit int) ([]domain.Post, int, error)
+	SchedulePromoFn        func(userID, postID int, promo domain.PromoSchedule) (domain.Post, error)
+	EndPromoFn             func(userID, postID int) (domain.Post, error)
+}
+
+func (m *productServiceMock) SchedulePromo(userID, postID int, promo domain.PromoSchedule) (domain.Post, error) {
+	if m.SchedulePromoFn == nil {
+		return domain.Post{}, nil
+	}
+	return m.SchedulePromoFn(userID, postID, promo)
+}
+
+func (m *productServiceMock) EndPromo(userID, postID int) (domain.Post, error) {
+	if m.EndPromoFn == nil {
+		return domain.Post{}, nil
+	}
+	return m.EndPromoFn(userID, postID)
+}
+
+func (m *productServiceMock) SetLike(userID, postID int, liked bool) (domain.PostCounts, error) {
+	if m.SetLikeFn == nil {
+		return domain.PostCounts{}, nil
+	}
+	return m.SetLikeFn(userID, postID, liked)
+}
+
+func (m *productServiceMock) SetFavorite(userID, postID int, favorite bool) (domain.PostCounts, error) {
+	if m.SetFavoriteFn == nil {
+		return domain.PostCounts{}, nil
+	}
+	return m.SetFavoriteFn(userID, postID, favorite)
+}
+
+func (m *productServiceMock) Favorites(userID, page, limit int) ([]domain.Post, int, error) {
+	if m.FavoritesFn == nil {
+		return nil, 0, nil
+	}
+	return m.FavoritesFn(userID, page, limit)
+}
+
+func (m *productServiceMock) SubscribeFeed(userID int, lastEventID uint64) (*service.FeedSubscription, error) {
+	if m.SubscribeFeedFn == nil {
+		return service.NewFeedHub(0, 0).Subscribe(nil, lastEventID), nil
+	}
+	return m.SubscribeFeedFn(userID, lastEventID)
+}
+
+func (m *productServiceMock) Feed(req service.FeedRequest) (service.FeedPage, error) {
+	if m.FeedFn == nil {
+		return service.FeedPage{}, nil
+	}
+	return m.FeedFn(req)
+}
+
+// sem Fn próprio, as variantes filtradas delegam para os mocks sem filtro
+func (m *productServiceMock) FollowedFiltered(userID int, order string, f domain.PostFilter) ([]domain.Post, domain.PostFacets, error) {
+	if m.FollowedFilteredFn == nil {
+		posts, err := m.FollowedLastTwoWeeks(userID, order)
+		return posts, domain.PostFacets{}, err
+	}
+	return m.FollowedFilteredFn(userID, order, f)
+}
+
+func (m *productServiceMock) PromoListFiltered(userID int, f domain.PostFilter) (domain.User, []domain.Post, domain.PostFacets, error) {
+	if m.PromoListFilteredFn == nil {
+		u, posts, err := m.PromoList(userID)
+		return u, posts, domain.PostFacets{}, err
+	}
+	return m.PromoListFilteredFn(userID, f)
 }
 
 func (m *productServiceMock) Publish(p service.PublishPayload) (int, error) {
@@ -57,6 +133,13 @@ func (m *productServiceMock) DeleteMyPost(userID, postID int) error {
 	return m.DeleteMyPostFn(userID, postID)
 }
 
+func (m *productServiceMock) Search(q string, page, limit int) ([]domain.Post, int, error) {
+	if m.SearchFn == nil {
+		return nil, 0, nil
+	}
+	return m.SearchFn(q, page, limit)
+}
+
 func TestNewProductHandlers(t *testing.T) {
 	ps := &productServiceMock{}
 	h := NewProductHandlers(ps)
@@ -436,3 +519,128 @@ func TestProductHandlers_DeleteMyPost_InvalidPostID(t *testing.T) {
 		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
 	}
 }
+
+func TestProductHandlers_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotQ string
+	var gotPage, gotLimit int
+	h := NewProductHandlers(&productServiceMock{
+		SearchFn: func(q string, page, limit int) ([]domain.Post, int, error) {
+			gotQ, gotPage, gotLimit = q, page, limit
+			if q == "" {
+				return nil, 0, domain.ErrSearchQueryEmpty
+			}
+			return []domain.Post{{PostID: 3}}, 5, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/products/search?q=t%C3%AAnis&page=2&limit=2", nil)
+	h.Search(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if gotQ != "tênis" || gotPage != 2 || gotLimit != 2 {
+		t.Fatalf("args = %q %d %d", gotQ, gotPage, gotLimit)
+	}
+	want := `"meta":{"page":2,"limit":2,"total":5,"total_pages":3}`
+	if !bytes.Contains(w.Body.Bytes(), []byte(want)) {
+		t.Fatalf("body = %s, want to contain %s", w.Body.String(), want)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/products/search", nil)
+	h.Search(c)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestProductHandlers_FollowedLastTwoWeeks_Filter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var got domain.PostFilter
+	h := NewProductHandlers(&productServiceMock{
+		FollowedFilteredFn: func(userID int, order string, f domain.PostFilter) ([]domain.Post, domain.PostFacets, error) {
+			got = f
+			return []domain.Post{}, domain.PostFacets{Brands: []domain.FacetCount{{Value: "Nike", Count: 1}}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/products/followed/10/list?brand=Nike,Adidas&category=2&min_price=10&promo=true", nil)
+	c.Params = gin.Params{{Key: "userId", Value: "10"}}
+	h.FollowedLastTwoWeeks(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(got.Brands) != 2 || len(got.Categories) != 1 || got.MinPrice == nil || *got.MinPrice != 10_00 || !got.PromoOnly {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"facets":{"brands":[{"value":"Nike","count":1}]`)) {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+
+	// filtro inválido
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/products/followed/10/list?min_price=50&max_price=10", nil)
+	c.Params = gin.Params{{Key: "userId", Value: "10"}}
+	h.FollowedLastTwoWeeks(c)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestProductHandlers_Feed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var got service.FeedRequest
+	h := NewProductHandlers(&productServiceMock{
+		FeedFn: func(req service.FeedRequest) (service.FeedPage, error) {
+			got = req
+			return service.FeedPage{Mode: service.FeedModeRanked, Posts: []domain.Post{{PostID: 1}}, NextCursor: "abc"}, nil
+		},
+	})
+	r := gin.New()
+	r.GET("/feed", func(c *gin.Context) { c.Set("auth_user_id", 7); h.Feed(c) })
+	r.GET("/feed-noauth", h.Feed)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/feed?days=30&ranked=true&limit=5&cursor=xyz&brand=Nike", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got.UserID != 7 || got.Days != 30 || !got.Ranked || got.Limit != 5 || got.Cursor != "xyz" || len(got.Filter.Brands) != 1 {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"next_cursor":"abc"`)) {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/feed?since=01-02-2026", nil))
+	if w.Code != http.StatusOK || got.Since.Format("02-01-2006") != "01-02-2026" {
+		t.Fatalf("status = %d, since = %v", w.Code, got.Since)
+	}
+
+	for _, q := range []string{"days=0", "days=abc", "days=5&since=01-01-2026", "since=2026-01-01", "ranked=talvez"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/feed?"+q, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want %d", q, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/feed-noauth", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"strconv"
 
 	"socialmeli/internal/domain"
 	"socialmeli/internal/service"
@@ -12,6 +13,8 @@ import (
 type usersCatalogService interface {
 	ListUsers(order string) ([]domain.User, error)
 	CreateUser(name string, isSeller bool) (domain.User, error)
+	SearchUsers(q string, sellersOnly bool, page, limit int) ([]domain.UserSummary, int, error)
+	Typeahead(q string, sellersOnly bool, limit int) ([]domain.UserSummary, error)
 }
 
 type UsersCatalogHandlers struct{ us usersCatalogService }
@@ -53,3 +56,88 @@ func (h *UsersCatalogHandlers) Create(c *gin.Context) {
 	}
 	c.JSON(http.StatusCreated, u)
 }
+
+// sellerParam lê ?seller=true (restringe a vendedores).
+func sellerParam(c *gin.Context) (bool, bool) {
+	v := c.Query("seller")
+	if v == "" {
+		return false, true
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro inválido: seller"})
+		return false, false
+	}
+	return b, true
+}
+
+// Search godoc
+// @Summary Busca usuários pelo nome
+// @Description Cada termo casa com o início de alguma palavra do nome, sem diferenciar acentos e maiúsculas. Nomes que começam pelo termo vêm primeiro.
+// @Tags users
+// @Produce json
+// @Param q query string true "Nome ou parte dele"
+// @Param seller query bool false "Somente vendedores"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/search [get]
+func (h *UsersCatalogHandlers) Search(c *gin.Context) {
+	sellers, ok := sellerParam(c)
+	if !ok {
+		return
+	}
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+	users, total, err := h.us.SearchUsers(c.Query("q"), sellers, page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"users": users,
+		"meta":  PageMeta{Page: page, Limit: limit, Total: total, TotalPages: (total + limit - 1) / limit},
+	})
+}
+
+type typeaheadItem struct {
+	UserID    int    `json:"user_id"`
+	UserName  string `json:"user_name"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// Typeahead godoc
+// @Summary Sugestões de usuários enquanto digita
+// @Description Resposta enxuta (id, nome e avatar) para autocompletar.
+// @Tags users
+// @Produce json
+// @Param q query string true "Texto digitado"
+// @Param seller query bool false "Somente vendedores"
+// @Param limit query int false "Máximo de sugestões (padrão 8, máx 20)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/typeahead [get]
+func (h *UsersCatalogHandlers) Typeahead(c *gin.Context) {
+	sellers, ok := sellerParam(c)
+	if !ok {
+		return
+	}
+	_, limit, ok := parsePageLimit(c, 8, 20)
+	if !ok {
+		return
+	}
+	users, err := h.us.Typeahead(c.Query("q"), sellers, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	out := make([]typeaheadItem, 0, len(users))
+	for _, u := range users {
+		out = append(out, typeaheadItem{UserID: u.ID, UserName: u.Name, AvatarURL: u.AvatarURL})
+	}
+	c.Header("Cache-Control", "private, max-age=30")
+	c.JSON(http.StatusOK, gin.H{"suggestions": out})
+}
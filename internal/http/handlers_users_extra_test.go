@@ -119,3 +119,50 @@ func TestUsersCatalogHandlers_Create_InvalidName(t *testing.T) {
 
 	require.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestUsersCatalogHandlers_SearchAndTypeahead(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{
+		{ID: 1, Name: "Maria José", IsSeller: false},
+		{ID: 2, Name: "João Silva", IsSeller: true},
+		{ID: 3, Name: "Joana", IsSeller: true},
+	})
+	acc, err := st.CreateAccount("Josias Loja", "j@example.com", "hash", true)
+	require.NoError(t, err)
+	_, err = st.UpdateAvatar(acc.ID, "/static/images/a_large.png")
+	require.NoError(t, err)
+
+	h := NewUsersCatalogHandlers(service.NewUserService(st))
+	r := gin.New()
+	r.GET("/users/search", h.Search)
+	r.GET("/users/typeahead", h.Typeahead)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/search?q=jo&limit=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Users []domain.UserSummary `json:"users"`
+		Meta  PageMeta             `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 4, resp.Meta.Total)
+	// começa com "jo" antes de "Maria José"; ordem alfabética entre eles
+	require.Equal(t, "Joana", resp.Users[0].Name)
+	require.Equal(t, "João Silva", resp.Users[1].Name)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/typeahead?q=JOS&seller=true", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"suggestions":[{"user_id":4,"user_name":"Josias Loja","avatar_url":"/static/images/a_large.png"}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/search?q=", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/typeahead?q=jo&seller=talvez", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
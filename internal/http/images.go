@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/media"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBlobStore mantém o comportamento original (disco em ./uploads, servido em /static/)
+// para handlers criados fora do NewRouter.
+func defaultBlobStore() store.BlobStore {
+	return store.NewLocalBlobStore("uploads", "/static/")
+}
+
+func defaultUploadService() *service.UploadService {
+	return service.NewUploadService(nil, defaultBlobStore())
+}
+
+// saveImage abre o arquivo do multipart e delega o processamento ao UploadService.
+func saveImage(ups *service.UploadService, ownerID int, file *multipart.FileHeader, maxBytes int64) (domain.ImageSet, error) {
+	f, err := file.Open()
+	if err != nil {
+		return domain.ImageSet{}, err
+	}
+	defer f.Close()
+	return ups.SaveImage(ownerID, f, maxBytes)
+}
+
+// imageError traduz erros do pipeline de imagem em 400 (conteúdo), 403 (cota),
+// 503 (imagem idêntica em coleta) ou 500 (armazenamento).
+func imageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrQuotaExceeded):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUploadBusy):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case isMediaError(err):
+		badRequest(c, err)
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao salvar"})
+	}
+}
+
+// isMediaError indica problema no conteúdo enviado (reenviar o mesmo arquivo não adianta).
+func isMediaError(err error) bool {
+	return errors.Is(err, media.ErrNotImage) || errors.Is(err, media.ErrUnsupported) ||
+		errors.Is(err, media.ErrTooLarge) || errors.Is(err, media.ErrDimensionLimit)
+}
+
+// serveBlob entrega /static/*key a partir do blob store (local ou memória).
+func serveBlob(blobs store.BlobStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimPrefix(c.Param("key"), "/")
+		data, ct, err := blobs.Get(key)
+		if err != nil {
+			if errors.Is(err, store.ErrBlobNotFound) || errors.Is(err, store.ErrInvalidBlobKey) {
+				c.Status(http.StatusNotFound)
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Data(http.StatusOK, ct, data)
+	}
+}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func multipartImage(t *testing.T, field, filename string, data []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile(field, filename)
+	require.NoError(t, err)
+	_, err = fw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	return &buf, mw.FormDataContentType()
+}
+
+func TestProductHandlers_UploadProductImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tmp := t.TempDir()
+	oldWd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmp))
+	t.Cleanup(func() { _ = os.Chdir(oldWd) })
+
+	h := NewProductHandlers(&productServiceMock{})
+	r := gin.New()
+	r.POST("/img", func(c *gin.Context) { c.Set("auth_user_id", 7); h.UploadProductImage(c) })
+	r.POST("/img-noauth", h.UploadProductImage)
+
+	// sem auth
+	{
+		body, ct := multipartImage(t, "image", "a.png", testPNG(t, 10, 10))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/img-noauth", body)
+		req.Header.Set("Content-Type", ct)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	// sem arquivo
+	{
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/img", nil)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	// "jpg" que na verdade é texto
+	{
+		body, ct := multipartImage(t, "image", "fake.jpg", []byte("#!/bin/sh\necho hi"))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/img", body)
+		req.Header.Set("Content-Type", ct)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	// sucesso
+	{
+		body, ct := multipartImage(t, "image", "produto.webp", testPNG(t, 900, 300))
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/img", body)
+		req.Header.Set("Content-Type", ct)
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			ImageURL string            `json:"image_url"`
+			Images   map[string]string `json:"images"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, resp.Images["large"], resp.ImageURL)
+		for _, size := range []string{"thumb", "medium", "large"} {
+			u := resp.Images[size]
+			require.True(t, strings.HasPrefix(u, "/static/images/"), u)
+			require.True(t, strings.HasSuffix(u, "_"+size+".png"), u)
+			_, err := os.Stat(filepath.Join("uploads", "images", strings.TrimPrefix(u, "/static/images/")))
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestRouter_UploadsGoToConfiguredBlobStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	acc, err := st.CreateAccount("User", "user@example.com", "hash", true)
+	require.NoError(t, err)
+	blobs := store.NewMemoryBlobStore("/static/")
+
+	r := NewRouter(service.NewUserService(st), service.NewProductService(st), service.NewAuthService(st), WithBlobStore(blobs))
+	token, err := MakeToken(acc.ID, time.Hour)
+	require.NoError(t, err)
+
+	body, ct := multipartImage(t, "image", "p.png", testPNG(t, 20, 20))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/products/me/image", body)
+	req.Header.Set("Content-Type", ct)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, blobs.Keys(), 3)
+
+	var resp struct {
+		Images map[string]string `json:"images"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	// servido pelo router a partir do blob store
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, resp.Images["thumb"], nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "image/png", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/static/products/nope.png", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouter_StorageUsageAndQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	acc, err := st.CreateAccount("User", "user@example.com", "hash", true)
+	require.NoError(t, err)
+	ups := service.NewUploadService(st, store.NewMemoryBlobStore("/static/"))
+	ups.SetQuota(service.Quota{MaxCount: 1, MaxBytes: 1 << 20})
+
+	r := NewRouter(service.NewUserService(st), service.NewProductService(st), service.NewAuthService(st), WithUploadService(ups))
+	token, err := MakeToken(acc.ID, time.Hour)
+	require.NoError(t, err)
+
+	upload := func(img []byte) *httptest.ResponseRecorder {
+		body, ct := multipartImage(t, "image", "p.png", img)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/products/me/image", body)
+		req.Header.Set("Content-Type", ct)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, upload(testPNG(t, 20, 20)).Code)
+	// mesmo conteúdo: deduplicado, não consome cota
+	require.Equal(t, http.StatusOK, upload(testPNG(t, 20, 20)).Code)
+
+	w := upload(testPNG(t, 30, 30))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "Cota de armazenamento excedida")
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/me/storage", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var usage domain.StorageUsage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	require.Equal(t, 1, usage.Count)
+	require.Equal(t, 1, usage.MaxCount)
+	require.Positive(t, usage.Bytes)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"math"
+	"net/http"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandlers struct {
+	ns *service.NotificationService
+}
+
+func NewNotificationHandlers(ns *service.NotificationService) *NotificationHandlers {
+	return &NotificationHandlers{ns: ns}
+}
+
+// authedNotifications devolve o usuário logado; sem serviço configurado responde 503.
+func (h *NotificationHandlers) authedNotifications(c *gin.Context) (int, bool) {
+	if h.ns == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "notificações indisponíveis"})
+		return 0, false
+	}
+	uid, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return 0, false
+	}
+	return uid.(int), true
+}
+
+// List godoc
+// @Summary Caixa de notificações do usuário logado
+// @Description Mais recentes primeiro. unread=true traz só as não lidas.
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Só não lidas"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /notifications [get]
+func (h *NotificationHandlers) List(c *gin.Context) {
+	uid, ok := h.authedNotifications(c)
+	if !ok {
+		return
+	}
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+	unreadOnly := false
+	switch c.Query("unread") {
+	case "", "false":
+	case "true":
+		unreadOnly = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro inválido: unread"})
+		return
+	}
+	items, total, unread, err := h.ns.List(uid, unreadOnly, page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": items,
+		"unread_count":  unread,
+		"meta": PageMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// UnreadCount godoc
+// @Summary Quantidade de notificações não lidas
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandlers) UnreadCount(c *gin.Context) {
+	uid, ok := h.authedNotifications(c)
+	if !ok {
+		return
+	}
+	n, err := h.ns.UnreadCount(uid)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread_count": n})
+}
+
+type markReadRequest struct {
+	IDs []int `json:"ids"`
+	All bool  `json:"all"`
+}
+
+// MarkRead godoc
+// @Summary Marca notificações como lidas
+// @Description Envie {"ids":[1,2]} ou {"all":true}.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Router /notifications/read [post]
+func (h *NotificationHandlers) MarkRead(c *gin.Context) {
+	uid, ok := h.authedNotifications(c)
+	if !ok {
+		return
+	}
+	var req markReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	updated, err := h.ns.MarkRead(uid, req.IDs, req.All)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	unread, err := h.ns.UnreadCount(uid)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": updated, "unread_count": unread})
+}
+
+// Preferences godoc
+// @Summary Preferências de notificação por tipo
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Router /notifications/preferences [get]
+func (h *NotificationHandlers) Preferences(c *gin.Context) {
+	uid, ok := h.authedNotifications(c)
+	if !ok {
+		return
+	}
+	prefs, err := h.ns.Preferences(uid)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences godoc
+// @Summary Liga/desliga tipos de notificação
+// @Description Só os tipos enviados mudam, ex.: {"promo_started":false}.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Router /notifications/preferences [put]
+func (h *NotificationHandlers) UpdatePreferences(c *gin.Context) {
+	uid, ok := h.authedNotifications(c)
+	if !ok {
+		return
+	}
+	var req domain.NotificationPrefs
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	prefs, err := h.ns.UpdatePreferences(uid, req)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	seller, err := st.CreateAccount("Seller", "seller@example.com", "hash", true)
+	require.NoError(t, err)
+	for i, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		buyer, err := st.CreateAccount("Buyer"+string(rune('A'+i)), email, "hash", false)
+		require.NoError(t, err)
+		require.NoError(t, st.Follow(buyer.ID, seller.ID))
+	}
+	ns := service.NewNotificationService(st)
+	for _, f := range []int{2, 3, 4} {
+		require.NoError(t, ns.FollowerAdded(f, seller.ID))
+	}
+
+	h := NewNotificationHandlers(ns)
+	r := gin.New()
+	auth := func(fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", seller.ID); fn(c) }
+	}
+	r.GET("/notifications", auth(h.List))
+	r.GET("/notifications/unread-count", auth(h.UnreadCount))
+	r.POST("/notifications/read", auth(h.MarkRead))
+	r.GET("/notifications/preferences", auth(h.Preferences))
+	r.PUT("/notifications/preferences", auth(h.UpdatePreferences))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do(http.MethodGet, "/notifications?limit=2", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Notifications []domain.Notification `json:"notifications"`
+		UnreadCount   int                   `json:"unread_count"`
+		Meta          PageMeta              `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Notifications, 2)
+	require.Equal(t, 3, list.UnreadCount)
+	require.Equal(t, PageMeta{Page: 1, Limit: 2, Total: 3, TotalPages: 2}, list.Meta)
+	require.Equal(t, 4, list.Notifications[0].ActorID)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/notifications?unread=talvez", "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/notifications/read", `{}`).Code)
+
+	w = do(http.MethodPost, "/notifications/read", `{"ids":[`+strconv.Itoa(list.Notifications[0].ID)+`]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"updated":1,"unread_count":2}`, w.Body.String())
+
+	w = do(http.MethodGet, "/notifications?unread=true", "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Notifications, 2)
+
+	w = do(http.MethodPost, "/notifications/read", `{"all":true}`)
+	require.JSONEq(t, `{"updated":2,"unread_count":0}`, w.Body.String())
+	require.JSONEq(t, `{"unread_count":0}`, do(http.MethodGet, "/notifications/unread-count", "").Body.String())
+
+	w = do(http.MethodPut, "/notifications/preferences", `{"new_follower":false}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"new_follower":false,"promo_started":true,"weekly_digest":true,"price_drop":true,"post_liked":true,"post_comment":true}`, w.Body.String())
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/notifications/preferences", `{"nope":true}`).Code)
+	require.JSONEq(t, `{"new_follower":false,"promo_started":true,"weekly_digest":true,"price_drop":true,"post_liked":true,"post_comment":true}`, do(http.MethodGet, "/notifications/preferences", "").Body.String())
+
+	// desligado: novos seguidores não geram notificação
+	require.NoError(t, ns.FollowerAdded(2, seller.ID))
+	require.JSONEq(t, `{"unread_count":0}`, do(http.MethodGet, "/notifications/unread-count", "").Body.String())
+}
+
+func TestNotificationHandlers_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewNotificationHandlers(nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/notifications", nil)
+	h.List(c)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
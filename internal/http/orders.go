@@ -0,0 +1,386 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/payment"
+	"socialmeli/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderHandlers struct {
+	orders *service.OrderService
+}
+
+func NewOrderHandlers(orders *service.OrderService) *OrderHandlers {
+	return &OrderHandlers{orders: orders}
+}
+
+// authedOrders devolve o usuário logado; sem serviço configurado responde 503.
+func (h *OrderHandlers) authedOrders(c *gin.Context) (int, bool) {
+	if h.orders == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "pedidos indisponíveis"})
+		return 0, false
+	}
+	uid, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return 0, false
+	}
+	return uid.(int), true
+}
+
+// Cart godoc
+// @Summary Carrinho do usuário logado
+// @Description Preços atuais (final_price) de cada post. Com coupon, mostra o desconto que o cupom daria.
+// @Tags cart
+// @Produce json
+// @Param coupon query string false "Código do cupom"
+// @Success 200 {object} domain.Cart
+// @Failure 400 {object} map[string]string
+// @Router /cart [get]
+func (h *OrderHandlers) Cart(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	cart, err := h.orders.Cart(uid, c.Query("coupon"))
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+type cartItemRequest struct {
+	PostID   int `json:"post_id"`
+	Quantity int `json:"quantity"`
+}
+
+// AddToCart godoc
+// @Summary Adiciona um post ao carrinho
+// @Description Soma a quantidade (padrão 1) ao que já houver do post.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.Cart
+// @Failure 400 {object} map[string]string
+// @Router /cart/items [post]
+func (h *OrderHandlers) AddToCart(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	var req cartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+	cart, err := h.orders.AddToCart(uid, req.PostID, req.Quantity)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// SetCartItem godoc
+// @Summary Troca a quantidade de um post no carrinho
+// @Description quantity 0 tira o post do carrinho.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 200 {object} domain.Cart
+// @Failure 400 {object} map[string]string
+// @Router /cart/items/{postId} [put]
+func (h *OrderHandlers) SetCartItem(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	var req cartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	cart, err := h.orders.SetCartQuantity(uid, postID, req.Quantity)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// RemoveFromCart godoc
+// @Summary Tira um post do carrinho
+// @Tags cart
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 200 {object} domain.Cart
+// @Failure 400 {object} map[string]string
+// @Router /cart/items/{postId} [delete]
+func (h *OrderHandlers) RemoveFromCart(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	cart, err := h.orders.SetCartQuantity(uid, postID, 0)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// Checkout godoc
+// @Summary Fecha o carrinho
+// @Description Cria um pedido pendente por vendedor, com cópia dos produtos e preços, e esvazia o carrinho.
+// @Description O corpo é opcional: {"coupon": "CODIGO"} aplica o cupom ao pedido do vendedor dele.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string "o carrinho mudou durante o checkout"
+// @Router /cart/checkout [post]
+func (h *OrderHandlers) Checkout(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	var req checkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		badRequest(c, err)
+		return
+	}
+	orders, err := h.orders.Checkout(uid, req.Coupon)
+	if errors.Is(err, domain.ErrCartChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"orders": orders})
+}
+
+type checkoutRequest struct {
+	Coupon string `json:"coupon"`
+}
+
+// list responde uma página de pedidos (compras ou vendas).
+func (h *OrderHandlers) list(c *gin.Context, fetch func(userID int, status string, page, limit int) ([]domain.Order, int, error)) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+	items, total, err := fetch(uid, c.Query("status"), page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"orders": items,
+		"meta": PageMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// Purchases godoc
+// @Summary Pedidos do usuário logado como comprador
+// @Tags orders
+// @Produce json
+// @Param status query string false "pending, paid, shipped, delivered ou cancelled"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/me/orders [get]
+func (h *OrderHandlers) Purchases(c *gin.Context) {
+	h.list(c, h.orders.Purchases)
+}
+
+// Sales godoc
+// @Summary Pedidos recebidos pelo vendedor logado
+// @Tags orders
+// @Produce json
+// @Param status query string false "pending, paid, shipped, delivered ou cancelled"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/me/sales [get]
+func (h *OrderHandlers) Sales(c *gin.Context) {
+	h.list(c, h.orders.Sales)
+}
+
+// Get godoc
+// @Summary Detalhe de um pedido (comprador ou vendedor)
+// @Tags orders
+// @Produce json
+// @Param orderId path int true "Pedido"
+// @Success 200 {object} domain.Order
+// @Failure 404 {object} map[string]string
+// @Router /orders/{orderId} [get]
+func (h *OrderHandlers) Get(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "orderId")
+	if !ok {
+		return
+	}
+	o, err := h.orders.Get(uid, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
+
+type orderStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// UpdateStatus godoc
+// @Summary Muda o status de um pedido
+// @Description pending → paid (comprador) → shipped (vendedor) → delivered; cancelled antes do envio.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param orderId path int true "Pedido"
+// @Success 200 {object} domain.Order
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orders/{orderId} [patch]
+func (h *OrderHandlers) UpdateStatus(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "orderId")
+	if !ok {
+		return
+	}
+	var req orderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	o, err := h.orders.Transition(uid, id, req.Status)
+	if errors.Is(err, domain.ErrOrderTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
+
+type payOrderRequest struct {
+	Token string `json:"token"`
+}
+
+// Pay godoc
+// @Summary Paga um pedido pendente
+// @Description token é o meio de pagamento tokenizado. 200 com o pedido pago; 202 quando o provedor confirma depois (callback); 402 se recusado.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param orderId path int true "Pedido"
+// @Success 200 {object} domain.Order
+// @Success 202 {object} domain.Order
+// @Failure 402 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orders/{orderId}/pay [post]
+func (h *OrderHandlers) Pay(c *gin.Context) {
+	uid, ok := h.authedOrders(c)
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "orderId")
+	if !ok {
+		return
+	}
+	var req payOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	o, err := h.orders.Pay(uid, id, req.Token)
+	switch {
+	case errors.Is(err, payment.ErrDeclined):
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrOrderTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPaymentsUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case err != nil:
+		badRequest(c, err)
+	case o.Status == domain.OrderPending:
+		c.JSON(http.StatusAccepted, o)
+	default:
+		c.JSON(http.StatusOK, o)
+	}
+}
+
+// PaymentCallback godoc
+// @Summary Callback do provedor de pagamentos
+// @Description Assinado no header X-Payment-Signature. Reentregas do mesmo evento são ignoradas.
+// @Tags orders
+// @Accept json
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Router /payments/callback [post]
+func (h *OrderHandlers) PaymentCallback(c *gin.Context) {
+	if h.orders == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "pedidos indisponíveis"})
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	err = h.orders.HandlePaymentCallback(body, c.GetHeader(payment.SignatureHeader))
+	switch {
+	case errors.Is(err, payment.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPaymentsUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case err != nil:
+		// erro nosso: 500 faz o provedor tentar de novo
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
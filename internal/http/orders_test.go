@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/payment"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Buyer"}, {ID: 2, Name: "Seller", IsSeller: true}})
+	postID, err := st.AddPost(domain.Post{UserID: 2, Price: 100_00, FinalPrice: 80_00, HasPromo: true, Discount: 20, Product: domain.Product{ProductName: "Fone"}})
+	require.NoError(t, err)
+
+	h := NewOrderHandlers(service.NewOrderService(st))
+	r := gin.New()
+	auth := func(uid int, fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", uid); fn(c) }
+	}
+	r.GET("/cart", auth(1, h.Cart))
+	r.POST("/cart/items", auth(1, h.AddToCart))
+	r.PUT("/cart/items/:postId", auth(1, h.SetCartItem))
+	r.DELETE("/cart/items/:postId", auth(1, h.RemoveFromCart))
+	r.POST("/cart/checkout", auth(1, h.Checkout))
+	r.GET("/users/me/orders", auth(1, h.Purchases))
+	r.GET("/users/me/sales", auth(2, h.Sales))
+	r.PATCH("/orders/:orderId", auth(1, h.UpdateStatus))
+	r.GET("/orders/:orderId", auth(2, h.Get))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/cart/checkout", "").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/cart/items", `{"post_id":`+strconv.Itoa(postID)+`}`).Code)
+	w := do(http.MethodPut, "/cart/items/"+strconv.Itoa(postID), `{"quantity":3}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var cart domain.Cart
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cart))
+	require.Equal(t, domain.Money(240_00), cart.Total)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/cart/items/"+strconv.Itoa(postID), `{"quantity":100}`).Code)
+
+	w = do(http.MethodPost, "/cart/checkout", "")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var placed struct {
+		Orders []domain.Order `json:"orders"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &placed))
+	require.Len(t, placed.Orders, 1)
+	orderPath := "/orders/" + strconv.Itoa(placed.Orders[0].ID)
+
+	require.Equal(t, http.StatusConflict, do(http.MethodPatch, orderPath, `{"status":"delivered"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPatch, orderPath, `{"status":"lost"}`).Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, orderPath, `{"status":"paid"}`).Code)
+
+	w = do(http.MethodGet, "/users/me/sales?status=paid", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Orders []domain.Order `json:"orders"`
+		Meta   PageMeta       `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Orders, 1)
+	require.Equal(t, "Fone", page.Orders[0].Items[0].Product.ProductName)
+
+	require.Equal(t, http.StatusOK, do(http.MethodGet, orderPath, "").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/orders/999", "").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, "/cart/items/"+strconv.Itoa(postID), "").Code)
+}
+
+func TestOrderHandlers_PaymentFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Buyer"}, {ID: 2, Name: "Seller", IsSeller: true}})
+	postID, err := st.AddPost(domain.Post{UserID: 2, Price: 50_00, FinalPrice: 50_00})
+	require.NoError(t, err)
+
+	gw := payment.NewFake("secret")
+	svc := service.NewOrderService(st)
+	svc.SetPaymentGateway(gw)
+	h := NewOrderHandlers(svc)
+	r := gin.New()
+	auth := func(uid int, fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", uid); fn(c) }
+	}
+	r.POST("/cart/items", auth(1, h.AddToCart))
+	r.POST("/cart/checkout", auth(1, h.Checkout))
+	r.POST("/orders/:orderId/pay", auth(1, h.Pay))
+	r.GET("/orders/:orderId", auth(1, h.Get))
+	r.POST("/payments/callback", h.PaymentCallback)
+
+	do := func(method, path, body string, header ...string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+	placeOrder := func() string {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, "/cart/items", `{"post_id":`+strconv.Itoa(postID)+`}`).Code)
+		w := do(http.MethodPost, "/cart/checkout", "")
+		require.Equal(t, http.StatusCreated, w.Code)
+		var placed struct {
+			Orders []domain.Order `json:"orders"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &placed))
+		return "/orders/" + strconv.Itoa(placed.Orders[0].ID)
+	}
+
+	path := placeOrder()
+	require.Equal(t, http.StatusPaymentRequired, do(http.MethodPost, path+"/pay", `{"token":"tok_decline"}`).Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPost, path+"/pay", `{"token":"tok_approve"}`).Code)
+
+	// confirmação atrasada chega pelo callback
+	path = placeOrder()
+	w := do(http.MethodPost, path+"/pay", `{"token":"tok_delayed"}`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var pending domain.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+
+	body, sig, err := gw.Confirm(pending.PaymentID, true)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/payments/callback", string(body), payment.SignatureHeader, "sha256=00").Code)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/payments/callback", string(body), payment.SignatureHeader, sig).Code)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/payments/callback", string(body), payment.SignatureHeader, sig).Code)
+
+	w = do(http.MethodGet, path, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var paid domain.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paid))
+	require.Equal(t, domain.OrderPaid, paid.Status)
+	require.Equal(t, payment.StatusCaptured, paid.PaymentStatus)
+}
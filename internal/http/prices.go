@@ -0,0 +1,143 @@
+package http
+
+import (
+	"net/http"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceHandlers struct {
+	prices *service.PriceService
+}
+
+func NewPriceHandlers(prices *service.PriceService) *PriceHandlers {
+	return &PriceHandlers{prices: prices}
+}
+
+func (h *PriceHandlers) available(c *gin.Context) bool {
+	if h.prices == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "histórico de preços indisponível"})
+		return false
+	}
+	return true
+}
+
+// authedPrices devolve o usuário logado; sem serviço configurado responde 503.
+func (h *PriceHandlers) authedPrices(c *gin.Context) (int, bool) {
+	if !h.available(c) {
+		return 0, false
+	}
+	uid, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return 0, false
+	}
+	return uid.(int), true
+}
+
+// History godoc
+// @Summary Histórico de preços de um post
+// @Description Cada ponto é o preço que valeu a partir de "at", inclusive início e fim de promoções. lowest_30d é o menor preço final dos últimos 30 dias.
+// @Tags products
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 200 {object} domain.PriceHistory
+// @Failure 404 {object} map[string]string
+// @Router /products/{postId}/price-history [get]
+func (h *PriceHandlers) History(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	hist, err := h.prices.History(postID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, hist)
+}
+
+type priceAlertRequest struct {
+	Threshold domain.Money `json:"threshold"`
+}
+
+// Subscribe godoc
+// @Summary Cria ou troca o alerta de queda de preço do usuário logado
+// @Description Notifica (price_drop) uma vez quando o final_price ficar abaixo de threshold. Assinar de novo rearma.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 200 {object} domain.PriceAlert
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/price-alert [put]
+func (h *PriceHandlers) Subscribe(c *gin.Context) {
+	uid, ok := h.authedPrices(c)
+	if !ok {
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	var req priceAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	a, err := h.prices.Subscribe(uid, postID, req.Threshold)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// Unsubscribe godoc
+// @Summary Remove o alerta de preço do usuário logado
+// @Tags products
+// @Param postId path int true "ID da publicacao"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /products/{postId}/price-alert [delete]
+func (h *PriceHandlers) Unsubscribe(c *gin.Context) {
+	uid, ok := h.authedPrices(c)
+	if !ok {
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	if err := h.prices.Unsubscribe(uid, postID); err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Alerts godoc
+// @Summary Alertas de preço do usuário logado
+// @Description Mais recentes primeiro; triggered_at indica os que já dispararam.
+// @Tags users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /users/me/price-alerts [get]
+func (h *PriceHandlers) Alerts(c *gin.Context) {
+	uid, ok := h.authedPrices(c)
+	if !ok {
+		return
+	}
+	alerts, err := h.prices.Alerts(uid)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+}
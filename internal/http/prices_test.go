@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Buyer"}, {ID: 2, Name: "Seller", IsSeller: true}})
+	postID, err := st.AddPost(domain.Post{UserID: 2, Price: 200_00, FinalPrice: 200_00, Product: domain.Product{ProductName: "Fone"}})
+	require.NoError(t, err)
+
+	h := NewPriceHandlers(service.NewPriceService(st))
+	r := gin.New()
+	auth := func(fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", 1); fn(c) }
+	}
+	r.GET("/products/:postId/price-history", h.History)
+	r.PUT("/products/:postId/price-alert", auth(h.Subscribe))
+	r.DELETE("/products/:postId/price-alert", auth(h.Unsubscribe))
+	r.GET("/users/me/price-alerts", auth(h.Alerts))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do(http.MethodGet, "/products/1/price-history", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var hist domain.PriceHistory
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hist))
+	require.Equal(t, postID, hist.PostID)
+	require.Equal(t, domain.Money(200_00), hist.Lowest30d)
+	require.Len(t, hist.Points, 1)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/products/9/price-history", "").Code)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/products/1/price-alert", `{"threshold":0}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/products/1/price-alert", `{"threshold":300}`).Code)
+	w = do(http.MethodPut, "/products/1/price-alert", `{"threshold":150}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = do(http.MethodGet, "/users/me/price-alerts", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Alerts []domain.PriceAlert `json:"alerts"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Alerts, 1)
+	require.Equal(t, domain.Money(150_00), body.Alerts[0].Threshold)
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/products/1/price-alert", "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/products/1/price-alert", "").Code)
+}
+
+func TestPriceHandlers_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewPriceHandlers(nil)
+	r := gin.New()
+	r.GET("/products/:postId/price-history", h.History)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/1/price-history", nil))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -2,10 +2,7 @@ package http
 
 import (
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
 
 	"socialmeli/internal/service"
 
@@ -13,10 +10,14 @@ import (
 )
 
 type ProfileHandlers struct {
-	us *service.UserService
+	us         *service.UserService
+	uploads    *service.UploadService
+	currencies *service.CurrencyService
 }
 
-func NewProfileHandlers(us *service.UserService) *ProfileHandlers { return &ProfileHandlers{us: us} }
+func NewProfileHandlers(us *service.UserService) *ProfileHandlers {
+	return &ProfileHandlers{us: us, uploads: defaultUploadService()}
+}
 
 func (h *ProfileHandlers) Me(c *gin.Context) {
 	uid, ok := c.Get("auth_user_id")
@@ -54,6 +55,10 @@ func (h *ProfileHandlers) MyPosts(c *gin.Context) {
 		badRequest(c, err)
 		return
 	}
+	posts, ok = convertPosts(c, h.currencies, posts)
+	if !ok {
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"posts": posts})
 }
 
@@ -66,43 +71,47 @@ func (h *ProfileHandlers) UploadAvatar(c *gin.Context) {
 	uid := uidAny.(int)
 
 	file, err := c.FormFile("avatar")
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
 	if file.Size > 2<<20 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "avatar muito grande (max 2MB)"})
 		return
 	}
+
+	images, err := saveImage(h.uploads, uid, file, 2<<20)
 	if err != nil {
-		badRequest(c, err)
+		imageError(c, err)
 		return
 	}
 
-	uploadDir := "uploads/avatars"
-	if err := os.MkdirAll(uploadDir, 0o755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao criar pasta"})
+	acc, err := h.us.UpdateAvatar(uid, images.Large)
+	if err != nil {
+		badRequest(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"user": acc, "images": images})
+}
 
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext == "" {
-		ext = ".jpg"
-	}
-	switch ext {
-	case ".jpg", ".jpeg", ".png", ".webp":
-		// ok
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "formato inválido (use jpg, png ou webp)"})
-		return
-	}
-	localPath := filepath.Join(uploadDir, strconv.Itoa(uid)+ext)
-	if err := c.SaveUploadedFile(file, localPath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao salvar"})
+// Storage godoc
+// @Summary Consumo de armazenamento do usuário logado
+// @Description Quantidade e bytes das imagens enviadas pelo usuário, com os limites da cota
+// @Tags users
+// @Produce json
+// @Success 200 {object} domain.StorageUsage
+// @Failure 401 {object} map[string]string
+// @Router /users/me/storage [get]
+func (h *ProfileHandlers) Storage(c *gin.Context) {
+	uid, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
 		return
 	}
-
-	avatarURL := "/static/avatars/" + strconv.Itoa(uid) + ext
-	acc, err := h.us.UpdateAvatar(uid, avatarURL)
+	usage, err := h.uploads.Usage(uid.(int))
 	if err != nil {
 		badRequest(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"user": acc})
+	c.JSON(http.StatusOK, usage)
 }
@@ -2,7 +2,11 @@ package http
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"image"
+	"image/png"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -21,6 +25,13 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
 func TestProfileHandlers_MeAndMyPosts(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -30,9 +41,9 @@ func TestProfileHandlers_MeAndMyPosts(t *testing.T) {
 	require.NoError(t, err)
 
 	// posts
-	_, err = st.AddPost(domain.Post{UserID: acc.ID, Product: domain.Product{ProductID: 1}, Category: 1, Price: 10, HasPromo: false, Date: time.Now().Add(-24 * time.Hour)})
+	_, err = st.AddPost(domain.Post{UserID: acc.ID, Product: domain.Product{ProductID: 1}, Category: 1, Price: 10_00, HasPromo: false, Date: time.Now().Add(-24 * time.Hour)})
 	require.NoError(t, err)
-	_, err = st.AddPost(domain.Post{UserID: acc.ID, Product: domain.Product{ProductID: 2}, Category: 2, Price: 20, HasPromo: true, Date: time.Now()})
+	_, err = st.AddPost(domain.Post{UserID: acc.ID, Product: domain.Product{ProductID: 2}, Category: 2, Price: 20_00, HasPromo: true, Date: time.Now()})
 	require.NoError(t, err)
 
 	us := service.NewUserService(st)
@@ -103,11 +114,11 @@ func TestProfileHandlers_UploadAvatar_ValidationsAndSuccess(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, w.Code)
 	}
 
-	// 2) extensão inválida
+	// 2) conteúdo que não é imagem (a extensão não importa)
 	{
 		var buf bytes.Buffer
 		mw := multipart.NewWriter(&buf)
-		fw, err := mw.CreateFormFile("avatar", "x.exe")
+		fw, err := mw.CreateFormFile("avatar", "x.png")
 		require.NoError(t, err)
 		fw.Write([]byte("abc"))
 		mw.Close()
@@ -119,13 +130,14 @@ func TestProfileHandlers_UploadAvatar_ValidationsAndSuccess(t *testing.T) {
 		require.Equal(t, http.StatusBadRequest, w.Code)
 	}
 
-	// 3) sucesso (png)
+	// 3) sucesso (png real, mesmo com extensão "errada")
 	{
 		var buf bytes.Buffer
 		mw := multipart.NewWriter(&buf)
-		fw, err := mw.CreateFormFile("avatar", "avatar.png")
+		fw, err := mw.CreateFormFile("avatar", "avatar.exe")
 		require.NoError(t, err)
-		fw.Write([]byte{0x89, 0x50, 0x4e, 0x47})
+		img := testPNG(t, 300, 300)
+		fw.Write(img)
 		mw.Close()
 
 		w := httptest.NewRecorder()
@@ -133,17 +145,21 @@ func TestProfileHandlers_UploadAvatar_ValidationsAndSuccess(t *testing.T) {
 		req.Header.Set("Content-Type", mw.FormDataContentType())
 		r.ServeHTTP(w, req)
 		require.Equal(t, http.StatusOK, w.Code)
-		require.Contains(t, w.Body.String(), "/static/avatars/")
+		require.Contains(t, w.Body.String(), "\"thumb\"")
 
-		// arquivo salvo
-		saved := filepath.Join("uploads", "avatars", fmt.Sprintf("%d.png", acc.ID))
-		_, err = os.Stat(saved)
-		require.NoError(t, err)
+		// arquivos salvos (um por tamanho), nomeados pelo sha256 do conteúdo
+		sum := sha256.Sum256(img)
+		hash := hex.EncodeToString(sum[:])
+		for _, size := range []string{"thumb", "medium", "large"} {
+			saved := filepath.Join("uploads", "images", fmt.Sprintf("%s_%s.png", hash, size))
+			_, err = os.Stat(saved)
+			require.NoError(t, err)
+		}
 
 		// avatar atualizado na conta
 		updated, ok := st.GetAccount(acc.ID)
 		require.True(t, ok)
-		require.Contains(t, updated.AvatarURL, "/static/avatars/")
+		require.Equal(t, fmt.Sprintf("/static/images/%s_large.png", hash), updated.AvatarURL)
 	}
 }
 
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+
+	"socialmeli/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchedulePromo godoc
+// @Summary Agenda ou troca a promoção de um post do usuário logado
+// @Description Sem datas vale desde já e não termina. Fora da janela has_promo é false e final_price volta ao preço cheio.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Param body body domain.PromoSchedule true "Desconto e janela"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /products/me/{postId}/promo [put]
+func (h *ProductHandlers) SchedulePromo(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	var req domain.PromoSchedule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	p, err := h.ps.SchedulePromo(uidAny.(int), postID, req)
+	promoResponse(c, p, err)
+}
+
+// EndPromo godoc
+// @Summary Encerra agora a promoção de um post do usuário logado
+// @Tags products
+// @Produce json
+// @Param postId path int true "ID da publicacao"
+// @Success 200 {object} domain.Post
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /products/me/{postId}/promo [delete]
+func (h *ProductHandlers) EndPromo(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	postID, ok := intParam(c, "postId")
+	if !ok {
+		return
+	}
+	p, err := h.ps.EndPromo(uidAny.(int), postID)
+	promoResponse(c, p, err)
+}
+
+func promoResponse(c *gin.Context, p domain.Post, err error) {
+	if err == domain.ErrPromoForbidden {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
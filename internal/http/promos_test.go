@@ -0,0 +1,67 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductHandlers_Promo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Other"}, {ID: 2, Name: "Seller", IsSeller: true}})
+	postID, err := st.AddPost(domain.Post{UserID: 2, Price: 200_00, FinalPrice: 200_00, Product: domain.Product{ProductName: "Fone"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, postID)
+
+	h := NewProductHandlers(service.NewProductService(st))
+	r := gin.New()
+	auth := func(uid int, fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", uid); fn(c) }
+	}
+	r.PUT("/products/me/:postId/promo", auth(2, h.SchedulePromo))
+	r.DELETE("/products/me/:postId/promo", auth(2, h.EndPromo))
+	r.PUT("/other/:postId/promo", auth(1, h.SchedulePromo))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/products/me/1/promo", `{"discount":0}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/products/me/1/promo", `{"discount":10,"promo_ends_at":"2020-01-01T00:00:00Z"}`).Code)
+	require.Equal(t, http.StatusForbidden, do(http.MethodPut, "/other/1/promo", `{"discount":10}`).Code)
+
+	w := do(http.MethodPut, "/products/me/1/promo", `{"discount":25,"promo_starts_at":"2099-01-01T00:00:00Z"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var p domain.Post
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.False(t, p.HasPromo)
+	require.Equal(t, domain.Money(200_00), p.FinalPrice)
+	require.NotNil(t, p.PromoStartsAt)
+
+	w = do(http.MethodPut, "/products/me/1/promo", `{"discount":25}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.True(t, p.HasPromo)
+	require.Equal(t, domain.Money(150_00), p.FinalPrice)
+
+	w = do(http.MethodDelete, "/products/me/1/promo", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	p = domain.Post{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.False(t, p.HasPromo)
+	require.Equal(t, 0.0, p.Discount)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/products/me/9/promo", "").Code)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type reviewRequest struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+// Reviews godoc
+// @Summary Avaliações de um vendedor
+// @Description Das alteradas mais recentemente para as mais antigas, com média e histograma das notas.
+// @Tags users
+// @Produce json
+// @Param userId path int true "Vendedor"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/{userId}/reviews [get]
+func (h *ProfileHandlers) Reviews(c *gin.Context) {
+	sellerID, ok := intParam(c, "userId")
+	if !ok {
+		return
+	}
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+	items, total, summary, err := h.us.Reviews(sellerID, page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": items,
+		"rating":  summary,
+		"meta": PageMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// Review godoc
+// @Summary Avalia um vendedor (ou edita a própria avaliação)
+// @Description Nota de 1 a 5 e texto. Uma avaliação por comprador e vendedor; enviar de novo substitui.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param userId path int true "Vendedor"
+// @Success 201 {object} domain.Review
+// @Success 200 {object} domain.Review
+// @Failure 400 {object} map[string]string
+// @Router /users/{userId}/review [put]
+func (h *ProfileHandlers) Review(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	sellerID, ok := intParam(c, "userId")
+	if !ok {
+		return
+	}
+	var req reviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	r, created, err := h.us.Review(uidAny.(int), sellerID, req.Rating, req.Text)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, r)
+}
+
+// ReplyReview godoc
+// @Summary Responde uma avaliação recebida
+// @Description Só o vendedor avaliado. Responder de novo substitui a resposta.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param reviewId path int true "Avaliação"
+// @Success 200 {object} domain.Review
+// @Failure 400 {object} map[string]string
+// @Router /users/me/reviews/{reviewId}/reply [put]
+func (h *ProfileHandlers) ReplyReview(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	reviewID, ok := intParam(c, "reviewId")
+	if !ok {
+		return
+	}
+	var req reviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	r, err := h.us.ReplyReview(uidAny.(int), reviewID, req.Text)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
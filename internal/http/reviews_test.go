@@ -0,0 +1,72 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	seller, err := st.CreateAccount("Seller", "seller@example.com", "hash", true)
+	require.NoError(t, err)
+	buyer, err := st.CreateAccount("Buyer", "buyer@example.com", "hash", false)
+	require.NoError(t, err)
+
+	h := NewProfileHandlers(service.NewUserService(st))
+	r := gin.New()
+	auth := func(uid int, fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", uid); fn(c) }
+	}
+	r.GET("/users/:userId/profile", h.GetProfile)
+	r.GET("/users/:userId/reviews", h.Reviews)
+	r.PUT("/users/:userId/review", auth(buyer.ID, h.Review))
+	r.PUT("/seller/users/:userId/review", auth(seller.ID, h.Review))
+	r.PUT("/users/me/reviews/:reviewId/reply", auth(seller.ID, h.ReplyReview))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	sellerPath := "/users/" + strconv.Itoa(seller.ID)
+
+	w := do(http.MethodPut, sellerPath+"/review", `{"rating":4,"text":"Bom vendedor"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var rev domain.Review
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rev))
+	require.Equal(t, http.StatusOK, do(http.MethodPut, sellerPath+"/review", `{"rating":5,"text":"Muito bom"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/seller"+sellerPath+"/review", `{"rating":5,"text":"Eu"}`).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, sellerPath+"/review", `{"rating":0,"text":"x"}`).Code)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPut, "/users/me/reviews/"+strconv.Itoa(rev.ID)+"/reply", `{"text":"Valeu!"}`).Code)
+
+	w = do(http.MethodGet, sellerPath+"/reviews", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Reviews []domain.Review      `json:"reviews"`
+		Rating  domain.RatingSummary `json:"rating"`
+		Meta    PageMeta             `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Reviews, 1)
+	require.Equal(t, 5, page.Reviews[0].Rating)
+	require.Equal(t, "Valeu!", page.Reviews[0].Reply)
+	require.Equal(t, 5.0, page.Rating.Average)
+
+	w = do(http.MethodGet, sellerPath+"/profile", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"rating":{"average":5,"count":1,"histogram":{"1":0,"2":0,"3":0,"4":0,"5":1}}`)
+}
@@ -1,16 +1,118 @@
 package http
 
 import (
-	"os"
 	"time"
 
 	"socialmeli/internal/service"
+	"socialmeli/internal/store"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(us *service.UserService, ps *service.ProductService, as *service.AuthService) *gin.Engine {
+// RouterOption ajusta dependências opcionais do router.
+type RouterOption func(*routerConfig)
+
+type routerConfig struct {
+	blobs      store.BlobStore
+	uploads    *service.UploadService
+	staging    *service.ChunkStager
+	events     *service.EventBus
+	notes      *service.NotificationService
+	hooks      *service.WebhookService
+	comments   *service.CommentService
+	orders     *service.OrderService
+	coupons    *service.CouponService
+	prices     *service.PriceService
+	currencies *service.CurrencyService
+	adminToken string
+}
+
+// WithBlobStore define onde os uploads são gravados (padrão: disco em ./uploads).
+func WithBlobStore(bs store.BlobStore) RouterOption {
+	return func(c *routerConfig) { c.blobs = bs }
+}
+
+// WithUploadService registra os uploads no store (necessário para a coleta de órfãos).
+// Sem ele os arquivos vão para o blob store sem registro.
+func WithUploadService(ups *service.UploadService) RouterOption {
+	return func(c *routerConfig) { c.uploads = ups }
+}
+
+// WithChunkStager define onde ficam os pedaços de uploads via URL assinada
+// (padrão: no blob store, com os offsets num store em memória da instância).
+func WithChunkStager(cs *service.ChunkStager) RouterOption {
+	return func(c *routerConfig) { c.staging = cs }
+}
+
+// WithEventBus define de onde vêm os eventos entregues no /ws
+// (padrão: um barramento próprio, sem produtores).
+func WithEventBus(b *service.EventBus) RouterOption {
+	return func(c *routerConfig) { c.events = b }
+}
+
+// WithNotificationService habilita a caixa de notificações (sem ela as rotas respondem 503).
+func WithNotificationService(ns *service.NotificationService) RouterOption {
+	return func(c *routerConfig) { c.notes = ns }
+}
+
+// WithWebhookService habilita os webhooks dos usuários (sem ele as rotas respondem 503).
+func WithWebhookService(ws *service.WebhookService) RouterOption {
+	return func(c *routerConfig) { c.hooks = ws }
+}
+
+// WithCommentService habilita perguntas e respostas nos posts (sem ele as rotas respondem 503).
+func WithCommentService(cs *service.CommentService) RouterOption {
+	return func(c *routerConfig) { c.comments = cs }
+}
+
+// WithOrderService habilita carrinho e pedidos (sem ele as rotas respondem 503).
+func WithOrderService(orders *service.OrderService) RouterOption {
+	return func(c *routerConfig) { c.orders = orders }
+}
+
+// WithCouponService habilita o cadastro de cupons pelos vendedores (sem ele as rotas respondem 503).
+// Para aplicar cupons no carrinho, o OrderService precisa de SetCoupons.
+func WithCouponService(cs *service.CouponService) RouterOption {
+	return func(c *routerConfig) { c.coupons = cs }
+}
+
+// WithPriceService habilita histórico e alertas de preço (sem ele as rotas respondem 503).
+func WithPriceService(ps *service.PriceService) RouterOption {
+	return func(c *routerConfig) { c.prices = ps }
+}
+
+// WithCurrencyService habilita cotações e o ?currency= das listagens (sem ele respondem 503).
+func WithCurrencyService(cs *service.CurrencyService) RouterOption {
+	return func(c *routerConfig) { c.currencies = cs }
+}
+
+// WithAdminToken define o token das rotas /admin (vazio = rotas desligadas).
+func WithAdminToken(token string) RouterOption {
+	return func(c *routerConfig) { c.adminToken = token }
+}
+
+func NewRouter(us *service.UserService, ps *service.ProductService, as *service.AuthService, opts ...RouterOption) *gin.Engine {
+	cfg := routerConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.blobs == nil && cfg.uploads != nil {
+		cfg.blobs = cfg.uploads.Blobs()
+	}
+	if cfg.blobs == nil {
+		cfg.blobs = defaultBlobStore()
+	}
+	if cfg.uploads == nil {
+		cfg.uploads = service.NewUploadService(nil, cfg.blobs)
+	}
+	if cfg.staging == nil {
+		cfg.staging = defaultChunkStager(cfg.blobs)
+	}
+	if cfg.events == nil {
+		cfg.events = service.NewEventBus()
+	}
+
 	r := gin.Default()
 	r.MaxMultipartMemory = 8 << 20 // 8MB
 
@@ -20,25 +122,33 @@ func NewRouter(us *service.UserService, ps *service.ProductService, as *service.
 			"http://127.0.0.1:5173",
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Content-Range"},
+		ExposeHeaders:    []string{"Content-Length", "Range"},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// static para avatar
-	_ = os.MkdirAll("uploads/avatars", 0o755)
-	r.Static("/static/avatars", "uploads/avatars")
-
-	// static para imagens de produtos
-	_ = os.MkdirAll("uploads/products", 0o755)
-	r.Static("/static/products", "uploads/products")
+	// avatares e imagens de produtos (local/memória; no S3 as URLs já apontam para o bucket)
+	r.GET("/static/*key", serveBlob(cfg.blobs))
 
 	uh := NewUserHandlers(us)
 	uc := NewUsersCatalogHandlers(us)
 	ph := NewProductHandlers(ps)
+	ph.uploads = cfg.uploads
+	ph.currencies = cfg.currencies
 	ah := NewAuthHandlers(as)
 	prof := NewProfileHandlers(us)
+	prof.uploads = cfg.uploads
+	prof.currencies = cfg.currencies
+	uph := NewUploadHandlers(cfg.uploads, cfg.staging)
+	wsh := NewWSHandlers(cfg.events)
+	nh := NewNotificationHandlers(cfg.notes)
+	whh := NewWebhookHandlers(cfg.hooks)
+	ch := NewCommentHandlers(cfg.comments)
+	oh := NewOrderHandlers(cfg.orders)
+	cph := NewCouponHandlers(cfg.coupons)
+	prh := NewPriceHandlers(cfg.prices)
+	curh := NewCurrencyHandlers(cfg.currencies)
 
 	// auth
 	r.POST("/auth/register", ah.Register)
@@ -48,10 +158,77 @@ func NewRouter(us *service.UserService, ps *service.ProductService, as *service.
 	authed.GET("/auth/me", prof.Me)
 	authed.GET("/users/me/posts", prof.MyPosts)
 	authed.POST("/users/me/avatar", prof.UploadAvatar)
+	authed.GET("/users/me/storage", prof.Storage)
 	// upload de imagem de produto (usa Bearer token)
 	authed.POST("/products/me/image", ph.UploadProductImage)
 	// apagar publicacao do usuario logado
 	authed.DELETE("/products/me/:postId", ph.DeleteMyPost)
+	// promoção de um post já publicado (agendar, trocar, encerrar)
+	authed.PUT("/products/me/:postId/promo", ph.SchedulePromo)
+	authed.DELETE("/products/me/:postId/promo", ph.EndPromo)
+	authed.GET("/products/feed", ph.Feed)
+	authed.GET("/products/feed/stream", ph.FeedStream)
+	// likes e favoritos
+	authed.POST("/products/:postId/like", ph.Like)
+	authed.DELETE("/products/:postId/like", ph.Like)
+	authed.POST("/products/:postId/favorite", ph.Favorite)
+	authed.DELETE("/products/:postId/favorite", ph.Favorite)
+	authed.GET("/users/me/favorites", ph.MyFavorites)
+	// alertas de queda de preço (o histórico é público)
+	authed.PUT("/products/:postId/price-alert", prh.Subscribe)
+	authed.DELETE("/products/:postId/price-alert", prh.Unsubscribe)
+	authed.GET("/users/me/price-alerts", prh.Alerts)
+	// avaliações de vendedores
+	authed.PUT("/users/:userId/review", prof.Review)
+	authed.PUT("/users/me/reviews/:reviewId/reply", prof.ReplyReview)
+	// perguntas e respostas (a listagem é pública)
+	authed.POST("/products/:postId/comments", ch.Create)
+	authed.PATCH("/products/:postId/comments/:commentId", ch.Edit)
+	authed.DELETE("/products/:postId/comments/:commentId", ch.Delete)
+	authed.PUT("/products/:postId/comments/:commentId/answer", ch.Answer)
+	authed.DELETE("/products/:postId/comments/:commentId/answer", ch.Answer)
+	// URL assinada: o PUT não usa Bearer, a própria URL é a credencial
+	authed.POST("/uploads/sign", uph.Sign)
+	r.PUT("/uploads/:token", uph.Put)
+	// notificações em tempo real
+	authed.POST("/ws/ticket", wsh.Ticket)
+	r.GET("/ws", wsh.Authenticate(), wsh.Connect)
+	// caixa de notificações
+	authed.GET("/notifications", nh.List)
+	authed.GET("/notifications/unread-count", nh.UnreadCount)
+	authed.POST("/notifications/read", nh.MarkRead)
+	authed.GET("/notifications/preferences", nh.Preferences)
+	authed.PUT("/notifications/preferences", nh.UpdatePreferences)
+	// carrinho e pedidos
+	authed.GET("/cart", oh.Cart)
+	authed.POST("/cart/items", oh.AddToCart)
+	authed.PUT("/cart/items/:postId", oh.SetCartItem)
+	authed.DELETE("/cart/items/:postId", oh.RemoveFromCart)
+	authed.POST("/cart/checkout", oh.Checkout)
+	authed.GET("/users/me/orders", oh.Purchases)
+	authed.GET("/users/me/sales", oh.Sales)
+	authed.GET("/orders/:orderId", oh.Get)
+	authed.PATCH("/orders/:orderId", oh.UpdateStatus)
+	authed.POST("/orders/:orderId/pay", oh.Pay)
+	// cupons do vendedor
+	authed.POST("/coupons", cph.Create)
+	authed.GET("/coupons", cph.List)
+	authed.DELETE("/coupons/:id", cph.Delete)
+	// callback do provedor de pagamentos (autenticado pela assinatura)
+	r.POST("/payments/callback", oh.PaymentCallback)
+	// webhooks
+	authed.POST("/webhooks", whh.Create)
+	authed.GET("/webhooks", whh.List)
+	authed.DELETE("/webhooks/:id", whh.Delete)
+	authed.GET("/webhooks/:id/deliveries", whh.Deliveries)
+	authed.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", whh.Redeliver)
+
+	// cotações (leitura pública, manutenção só com X-Admin-Token)
+	r.GET("/exchange-rates", curh.Rates)
+	admin := r.Group("/admin", AdminMiddleware(cfg.adminToken))
+	admin.PUT("/exchange-rates", curh.SetRates)
+	admin.POST("/exchange-rates/import", curh.Import)
+	admin.DELETE("/exchange-rates/:currency", curh.Delete)
 
 	// health
 	r.GET("/health", func(c *gin.Context) { c.JSON(200, gin.H{"status": "ok"}) })
@@ -59,6 +236,8 @@ func NewRouter(us *service.UserService, ps *service.ProductService, as *service.
 	// users catalog
 	r.GET("/users", uc.List)
 	r.POST("/users", uc.Create)
+	r.GET("/users/search", uc.Search)
+	r.GET("/users/typeahead", uc.Typeahead)
 
 	// follow
 	r.POST("/users/:userId/follow/:userIdToFollow", uh.Follow)
@@ -70,10 +249,14 @@ func NewRouter(us *service.UserService, ps *service.ProductService, as *service.
 
 	// profile publico
 	r.GET("/users/:userId/profile", prof.GetProfile)
+	r.GET("/users/:userId/reviews", prof.Reviews)
 
 	// products
 	r.POST("/products/publish", ph.Publish)
 	r.GET("/products/followed/:userId/list", ph.FollowedLastTwoWeeks)
+	r.GET("/products/search", ph.Search)
+	r.GET("/products/:postId/comments", ch.List)
+	r.GET("/products/:postId/price-history", prh.History)
 
 	r.POST("/products/promo-pub", ph.PromoPublish)
 	r.GET("/products/promo-pub/count", ph.PromoCount)
@@ -0,0 +1,313 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialmeli/internal/media"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	signedUploadTTL      = 15 * time.Minute
+	maxSignedUploadBytes = 20 << 20 // 20MB (o multipart direto continua limitado a 4MB)
+	maxUploadChunkBytes  = 8 << 20  // cada PUT carrega no máximo 8MB
+)
+
+// uploadGrant é o que a URL assinada autoriza: um arquivo de Size bytes e tipo CT,
+// enviado para a conta Sub até Exp. ID identifica o upload entre os pedaços.
+type uploadGrant struct {
+	ID   string `json:"id"`
+	Sub  int    `json:"sub"`
+	Size int64  `json:"size"`
+	CT   string `json:"ct"`
+	Exp  int64  `json:"exp"`
+}
+
+var (
+	errUploadTokenInvalid = errors.New("URL de upload inválida")
+	errUploadTokenExpired = errors.New("URL de upload expirada")
+)
+
+// makeUploadToken gera <payload>.<assinatura>. O prefixo "upload." na assinatura
+// impede que um token de upload seja aceito como token de sessão (e vice-versa).
+func makeUploadToken(g uploadGrant) (string, error) {
+	pb, err := json.Marshal(g)
+	if err != nil {
+		return "", err
+	}
+	payload := b64(pb)
+	return payload + "." + sign("upload."+payload), nil
+}
+
+func parseUploadToken(token string, now time.Time) (uploadGrant, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || sign("upload."+payload) != sig {
+		return uploadGrant{}, errUploadTokenInvalid
+	}
+	pb, err := unb64(payload)
+	if err != nil {
+		return uploadGrant{}, errUploadTokenInvalid
+	}
+	var g uploadGrant
+	if err := json.Unmarshal(pb, &g); err != nil {
+		return uploadGrant{}, errUploadTokenInvalid
+	}
+	if now.Unix() > g.Exp {
+		return uploadGrant{}, errUploadTokenExpired
+	}
+	return g, nil
+}
+
+// defaultChunkStager guarda os offsets num store em memória próprio: serve para uma
+// instância só. Com várias instâncias use WithChunkStager com o store compartilhado.
+func defaultChunkStager(blobs store.BlobStore) *service.ChunkStager {
+	return service.NewChunkStager(store.NewMemoryStore(), blobs)
+}
+
+type UploadHandlers struct {
+	uploads *service.UploadService
+	staging *service.ChunkStager
+	now     func() time.Time
+}
+
+func NewUploadHandlers(ups *service.UploadService, staging *service.ChunkStager) *UploadHandlers {
+	return &UploadHandlers{uploads: ups, staging: staging, now: time.Now}
+}
+
+type signUploadRequest struct {
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Sign godoc
+// @Summary Gera URL assinada para upload direto
+// @Description Retorna uma URL de curta duração (15 min) para enviar a imagem via PUT, inteira ou em pedaços (Content-Range), sem passar pelo multipart.
+// @Tags uploads
+// @Accept json
+// @Produce json
+// @Param body body signUploadRequest true "Tipo e tamanho do arquivo"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /uploads/sign [post]
+func (h *UploadHandlers) Sign(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+
+	var req signUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if _, ok := media.ContentTypeFormat(req.ContentType); !ok {
+		badRequest(c, media.ErrUnsupported)
+		return
+	}
+	if req.Size <= 0 || req.Size > maxSignedUploadBytes {
+		badRequest(c, fmt.Errorf("size deve estar entre 1 e %d bytes", maxSignedUploadBytes))
+		return
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao assinar"})
+		return
+	}
+	exp := h.now().Add(signedUploadTTL)
+	token, err := makeUploadToken(uploadGrant{
+		ID:   hex.EncodeToString(id),
+		Sub:  uidAny.(int),
+		Size: req.Size,
+		CT:   req.ContentType,
+		Exp:  exp.Unix(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao assinar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"upload_url":      "/uploads/" + token,
+		"method":          http.MethodPut,
+		"content_type":    req.ContentType,
+		"size":            req.Size,
+		"expires_at":      exp.UTC().Format(time.RFC3339),
+		"max_chunk_bytes": maxUploadChunkBytes,
+	})
+}
+
+// parseContentRange aceita "bytes <ini>-<fim>/<total>" e "bytes */<total>" (consulta).
+func parseContentRange(h string) (start, end, total int64, query bool, err error) {
+	spec, ok := strings.CutPrefix(h, "bytes ")
+	if !ok {
+		return 0, 0, 0, false, errors.New("Content-Range inválido")
+	}
+	rng, tot, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, 0, false, errors.New("Content-Range inválido")
+	}
+	if total, err = strconv.ParseInt(tot, 10, 64); err != nil || total <= 0 {
+		return 0, 0, 0, false, errors.New("Content-Range inválido")
+	}
+	if rng == "*" {
+		return 0, 0, total, true, nil
+	}
+	a, b, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, 0, false, errors.New("Content-Range inválido")
+	}
+	start, err1 := strconv.ParseInt(a, 10, 64)
+	end, err2 := strconv.ParseInt(b, 10, 64)
+	if err1 != nil || err2 != nil || start < 0 || end < start || end >= total {
+		return 0, 0, 0, false, errors.New("Content-Range inválido")
+	}
+	return start, end, total, false, nil
+}
+
+// incomplete responde 308 com o que já foi recebido (mesma convenção de uploads
+// retomáveis do GCS): o cliente continua a partir de "received".
+func incomplete(c *gin.Context, received int64) {
+	if received > 0 {
+		c.Header("Range", fmt.Sprintf("bytes=0-%d", received-1))
+	}
+	c.JSON(http.StatusPermanentRedirect, gin.H{"received": received})
+}
+
+// Put godoc
+// @Summary Recebe upload via URL assinada
+// @Description Corpo cru da imagem. Sem Content-Range o arquivo vai inteiro; com "bytes ini-fim/total" vai em pedaços, respondendo 308 até completar. "bytes */total" consulta quanto já foi recebido.
+// @Tags uploads
+// @Accept image/jpeg,image/png,image/gif
+// @Produce json
+// @Param token path string true "Token da URL assinada"
+// @Success 200 {object} map[string]interface{}
+// @Success 308 {object} map[string]interface{} "Upload incompleto"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]interface{} "Offset fora de ordem"
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /uploads/{token} [put]
+func (h *UploadHandlers) Put(c *gin.Context) {
+	grant, err := parseUploadToken(c.Param("token"), h.now())
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	start, end, total := int64(0), grant.Size-1, grant.Size
+	if cr := c.GetHeader("Content-Range"); cr != "" {
+		var query bool
+		start, end, total, query, err = parseContentRange(cr)
+		if err != nil {
+			badRequest(c, err)
+			return
+		}
+		if total != grant.Size {
+			badRequest(c, errors.New("tamanho total difere do assinado"))
+			return
+		}
+		if query {
+			received, err := h.staging.Offset(grant.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao consultar upload"})
+				return
+			}
+			if received < grant.Size {
+				incomplete(c, received)
+				return
+			}
+			h.finish(c, grant)
+			return
+		}
+	}
+
+	if ct, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type")); ct != grant.CT {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type difere do assinado (" + grant.CT + ")"})
+		return
+	}
+	chunk := end - start + 1
+	if chunk > maxUploadChunkBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("pedaço muito grande (max %d bytes)", maxUploadChunkBytes)})
+		return
+	}
+	if c.Request.ContentLength >= 0 && c.Request.ContentLength != chunk {
+		badRequest(c, errors.New("Content-Length não confere com o intervalo"))
+		return
+	}
+
+	body := io.LimitReader(c.Request.Body, chunk)
+	received, err := h.staging.Append(grant.ID, start, grant.Size, body)
+	switch {
+	case errors.Is(err, service.ErrChunkOffset):
+		if received > 0 {
+			c.Header("Range", fmt.Sprintf("bytes=0-%d", received-1))
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "received": received})
+		return
+	case errors.Is(err, service.ErrChunkOverflow):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		// conexão caiu no meio: o que chegou foi mantido
+		incomplete(c, received)
+		return
+	}
+
+	if received < grant.Size {
+		incomplete(c, received)
+		return
+	}
+	h.finish(c, grant)
+}
+
+// finish confere o conteúdo real contra o tipo assinado e processa a imagem.
+// Repetir o último PUT depois de concluído é inofensivo: o conteúdo é o mesmo e
+// o UploadService deduplica pelo hash.
+func (h *UploadHandlers) finish(c *gin.Context, grant uploadGrant) {
+	f, err := h.staging.Open(grant.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao ler upload"})
+		return
+	}
+
+	head := make([]byte, 16)
+	n, _ := io.ReadFull(f, head)
+	format, err := media.Sniff(head[:n])
+	want, _ := media.ContentTypeFormat(grant.CT)
+	if err != nil || format != want {
+		_ = h.staging.Remove(grant.ID)
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "conteúdo não corresponde a " + grant.CT})
+		return
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao ler upload"})
+		return
+	}
+
+	images, err := h.uploads.SaveImage(grant.Sub, f, grant.Size)
+	if err != nil {
+		if isMediaError(err) {
+			_ = h.staging.Remove(grant.ID)
+		}
+		imageError(c, err)
+		return
+	}
+	_ = h.staging.Remove(grant.ID)
+	c.JSON(http.StatusOK, gin.H{"image_url": images.Large, "images": images})
+}
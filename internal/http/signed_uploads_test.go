@@ -0,0 +1,161 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func signedUploadRouter(t *testing.T) (*gin.Engine, string, *store.MemoryBlobStore) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	acc, err := st.CreateAccount("User", "user@example.com", "hash", true)
+	require.NoError(t, err)
+	blobs := store.NewMemoryBlobStore("/static/")
+	r := NewRouter(service.NewUserService(st), service.NewProductService(st), service.NewAuthService(st),
+		WithUploadService(service.NewUploadService(st, blobs)),
+		WithChunkStager(service.NewChunkStager(st, blobs)))
+
+	token, err := MakeToken(acc.ID, time.Hour)
+	require.NoError(t, err)
+	return r, token, blobs
+}
+
+func signUpload(t *testing.T, r *gin.Engine, token, ct string, size int) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/uploads/sign", strings.NewReader(fmt.Sprintf(`{"content_type":%q,"size":%d}`, ct, size)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		UploadURL string `json:"upload_url"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.True(t, strings.HasPrefix(resp.UploadURL, "/uploads/"))
+	return resp.UploadURL
+}
+
+func putChunk(r *gin.Engine, url, ct, contentRange string, body []byte) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", ct)
+	if contentRange != "" {
+		req.Header.Set("Content-Range", contentRange)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSignedUpload_Chunked(t *testing.T) {
+	r, token, blobs := signedUploadRouter(t)
+	img := testPNG(t, 40, 40)
+	total := len(img)
+	half := total / 2
+	url := signUpload(t, r, token, "image/png", total)
+
+	w := putChunk(r, url, "image/png", fmt.Sprintf("bytes 0-%d/%d", half-1, total), img[:half])
+	require.Equal(t, http.StatusPermanentRedirect, w.Code)
+	require.Equal(t, fmt.Sprintf("bytes=0-%d", half-1), w.Header().Get("Range"))
+
+	// consulta de status depois de "perder" a resposta
+	w = putChunk(r, url, "", fmt.Sprintf("bytes */%d", total), nil)
+	require.Equal(t, http.StatusPermanentRedirect, w.Code)
+	require.Contains(t, w.Body.String(), fmt.Sprintf(`"received":%d`, half))
+
+	// pedaço fora de ordem
+	w = putChunk(r, url, "image/png", fmt.Sprintf("bytes 0-%d/%d", total-1, total), img)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = putChunk(r, url, "image/png", fmt.Sprintf("bytes %d-%d/%d", half, total-1, total), img[half:])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"image_url":"/static/images/`)
+	require.Len(t, blobs.Keys(), 3)
+}
+
+func TestSignedUpload_SingleShotAndValidation(t *testing.T) {
+	r, token, _ := signedUploadRouter(t)
+	img := testPNG(t, 10, 10)
+
+	// tipo não aceito na assinatura
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/uploads/sign", strings.NewReader(`{"content_type":"image/bmp","size":10}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// sem auth
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/uploads/sign", strings.NewReader(`{}`)))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	url := signUpload(t, r, token, "image/png", len(img))
+
+	// assinatura adulterada
+	w = putChunk(r, url+"x", "image/png", "", img)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// token de sessão não serve como URL de upload
+	w = putChunk(r, "/uploads/"+token, "image/png", "", img)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Content-Type diferente do assinado
+	w = putChunk(r, url, "image/jpeg", "", img)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	// tamanho diferente do assinado
+	w = putChunk(r, url, "image/png", "", img[:len(img)-1])
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = putChunk(r, url, "image/png", "", img)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestSignedUpload_ContentMustMatchType(t *testing.T) {
+	r, token, blobs := signedUploadRouter(t)
+	fake := []byte("GIF89a-nao-e-png")
+	url := signUpload(t, r, token, "image/png", len(fake))
+
+	w := putChunk(r, url, "image/png", "", fake)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	require.Empty(t, blobs.Keys())
+}
+
+func TestParseUploadToken_Expired(t *testing.T) {
+	tok, err := makeUploadToken(uploadGrant{ID: "ab", Sub: 1, Size: 1, CT: "image/png", Exp: time.Now().Add(-time.Minute).Unix()})
+	require.NoError(t, err)
+	_, err = parseUploadToken(tok, time.Now())
+	require.ErrorIs(t, err, errUploadTokenExpired)
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, end, total, query, err := parseContentRange("bytes 10-19/100")
+	require.NoError(t, err)
+	require.Equal(t, []int64{10, 19, 100}, []int64{start, end, total})
+	require.False(t, query)
+
+	_, _, total, query, err = parseContentRange("bytes */100")
+	require.NoError(t, err)
+	require.True(t, query)
+	require.EqualValues(t, 100, total)
+
+	for _, bad := range []string{"", "bytes 5-1/10", "bytes 0-10/10", "items 0-1/2", "bytes 0-1"} {
+		_, _, _, _, err := parseContentRange(bad)
+		require.Error(t, err, bad)
+	}
+}
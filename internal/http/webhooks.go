@@ -0,0 +1,187 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"socialmeli/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandlers struct {
+	ws *service.WebhookService
+}
+
+func NewWebhookHandlers(ws *service.WebhookService) *WebhookHandlers {
+	return &WebhookHandlers{ws: ws}
+}
+
+// authedWebhooks devolve o usuário logado; sem serviço configurado responde 503.
+func (h *WebhookHandlers) authedWebhooks(c *gin.Context) (int, bool) {
+	if h.ws == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "webhooks indisponíveis"})
+		return 0, false
+	}
+	uid, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return 0, false
+	}
+	return uid.(int), true
+}
+
+func intParam(c *gin.Context, name string) (int, bool) {
+	v, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro inválido: " + name})
+		return 0, false
+	}
+	return v, true
+}
+
+type createWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// Create godoc
+// @Summary Cadastra um webhook
+// @Description Eventos: follower.new, post.created, post.updated, post.deleted. O secret só é devolvido aqui.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 201 {object} domain.Webhook
+// @Failure 400 {object} map[string]string
+// @Router /webhooks [post]
+func (h *WebhookHandlers) Create(c *gin.Context) {
+	uid, ok := h.authedWebhooks(c)
+	if !ok {
+		return
+	}
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	w, err := h.ws.Create(uid, req.URL, req.Events)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, w)
+}
+
+// List godoc
+// @Summary Webhooks do usuário logado
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /webhooks [get]
+func (h *WebhookHandlers) List(c *gin.Context) {
+	uid, ok := h.authedWebhooks(c)
+	if !ok {
+		return
+	}
+	ws, err := h.ws.List(uid)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": ws})
+}
+
+// Delete godoc
+// @Summary Remove um webhook (e suas entregas)
+// @Tags webhooks
+// @Param id path int true "Webhook"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandlers) Delete(c *gin.Context) {
+	uid, ok := h.authedWebhooks(c)
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.ws.Delete(uid, id); err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Deliveries godoc
+// @Summary Entregas de um webhook
+// @Description Mais recentes primeiro, com tentativas, último status HTTP e erro. status=dead lista a dead-letter.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook"
+// @Param status query string false "pending, succeeded ou dead"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandlers) Deliveries(c *gin.Context) {
+	uid, ok := h.authedWebhooks(c)
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	page, limit, ok := parsePageLimit(c, 20, 100)
+	if !ok {
+		return
+	}
+	items, total, err := h.ws.Deliveries(uid, id, c.Query("status"), page, limit)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": items,
+		"meta": PageMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+		},
+	})
+}
+
+// Redeliver godoc
+// @Summary Reenvia uma entrega da dead-letter
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook"
+// @Param deliveryId path int true "Entrega"
+// @Success 200 {object} domain.WebhookDelivery
+// @Failure 400 {object} map[string]string
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandlers) Redeliver(c *gin.Context) {
+	uid, ok := h.authedWebhooks(c)
+	if !ok {
+		return
+	}
+	id, ok := intParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := intParam(c, "deliveryId")
+	if !ok {
+		return
+	}
+	d, err := h.ws.Redeliver(uid, id, deliveryID)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received []string
+	recv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(service.WebhookEventHeader))
+	}))
+	defer recv.Close()
+
+	st := store.NewMemoryStore()
+	seller, err := st.CreateAccount("Seller", "seller@example.com", "hash", true)
+	require.NoError(t, err)
+	buyer, err := st.CreateAccount("Buyer", "buyer@example.com", "hash", false)
+	require.NoError(t, err)
+
+	ws := service.NewWebhookService(st)
+	ws.SetAllowPrivate(true) // receptor local
+	us := service.NewUserService(st)
+	us.SetWebhooks(ws)
+
+	h := NewWebhookHandlers(ws)
+	r := gin.New()
+	auth := func(uid int, fn gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) { c.Set("auth_user_id", uid); fn(c) }
+	}
+	r.POST("/webhooks", auth(seller.ID, h.Create))
+	r.GET("/webhooks", auth(seller.ID, h.List))
+	r.DELETE("/webhooks/:id", auth(seller.ID, h.Delete))
+	r.GET("/webhooks/:id/deliveries", auth(seller.ID, h.Deliveries))
+	r.GET("/other/webhooks/:id/deliveries", auth(buyer.ID, h.Deliveries))
+	r.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", auth(seller.ID, h.Redeliver))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/webhooks", `{"url":"nope","events":["post.created"]}`).Code)
+	w := do(http.MethodPost, "/webhooks", `{"url":"`+recv.URL+`","events":["follower.new"]}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var hook domain.Webhook
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hook))
+	require.NotEmpty(t, hook.Secret)
+	require.NotContains(t, do(http.MethodGet, "/webhooks", "").Body.String(), hook.Secret)
+
+	require.NoError(t, us.Follow(buyer.ID, seller.ID))
+	n, err := ws.ProcessDue()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{domain.WebhookFollowerNew}, received)
+
+	path := "/webhooks/" + strconv.Itoa(hook.ID) + "/deliveries"
+	w = do(http.MethodGet, path+"?status=succeeded", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Deliveries []domain.WebhookDelivery `json:"deliveries"`
+		Meta       PageMeta                 `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Deliveries, 1)
+	require.Equal(t, 1, page.Deliveries[0].Attempts)
+	require.Equal(t, 1, page.Meta.Total)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, path+"?status=nope", "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/other"+path, "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, path+"/"+strconv.Itoa(page.Deliveries[0].ID)+"/redeliver", "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/webhooks/abc/deliveries", "").Code)
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/webhooks/"+strconv.Itoa(hook.ID), "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/webhooks/"+strconv.Itoa(hook.ID), "").Code)
+}
+
+func TestWebhookHandlers_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+	NewWebhookHandlers(nil).List(c)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
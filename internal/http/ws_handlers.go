@@ -0,0 +1,360 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"socialmeli/internal/service"
+	"socialmeli/internal/ws"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// wsIdleTimeout: sem nenhuma mensagem do cliente (nem ping) nesse tempo a conexão cai.
+	wsIdleTimeout  = 90 * time.Second
+	wsWriteTimeout = 10 * time.Second
+)
+
+const (
+	// wsSendBuffer mensagens podem esperar o writer; passou disso o cliente é lento e cai.
+	wsSendBuffer = 32
+	// wsMaxUnacked eventos sem ack derrubam a conexão (cliente travado).
+	wsMaxUnacked = 64
+	// wsTicketTTL: o ticket do /ws vai na query (o navegador não manda header no
+	// WebSocket) e acaba no log de acesso; por isso vale pouco e uma vez só.
+	wsTicketTTL = 30 * time.Second
+)
+
+// Protocolo do /ws (JSON em mensagens de texto).
+//
+//	cliente: {"type":"subscribe","topics":["follower.new"]} (sem topics = todos)
+//	         {"type":"ack","id":7}
+//	         {"type":"ping"}
+//	servidor: {"type":"hello","topics":[...]}  tópicos disponíveis, ao conectar
+//	          {"type":"subscribed","topics":[...]}
+//	          {"type":"event","id":7,"event":{...}}  precisa de ack
+//	          {"type":"pong"}
+//	          {"type":"error","error":"..."}
+type wsMessage struct {
+	Type   string         `json:"type"`
+	ID     uint64         `json:"id,omitempty"`
+	Topics []string       `json:"topics,omitempty"`
+	Event  *service.Event `json:"event,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+type wsClient struct {
+	userID int
+	conn   *ws.Conn
+	send   chan wsMessage
+	done   chan struct{}
+
+	once        sync.Once
+	closeCode   int
+	closeReason string
+
+	mu      sync.Mutex
+	topics  map[string]bool
+	nextID  uint64
+	unacked map[uint64]bool
+}
+
+func newWSClient(userID int, conn *ws.Conn) *wsClient {
+	return &wsClient{
+		userID:  userID,
+		conn:    conn,
+		send:    make(chan wsMessage, wsSendBuffer),
+		done:    make(chan struct{}),
+		topics:  map[string]bool{},
+		unacked: map[uint64]bool{},
+	}
+}
+
+// kill encerra a conexão (o writer manda o close); só o primeiro motivo vale.
+func (c *wsClient) kill(code int, reason string) {
+	c.once.Do(func() {
+		c.closeCode, c.closeReason = code, reason
+		close(c.done)
+	})
+}
+
+func (c *wsClient) enqueue(m wsMessage) {
+	select {
+	case c.send <- m:
+	case <-c.done:
+	default:
+		c.kill(ws.CloseTryAgainLater, "cliente lento")
+	}
+}
+
+func (c *wsClient) deliver(ev service.Event) {
+	c.mu.Lock()
+	if !c.topics[ev.Type] {
+		c.mu.Unlock()
+		return
+	}
+	if len(c.unacked) >= wsMaxUnacked {
+		c.mu.Unlock()
+		c.kill(ws.ClosePolicyViolation, "eventos sem ack")
+		return
+	}
+	c.nextID++
+	id := c.nextID
+	c.unacked[id] = true
+	c.mu.Unlock()
+	c.enqueue(wsMessage{Type: "event", ID: id, Event: &ev})
+}
+
+func (c *wsClient) writeLoop() {
+	defer c.conn.Close()
+	for {
+		select {
+		case m := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := c.conn.WriteJSON(m); err != nil {
+				c.kill(ws.CloseGoingAway, "")
+				return
+			}
+		case <-c.done:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			_ = c.conn.WriteClose(c.closeCode, c.closeReason)
+			return
+		}
+	}
+}
+
+func (c *wsClient) handle(m wsMessage) {
+	switch m.Type {
+	case "subscribe":
+		topics := m.Topics
+		if len(topics) == 0 {
+			topics = service.EventTypes
+		}
+		set := map[string]bool{}
+		for _, t := range topics {
+			if !knownEventType(t) {
+				c.enqueue(wsMessage{Type: "error", Error: "Tópico inválido: " + t})
+				return
+			}
+			set[t] = true
+		}
+		c.mu.Lock()
+		c.topics = set
+		c.mu.Unlock()
+		list := make([]string, 0, len(set))
+		for t := range set {
+			list = append(list, t)
+		}
+		sort.Strings(list)
+		c.enqueue(wsMessage{Type: "subscribed", Topics: list})
+	case "ack":
+		c.mu.Lock()
+		ok := c.unacked[m.ID]
+		delete(c.unacked, m.ID)
+		c.mu.Unlock()
+		if !ok {
+			c.enqueue(wsMessage{Type: "error", Error: "ack desconhecido"})
+		}
+	case "ping":
+		c.enqueue(wsMessage{Type: "pong"})
+	default:
+		c.enqueue(wsMessage{Type: "error", Error: "mensagem inválida"})
+	}
+}
+
+func knownEventType(t string) bool {
+	for _, k := range service.EventTypes {
+		if k == t {
+			return true
+		}
+	}
+	return false
+}
+
+// wsRegistry guarda as conexões abertas por usuário e entrega os eventos do barramento.
+type wsRegistry struct {
+	mu    sync.RWMutex
+	conns map[int]map[*wsClient]struct{}
+}
+
+func newWSRegistry() *wsRegistry {
+	return &wsRegistry{conns: map[int]map[*wsClient]struct{}{}}
+}
+
+func (r *wsRegistry) add(c *wsClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conns[c.userID] == nil {
+		r.conns[c.userID] = map[*wsClient]struct{}{}
+	}
+	r.conns[c.userID][c] = struct{}{}
+}
+
+func (r *wsRegistry) remove(c *wsClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns[c.userID], c)
+	if len(r.conns[c.userID]) == 0 {
+		delete(r.conns, c.userID)
+	}
+}
+
+// count devolve quantas conexões o usuário tem abertas.
+func (r *wsRegistry) count(userID int) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.conns[userID])
+}
+
+func (r *wsRegistry) deliver(ev service.Event) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for c := range r.conns[ev.UserID] {
+		c.deliver(ev)
+	}
+}
+
+type wsTicket struct {
+	userID  int
+	expires time.Time
+}
+
+// wsTickets são credenciais de uso único para abrir o /ws.
+type wsTickets struct {
+	mu sync.Mutex
+	m  map[string]wsTicket
+}
+
+func (t *wsTickets) issue(userID int, now time.Time) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k, v := range t.m {
+		if !now.Before(v.expires) {
+			delete(t.m, k)
+		}
+	}
+	t.m[id] = wsTicket{userID: userID, expires: now.Add(wsTicketTTL)}
+	return id, nil
+}
+
+// redeem consome o ticket: a segunda tentativa com o mesmo valor falha.
+func (t *wsTickets) redeem(id string, now time.Time) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v, ok := t.m[id]
+	delete(t.m, id)
+	if !ok || !now.Before(v.expires) {
+		return 0, false
+	}
+	return v.userID, true
+}
+
+type WSHandlers struct {
+	reg     *wsRegistry
+	tickets *wsTickets
+}
+
+// NewWSHandlers assina o barramento: eventos de um usuário vão para todas as conexões dele.
+func NewWSHandlers(bus *service.EventBus) *WSHandlers {
+	h := &WSHandlers{reg: newWSRegistry(), tickets: &wsTickets{m: map[string]wsTicket{}}}
+	bus.Subscribe(h.reg.deliver)
+	return h
+}
+
+// Ticket godoc
+// @Summary Ticket para abrir o WebSocket
+// @Description Ticket de uso único, válido por 30s, para GET /ws?ticket= (navegadores não mandam Authorization no WebSocket).
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string "token ausente"
+// @Router /ws/ticket [post]
+func (h *WSHandlers) Ticket(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	ticket, err := h.tickets.issue(uidAny.(int), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao gerar ticket"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ticket": ticket, "expires_in": int(wsTicketTTL.Seconds())})
+}
+
+// Authenticate autentica o /ws pelo ?ticket= (navegador) ou pelo header Bearer.
+func (h *WSHandlers) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ticket := c.Query("ticket"); ticket != "" {
+			uid, ok := h.tickets.redeem(ticket, time.Now())
+			if !ok {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "ticket inválido ou expirado"})
+				return
+			}
+			c.Set("auth_user_id", uid)
+			c.Next()
+			return
+		}
+		AuthMiddleware()(c)
+	}
+}
+
+// Connect godoc
+// @Summary Canal de notificações (WebSocket)
+// @Description Upgrade para WebSocket. Autentica pelo Bearer ou, como navegadores não mandam header no WebSocket, por ?ticket= (POST /ws/ticket).
+// @Description Mensagens JSON: subscribe, ack e ping do cliente; hello, subscribed, event, pong e error do servidor.
+// @Tags notifications
+// @Param ticket query string false "Ticket de uso único"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} map[string]string "token ausente"
+// @Router /ws [get]
+func (h *WSHandlers) Connect(c *gin.Context) {
+	uidAny, ok := c.Get("auth_user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token ausente"})
+		return
+	}
+	conn, err := ws.Upgrade(c.Writer, c.Request)
+	if err != nil {
+		return // Upgrade já respondeu
+	}
+
+	client := newWSClient(uidAny.(int), conn)
+	h.reg.add(client)
+	defer h.reg.remove(client)
+
+	written := make(chan struct{})
+	go func() {
+		client.writeLoop()
+		close(written)
+	}()
+	client.enqueue(wsMessage{Type: "hello", Topics: service.EventTypes})
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(wsIdleTimeout))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			client.kill(ws.CloseGoingAway, "")
+			break
+		}
+		var m wsMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			client.enqueue(wsMessage{Type: "error", Error: "JSON inválido"})
+			continue
+		}
+		client.handle(m)
+	}
+	<-written
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"socialmeli/internal/service"
+	"socialmeli/internal/store"
+	"socialmeli/internal/ws"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func readWS(t *testing.T, c *ws.Conn) wsMessage {
+	t.Helper()
+	c.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := c.ReadMessage()
+	require.NoError(t, err)
+	var m wsMessage
+	require.NoError(t, json.Unmarshal(data, &m))
+	return m
+}
+
+// wsTicketFor pede um ticket do /ws com o Bearer.
+func wsTicketFor(t *testing.T, baseURL, token string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/ws/ticket", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body struct {
+		Ticket    string `json:"ticket"`
+		ExpiresIn int    `json:"expires_in"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.NotEmpty(t, body.Ticket)
+	require.Equal(t, 30, body.ExpiresIn)
+	return body.Ticket
+}
+
+func TestWS_NotificationsFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	st := store.NewMemoryStore()
+	seller, err := st.CreateAccount("Seller", "seller@example.com", "hash", true)
+	require.NoError(t, err)
+	buyer, err := st.CreateAccount("Buyer", "buyer@example.com", "hash", false)
+	require.NoError(t, err)
+
+	bus := service.NewEventBus()
+	us := service.NewUserService(st)
+	us.SetEventBus(bus)
+	srv := httptest.NewServer(NewRouter(us, service.NewProductService(st), service.NewAuthService(st), WithEventBus(bus)))
+	defer srv.Close()
+	wsURL := "ws" + srv.URL[len("http"):] + "/ws"
+
+	// sem token
+	_, resp, err := ws.Dial(wsURL, nil)
+	require.ErrorIs(t, err, ws.ErrBadHandshake)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	token, err := MakeToken(seller.ID, time.Hour)
+	require.NoError(t, err)
+	// o token da sessão não vale mais na query (ficaria no log de acesso)
+	_, resp, err = ws.Dial(wsURL+"?access_token="+token, nil)
+	require.ErrorIs(t, err, ws.ErrBadHandshake)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	ticket := wsTicketFor(t, srv.URL, token)
+	c, _, err := ws.Dial(wsURL+"?ticket="+ticket, nil)
+	require.NoError(t, err)
+	defer c.Close()
+	// uso único
+	_, resp, err = ws.Dial(wsURL+"?ticket="+ticket, nil)
+	require.ErrorIs(t, err, ws.ErrBadHandshake)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	// fora do navegador o Bearer no header continua valendo
+	bearer, _, err := ws.Dial(wsURL, http.Header{"Authorization": {"Bearer " + token}})
+	require.NoError(t, err)
+	require.Equal(t, "hello", readWS(t, bearer).Type)
+	bearer.Close()
+
+	hello := readWS(t, c)
+	require.Equal(t, "hello", hello.Type)
+	require.Contains(t, hello.Topics, service.EventNewFollower)
+
+	// antes do subscribe nada é entregue
+	require.NoError(t, us.Follow(buyer.ID, seller.ID))
+	require.NoError(t, c.WriteJSON(wsMessage{Type: "ping"}))
+	require.Equal(t, "pong", readWS(t, c).Type)
+
+	require.NoError(t, c.WriteJSON(wsMessage{Type: "subscribe", Topics: []string{"nope"}}))
+	require.Equal(t, "error", readWS(t, c).Type)
+	require.NoError(t, c.WriteJSON(wsMessage{Type: "subscribe"}))
+	sub := readWS(t, c)
+	require.Equal(t, "subscribed", sub.Type)
+	require.Equal(t, []string{service.EventNewFollower, service.EventNotification}, sub.Topics)
+
+	require.NoError(t, us.Unfollow(buyer.ID, seller.ID))
+	require.NoError(t, us.Follow(buyer.ID, seller.ID))
+	ev := readWS(t, c)
+	require.Equal(t, "event", ev.Type)
+	require.NotZero(t, ev.ID)
+	require.Equal(t, service.EventNewFollower, ev.Event.Type)
+	require.Equal(t, seller.ID, ev.Event.UserID)
+	require.Equal(t, float64(buyer.ID), ev.Event.Data.(map[string]any)["follower_id"])
+
+	require.NoError(t, c.WriteJSON(wsMessage{Type: "ack", ID: ev.ID}))
+	require.NoError(t, c.WriteJSON(wsMessage{Type: "ack", ID: ev.ID}))
+	require.Equal(t, "ack desconhecido", readWS(t, c).Error)
+
+	// evento de outro usuário não chega aqui
+	bus.Publish(service.Event{Type: service.EventNewFollower, UserID: buyer.ID})
+	require.NoError(t, c.WriteJSON(wsMessage{Type: "ping"}))
+	require.Equal(t, "pong", readWS(t, c).Type)
+
+	require.NoError(t, c.WriteClose(ws.CloseNormal, ""))
+	var ce *ws.CloseError
+	c.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = c.ReadMessage()
+	require.True(t, errors.As(err, &ce))
+}
+
+func TestWSClient_Backpressure(t *testing.T) {
+	ev := service.Event{Type: service.EventNewFollower, UserID: 1}
+
+	// writer parado: estoura o buffer de envio
+	slow := newWSClient(1, nil)
+	slow.topics[service.EventNewFollower] = true
+	for i := 0; i <= wsSendBuffer; i++ {
+		slow.deliver(ev)
+	}
+	<-slow.done
+	require.Equal(t, ws.CloseTryAgainLater, slow.closeCode)
+
+	// writer em dia, mas sem acks
+	stuck := newWSClient(1, nil)
+	stuck.topics[service.EventNewFollower] = true
+	for i := 0; i <= wsMaxUnacked; i++ {
+		stuck.deliver(ev)
+		select {
+		case <-stuck.send:
+		default:
+		}
+	}
+	<-stuck.done
+	require.Equal(t, ws.ClosePolicyViolation, stuck.closeCode)
+}
+
+func TestWSRegistry_TracksConnections(t *testing.T) {
+	reg := newWSRegistry()
+	a, b := newWSClient(1, nil), newWSClient(1, nil)
+	reg.add(a)
+	reg.add(b)
+	require.Equal(t, 2, reg.count(1))
+	reg.remove(a)
+	reg.remove(b)
+	require.Equal(t, 0, reg.count(1))
+}
+
+func TestWSTickets_Expire(t *testing.T) {
+	tickets := &wsTickets{m: map[string]wsTicket{}}
+	now := time.Now()
+	id, err := tickets.issue(7, now)
+	require.NoError(t, err)
+	_, ok := tickets.redeem(id, now.Add(wsTicketTTL))
+	require.False(t, ok)
+
+	id, _ = tickets.issue(7, now)
+	uid, ok := tickets.redeem(id, now.Add(time.Second))
+	require.True(t, ok)
+	require.Equal(t, 7, uid)
+}
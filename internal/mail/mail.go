@@ -0,0 +1,137 @@
+// Package mail envia e-mails da aplicação (hoje só o resumo semanal).
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var ErrNoRecipient = errors.New("e-mail sem destinatário")
+
+// Message é um e-mail com as versões texto e HTML do mesmo conteúdo.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer entrega mensagens. Implementações precisam aceitar chamadas concorrentes.
+type Mailer interface {
+	Send(m Message) error
+}
+
+// FileMailer grava cada mensagem como um arquivo .eml em dir, para desenvolvimento
+// local (abra no cliente de e-mail ou inspecione o texto).
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._@-]+`)
+
+func (f *FileMailer) Send(m Message) error {
+	if m.To == "" {
+		return ErrNoRecipient
+	}
+	data, err := Render(f.from, m, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+	// nome ordenável por data; o arquivo aparece inteiro (temporário + rename)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(m.To, "_"))
+	tmp, err := os.CreateTemp(f.dir, ".mail-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(f.dir, name))
+}
+
+// Render monta a mensagem no formato RFC 5322 (multipart/alternative, texto antes do HTML).
+func Render(from string, m Message, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ ctype, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.ctype},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: %s\r\n", from)
+	fmt.Fprintf(&out, "To: %s\r\n", m.To)
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&out, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	out.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&out, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// MemoryMailer guarda as mensagens enviadas (testes).
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewMemoryMailer() *MemoryMailer { return &MemoryMailer{} }
+
+func (m *MemoryMailer) Send(msg Message) error {
+	if msg.To == "" {
+		return ErrNoRecipient
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent devolve uma cópia das mensagens, na ordem de envio.
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
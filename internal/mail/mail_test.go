@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender_MultipartAlternative(t *testing.T) {
+	m := Message{To: "ana@example.com", Subject: "Promoções da semana", Text: "Olá, Ana", HTML: "<p>Olá, Ana</p>"}
+	data, err := Render("noreply@socialmeli.local", m, time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != m.Subject || msg.Header.Get("To") != m.To {
+		t.Fatalf("unexpected headers: %v", msg.Header)
+	}
+	mt, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mt != "multipart/alternative" {
+		t.Fatalf("unexpected content type %q (%v)", mt, err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		p, err := mr.NextPart() // decodifica o quoted-printable
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("part: %v", err)
+		}
+		b, _ := io.ReadAll(p)
+		parts = append(parts, string(b))
+	}
+	if len(parts) != 2 || parts[0] != m.Text || parts[1] != m.HTML {
+		t.Fatalf("unexpected parts %q", parts)
+	}
+}
+
+func TestFileMailer_DropsEML(t *testing.T) {
+	dir := t.TempDir()
+	fm := NewFileMailer(dir, "noreply@socialmeli.local")
+	if err := fm.Send(Message{Subject: "x"}); err != ErrNoRecipient {
+		t.Fatalf("expected ErrNoRecipient, got %v", err)
+	}
+	if err := fm.Send(Message{To: "ana@example.com", Subject: "x", Text: "a", HTML: "<b>a</b>"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), "-ana@example.com.eml") {
+		t.Fatalf("expected one .eml file, got %v", entries)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if !bytes.Contains(data, []byte("To: ana@example.com\r\n")) {
+		t.Fatalf("unexpected file:\n%s", data)
+	}
+}
@@ -0,0 +1,102 @@
+package media
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// jpegOrientation procura a tag Orientation (0x0112) no segmento APP1/Exif.
+// Retorna 1 (normal) se não houver EXIF ou se estiver malformado.
+func jpegOrientation(data []byte) int {
+	i := 2 // pula SOI
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // SOS/EOI: acabaram os cabeçalhos
+			return 1
+		}
+		segLen := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if segLen < 2 || i+2+segLen > len(data) {
+			return 1
+		}
+		seg := data[i+4 : i+2+segLen]
+		if marker == 0xE1 && len(seg) > 6 && string(seg[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(seg[6:])
+		}
+		i += 2 + segLen
+	}
+	return 1
+}
+
+func tiffOrientation(t []byte) int {
+	if len(t) < 8 {
+		return 1
+	}
+	var bo binary.ByteOrder
+	switch string(t[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(bo.Uint32(t[4:8]))
+	if ifd+2 > len(t) {
+		return 1
+	}
+	n := int(bo.Uint16(t[ifd : ifd+2]))
+	for k := 0; k < n; k++ {
+		e := ifd + 2 + k*12
+		if e+12 > len(t) {
+			return 1
+		}
+		if bo.Uint16(t[e:e+2]) == 0x0112 {
+			v := int(bo.Uint16(t[e+8 : e+10]))
+			if v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation aplica as 8 orientações EXIF (espelhamentos/rotações).
+func applyOrientation(src image.Image, o int) image.Image {
+	if o <= 1 || o > 8 {
+		return src
+	}
+	s := toNRGBA(src)
+	w, h := s.Rect.Dx(), s.Rect.Dy()
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch o {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dy*dst.Stride+dx*4:dy*dst.Stride+dx*4+4], s.Pix[y*s.Stride+x*4:y*s.Stride+x*4+4])
+		}
+	}
+	return dst
+}
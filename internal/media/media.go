@@ -0,0 +1,165 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif" // registra o decoder de gif
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+var (
+	ErrNotImage       = errors.New("arquivo não é uma imagem válida")
+	ErrUnsupported    = errors.New("formato de imagem não suportado (use jpg, png, gif ou webp)")
+	ErrTooLarge       = errors.New("imagem muito grande")
+	ErrDimensionLimit = errors.New("dimensões da imagem excedem o limite")
+)
+
+// Format é o tipo real detectado pelos magic bytes (nunca pela extensão).
+type Format string
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatGIF  Format = "gif"
+	FormatWEBP Format = "webp"
+)
+
+// Size identifica um dos tamanhos normalizados gerados no upload.
+type Size string
+
+const (
+	SizeThumb  Size = "thumb"
+	SizeMedium Size = "medium"
+	SizeLarge  Size = "large"
+)
+
+// Sizes lista os tamanhos gerados, com o lado maior (px) de cada um.
+var Sizes = []struct {
+	Name Size
+	Max  int
+}{
+	{SizeThumb, 200},
+	{SizeMedium, 640},
+	{SizeLarge, 1280},
+}
+
+// limite de pixels decodificados (evita "decompression bombs")
+const maxPixels = 40_000_000
+
+// Variant é uma versão re-encodada (sem metadados) de um tamanho.
+type Variant struct {
+	Size        Size
+	Data        []byte
+	Ext         string
+	ContentType string
+	Width       int
+	Height      int
+}
+
+type Result struct {
+	Format   Format
+	Variants []Variant
+}
+
+// Sniff detecta o formato pelos primeiros bytes do arquivo.
+func Sniff(data []byte) (Format, error) {
+	switch {
+	case len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF:
+		return FormatJPEG, nil
+	case len(data) >= 8 && bytes.Equal(data[:8], []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}):
+		return FormatPNG, nil
+	case len(data) >= 6 && (string(data[:6]) == "GIF87a" || string(data[:6]) == "GIF89a"):
+		return FormatGIF, nil
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return FormatWEBP, nil
+	}
+	return "", ErrNotImage
+}
+
+// ContentTypeFormat mapeia o Content-Type declarado pelo cliente para o formato aceito.
+func ContentTypeFormat(ct string) (Format, bool) {
+	switch ct {
+	case "image/jpeg":
+		return FormatJPEG, true
+	case "image/png":
+		return FormatPNG, true
+	case "image/gif":
+		return FormatGIF, true
+	case "image/webp":
+		return FormatWEBP, true
+	}
+	return "", false
+}
+
+// Process lê até maxBytes, valida o conteúdo e gera as variantes normalizadas.
+// Como tudo é re-encodado a partir dos pixels, EXIF e demais metadados são descartados;
+// a orientação EXIF (JPEG) é aplicada antes para a imagem não ficar "deitada".
+func Process(r io.Reader, maxBytes int64) (Result, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return Result{}, err
+	}
+	if int64(len(data)) > maxBytes {
+		return Result{}, ErrTooLarge
+	}
+
+	format, err := Sniff(data)
+	if err != nil {
+		return Result{}, err
+	}
+	if format == FormatWEBP {
+		// a stdlib não decodifica webp: guarda como veio, só sem metadados
+		return processWebP(data)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Result{}, ErrNotImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return Result{}, ErrDimensionLimit
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Result{}, ErrNotImage
+	}
+	if format == FormatJPEG {
+		src = applyOrientation(src, jpegOrientation(data))
+	}
+
+	out := Result{Format: format}
+	for _, s := range Sizes {
+		img := fit(src, s.Max)
+		v, err := encode(img, format)
+		if err != nil {
+			return Result{}, err
+		}
+		v.Size = s.Name
+		out.Variants = append(out.Variants, v)
+	}
+	return out, nil
+}
+
+// encode mantém JPEG como JPEG; PNG e GIF viram PNG (preserva transparência).
+func encode(img image.Image, format Format) (Variant, error) {
+	var buf bytes.Buffer
+	b := img.Bounds()
+	v := Variant{Width: b.Dx(), Height: b.Dy()}
+	if format == FormatJPEG {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return Variant{}, err
+		}
+		v.Ext, v.ContentType = ".jpg", "image/jpeg"
+	} else {
+		if err := png.Encode(&buf, img); err != nil {
+			return Variant{}, err
+		}
+		v.Ext, v.ContentType = ".png", "image/png"
+	}
+	v.Data = buf.Bytes()
+	return v, nil
+}
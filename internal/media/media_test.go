@@ -0,0 +1,191 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// jpegWithOrientation gera um JPEG w x h com um segmento APP1/Exif contendo a orientação.
+func jpegWithOrientation(t *testing.T, w, h int, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)), nil))
+	raw := buf.Bytes()
+
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	ifd := make([]byte, 2+12+4)
+	binary.LittleEndian.PutUint16(ifd[0:], 1)
+	binary.LittleEndian.PutUint16(ifd[2:], 0x0112)
+	binary.LittleEndian.PutUint16(ifd[4:], 3) // SHORT
+	binary.LittleEndian.PutUint32(ifd[6:], 1)
+	binary.LittleEndian.PutUint16(ifd[10:], orientation)
+	payload := append([]byte("Exif\x00\x00"), append(tiff, ifd...)...)
+
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	seg = append(seg, payload...)
+
+	out := append([]byte{}, raw[:2]...)
+	out = append(out, seg...)
+	return append(out, raw[2:]...)
+}
+
+func TestSniff(t *testing.T) {
+	f, err := Sniff([]byte{0xFF, 0xD8, 0xFF, 0xE0})
+	require.NoError(t, err)
+	require.Equal(t, FormatJPEG, f)
+
+	f, err = Sniff([]byte("GIF89a..."))
+	require.NoError(t, err)
+	require.Equal(t, FormatGIF, f)
+
+	f, err = Sniff([]byte("RIFF\x00\x00\x00\x00WEBPVP8 "))
+	require.NoError(t, err)
+	require.Equal(t, FormatWEBP, f)
+
+	_, err = Sniff([]byte("MZ\x90\x00 not an image"))
+	require.ErrorIs(t, err, ErrNotImage)
+}
+
+func TestProcess_GeneratesSizes(t *testing.T) {
+	res, err := Process(bytes.NewReader(pngBytes(t, 1600, 800)), 4<<20)
+	require.NoError(t, err)
+	require.Equal(t, FormatPNG, res.Format)
+	require.Len(t, res.Variants, 3)
+
+	want := map[Size][2]int{SizeThumb: {200, 100}, SizeMedium: {640, 320}, SizeLarge: {1280, 640}}
+	for _, v := range res.Variants {
+		require.Equal(t, ".png", v.Ext)
+		require.Equal(t, want[v.Size], [2]int{v.Width, v.Height}, v.Size)
+		cfg, err := png.DecodeConfig(bytes.NewReader(v.Data))
+		require.NoError(t, err)
+		require.Equal(t, v.Width, cfg.Width)
+	}
+}
+
+func TestProcess_DoesNotUpscale(t *testing.T) {
+	res, err := Process(bytes.NewReader(pngBytes(t, 50, 30)), 4<<20)
+	require.NoError(t, err)
+	for _, v := range res.Variants {
+		require.Equal(t, 50, v.Width)
+		require.Equal(t, 30, v.Height)
+	}
+}
+
+func TestProcess_StripsExifAndAppliesOrientation(t *testing.T) {
+	data := jpegWithOrientation(t, 40, 20, 6)
+	require.Equal(t, 6, jpegOrientation(data))
+
+	res, err := Process(bytes.NewReader(data), 4<<20)
+	require.NoError(t, err)
+	for _, v := range res.Variants {
+		require.Equal(t, ".jpg", v.Ext)
+		require.False(t, bytes.Contains(v.Data, []byte("Exif")))
+		// orientação 6 = rotação de 90°, então largura e altura trocam
+		require.Equal(t, 20, v.Width)
+		require.Equal(t, 40, v.Height)
+	}
+}
+
+func TestProcess_Rejections(t *testing.T) {
+	_, err := Process(bytes.NewReader([]byte("just text")), 1<<20)
+	require.ErrorIs(t, err, ErrNotImage)
+
+	_, err = Process(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00WEBPVP8 ")), 1<<20)
+	require.ErrorIs(t, err, ErrNotImage)
+
+	// magic bytes de PNG mas conteúdo truncado
+	_, err = Process(bytes.NewReader([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n', 0, 0}), 1<<20)
+	require.ErrorIs(t, err, ErrNotImage)
+
+	_, err = Process(bytes.NewReader(pngBytes(t, 10, 10)), 10)
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+// webpChunk monta um chunk RIFF (id de 4 bytes, tamanho LE, payload com padding par).
+func webpChunk(id string, payload []byte) []byte {
+	out := append([]byte(id), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(payload)))
+	out = append(out, payload...)
+	if len(payload)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+func webpFile(chunks ...[]byte) []byte {
+	body := []byte("WEBP")
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+	out := append([]byte("RIFF"), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(body)))
+	return append(out, body...)
+}
+
+// vp8l é o cabeçalho de um bitstream lossless w x h (o resto não é lido).
+func vp8l(w, h int) []byte {
+	p := []byte{0x2f, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(p[1:], uint32(w-1)|uint32(h-1)<<14)
+	return p
+}
+
+func TestProcess_WebPStoredAsIsWithoutMetadata(t *testing.T) {
+	vp8x := make([]byte, 10)
+	vp8x[0] = vp8xFlagEXIF | vp8xFlagXMP
+	vp8x[4], vp8x[7] = 99, 49 // canvas 100 x 50 (valores - 1)
+	data := webpFile(
+		webpChunk("VP8X", vp8x),
+		webpChunk("VP8L", vp8l(100, 50)),
+		webpChunk("EXIF", []byte("Exif\x00\x00GPS...")),
+		webpChunk("XMP ", []byte("<x:xmpmeta/>")),
+	)
+
+	res, err := Process(bytes.NewReader(data), 4<<20)
+	require.NoError(t, err)
+	require.Equal(t, FormatWEBP, res.Format)
+	require.Len(t, res.Variants, 3)
+	for _, v := range res.Variants {
+		require.Equal(t, ".webp", v.Ext)
+		require.Equal(t, "image/webp", v.ContentType)
+		require.Equal(t, [2]int{100, 50}, [2]int{v.Width, v.Height})
+		require.False(t, bytes.Contains(v.Data, []byte("Exif")))
+		require.False(t, bytes.Contains(v.Data, []byte("xmpmeta")))
+		require.Equal(t, byte(0), v.Data[20]&(vp8xFlagEXIF|vp8xFlagXMP))
+		require.Equal(t, uint32(len(v.Data)-8), binary.LittleEndian.Uint32(v.Data[4:8]))
+	}
+
+	// sem VP8X as dimensões vêm do bitstream
+	res, err = Process(bytes.NewReader(webpFile(webpChunk("VP8L", vp8l(30, 20)))), 4<<20)
+	require.NoError(t, err)
+	require.Equal(t, 30, res.Variants[0].Width)
+
+	_, err = Process(bytes.NewReader(webpFile(webpChunk("VP8L", vp8l(10000, 10000)))), 4<<20)
+	require.ErrorIs(t, err, ErrDimensionLimit)
+	_, err = Process(bytes.NewReader(webpFile(webpChunk("VP8L", []byte{0x00, 1, 2, 3, 4}))), 4<<20)
+	require.ErrorIs(t, err, ErrNotImage)
+
+	f, ok := ContentTypeFormat("image/webp")
+	require.True(t, ok)
+	require.Equal(t, FormatWEBP, f)
+}
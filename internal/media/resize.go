@@ -0,0 +1,82 @@
+package media
+
+import (
+	"image"
+	"image/draw"
+)
+
+// fit reduz a imagem para caber num quadrado max x max mantendo a proporção.
+// Nunca amplia: imagens menores apenas são copiadas (e re-encodadas depois).
+func fit(src image.Image, max int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= max && h <= max {
+		return toNRGBA(src)
+	}
+	nw, nh := max, max
+	if w >= h {
+		nh = h * max / w
+	} else {
+		nw = w * max / h
+	}
+	if nw < 1 {
+		nw = 1
+	}
+	if nh < 1 {
+		nh = 1
+	}
+	return boxResize(toNRGBA(src), nw, nh)
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if n, ok := src.(*image.NRGBA); ok && n.Rect.Min == (image.Point{}) {
+		return n
+	}
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Rect, src, b.Min, draw.Src)
+	return dst
+}
+
+// boxResize faz média de área: cada pixel destino é a média dos pixels origem que cobre.
+// É simples e evita o serrilhado de nearest-neighbor em reduções grandes.
+func boxResize(src *image.NRGBA, nw, nh int) *image.NRGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, nw, nh))
+	for y := 0; y < nh; y++ {
+		y0 := y * sh / nh
+		y1 := (y + 1) * sh / nh
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < nw; x++ {
+			x0 := x * sw / nw
+			x1 := (x + 1) * sw / nw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					// pondera pelo alpha para não "escurecer" bordas transparentes
+					pa := uint64(p[3])
+					r += uint64(p[0]) * pa
+					g += uint64(p[1]) * pa
+					bl += uint64(p[2]) * pa
+					a += pa
+					n++
+				}
+			}
+			o := dst.Pix[y*dst.Stride+x*4:]
+			if a > 0 {
+				o[0] = uint8(r / a)
+				o[1] = uint8(g / a)
+				o[2] = uint8(bl / a)
+			}
+			o[3] = uint8(a / n)
+		}
+	}
+	return dst
+}
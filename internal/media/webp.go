@@ -0,0 +1,99 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// flags do chunk VP8X com metadados
+const (
+	vp8xFlagEXIF = 0x08
+	vp8xFlagXMP  = 0x04
+)
+
+// processWebP trata WebP sem decodificar (a stdlib não tem decoder): confere o
+// container RIFF, lê as dimensões do cabeçalho e remove os chunks EXIF e XMP.
+// Os pixels ficam como vieram, então os três tamanhos são o mesmo arquivo.
+func processWebP(data []byte) (Result, error) {
+	clean, w, h, err := stripWebP(data)
+	if err != nil {
+		return Result{}, err
+	}
+	if w <= 0 || h <= 0 || w*h > maxPixels {
+		return Result{}, ErrDimensionLimit
+	}
+	out := Result{Format: FormatWEBP}
+	for _, s := range Sizes {
+		out.Variants = append(out.Variants, Variant{
+			Size: s.Name, Data: clean, Ext: ".webp", ContentType: "image/webp", Width: w, Height: h,
+		})
+	}
+	return out, nil
+}
+
+// stripWebP percorre os chunks, copia todos menos EXIF/XMP e devolve as dimensões
+// do canvas (VP8X) ou do bitstream (VP8/VP8L).
+func stripWebP(data []byte) ([]byte, int, int, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, 0, 0, ErrNotImage
+	}
+	var out bytes.Buffer
+	out.Write(data[:12])
+	w, h, vp8x := 0, 0, -1
+	for p := 12; p < len(data); {
+		if p+8 > len(data) {
+			return nil, 0, 0, ErrNotImage
+		}
+		id := string(data[p : p+4])
+		size := int(binary.LittleEndian.Uint32(data[p+4 : p+8]))
+		end := p + 8 + size
+		if size < 0 || end > len(data) {
+			return nil, 0, 0, ErrNotImage
+		}
+		payload := data[p+8 : end]
+		if size%2 == 1 && end < len(data) {
+			end++ // padding
+		}
+		switch id {
+		case "EXIF", "XMP ":
+			p = end
+			continue
+		case "VP8X":
+			if len(payload) < 10 {
+				return nil, 0, 0, ErrNotImage
+			}
+			vp8x = out.Len() + 8
+			w = 1 + (int(payload[4]) | int(payload[5])<<8 | int(payload[6])<<16)
+			h = 1 + (int(payload[7]) | int(payload[8])<<8 | int(payload[9])<<16)
+		case "VP8 ":
+			if w == 0 {
+				// frame tag (3 bytes) + start code 9d 01 2a + largura/altura (14 bits)
+				if len(payload) < 10 || payload[3] != 0x9d || payload[4] != 0x01 || payload[5] != 0x2a {
+					return nil, 0, 0, ErrNotImage
+				}
+				w = int(binary.LittleEndian.Uint16(payload[6:8]) & 0x3fff)
+				h = int(binary.LittleEndian.Uint16(payload[8:10]) & 0x3fff)
+			}
+		case "VP8L":
+			if w == 0 {
+				if len(payload) < 5 || payload[0] != 0x2f {
+					return nil, 0, 0, ErrNotImage
+				}
+				bits := binary.LittleEndian.Uint32(payload[1:5])
+				w = 1 + int(bits&0x3fff)
+				h = 1 + int(bits>>14&0x3fff)
+			}
+		}
+		out.Write(data[p:end])
+		p = end
+	}
+	if w == 0 {
+		return nil, 0, 0, ErrNotImage
+	}
+	clean := out.Bytes()
+	if vp8x >= 0 {
+		clean[vp8x] &^= vp8xFlagEXIF | vp8xFlagXMP
+	}
+	binary.LittleEndian.PutUint32(clean[4:8], uint32(len(clean)-8))
+	return clean, w, h, nil
+}
@@ -0,0 +1,170 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Tokens que controlam o resultado no provedor falso. Qualquer outro token aprova.
+const (
+	TokenApprove = "tok_approve"
+	TokenDecline = "tok_decline"
+	TokenDelayed = "tok_delayed" // fica pending até Confirm (ou ConfirmAfter)
+)
+
+// Fake simula um provedor: aprova, recusa ou deixa pendente conforme o token, e
+// gera callbacks assinados como o provedor real faria.
+type Fake struct {
+	secret string
+
+	mu       sync.Mutex
+	payments map[string]Payment
+	byRef    map[string]string
+	seq      int
+	events   int
+
+	// callback recebe os callbacks assinados (em produção seria um POST no nosso webhook).
+	callback func(body []byte, signature string)
+	// confirmAfter > 0 aprova sozinho os pagamentos pendentes depois desse tempo.
+	confirmAfter time.Duration
+}
+
+func NewFake(secret string) *Fake {
+	return &Fake{secret: secret, payments: map[string]Payment{}, byRef: map[string]string{}}
+}
+
+// SetCallback define para onde vão os callbacks e, com after > 0, faz os pagamentos
+// pendentes serem aprovados sozinhos depois de after.
+func (f *Fake) SetCallback(fn func(body []byte, signature string), after time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.callback, f.confirmAfter = fn, after
+}
+
+func (f *Fake) Authorize(req AuthorizeRequest) (Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// mesma referência devolve o mesmo pagamento, a não ser que ele tenha sido recusado
+	if id, ok := f.byRef[req.Reference]; ok && f.payments[id].Status != StatusDeclined {
+		return f.payments[id], nil
+	}
+	f.seq++
+	p := Payment{
+		ID:        fmt.Sprintf("pay_%06d", f.seq),
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Status:    StatusAuthorized,
+		CreatedAt: time.Now().UTC(),
+	}
+	switch req.Token {
+	case TokenDecline:
+		p.Status = StatusDeclined
+	case TokenDelayed:
+		p.Status = StatusPending
+	}
+	f.payments[p.ID] = p
+	f.byRef[p.Reference] = p.ID
+	if p.Status == StatusDeclined {
+		return p, ErrDeclined
+	}
+	if p.Status == StatusPending && f.confirmAfter > 0 {
+		id := p.ID
+		time.AfterFunc(f.confirmAfter, func() { _, _, _ = f.Confirm(id, true) })
+	}
+	return p, nil
+}
+
+func (f *Fake) Capture(paymentID string) (Payment, error) {
+	return f.move(paymentID, StatusAuthorized, StatusCaptured)
+}
+
+func (f *Fake) Refund(paymentID string) (Payment, error) {
+	p, err := f.move(paymentID, StatusCaptured, StatusRefunded)
+	if err != nil {
+		return p, err
+	}
+	f.emit(EventRefunded, p)
+	return p, nil
+}
+
+// Void cancela a reserva de um pagamento autorizado e ainda não capturado.
+func (f *Fake) Void(paymentID string) (Payment, error) {
+	p, err := f.move(paymentID, StatusAuthorized, StatusVoided)
+	if err != nil {
+		return p, err
+	}
+	f.emit(EventVoided, p)
+	return p, nil
+}
+
+func (f *Fake) move(paymentID, from, to string) (Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payments[paymentID]
+	if !ok {
+		return Payment{}, ErrUnknownPayment
+	}
+	if p.Status == to {
+		return p, nil // repetir a operação não cobra/estorna de novo
+	}
+	if p.Status != from {
+		return p, ErrInvalidState
+	}
+	p.Status = to
+	f.payments[paymentID] = p
+	return p, nil
+}
+
+// Confirm resolve um pagamento pendente (aprovado ou recusado) e devolve o callback
+// assinado que o provedor mandaria; ele também vai para o callback configurado.
+func (f *Fake) Confirm(paymentID string, approve bool) (body []byte, signature string, err error) {
+	to, event := StatusAuthorized, EventAuthorized
+	if !approve {
+		to, event = StatusDeclined, EventDeclined
+	}
+	p, err := f.move(paymentID, StatusPending, to)
+	if err != nil {
+		return nil, "", err
+	}
+	body, signature = f.emit(event, p)
+	return body, signature, nil
+}
+
+// Get devolve o pagamento como o provedor o vê.
+func (f *Fake) Get(paymentID string) (Payment, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payments[paymentID]
+	return p, ok
+}
+
+// emit monta, assina e entrega um callback.
+func (f *Fake) emit(eventType string, p Payment) ([]byte, string) {
+	f.mu.Lock()
+	f.events++
+	ev := Event{ID: fmt.Sprintf("evt_%06d", f.events), Type: eventType, Payment: p, CreatedAt: time.Now().UTC()}
+	cb := f.callback
+	f.mu.Unlock()
+
+	body, _ := json.Marshal(ev)
+	sig := Sign(f.secret, body)
+	if cb != nil {
+		cb(body, sig)
+	}
+	return body, sig
+}
+
+// VerifyWebhook confere a assinatura e decodifica o evento.
+func (f *Fake) VerifyWebhook(body []byte, signature string) (Event, error) {
+	if !ValidSignature(f.secret, body, signature) {
+		return Event{}, ErrInvalidSignature
+	}
+	var ev Event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return Event{}, err
+	}
+	return ev, nil
+}
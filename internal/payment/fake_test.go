@@ -0,0 +1,83 @@
+package payment
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake_ApproveDeclineAndRefund(t *testing.T) {
+	f := NewFake("secret")
+
+	p, err := f.Authorize(AuthorizeRequest{Reference: "order-1", Amount: 10_00, Token: TokenApprove})
+	if err != nil || p.Status != StatusAuthorized {
+		t.Fatalf("authorize: %+v %v", p, err)
+	}
+	// mesma referência não cria outro pagamento
+	if again, _ := f.Authorize(AuthorizeRequest{Reference: "order-1", Amount: 10}); again.ID != p.ID {
+		t.Fatalf("expected idempotent authorize, got %+v", again)
+	}
+	if _, err := f.Refund(p.ID); err != ErrInvalidState {
+		t.Fatalf("expected ErrInvalidState refunding before capture, got %v", err)
+	}
+	if p, _ = f.Capture(p.ID); p.Status != StatusCaptured {
+		t.Fatalf("capture: %+v", p)
+	}
+	if p, _ = f.Capture(p.ID); p.Status != StatusCaptured {
+		t.Fatalf("second capture must be a no-op: %+v", p)
+	}
+	if p, _ = f.Refund(p.ID); p.Status != StatusRefunded {
+		t.Fatalf("refund: %+v", p)
+	}
+	if _, err := f.Void(p.ID); err != ErrInvalidState {
+		t.Fatalf("expected ErrInvalidState voiding a refunded payment, got %v", err)
+	}
+	v, _ := f.Authorize(AuthorizeRequest{Reference: "order-3", Amount: 10_00, Token: TokenApprove})
+	if v, _ = f.Void(v.ID); v.Status != StatusVoided {
+		t.Fatalf("void: %+v", v)
+	}
+	if _, err := f.Capture(v.ID); err != ErrInvalidState {
+		t.Fatalf("expected ErrInvalidState capturing a voided payment, got %v", err)
+	}
+
+	d, err := f.Authorize(AuthorizeRequest{Reference: "order-2", Amount: 10_00, Token: TokenDecline})
+	if err != ErrDeclined || d.Status != StatusDeclined {
+		t.Fatalf("expected decline, got %+v %v", d, err)
+	}
+	// depois de recusado, tentar de novo com outro meio gera outro pagamento
+	if retry, err := f.Authorize(AuthorizeRequest{Reference: "order-2", Amount: 10}); err != nil || retry.ID == d.ID {
+		t.Fatalf("retry: %+v %v", retry, err)
+	}
+	if _, err := f.Capture("pay_x"); err != ErrUnknownPayment {
+		t.Fatalf("expected ErrUnknownPayment, got %v", err)
+	}
+}
+
+func TestFake_DelayedConfirmationAndSignature(t *testing.T) {
+	f := NewFake("secret")
+	got := make(chan []byte, 1)
+	f.SetCallback(func(body []byte, signature string) {
+		if ev, err := f.VerifyWebhook(body, signature); err != nil || ev.Type != EventAuthorized {
+			t.Errorf("callback: %+v %v", ev, err)
+		}
+		got <- body
+	}, 10*time.Millisecond)
+
+	p, err := f.Authorize(AuthorizeRequest{Reference: "order-3", Amount: 10_00, Token: TokenDelayed})
+	if err != nil || p.Status != StatusPending {
+		t.Fatalf("authorize: %+v %v", p, err)
+	}
+	select {
+	case body := <-got:
+		if _, err := f.VerifyWebhook(body, "sha256=00"); err != ErrInvalidSignature {
+			t.Fatalf("expected ErrInvalidSignature, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no callback for delayed payment")
+	}
+	if p, _ = f.Get(p.ID); p.Status != StatusAuthorized {
+		t.Fatalf("expected authorized after confirmation, got %+v", p)
+	}
+	if _, _, err := f.Confirm(p.ID, false); err != ErrInvalidState {
+		t.Fatalf("expected ErrInvalidState confirming twice, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+// Package payment tem os tipos trocados com o provedor de pagamentos e um
+// provedor falso, todo local, para desenvolvimento e testes ponta a ponta.
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+var (
+	ErrDeclined         = errors.New("Pagamento recusado.")
+	ErrUnknownPayment   = errors.New("Pagamento inexistente.")
+	ErrInvalidState     = errors.New("Operação não permitida no estado atual do pagamento.")
+	ErrInvalidSignature = errors.New("Assinatura do callback inválida.")
+)
+
+// Estados de um pagamento.
+const (
+	StatusPending    = "pending"    // aguardando confirmação assíncrona do provedor
+	StatusAuthorized = "authorized" // valor reservado, falta capturar
+	StatusCaptured   = "captured"   // cobrado
+	StatusDeclined   = "declined"   // recusado
+	StatusRefunded   = "refunded"   // estornado
+	StatusVoided     = "voided"     // reserva cancelada sem cobrar
+)
+
+// Eventos enviados pelo provedor nos callbacks.
+const (
+	EventAuthorized = "payment.authorized" // confirmação atrasada aprovou o pagamento
+	EventDeclined   = "payment.declined"   // confirmação atrasada recusou o pagamento
+	EventRefunded   = "payment.refunded"
+	EventVoided     = "payment.voided"
+)
+
+// SignatureHeader é o header em que o provedor manda a assinatura do callback.
+const SignatureHeader = "X-Payment-Signature"
+
+// AuthorizeRequest pede a reserva de Amount. Reference identifica a compra do
+// nosso lado (ex.: "order-12") e funciona como chave de idempotência.
+type AuthorizeRequest struct {
+	Reference string
+	Amount    domain.Money
+	Currency  string // ISO 4217
+	Token     string // meio de pagamento tokenizado no cliente
+}
+
+type Payment struct {
+	ID        string       `json:"id"`
+	Reference string       `json:"reference"`
+	Amount    domain.Money `json:"amount"`
+	Currency  string       `json:"currency"`
+	Status    string       `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Event é o corpo de um callback. ID é único por evento: entregas repetidas do
+// mesmo evento trazem o mesmo ID.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Payment   Payment   `json:"payment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Sign assina o corpo de um callback (HMAC-SHA256 em hex com o segredo compartilhado).
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidSignature compara em tempo constante.
+func ValidSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// CommentService cuida das perguntas e respostas nos posts. Perguntas são
+// comentários de topo; respostas ficam um nível abaixo e o vendedor (dono do
+// post) pode marcar uma delas como a resposta da pergunta.
+type CommentService struct {
+	st       store.Store
+	now      func() time.Time
+	notifier *NotificationService
+}
+
+func NewCommentService(st store.Store) *CommentService {
+	return &CommentService{st: st, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// SetNotifications faz comentários novos notificarem o dono do post (e, nas
+// respostas, o autor da pergunta).
+func (s *CommentService) SetNotifications(n *NotificationService) { s.notifier = n }
+
+// Add publica uma pergunta (parentID 0) ou uma resposta à pergunta parentID.
+func (s *CommentService) Add(userID, postID, parentID int, text string) (domain.Comment, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.Comment{}, err
+	}
+	if err := domain.ValidateID(postID); err != nil {
+		return domain.Comment{}, err
+	}
+	if _, ok := s.st.GetUser(userID); !ok {
+		return domain.Comment{}, store.ErrUserNotFound
+	}
+	text = domain.NormalizeCommentText(text)
+	if err := domain.ValidateCommentText(text); err != nil {
+		return domain.Comment{}, err
+	}
+	if parentID != 0 {
+		parent, ok := s.st.GetComment(parentID)
+		if !ok || parent.PostID != postID {
+			return domain.Comment{}, store.ErrCommentNotFound
+		}
+		if parent.ParentID != 0 {
+			return domain.Comment{}, domain.ErrCommentReplyDepth
+		}
+	}
+	c, err := s.st.AddComment(domain.Comment{PostID: postID, UserID: userID, ParentID: parentID, Text: text})
+	if err == nil && s.notifier != nil {
+		logNotifyErr(s.notifier.CommentAdded(c))
+	}
+	return c, err
+}
+
+// comment busca o comentário garantindo que pertence ao post da rota.
+func (s *CommentService) comment(postID, commentID int) (domain.Comment, error) {
+	if err := domain.ValidateID(postID); err != nil {
+		return domain.Comment{}, err
+	}
+	if err := domain.ValidateID(commentID); err != nil {
+		return domain.Comment{}, err
+	}
+	c, ok := s.st.GetComment(commentID)
+	if !ok || c.PostID != postID {
+		return domain.Comment{}, store.ErrCommentNotFound
+	}
+	return c, nil
+}
+
+// Edit troca o texto do comentário; só o autor pode editar.
+func (s *CommentService) Edit(userID, postID, commentID int, text string) (domain.Comment, error) {
+	c, err := s.comment(postID, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if c.UserID != userID {
+		return domain.Comment{}, domain.ErrCommentForbidden
+	}
+	text = domain.NormalizeCommentText(text)
+	if err := domain.ValidateCommentText(text); err != nil {
+		return domain.Comment{}, err
+	}
+	return s.st.UpdateComment(commentID, text, s.now())
+}
+
+// Delete apaga o comentário (e as respostas, se for pergunta). Podem apagar o
+// autor e o dono do post.
+func (s *CommentService) Delete(userID, postID, commentID int) error {
+	c, err := s.comment(postID, commentID)
+	if err != nil {
+		return err
+	}
+	if c.UserID != userID {
+		post, ok := s.st.GetPost(postID)
+		if !ok {
+			return store.ErrPostNotFound
+		}
+		if post.UserID != userID {
+			return domain.ErrCommentForbidden
+		}
+	}
+	return s.st.DeleteComment(commentID)
+}
+
+// MarkAnswer marca (answer=true) ou desmarca a resposta commentID como a resposta
+// da pergunta. Só o dono do post pode; marcar outra resposta substitui a anterior.
+func (s *CommentService) MarkAnswer(userID, postID, commentID int, answer bool) (domain.Comment, error) {
+	c, err := s.comment(postID, commentID)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	post, ok := s.st.GetPost(postID)
+	if !ok {
+		return domain.Comment{}, store.ErrPostNotFound
+	}
+	if post.UserID != userID {
+		return domain.Comment{}, domain.ErrCommentAnswerOwner
+	}
+	if c.ParentID == 0 {
+		return domain.Comment{}, domain.ErrCommentNotReply
+	}
+	answerID := 0
+	if answer {
+		answerID = c.ID
+	} else if !c.IsAnswer {
+		return c, nil
+	}
+	if err := s.st.SetCommentAnswer(c.ParentID, answerID); err != nil {
+		return domain.Comment{}, err
+	}
+	c.IsAnswer = answer
+	return c, nil
+}
+
+// List pagina as perguntas do post (mais recentes primeiro) com as respostas. page começa em 1.
+func (s *CommentService) List(postID, page, limit int) ([]domain.Comment, int, error) {
+	if err := domain.ValidateID(postID); err != nil {
+		return nil, 0, err
+	}
+	return s.st.Comments(postID, limit, (page-1)*limit)
+}
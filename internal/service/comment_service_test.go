@@ -0,0 +1,81 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func TestCommentService_QAThread(t *testing.T) {
+	st, ps := feedStore(t)
+	publishAt(t, ps, 2, 0, 0)
+	cs := NewCommentService(st)
+
+	q, err := cs.Add(1, 1, 0, "  Tem no tamanho 42?\r\nObrigado!  ")
+	if err != nil || q.Text != "Tem no tamanho 42?\nObrigado!" || q.UserName != "Buyer" {
+		t.Fatalf("add: %+v %v", q, err)
+	}
+	if _, err := cs.Add(1, 1, 0, strings.Repeat("a", domain.MaxCommentLen+1)); err != domain.ErrMaxLen500 {
+		t.Fatalf("expected ErrMaxLen500, got %v", err)
+	}
+	if _, err := cs.Add(1, 99, 0, "oi"); err != store.ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+
+	other, _ := cs.Add(4, 1, q.ID, "Também quero saber")
+	answer, err := cs.Add(2, 1, q.ID, "Tem sim")
+	if err != nil || !answer.BySeller {
+		t.Fatalf("seller reply: %+v %v", answer, err)
+	}
+	if _, err := cs.Add(1, 1, answer.ID, "Valeu"); err != domain.ErrCommentReplyDepth {
+		t.Fatalf("expected ErrCommentReplyDepth, got %v", err)
+	}
+
+	// edição só pelo autor
+	if _, err := cs.Edit(2, 1, q.ID, "mudou"); err != domain.ErrCommentForbidden {
+		t.Fatalf("expected ErrCommentForbidden, got %v", err)
+	}
+	if c, err := cs.Edit(1, 1, q.ID, "Tem no 43?"); err != nil || c.EditedAt == nil {
+		t.Fatalf("edit: %+v %v", c, err)
+	}
+
+	// marcação só pelo vendedor e só em respostas
+	if _, err := cs.MarkAnswer(1, 1, answer.ID, true); err != domain.ErrCommentAnswerOwner {
+		t.Fatalf("expected ErrCommentAnswerOwner, got %v", err)
+	}
+	if _, err := cs.MarkAnswer(2, 1, q.ID, true); err != domain.ErrCommentNotReply {
+		t.Fatalf("expected ErrCommentNotReply, got %v", err)
+	}
+	if c, err := cs.MarkAnswer(2, 1, answer.ID, true); err != nil || !c.IsAnswer {
+		t.Fatalf("mark: %+v %v", c, err)
+	}
+	list, total, err := cs.List(1, 1, 10)
+	if err != nil || total != 1 || !list[0].Answered || len(list[0].Replies) != 2 {
+		t.Fatalf("list: %+v %d %v", list, total, err)
+	}
+	if _, err := cs.MarkAnswer(2, 1, answer.ID, false); err != nil {
+		t.Fatalf("unmark: %v", err)
+	}
+	if list, _, _ = cs.List(1, 1, 10); list[0].Answered {
+		t.Fatalf("question still answered after unmark")
+	}
+
+	// apagar: autor ou dono do post; ninguém mais
+	if err := cs.Delete(1, 1, other.ID); err != domain.ErrCommentForbidden {
+		t.Fatalf("expected ErrCommentForbidden, got %v", err)
+	}
+	if err := cs.Delete(2, 1, other.ID); err != nil {
+		t.Fatalf("owner delete: %v", err)
+	}
+	if err := cs.Delete(1, 1, q.ID); err != nil {
+		t.Fatalf("author delete: %v", err)
+	}
+	if _, total, _ := cs.List(1, 1, 10); total != 0 {
+		t.Fatalf("expected empty thread, got %d", total)
+	}
+	if err := cs.Delete(1, 2, answer.ID); err != store.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+}
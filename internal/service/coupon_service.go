@@ -0,0 +1,161 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// CouponInput são os campos que o vendedor escolhe ao criar um cupom.
+type CouponInput struct {
+	Code     string       `json:"code"`
+	Kind     string       `json:"kind"`
+	Value    json.Number  `json:"value"` // percentual ou valor, conforme Kind
+	MinOrder domain.Money `json:"min_order"`
+	// moeda de value (fixed) e min_order; vazio = domain.BaseCurrency
+	Currency       string     `json:"currency"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	MaxUses        int        `json:"max_uses"`
+	MaxUsesPerUser int        `json:"max_uses_per_user"`
+}
+
+// CouponService cuida dos cupons dos vendedores: cadastro, validação contra o
+// carrinho e resgate (o store garante os limites sob concorrência).
+type CouponService struct {
+	st  store.Store
+	now func() time.Time
+}
+
+func NewCouponService(st store.Store) *CouponService {
+	return &CouponService{st: st, now: func() time.Time { return time.Now().UTC() }}
+}
+
+func (s *CouponService) Create(sellerID int, in CouponInput) (domain.Coupon, error) {
+	if err := domain.ValidateID(sellerID); err != nil {
+		return domain.Coupon{}, err
+	}
+	seller, ok := s.st.GetUser(sellerID)
+	if !ok {
+		return domain.Coupon{}, store.ErrUserNotFound
+	}
+	if !seller.IsSeller {
+		return domain.Coupon{}, domain.ErrCouponNotSeller
+	}
+	currency := domain.BaseCurrency
+	if in.Currency != "" {
+		currency = domain.NormalizeCurrency(in.Currency)
+		if err := domain.ValidateCurrency(currency); err != nil {
+			return domain.Coupon{}, err
+		}
+	}
+	c := domain.Coupon{
+		SellerID:       sellerID,
+		Code:           domain.NormalizeCouponCode(in.Code),
+		Kind:           in.Kind,
+		MinOrder:       in.MinOrder,
+		Currency:       currency,
+		ExpiresAt:      in.ExpiresAt,
+		MaxUses:        in.MaxUses,
+		MaxUsesPerUser: in.MaxUsesPerUser,
+	}
+	if err := c.SetValue(in.Value.String()); err != nil {
+		return domain.Coupon{}, err
+	}
+	if err := domain.ValidateCoupon(c, s.now()); err != nil {
+		return domain.Coupon{}, err
+	}
+	return s.st.CreateCoupon(c)
+}
+
+func (s *CouponService) List(sellerID int) ([]domain.Coupon, error) {
+	if err := domain.ValidateID(sellerID); err != nil {
+		return nil, err
+	}
+	return s.st.CouponsBySeller(sellerID)
+}
+
+// Delete apaga um cupom do vendedor. Pedidos que já usaram guardam o código e o desconto.
+func (s *CouponService) Delete(sellerID, couponID int) error {
+	if err := domain.ValidateID(couponID); err != nil {
+		return err
+	}
+	c, ok := s.st.GetCoupon(couponID)
+	if !ok || c.SellerID != sellerID {
+		return store.ErrCouponNotFound
+	}
+	return s.st.DeleteCoupon(couponID)
+}
+
+// Apply confere o cupom para o usuário e o carrinho e devolve o carrinho com o
+// desconto (sobre os produtos do vendedor do cupom). Não consome usos.
+func (s *CouponService) Apply(userID int, code string, cart domain.Cart) (domain.Cart, domain.Coupon, error) {
+	c, ok := s.st.CouponByCode(domain.NormalizeCouponCode(code))
+	if !ok {
+		return domain.Cart{}, domain.Coupon{}, store.ErrCouponNotFound
+	}
+	if c.ExpiresAt != nil && !s.now().Before(*c.ExpiresAt) {
+		return domain.Cart{}, domain.Coupon{}, domain.ErrCouponExpired
+	}
+	subtotal := sellerSubtotal(cart, c.SellerID)
+	if subtotal == 0 {
+		return domain.Cart{}, domain.Coupon{}, domain.ErrCouponNoItems
+	}
+	if !c.AppliesTo(cart.Currency) {
+		return domain.Cart{}, domain.Coupon{}, domain.ErrCouponCurrency
+	}
+	if subtotal < c.MinOrder {
+		return domain.Cart{}, domain.Coupon{}, domain.ErrCouponMinOrder
+	}
+	if c.MaxUses > 0 && c.Uses >= c.MaxUses {
+		return domain.Cart{}, domain.Coupon{}, domain.ErrCouponExhausted
+	}
+	if c.MaxUsesPerUser > 0 {
+		used, err := s.st.CouponUsesBy(c.ID, userID)
+		if err != nil {
+			return domain.Cart{}, domain.Coupon{}, err
+		}
+		if used >= c.MaxUsesPerUser {
+			return domain.Cart{}, domain.Coupon{}, domain.ErrCouponUserLimit
+		}
+	}
+	cart.CouponCode = c.Code
+	cart.Discount = c.Discount(subtotal)
+	cart.Total = cart.Subtotal - cart.Discount
+	return cart, c, nil
+}
+
+// Redeem consome um uso do cupom. Entre o Apply e aqui outro checkout pode ter
+// levado o último uso: nesse caso volta domain.ErrCouponExhausted.
+func (s *CouponService) Redeem(userID int, c domain.Coupon) error {
+	return s.st.RedeemCoupon(c.ID, userID)
+}
+
+// Release devolve o uso de um checkout que não foi concluído.
+func (s *CouponService) Release(userID int, c domain.Coupon) error {
+	return s.st.ReleaseCoupon(c.ID, userID)
+}
+
+// ReleaseOrder devolve o uso do cupom de um pedido cancelado. Cupom apagado (os usos
+// vão junto) ou com o código reaproveitado por outro vendedor não tem o que devolver.
+func (s *CouponService) ReleaseOrder(o domain.Order) error {
+	if o.CouponCode == "" {
+		return nil
+	}
+	c, ok := s.st.CouponByCode(o.CouponCode)
+	if !ok || c.SellerID != o.SellerID {
+		return nil
+	}
+	return s.st.ReleaseCoupon(c.ID, o.BuyerID)
+}
+
+func sellerSubtotal(cart domain.Cart, sellerID int) domain.Money {
+	var total domain.Money
+	for _, it := range cart.Items {
+		if it.SellerID == sellerID {
+			total += it.Subtotal
+		}
+	}
+	return total
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func couponOrders(t *testing.T) (*store.MemoryStore, *OrderService, *CouponService) {
+	t.Helper()
+	st, ps := feedStore(t)
+	publishAt(t, ps, 2, 0, 0)  // post 1: 100 do vendedor 2
+	publishAt(t, ps, 3, 0, 10) // post 2: 90 do vendedor 3
+	cs := NewCouponService(st)
+	svc := NewOrderService(st)
+	svc.SetCoupons(cs)
+	return st, svc, cs
+}
+
+func TestCouponService_Create(t *testing.T) {
+	_, _, cs := couponOrders(t)
+
+	if _, err := cs.Create(1, CouponInput{Code: "PROMO10", Kind: domain.CouponPercent, Value: "10"}); err != domain.ErrCouponNotSeller {
+		t.Fatalf("expected ErrCouponNotSeller, got %v", err)
+	}
+	if _, err := cs.Create(2, CouponInput{Code: "PROMO10", Kind: "bogo", Value: "10"}); err != domain.ErrCouponKind {
+		t.Fatalf("expected ErrCouponKind, got %v", err)
+	}
+	c, err := cs.Create(2, CouponInput{Code: " promo10 ", Kind: domain.CouponPercent, Value: "10"})
+	if err != nil || c.Code != "PROMO10" || c.SellerID != 2 {
+		t.Fatalf("create: %+v %v", c, err)
+	}
+	if _, err := cs.Create(3, CouponInput{Code: "promo10", Kind: domain.CouponFixed, Value: "5"}); err != domain.ErrCouponCodeTaken {
+		t.Fatalf("expected ErrCouponCodeTaken, got %v", err)
+	}
+	if err := cs.Delete(3, c.ID); err != store.ErrCouponNotFound {
+		t.Fatalf("only the owner deletes, got %v", err)
+	}
+	if err := cs.Delete(2, c.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if list, _ := cs.List(2); len(list) != 0 {
+		t.Fatalf("expected no coupons, got %+v", list)
+	}
+}
+
+func TestOrderService_CheckoutWithCoupon(t *testing.T) {
+	_, svc, cs := couponOrders(t)
+	c, _ := cs.Create(2, CouponInput{Code: "PROMO10", Kind: domain.CouponPercent, Value: "10", MinOrder: 150_00, MaxUsesPerUser: 1})
+	_, _ = svc.AddToCart(1, 1, 1)
+	_, _ = svc.AddToCart(1, 2, 1)
+
+	if _, err := svc.Cart(1, "NOPE"); err != store.ErrCouponNotFound {
+		t.Fatalf("expected ErrCouponNotFound, got %v", err)
+	}
+	// só os produtos do vendedor 2 contam para o mínimo
+	if _, err := svc.Cart(1, "promo10"); err != domain.ErrCouponMinOrder {
+		t.Fatalf("expected ErrCouponMinOrder, got %v", err)
+	}
+	_, _ = svc.AddToCart(1, 1, 1)
+	cart, err := svc.Cart(1, "promo10")
+	if err != nil || cart.Subtotal != 290_00 || cart.Discount != 20_00 || cart.Total != 270_00 || cart.CouponCode != "PROMO10" {
+		t.Fatalf("unexpected preview %+v %v", cart, err)
+	}
+	if got, _ := cs.st.GetCoupon(c.ID); got.Uses != 0 {
+		t.Fatalf("preview must not use the coupon, got %d uses", got.Uses)
+	}
+
+	orders, err := svc.Checkout(1, "promo10")
+	if err != nil || len(orders) != 2 {
+		t.Fatalf("checkout: %+v %v", orders, err)
+	}
+	if o := orders[0]; o.SellerID != 2 || o.Subtotal != 200_00 || o.Discount != 20_00 || o.Total != 180_00 || o.CouponCode != "PROMO10" {
+		t.Fatalf("unexpected discounted order %+v", o)
+	}
+	if o := orders[1]; o.Discount != 0 || o.CouponCode != "" || o.Total != 90_00 {
+		t.Fatalf("other seller's order must not be discounted: %+v", o)
+	}
+
+	_, _ = svc.AddToCart(1, 1, 2)
+	if _, err := svc.Checkout(1, "PROMO10"); err != domain.ErrCouponUserLimit {
+		t.Fatalf("expected ErrCouponUserLimit, got %v", err)
+	}
+	if cart, _ := svc.Cart(1, ""); len(cart.Items) != 1 {
+		t.Fatalf("failed checkout must keep the cart, got %+v", cart)
+	}
+}
+
+func TestOrderService_CouponCurrency(t *testing.T) {
+	_, svc, cs := couponOrders(t)
+	if _, err := cs.Create(2, CouponInput{Code: "REAIS", Kind: domain.CouponFixed, Value: "10", Currency: "XXX"}); err != domain.ErrCurrency {
+		t.Fatalf("expected ErrCurrency, got %v", err)
+	}
+	brl, _ := cs.Create(2, CouponInput{Code: "REAIS", Kind: domain.CouponFixed, Value: "10"})
+	if brl.Currency != domain.BaseCurrency {
+		t.Fatalf("coupon without currency must be in %s, got %q", domain.BaseCurrency, brl.Currency)
+	}
+	_, _ = cs.Create(2, CouponInput{Code: "DOLAR", Kind: domain.CouponFixed, Value: "10", Currency: "usd"})
+	_, _ = cs.Create(2, CouponInput{Code: "DOLARMIN", Kind: domain.CouponPercent, Value: "10", MinOrder: 50_00, Currency: "USD"})
+	_, _ = cs.Create(2, CouponInput{Code: "DEZ", Kind: domain.CouponPercent, Value: "10", Currency: "USD"})
+	_, _ = svc.AddToCart(1, 1, 1) // carrinho em BRL
+
+	for _, code := range []string{"DOLAR", "DOLARMIN"} {
+		if _, err := svc.Cart(1, code); err != domain.ErrCouponCurrency {
+			t.Fatalf("%s: expected ErrCouponCurrency, got %v", code, err)
+		}
+	}
+	if cart, err := svc.Cart(1, "REAIS"); err != nil || cart.Discount != 10_00 {
+		t.Fatalf("same currency: %+v %v", cart, err)
+	}
+	// percentual sem mínimo não compara valores: vale em qualquer moeda
+	if cart, err := svc.Cart(1, "DEZ"); err != nil || cart.Discount != 10_00 {
+		t.Fatalf("percent coupon: %+v %v", cart, err)
+	}
+}
+
+func TestOrderService_CancelReleasesCoupon(t *testing.T) {
+	_, svc, cs := couponOrders(t)
+	c, _ := cs.Create(2, CouponInput{Code: "UMAVEZ", Kind: domain.CouponFixed, Value: "5", MaxUsesPerUser: 1})
+	_, _ = svc.AddToCart(1, 1, 1)
+	orders, err := svc.Checkout(1, "UMAVEZ")
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if got, _ := cs.st.GetCoupon(c.ID); got.Uses != 1 {
+		t.Fatalf("expected 1 use, got %d", got.Uses)
+	}
+
+	if _, err := svc.Transition(1, orders[0].ID, domain.OrderCancelled); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if got, _ := cs.st.GetCoupon(c.ID); got.Uses != 0 {
+		t.Fatalf("cancel must release the coupon use, got %d", got.Uses)
+	}
+	if n, _ := cs.st.CouponUsesBy(c.ID, 1); n != 0 {
+		t.Fatalf("cancel must release the buyer's use, got %d", n)
+	}
+	// o comprador pode usar o cupom de novo
+	_, _ = svc.AddToCart(1, 1, 1)
+	if _, err := svc.Checkout(1, "UMAVEZ"); err != nil {
+		t.Fatalf("checkout after cancel: %v", err)
+	}
+}
+
+func TestOrderService_CheckoutCouponExpired(t *testing.T) {
+	_, svc, cs := couponOrders(t)
+	soon := time.Now().UTC().Add(time.Hour)
+	_, _ = cs.Create(3, CouponInput{Code: "HOJE", Kind: domain.CouponFixed, Value: "5", ExpiresAt: &soon})
+	cs.now = func() time.Time { return soon }
+	_, _ = svc.AddToCart(1, 2, 1)
+
+	if _, err := svc.Checkout(1, "HOJE"); err != domain.ErrCouponExpired {
+		t.Fatalf("expected ErrCouponExpired, got %v", err)
+	}
+	if _, err := svc.Checkout(1, "NOPE"); err != store.ErrCouponNotFound {
+		t.Fatalf("expected ErrCouponNotFound, got %v", err)
+	}
+}
+
+func TestOrderService_CheckoutCouponConcurrent(t *testing.T) {
+	st, svc, cs := couponOrders(t)
+	c, _ := cs.Create(2, CouponInput{Code: "FLASH", Kind: domain.CouponFixed, Value: "10", MaxUses: 3})
+	buyers := []int{}
+	for id := 10; id < 20; id++ {
+		buyers = append(buyers, id)
+	}
+	users := make([]domain.User, len(buyers))
+	for i, id := range buyers {
+		users[i] = domain.User{ID: id, Name: "Buyer"}
+	}
+	st.SeedUsers(users)
+	for _, id := range buyers {
+		if _, err := svc.AddToCart(id, 1, 1); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	placed := 0
+	for _, id := range buyers {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if _, err := svc.Checkout(id, "FLASH"); err == nil {
+				mu.Lock()
+				placed++
+				mu.Unlock()
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if got, _ := st.GetCoupon(c.ID); placed != 3 || got.Uses != 3 {
+		t.Fatalf("expected 3 discounted checkouts, got %d (uses %d)", placed, got.Uses)
+	}
+}
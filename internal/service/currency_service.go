@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+var ErrRatesFile = errors.New("Arquivo de cotações inválido: use linhas moeda,cotação (ex.: ARS,0.0055).")
+
+// CurrencyService mantém a tabela de cotações e converte os preços das
+// listagens para a moeda pedida.
+type CurrencyService struct {
+	st  store.Store
+	now func() time.Time
+}
+
+func NewCurrencyService(st store.Store) *CurrencyService {
+	return &CurrencyService{st: st, now: func() time.Time { return time.Now().UTC() }}
+}
+
+func (s *CurrencyService) Rates() ([]domain.ExchangeRate, error) {
+	return s.st.ExchangeRates()
+}
+
+// SetRates valida todas as cotações antes de gravar qualquer uma e devolve a
+// tabela completa.
+func (s *CurrencyService) SetRates(rates []domain.ExchangeRate) ([]domain.ExchangeRate, error) {
+	if len(rates) == 0 {
+		return nil, domain.ErrExchangeRate
+	}
+	now := s.now()
+	out := make([]domain.ExchangeRate, len(rates))
+	for i, r := range rates {
+		r.Currency = domain.NormalizeCurrency(r.Currency)
+		r.Rate = domain.RoundRate(r.Rate)
+		if err := domain.ValidateExchangeRate(r); err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Currency, err)
+		}
+		r.UpdatedAt = now
+		out[i] = r
+	}
+	if err := s.st.SetExchangeRates(out); err != nil {
+		return nil, err
+	}
+	return s.st.ExchangeRates()
+}
+
+// Import lê cotações em CSV (moeda,cotação por linha; cabeçalho e linhas com #
+// são ignorados) e grava como SetRates.
+func (s *CurrencyService) Import(r io.Reader) ([]domain.ExchangeRate, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	var rates []domain.ExchangeRate
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w (%v)", ErrRatesFile, err)
+		}
+		if first && strings.EqualFold(strings.TrimSpace(rec[0]), "currency") {
+			continue
+		}
+		line, _ := cr.FieldPos(1)
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rec[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w (linha %d)", ErrRatesFile, line)
+		}
+		rates = append(rates, domain.ExchangeRate{Currency: rec[0], Rate: rate})
+	}
+	return s.SetRates(rates)
+}
+
+func (s *CurrencyService) Delete(currency string) error {
+	return s.st.DeleteExchangeRate(domain.NormalizeCurrency(currency))
+}
+
+// ConvertPosts devolve uma cópia dos posts com Converted preenchido na moeda to
+// (vazio = sem conversão). Preço e moeda originais ficam como estão.
+func (s *CurrencyService) ConvertPosts(posts []domain.Post, to string) ([]domain.Post, error) {
+	if to == "" {
+		return posts, nil
+	}
+	to = domain.NormalizeCurrency(to)
+	if err := domain.ValidateCurrency(to); err != nil {
+		return nil, err
+	}
+	list, err := s.st.ExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+	rates := domain.NewRates(list)
+	out := make([]domain.Post, len(posts))
+	for i, p := range posts {
+		if out[i], err = rates.ConvertPost(p, to); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
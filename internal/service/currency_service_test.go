@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func TestCurrencyService_SetRates(t *testing.T) {
+	st := store.NewMemoryStore()
+	svc := NewCurrencyService(st)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	if _, err := svc.SetRates(nil); err != domain.ErrExchangeRate {
+		t.Fatalf("expected ErrExchangeRate, got %v", err)
+	}
+	// uma cotação ruim barra o lote inteiro
+	_, err := svc.SetRates([]domain.ExchangeRate{{Currency: "ars", Rate: 0.0055}, {Currency: "XYZ", Rate: 1}})
+	if !errors.Is(err, domain.ErrCurrency) {
+		t.Fatalf("expected ErrCurrency, got %v", err)
+	}
+	if rates, _ := svc.Rates(); len(rates) != 0 {
+		t.Fatalf("nothing must be stored, got %+v", rates)
+	}
+	if _, err := svc.SetRates([]domain.ExchangeRate{{Currency: "BRL", Rate: 1}}); !errors.Is(err, domain.ErrExchangeRateBase) {
+		t.Fatalf("expected ErrExchangeRateBase, got %v", err)
+	}
+
+	rates, err := svc.SetRates([]domain.ExchangeRate{{Currency: " ars ", Rate: 0.005512345678}})
+	if err != nil || len(rates) != 1 || rates[0].Currency != "ARS" || rates[0].Rate != 0.00551235 || !rates[0].UpdatedAt.Equal(now) {
+		t.Fatalf("unexpected: %+v %v", rates, err)
+	}
+	if err := svc.Delete("ars"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+}
+
+func TestCurrencyService_Import(t *testing.T) {
+	svc := NewCurrencyService(store.NewMemoryStore())
+	csv := "currency,rate\n# cotações de 01/03\nARS, 0.0055\nmxn,0.3\n"
+	rates, err := svc.Import(strings.NewReader(csv))
+	if err != nil || len(rates) != 2 || rates[0].Currency != "ARS" || rates[1].Rate != 0.3 {
+		t.Fatalf("unexpected: %+v %v", rates, err)
+	}
+
+	for _, bad := range []string{"ARS;0.0055\n", "ARS,0.0055\nMXN,abc\n"} {
+		if _, err := svc.Import(strings.NewReader(bad)); !errors.Is(err, ErrRatesFile) {
+			t.Fatalf("%q: expected ErrRatesFile, got %v", bad, err)
+		}
+	}
+	if _, err := svc.Import(strings.NewReader("ARS,0.0055\nMXN,abc\n")); err == nil || !strings.Contains(err.Error(), "linha 2") {
+		t.Fatalf("error must point to the line, got %v", err)
+	}
+}
+
+func TestCurrencyService_ConvertPosts(t *testing.T) {
+	st := store.NewMemoryStore()
+	svc := NewCurrencyService(st)
+	_ = st.SetExchangeRates([]domain.ExchangeRate{{Currency: "ARS", Rate: 0.0055}})
+	posts := []domain.Post{
+		{PostID: 1, Price: 100_00, FinalPrice: 90_00, Currency: "BRL"},
+		{PostID: 2, Price: 1000_00, FinalPrice: 1000_00, Currency: "ARS"},
+	}
+
+	same, err := svc.ConvertPosts(posts, "")
+	if err != nil || same[0].Converted != nil {
+		t.Fatalf("without currency nothing changes: %+v %v", same, err)
+	}
+	out, err := svc.ConvertPosts(posts, "brl")
+	if err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if out[0].Converted.FinalPrice != 90_00 || out[1].Converted.Price != 5_50 || out[1].Price != 1000_00 {
+		t.Fatalf("unexpected: %+v %+v", out[0].Converted, out[1].Converted)
+	}
+	if posts[1].Converted != nil {
+		t.Fatalf("input slice must not change")
+	}
+	if _, err := svc.ConvertPosts(posts, "REAL"); err != domain.ErrCurrency {
+		t.Fatalf("expected ErrCurrency, got %v", err)
+	}
+	if _, err := svc.ConvertPosts(posts, "MXN"); !errors.Is(err, domain.ErrNoExchangeRate) {
+		t.Fatalf("expected ErrNoExchangeRate, got %v", err)
+	}
+}
+
+func TestProductService_Publish_Currency(t *testing.T) {
+	st, ps := feedStore(t)
+	p := validPayload()
+	p.UserID = 2
+	p.Currency = "ars"
+	id, err := ps.Publish(p)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if post, _ := st.GetPost(id); post.Currency != "ARS" {
+		t.Fatalf("expected ARS, got %q", post.Currency)
+	}
+
+	p.Currency = "PESO"
+	if _, err := ps.Publish(p); err != domain.ErrCurrency {
+		t.Fatalf("expected ErrCurrency, got %v", err)
+	}
+	p.Currency = ""
+	id, _ = ps.Publish(p)
+	if post, _ := st.GetPost(id); post.Currency != domain.BaseCurrency {
+		t.Fatalf("expected BRL by default, got %q", post.Currency)
+	}
+}
+
+func TestOrderService_CartSingleCurrency(t *testing.T) {
+	st, ps := feedStore(t)
+	publishAt(t, ps, 2, 0, 0) // post 1 em BRL
+	p := validPayload()
+	p.UserID, p.Currency = 3, "MXN"
+	mxn, _ := ps.Publish(p)
+	svc := NewOrderService(st)
+
+	if _, err := svc.AddToCart(1, 1, 1); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := svc.AddToCart(1, mxn, 1); err != domain.ErrCartCurrency {
+		t.Fatalf("expected ErrCartCurrency, got %v", err)
+	}
+	// a quantidade do próprio item continua mudando
+	cart, err := svc.SetCartQuantity(1, 1, 3)
+	if err != nil || cart.Currency != "BRL" {
+		t.Fatalf("unexpected: %+v %v", cart, err)
+	}
+	orders, err := svc.Checkout(1, "")
+	if err != nil || len(orders) != 1 || orders[0].Currency != "BRL" {
+		t.Fatalf("checkout: %+v %v", orders, err)
+	}
+	if _, err := svc.AddToCart(1, mxn, 1); err != nil {
+		t.Fatalf("empty cart accepts any currency: %v", err)
+	}
+}
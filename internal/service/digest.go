@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/mail"
+	"socialmeli/internal/store"
+)
+
+// DefaultDigestPeriod é o intervalo entre dois resumos do mesmo usuário.
+const DefaultDigestPeriod = 7 * 24 * time.Hour
+
+// Digest é o resumo de um usuário: promoções dos vendedores seguidos publicadas
+// em [Since, Until). Os limites são dias inteiros (as datas dos posts não têm hora).
+type Digest struct {
+	UserName string
+	Since    time.Time
+	Until    time.Time
+	Sellers  []DigestSeller
+	Total    int
+}
+
+type DigestSeller struct {
+	SellerID int
+	Name     string
+	Posts    []domain.Post
+}
+
+var digestFuncs = map[string]any{
+	"day":   func(t time.Time) string { return t.Format("02/01/2006") },
+	"last":  func(t time.Time) string { return t.AddDate(0, 0, -1).Format("02/01/2006") },
+	"money": domain.FormatPrice,
+}
+
+var digestText = texttemplate.Must(texttemplate.New("digest.txt").Funcs(digestFuncs).Parse(
+	`Olá, {{.UserName}}!
+
+{{.Total}} promoções de quem você segue entre {{day .Since}} e {{last .Until}}:
+{{range .Sellers}}
+{{.Name}}
+{{- range .Posts}}
+  - {{.Product.ProductName}} ({{.Product.Brand}}): {{money .FinalPrice .Currency}} (-{{.Discount}}%, era {{money .Price .Currency}}) em {{.DateStr}}
+{{- end}}
+{{end}}
+Para não receber mais este resumo, desligue "weekly_digest" nas preferências de notificação.
+`))
+
+var digestHTML = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(digestFuncs).Parse(
+	`<!doctype html>
+<html lang="pt-BR">
+<body style="font-family: sans-serif">
+<h1>Olá, {{.UserName}}!</h1>
+<p>{{.Total}} promoções de quem você segue entre {{day .Since}} e {{last .Until}}:</p>
+{{range .Sellers}}
+<h2>{{.Name}}</h2>
+<ul>
+{{- range .Posts}}
+  <li><strong>{{.Product.ProductName}}</strong> ({{.Product.Brand}}): {{money .FinalPrice .Currency}} <small>(-{{.Discount}}%, era <s>{{money .Price .Currency}}</s>) em {{.DateStr}}</small></li>
+{{- end}}
+</ul>
+{{end}}
+<p><small>Para não receber mais este resumo, desligue "weekly_digest" nas preferências de notificação.</small></p>
+</body>
+</html>
+`))
+
+// DigestService monta e envia o resumo semanal das promoções dos vendedores seguidos.
+// O momento do último resumo fica no store: reiniciar ou rodar de novo não repete envios.
+type DigestService struct {
+	st     store.Store
+	mailer mail.Mailer
+	period time.Duration
+	now    func() time.Time
+}
+
+func NewDigestService(st store.Store, m mail.Mailer, period time.Duration) *DigestService {
+	if period <= 0 {
+		period = DefaultDigestPeriod
+	}
+	return &DigestService{st: st, mailer: m, period: period, now: time.Now}
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Build junta as promoções publicadas em [since, until) pelos vendedores que userID segue.
+func (s *DigestService) Build(userID int, since, until time.Time) (Digest, error) {
+	sellers, err := s.st.FollowedBy(userID)
+	if err != nil {
+		return Digest{}, err
+	}
+	sort.Slice(sellers, func(i, j int) bool { return strings.ToLower(sellers[i].Name) < strings.ToLower(sellers[j].Name) })
+
+	d := Digest{Since: since, Until: until}
+	for _, seller := range sellers {
+		var posts []domain.Post
+		for _, p := range s.st.PromoPostsBySeller(seller.ID) {
+			if !p.Date.Before(since) && p.Date.Before(until) {
+				posts = append(posts, p)
+			}
+		}
+		if len(posts) == 0 {
+			continue
+		}
+		sort.Slice(posts, func(i, j int) bool {
+			if !posts[i].Date.Equal(posts[j].Date) {
+				return posts[i].Date.After(posts[j].Date)
+			}
+			return posts[i].PostID > posts[j].PostID
+		})
+		d.Sellers = append(d.Sellers, DigestSeller{SellerID: seller.ID, Name: seller.Name, Posts: posts})
+		d.Total += len(posts)
+	}
+	return d, nil
+}
+
+// Render gera a mensagem (texto e HTML) do resumo para o e-mail to.
+func (s *DigestService) Render(to string, d Digest) (mail.Message, error) {
+	var text, html strings.Builder
+	if err := digestText.Execute(&text, d); err != nil {
+		return mail.Message{}, err
+	}
+	if err := digestHTML.Execute(&html, d); err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		To:      to,
+		Subject: fmt.Sprintf("Resumo semanal: %d promoções de quem você segue", d.Total),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// SendDue envia o resumo de quem tem conta e não recebeu nenhum no último período.
+// Quem não tem promoções novas (ou desligou weekly_digest) não recebe nada, mas
+// também é marcado: a janela seguinte começa de onde esta terminou.
+// Falhas de envio não marcam o usuário (tenta de novo na próxima rodada).
+func (s *DigestService) SendDue() (int, error) {
+	now := s.now()
+	users, err := s.st.ListUsers(domain.NameAsc)
+	if err != nil {
+		return 0, err
+	}
+
+	type due struct {
+		acc  domain.Account
+		last time.Time
+	}
+	var pending []due
+	var ids []int
+	for _, u := range users {
+		acc, ok := s.st.GetAccount(u.ID)
+		if !ok || acc.Email == "" {
+			continue
+		}
+		last, err := s.st.LastDigestAt(u.ID)
+		if err != nil {
+			return 0, err
+		}
+		if !last.IsZero() && now.Sub(last) < s.period {
+			continue
+		}
+		pending = append(pending, due{acc: acc, last: last})
+		ids = append(ids, u.ID)
+	}
+	optOut, err := s.st.NotificationOptOuts(domain.NotificationWeeklyDigest, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	until := startOfDay(now)
+	sent := 0
+	var errs []error
+	for _, p := range pending {
+		if !optOut[p.acc.ID] {
+			since := startOfDay(now.Add(-s.period))
+			if !p.last.IsZero() {
+				since = startOfDay(p.last)
+			}
+			ok, err := s.send(p.acc, since, until)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("usuário %d: %w", p.acc.ID, err))
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+		if err := s.st.SetLastDigestAt(p.acc.ID, now); err != nil {
+			errs = append(errs, fmt.Errorf("usuário %d: %w", p.acc.ID, err))
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+func (s *DigestService) send(acc domain.Account, since, until time.Time) (bool, error) {
+	d, err := s.Build(acc.ID, since, until)
+	if err != nil || d.Total == 0 {
+		return false, err
+	}
+	d.UserName = acc.Name
+	msg, err := s.Render(acc.Email, d)
+	if err != nil {
+		return false, err
+	}
+	return true, s.mailer.Send(msg)
+}
+
+// Run verifica a cada every quem está devendo resumo (o período de cada usuário
+// é contado a partir do último envio, então every só define a precisão).
+func (s *DigestService) Run(ctx context.Context, every time.Duration, logf func(format string, args ...any)) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			n, err := s.SendDue()
+			if err != nil {
+				logf("digest: %v", err)
+			}
+			if n > 0 {
+				logf("digest: %d resumos enviados", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/mail"
+	"socialmeli/internal/store"
+)
+
+// digestStore: Ana segue Loja A e Loja B; Bia segue só Loja B; Caio não tem e-mail.
+func digestStore(t *testing.T) (*store.MemoryStore, map[string]int) {
+	t.Helper()
+	st := store.NewMemoryStore()
+	ids := map[string]int{}
+	for _, a := range []struct {
+		name, email string
+		seller      bool
+	}{{"Loja B", "b@loja", true}, {"Loja A", "a@loja", true}, {"Ana", "ana@example.com", false}, {"Bia", "bia@example.com", false}} {
+		acc, err := st.CreateAccount(a.name, a.email, "hash", a.seller)
+		if err != nil {
+			t.Fatalf("account: %v", err)
+		}
+		ids[a.name] = acc.ID
+	}
+	caio, _ := st.CreateUser("Caio", false)
+	ids["Caio"] = caio.ID
+	for _, f := range [][2]string{{"Ana", "Loja A"}, {"Ana", "Loja B"}, {"Bia", "Loja B"}, {"Caio", "Loja A"}} {
+		if err := st.Follow(ids[f[0]], ids[f[1]]); err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+	}
+	return st, ids
+}
+
+func addPromo(t *testing.T, st *store.MemoryStore, seller int, name string, date time.Time, discount float64) {
+	t.Helper()
+	_, err := st.AddPost(domain.Post{
+		UserID: seller, Date: date, DateStr: date.Format("02-01-2006"),
+		Product: domain.Product{ProductName: name, Brand: "Marca"}, Price: 100_00,
+		HasPromo: discount > 0, Discount: discount, FinalPrice: domain.FinalPrice(100_00, discount, discount > 0),
+	})
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+}
+
+func TestDigest_SendDue(t *testing.T) {
+	st, ids := digestStore(t)
+	now := time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC) // segunda
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	addPromo(t, st, ids["Loja A"], "Tênis", day(3), 20)
+	addPromo(t, st, ids["Loja B"], "Camisa <azul>", day(8), 10)
+	addPromo(t, st, ids["Loja B"], "Sem promo", day(8), 0)
+	addPromo(t, st, ids["Loja A"], "Antiga", day(1), 30) // antes da janela
+	addPromo(t, st, ids["Loja A"], "Hoje", day(9), 5)    // entra no próximo resumo
+
+	mailer := mail.NewMemoryMailer()
+	ds := NewDigestService(st, mailer, 0)
+	ds.now = func() time.Time { return now }
+
+	n, err := ds.SendDue()
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 digests, got %d (%v)", n, err)
+	}
+	sent := mailer.Sent()
+	if sent[0].To != "ana@example.com" || !strings.Contains(sent[0].Subject, "2 promoções") {
+		t.Fatalf("unexpected first digest %+v", sent[0])
+	}
+	text := sent[0].Text
+	if strings.Index(text, "Loja A") > strings.Index(text, "Loja B") || !strings.Contains(text, "R$ 80,00") {
+		t.Fatalf("unexpected text:\n%s", text)
+	}
+	if strings.Contains(text, "Antiga") || strings.Contains(text, "Hoje") || strings.Contains(text, "Sem promo") {
+		t.Fatalf("posts outside the window in digest:\n%s", text)
+	}
+	if !strings.Contains(sent[0].HTML, "Camisa &lt;azul&gt;") {
+		t.Fatalf("expected escaped HTML:\n%s", sent[0].HTML)
+	}
+	if at, _ := st.LastDigestAt(ids["Ana"]); !at.Equal(now) {
+		t.Fatalf("expected last digest recorded, got %v", at)
+	}
+
+	// rodar de novo no mesmo período não repete
+	if n, _ := ds.SendDue(); n != 0 {
+		t.Fatalf("expected no duplicates, got %d", n)
+	}
+
+	// uma semana depois: só o que veio desde o último resumo
+	now = now.AddDate(0, 0, 7)
+	if n, _ := ds.SendDue(); n != 1 {
+		t.Fatalf("expected 1 digest, got %d", n)
+	}
+	last := mailer.Sent()[2]
+	if last.To != "ana@example.com" || !strings.Contains(last.Text, "Hoje") || strings.Contains(last.Text, "Tênis") {
+		t.Fatalf("unexpected second digest %+v", last)
+	}
+}
+
+func TestDigest_PostCurrency(t *testing.T) {
+	st, ids := digestStore(t)
+	now := time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC)
+	day := now.AddDate(0, 0, -1)
+	_, err := st.AddPost(domain.Post{
+		UserID: ids["Loja B"], Date: day, DateStr: day.Format("02-01-2006"), Currency: "MXN",
+		Product: domain.Product{ProductName: "Sombrero", Brand: "Marca"}, Price: 500_00,
+		HasPromo: true, Discount: 10, FinalPrice: domain.FinalPrice(500_00, 10, true),
+	})
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	addPromo(t, st, ids["Loja A"], "Tênis", day, 20)
+
+	mailer := mail.NewMemoryMailer()
+	ds := NewDigestService(st, mailer, 0)
+	ds.now = func() time.Time { return now }
+	if n, err := ds.SendDue(); err != nil || n != 2 {
+		t.Fatalf("expected 2 digests, got %d (%v)", n, err)
+	}
+	ana := mailer.Sent()[0]
+	for _, want := range []string{"MXN 450,00", "era MXN 500,00", "R$ 80,00"} {
+		if !strings.Contains(ana.Text, want) {
+			t.Fatalf("expected %q in text:\n%s", want, ana.Text)
+		}
+	}
+	if strings.Contains(ana.Text, "R$ 450,00") || !strings.Contains(ana.HTML, "MXN 450,00") {
+		t.Fatalf("MXN post shown in reais:\n%s\n%s", ana.Text, ana.HTML)
+	}
+}
+
+func TestDigest_OptOut(t *testing.T) {
+	st, ids := digestStore(t)
+	now := time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC)
+	addPromo(t, st, ids["Loja B"], "Camisa", now.AddDate(0, 0, -1), 10)
+	if err := st.SetNotificationPrefs(ids["Bia"], domain.NotificationPrefs{domain.NotificationWeeklyDigest: false}); err != nil {
+		t.Fatalf("prefs: %v", err)
+	}
+
+	mailer := mail.NewMemoryMailer()
+	ds := NewDigestService(st, mailer, 0)
+	ds.now = func() time.Time { return now }
+	if n, err := ds.SendDue(); err != nil || n != 1 || mailer.Sent()[0].To != "ana@example.com" {
+		t.Fatalf("expected only Ana, got %d %v", n, err)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// Tipos de evento do barramento interno (também são os tópicos do /ws).
+const (
+	EventNewFollower  = "follower.new"     // alguém passou a seguir UserID
+	EventNotification = "notification.new" // nova entrada na caixa de notificações (Data é a domain.Notification)
+)
+
+// EventTypes lista os tipos conhecidos, na ordem em que foram criados.
+var EventTypes = []string{EventNewFollower, EventNotification}
+
+// Event é algo que aconteceu para o usuário UserID (o destinatário).
+type Event struct {
+	Type   string    `json:"type"`
+	UserID int       `json:"user_id"`
+	Data   any       `json:"data,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// FollowerEventData acompanha EventNewFollower.
+type FollowerEventData struct {
+	FollowerID   int    `json:"follower_id"`
+	FollowerName string `json:"follower_name"`
+}
+
+// EventBus entrega eventos aos assinantes de forma síncrona, na goroutine de quem
+// publica: os assinantes não podem bloquear.
+type EventBus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]func(Event)
+}
+
+func NewEventBus() *EventBus { return &EventBus{subs: map[int]func(Event){}} }
+
+// Subscribe registra fn e devolve a função que cancela a assinatura.
+func (b *EventBus) Subscribe(fn func(Event)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+	}
+}
+
+func (b *EventBus) Publish(ev Event) {
+	if ev.At.IsZero() {
+		ev.At = time.Now().UTC()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, fn := range b.subs {
+		fn(ev)
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func TestEventBus_SubscribeAndCancel(t *testing.T) {
+	bus := NewEventBus()
+	var got []Event
+	cancel := bus.Subscribe(func(ev Event) { got = append(got, ev) })
+
+	bus.Publish(Event{Type: EventNewFollower, UserID: 2})
+	cancel()
+	bus.Publish(Event{Type: EventNewFollower, UserID: 3})
+
+	if len(got) != 1 || got[0].UserID != 2 || got[0].At.IsZero() {
+		t.Fatalf("unexpected events %+v", got)
+	}
+}
+
+func TestUserService_FollowPublishesEvent(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Buyer"}, {ID: 2, Name: "Seller", IsSeller: true}})
+	svc := NewUserService(st)
+	bus := NewEventBus()
+	svc.SetEventBus(bus)
+	var got []Event
+	bus.Subscribe(func(ev Event) { got = append(got, ev) })
+
+	if err := svc.Follow(1, 2); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if err := svc.Follow(1, 99); err == nil {
+		t.Fatalf("expected error for unknown seller")
+	}
+	if len(got) != 1 || got[0].UserID != 2 || got[0].Data.(FollowerEventData).FollowerName != "Buyer" {
+		t.Fatalf("unexpected events %+v", got)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+// Tipos de evento do stream do feed.
+const (
+	FeedEventPost    = "post"    // post novo sem promoção
+	FeedEventPromo   = "promo"   // post novo em promoção
+	FeedEventDeleted = "deleted" // post apagado pelo vendedor
+	FeedEventUpdated = "updated" // promoção de um post agendada, trocada ou encerrada
+)
+
+const (
+	// DefaultFeedHistory é quantos eventos recentes ficam guardados para retomar via Last-Event-ID.
+	DefaultFeedHistory = 512
+	// DefaultFeedBuffer é quantos eventos podem esperar por uma conexão lenta antes de ela cair.
+	DefaultFeedBuffer = 64
+)
+
+// FeedEvent é uma mudança no feed de quem segue SellerID.
+type FeedEvent struct {
+	ID       uint64       `json:"id"`
+	Type     string       `json:"type"`
+	SellerID int          `json:"user_id"`
+	PostID   int          `json:"post_id"`
+	Post     *domain.Post `json:"post,omitempty"`
+}
+
+// FeedHub é o pub/sub em processo dos eventos do feed. Guarda os últimos eventos
+// para quem reconecta com Last-Event-ID e derruba a assinatura que não dá conta
+// de consumir (o cliente reconecta e retoma pelo histórico).
+type FeedHub struct {
+	mu       sync.Mutex
+	nextID   uint64
+	history  []FeedEvent
+	histSize int
+	bufSize  int
+	subs     map[*FeedSubscription]struct{}
+}
+
+func NewFeedHub(historySize, bufferSize int) *FeedHub {
+	if historySize <= 0 {
+		historySize = DefaultFeedHistory
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultFeedBuffer
+	}
+	return &FeedHub{
+		// ids partem do relógio: um Last-Event-ID de antes de reiniciar é sempre
+		// menor que o histórico atual e vira reset em vez de replay errado
+		nextID:   uint64(time.Now().UnixMicro()),
+		histSize: historySize,
+		bufSize:  bufferSize,
+		subs:     map[*FeedSubscription]struct{}{},
+	}
+}
+
+// FeedSubscription recebe os eventos dos vendedores seguidos no momento da assinatura.
+type FeedSubscription struct {
+	hub     *FeedHub
+	sellers map[int]bool
+	ch      chan FeedEvent
+	lagged  bool
+
+	// Backlog são os eventos perdidos desde o Last-Event-ID, em ordem.
+	Backlog []FeedEvent
+	// Reset indica que o Last-Event-ID não está mais no histórico: recarregue o feed.
+	Reset bool
+}
+
+// Events fecha quando a assinatura termina (Close ou atraso, ver Lagged).
+func (s *FeedSubscription) Events() <-chan FeedEvent { return s.ch }
+
+// Lagged diz se a assinatura caiu por não consumir rápido o bastante.
+func (s *FeedSubscription) Lagged() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.lagged
+}
+
+func (s *FeedSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		delete(s.hub.subs, s)
+		close(s.ch)
+	}
+}
+
+// Subscribe assina os eventos dos vendedores; lastID 0 = só eventos novos.
+func (h *FeedHub) Subscribe(sellerIDs []int, lastID uint64) *FeedSubscription {
+	sub := &FeedSubscription{hub: h, sellers: map[int]bool{}, ch: make(chan FeedEvent, h.bufSize)}
+	for _, id := range sellerIDs {
+		sub.sellers[id] = true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if latest := h.nextID - 1; lastID > 0 && lastID != latest {
+		if lastID > latest || len(h.history) == 0 || lastID+1 < h.history[0].ID {
+			sub.Reset = true
+		} else {
+			for _, ev := range h.history {
+				if ev.ID > lastID && sub.sellers[ev.SellerID] {
+					sub.Backlog = append(sub.Backlog, ev)
+				}
+			}
+		}
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// Publish numera o evento, guarda no histórico e entrega sem bloquear.
+func (h *FeedHub) Publish(ev FeedEvent) FeedEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ev.ID = h.nextID
+	h.nextID++
+	if len(h.history) == h.histSize {
+		copy(h.history, h.history[1:])
+		h.history = h.history[:len(h.history)-1]
+	}
+	h.history = append(h.history, ev)
+
+	for sub := range h.subs {
+		if !sub.sellers[ev.SellerID] {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			sub.lagged = true
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return ev
+}
+
+// Subscribers conta as assinaturas ativas.
+func (h *FeedHub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
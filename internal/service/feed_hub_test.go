@@ -0,0 +1,96 @@
+package service
+
+import "testing"
+
+func TestFeedHub_DeliversOnlyFollowedSellers(t *testing.T) {
+	h := NewFeedHub(10, 4)
+	sub := h.Subscribe([]int{2}, 0)
+	defer sub.Close()
+
+	h.Publish(FeedEvent{Type: FeedEventPost, SellerID: 3, PostID: 1})
+	sent := h.Publish(FeedEvent{Type: FeedEventPost, SellerID: 2, PostID: 2})
+
+	select {
+	case ev := <-sub.Events():
+		if ev.ID != sent.ID || ev.PostID != 2 {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	default:
+		t.Fatalf("expected an event")
+	}
+	select {
+	case ev := <-sub.Events():
+		t.Fatalf("unexpected extra event %+v", ev)
+	default:
+	}
+}
+
+func TestFeedHub_ResumeFromHistory(t *testing.T) {
+	h := NewFeedHub(3, 4)
+	first := h.Publish(FeedEvent{SellerID: 2, PostID: 1})
+	h.Publish(FeedEvent{SellerID: 3, PostID: 2})
+	last := h.Publish(FeedEvent{SellerID: 2, PostID: 3})
+
+	sub := h.Subscribe([]int{2}, first.ID)
+	if sub.Reset || len(sub.Backlog) != 1 || sub.Backlog[0].ID != last.ID {
+		t.Fatalf("expected backlog with the last event, got %+v reset=%v", sub.Backlog, sub.Reset)
+	}
+	sub.Close()
+
+	// em dia: nada a reenviar
+	if sub := h.Subscribe([]int{2}, last.ID); sub.Reset || len(sub.Backlog) != 0 {
+		t.Fatalf("expected nothing to resend")
+	}
+
+	// o histórico guarda só 3: depois de mais 2, o evento seguinte ao primeiro se perdeu
+	h.Publish(FeedEvent{SellerID: 2, PostID: 4})
+	h.Publish(FeedEvent{SellerID: 2, PostID: 5})
+	if sub := h.Subscribe([]int{2}, first.ID); !sub.Reset {
+		t.Fatalf("expected reset when Last-Event-ID left the history")
+	}
+	// id de outro processo (maior que o último)
+	if sub := h.Subscribe([]int{2}, last.ID+100); !sub.Reset {
+		t.Fatalf("expected reset for unknown future id")
+	}
+}
+
+func TestFeedHub_SlowSubscriberIsDropped(t *testing.T) {
+	h := NewFeedHub(10, 2)
+	slow := h.Subscribe([]int{2}, 0)
+	for i := 0; i < 3; i++ {
+		h.Publish(FeedEvent{SellerID: 2, PostID: i + 1})
+	}
+	n := 0
+	for range slow.Events() {
+		n++
+	}
+	if n != 2 || !slow.Lagged() || h.Subscribers() != 0 {
+		t.Fatalf("expected slow subscriber dropped after 2 events, got n=%d lagged=%v subs=%d", n, slow.Lagged(), h.Subscribers())
+	}
+	slow.Close() // já removido: não pode fechar o canal de novo
+}
+
+func TestProductService_PublishAndDeleteFeedEvents(t *testing.T) {
+	_, svc := feedStore(t)
+	sub, err := svc.SubscribeFeed(1, 0)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer sub.Close()
+
+	publishAt(t, svc, 2, 0, 10)
+	ev := <-sub.Events()
+	if ev.Type != FeedEventPromo || ev.Post == nil || ev.Post.UserID != 2 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	if err := svc.DeleteMyPost(2, ev.PostID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if del := <-sub.Events(); del.Type != FeedEventDeleted || del.PostID != ev.PostID {
+		t.Fatalf("unexpected event %+v", del)
+	}
+
+	if _, err := svc.SubscribeFeed(99, 0); err == nil {
+		t.Fatalf("expected error for unknown user")
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// NotificationService grava a caixa de notificações e, com um barramento ligado,
+// avisa em tempo real (EventNotification no /ws).
+type NotificationService struct {
+	st     store.Store
+	events *EventBus
+}
+
+func NewNotificationService(st store.Store) *NotificationService {
+	return &NotificationService{st: st}
+}
+
+func (s *NotificationService) SetEventBus(b *EventBus) { s.events = b }
+
+// notify entrega n a cada destinatário que não desligou o tipo.
+func (s *NotificationService) notify(recipients []int, n domain.Notification) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	optOut, err := s.st.NotificationOptOuts(n.Type, recipients)
+	if err != nil {
+		return err
+	}
+	batch := make([]domain.Notification, 0, len(recipients))
+	for _, uid := range recipients {
+		if optOut[uid] {
+			continue
+		}
+		n.UserID = uid
+		batch = append(batch, n)
+	}
+	// publica as linhas gravadas: o cliente precisa do ID para marcar como lida
+	stored, err := s.st.AddNotifications(batch)
+	if err != nil {
+		return err
+	}
+	if s.events != nil {
+		for _, nt := range stored {
+			s.events.Publish(Event{Type: EventNotification, UserID: nt.UserID, Data: nt})
+		}
+	}
+	return nil
+}
+
+// FollowerAdded avisa o vendedor do novo seguidor.
+func (s *NotificationService) FollowerAdded(followerID, sellerID int) error {
+	follower, _ := s.st.GetUser(followerID)
+	return s.notify([]int{sellerID}, domain.Notification{
+		Type:    domain.NotificationNewFollower,
+		ActorID: followerID,
+		Message: fmt.Sprintf("%s começou a seguir você.", follower.Name),
+	})
+}
+
+// PromoPublished avisa os seguidores do vendedor de uma promoção nova.
+func (s *NotificationService) PromoPublished(p domain.Post) error {
+	if !p.HasPromo {
+		return nil
+	}
+	followers, err := s.st.FollowersOf(p.UserID)
+	if err != nil {
+		return err
+	}
+	ids := make([]int, len(followers))
+	for i, u := range followers {
+		ids[i] = u.ID
+	}
+	seller, _ := s.st.GetUser(p.UserID)
+	return s.notify(ids, domain.Notification{
+		Type:    domain.NotificationPromoStarted,
+		ActorID: p.UserID,
+		PostID:  p.PostID,
+		Message: fmt.Sprintf("%s publicou uma promoção: %s com %g%% de desconto.", seller.Name, p.Product.ProductName, p.Discount),
+	})
+}
+
+// PriceDropped avisa o dono do alerta que o preço do post ficou abaixo do pedido.
+func (s *NotificationService) PriceDropped(a domain.PriceAlert, p domain.Post) error {
+	return s.notify([]int{a.UserID}, domain.Notification{
+		Type:    domain.NotificationPriceDrop,
+		ActorID: p.UserID,
+		PostID:  p.PostID,
+		Message: fmt.Sprintf("%s baixou para %s (seu alerta: abaixo de %s).", p.Product.ProductName,
+			domain.FormatPrice(p.FinalPrice, p.Currency), domain.FormatPrice(a.Threshold, p.Currency)),
+	})
+}
+
+// PostLiked avisa o dono do post de um like novo (curtir o próprio post não avisa).
+func (s *NotificationService) PostLiked(userID, postID int) error {
+	p, ok := s.st.GetPost(postID)
+	if !ok || p.UserID == userID {
+		return nil
+	}
+	u, _ := s.st.GetUser(userID)
+	return s.notify([]int{p.UserID}, domain.Notification{
+		Type:    domain.NotificationPostLiked,
+		ActorID: userID,
+		PostID:  postID,
+		Message: fmt.Sprintf("%s curtiu %s.", u.Name, p.Product.ProductName),
+	})
+}
+
+// CommentAdded avisa o dono do post do comentário novo e, se for resposta, também
+// o autor da pergunta. Quem comentou não é avisado.
+func (s *NotificationService) CommentAdded(c domain.Comment) error {
+	p, ok := s.st.GetPost(c.PostID)
+	if !ok {
+		return nil
+	}
+	u, _ := s.st.GetUser(c.UserID)
+	n := domain.Notification{Type: domain.NotificationComment, ActorID: c.UserID, PostID: c.PostID}
+	if p.UserID != c.UserID {
+		n.Message = fmt.Sprintf("%s comentou em %s: %s", u.Name, p.Product.ProductName, c.Text)
+		if err := s.notify([]int{p.UserID}, n); err != nil {
+			return err
+		}
+	}
+	if c.ParentID == 0 {
+		return nil
+	}
+	q, ok := s.st.GetComment(c.ParentID)
+	if !ok || q.UserID == c.UserID || q.UserID == p.UserID {
+		return nil
+	}
+	n.Message = fmt.Sprintf("%s respondeu sua pergunta sobre %s: %s", u.Name, p.Product.ProductName, c.Text)
+	return s.notify([]int{q.UserID}, n)
+}
+
+// logNotifyErr: falhas ao notificar não desfazem a ação que as causou.
+func logNotifyErr(err error) {
+	if err != nil {
+		log.Printf("notificações: %v", err)
+	}
+}
+
+// List devolve uma página da caixa (mais recentes primeiro), o total e quantas não lidas.
+func (s *NotificationService) List(userID int, unreadOnly bool, page, limit int) ([]domain.Notification, int, int, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, 0, 0, err
+	}
+	items, total, err := s.st.Notifications(userID, unreadOnly, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := s.st.UnreadNotifications(userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return items, total, unread, nil
+}
+
+func (s *NotificationService) UnreadCount(userID int) (int, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return 0, err
+	}
+	return s.st.UnreadNotifications(userID)
+}
+
+// MarkRead marca ids (ou todas, com all) como lidas e devolve quantas mudaram.
+func (s *NotificationService) MarkRead(userID int, ids []int, all bool) (int, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return 0, err
+	}
+	if all == (len(ids) > 0) {
+		return 0, domain.ErrNotificationIDs
+	}
+	for _, id := range ids {
+		if err := domain.ValidateID(id); err != nil {
+			return 0, err
+		}
+	}
+	return s.st.MarkNotificationsRead(userID, ids)
+}
+
+// Preferences devolve a preferência de todos os tipos (ligado por padrão).
+func (s *NotificationService) Preferences(userID int) (domain.NotificationPrefs, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, err
+	}
+	prefs, err := s.st.NotificationPrefs(userID)
+	if err != nil {
+		return nil, err
+	}
+	return prefs.WithDefaults(), nil
+}
+
+// UpdatePreferences altera só os tipos informados.
+func (s *NotificationService) UpdatePreferences(userID int, prefs domain.NotificationPrefs) (domain.NotificationPrefs, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, err
+	}
+	for t := range prefs {
+		if err := domain.ValidateNotificationType(t); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, t)
+		}
+	}
+	if err := s.st.SetNotificationPrefs(userID, prefs); err != nil {
+		return nil, err
+	}
+	return s.Preferences(userID)
+}
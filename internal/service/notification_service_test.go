@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func notificationStore(t *testing.T) (*store.MemoryStore, *ProductService, *UserService, *NotificationService) {
+	t.Helper()
+	st, ps := feedStore(t)
+	ns := NewNotificationService(st)
+	ps.SetNotifications(ns)
+	us := NewUserService(st)
+	us.SetNotifications(ns)
+	return st, ps, us, ns
+}
+
+func TestNotifications_NewFollower(t *testing.T) {
+	_, _, us, ns := notificationStore(t)
+	bus := NewEventBus()
+	ns.SetEventBus(bus)
+	var got []Event
+	bus.Subscribe(func(ev Event) { got = append(got, ev) })
+
+	if err := us.Follow(4, 2); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	items, total, unread, err := ns.List(2, false, 1, 10)
+	if err != nil || total != 1 || unread != 1 {
+		t.Fatalf("expected 1 notification, got %d/%d (%v)", total, unread, err)
+	}
+	if items[0].Type != domain.NotificationNewFollower || items[0].ActorID != 4 || items[0].Message != "Other começou a seguir você." {
+		t.Fatalf("unexpected notification %+v", items[0])
+	}
+	if len(got) != 1 || got[0].Type != EventNotification || got[0].UserID != 2 {
+		t.Fatalf("expected realtime event for seller, got %+v", got)
+	}
+	// o evento leva a linha gravada: mesmo ID e data da caixa
+	sent, ok := got[0].Data.(domain.Notification)
+	if !ok || sent.ID == 0 || sent.ID != items[0].ID || !sent.CreatedAt.Equal(items[0].CreatedAt) {
+		t.Fatalf("expected event with stored notification %+v, got %+v", items[0], got[0].Data)
+	}
+}
+
+func TestNotifications_PromoRespectsPrefs(t *testing.T) {
+	_, ps, _, ns := notificationStore(t)
+	if _, err := ns.UpdatePreferences(4, domain.NotificationPrefs{domain.NotificationPromoStarted: false}); err != nil {
+		t.Fatalf("prefs: %v", err)
+	}
+
+	publishAt(t, ps, 3, 0, 0)  // sem promoção: ninguém é notificado
+	publishAt(t, ps, 3, 0, 20) // seguidores 1 e 4; 4 desligou
+
+	if n, _ := ns.UnreadCount(1); n != 1 {
+		t.Fatalf("expected buyer notified once, got %d", n)
+	}
+	if n, _ := ns.UnreadCount(4); n != 0 {
+		t.Fatalf("expected opted-out user not notified, got %d", n)
+	}
+	items, _, _, _ := ns.List(1, false, 1, 10)
+	if items[0].Type != domain.NotificationPromoStarted || items[0].PostID == 0 {
+		t.Fatalf("unexpected notification %+v", items[0])
+	}
+}
+
+func TestNotifications_PostLiked(t *testing.T) {
+	_, ps, _, ns := notificationStore(t)
+	bus := NewEventBus()
+	ns.SetEventBus(bus)
+	var got []Event
+	bus.Subscribe(func(ev Event) { got = append(got, ev) })
+	publishAt(t, ps, 2, 0, 0)
+
+	ps.SetLike(1, 1, true)
+	ps.SetLike(1, 1, true) // repetido não notifica de novo
+	ps.SetLike(2, 1, true) // o próprio dono também não
+	items, total, _, err := ns.List(2, false, 1, 10)
+	if err != nil || total != 1 {
+		t.Fatalf("expected 1 notification, got %d (%v)", total, err)
+	}
+	if items[0].Type != domain.NotificationPostLiked || items[0].ActorID != 1 || items[0].PostID != 1 || items[0].Message != "Buyer curtiu Mouse Gamer." {
+		t.Fatalf("unexpected notification %+v", items[0])
+	}
+	if len(got) != 1 || got[0].Type != EventNotification || got[0].UserID != 2 {
+		t.Fatalf("expected realtime event for the owner, got %+v", got)
+	}
+
+	if _, err := ns.UpdatePreferences(2, domain.NotificationPrefs{domain.NotificationPostLiked: false}); err != nil {
+		t.Fatalf("prefs: %v", err)
+	}
+	ps.SetLike(4, 1, true)
+	if _, total, _, _ := ns.List(2, false, 1, 10); total != 1 {
+		t.Fatalf("opted-out owner must not be notified, got %d", total)
+	}
+}
+
+func TestNotifications_CommentAdded(t *testing.T) {
+	st, ps, _, ns := notificationStore(t)
+	bus := NewEventBus()
+	ns.SetEventBus(bus)
+	var got []Event
+	bus.Subscribe(func(ev Event) { got = append(got, ev) })
+	publishAt(t, ps, 2, 0, 0)
+	cs := NewCommentService(st)
+	cs.SetNotifications(ns)
+
+	q, err := cs.Add(1, 1, 0, "Tem no 42?")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	items, total, _, _ := ns.List(2, false, 1, 10)
+	if total != 1 || items[0].Type != domain.NotificationComment || items[0].ActorID != 1 || items[0].PostID != 1 || items[0].Message != "Buyer comentou em Mouse Gamer: Tem no 42?" {
+		t.Fatalf("unexpected owner notifications %d %+v", total, items)
+	}
+	if len(got) != 1 || got[0].Type != EventNotification || got[0].UserID != 2 {
+		t.Fatalf("expected realtime event for the owner, got %+v", got)
+	}
+
+	// resposta do vendedor: só quem perguntou é avisado
+	if _, err := cs.Add(2, 1, q.ID, "Tem sim"); err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+	if _, total, _, _ := ns.List(2, false, 1, 10); total != 1 {
+		t.Fatalf("owner must not be notified of own reply, got %d", total)
+	}
+	items, total, _, _ = ns.List(1, false, 1, 10)
+	if total != 1 || items[0].ActorID != 2 || items[0].Message != "SellerA respondeu sua pergunta sobre Mouse Gamer: Tem sim" {
+		t.Fatalf("unexpected asker notifications %d %+v", total, items)
+	}
+
+	// resposta de terceiro: dono e quem perguntou
+	cs.Add(4, 1, q.ID, "Também quero")
+	if n, _ := ns.UnreadCount(2); n != 2 {
+		t.Fatalf("expected owner notified twice, got %d", n)
+	}
+	if n, _ := ns.UnreadCount(1); n != 2 {
+		t.Fatalf("expected asker notified twice, got %d", n)
+	}
+}
+
+func TestNotifications_MarkReadAndPrefs(t *testing.T) {
+	_, ps, _, ns := notificationStore(t)
+	publishAt(t, ps, 2, 0, 10)
+	publishAt(t, ps, 2, 0, 15)
+
+	if _, err := ns.MarkRead(1, nil, false); !errors.Is(err, domain.ErrNotificationIDs) {
+		t.Fatalf("expected ErrNotificationIDs, got %v", err)
+	}
+	if _, err := ns.MarkRead(1, []int{1}, true); !errors.Is(err, domain.ErrNotificationIDs) {
+		t.Fatalf("expected ErrNotificationIDs for ids+all, got %v", err)
+	}
+	items, _, _, _ := ns.List(1, true, 1, 10)
+	if n, err := ns.MarkRead(1, []int{items[0].ID}, false); err != nil || n != 1 {
+		t.Fatalf("mark: %d %v", n, err)
+	}
+	if n, err := ns.MarkRead(1, nil, true); err != nil || n != 1 {
+		t.Fatalf("mark all: %d %v", n, err)
+	}
+
+	prefs, err := ns.Preferences(1)
+	if err != nil || len(prefs) != len(domain.NotificationTypes) || !prefs[domain.NotificationNewFollower] {
+		t.Fatalf("expected all types enabled by default, got %v (%v)", prefs, err)
+	}
+	if _, err := ns.UpdatePreferences(1, domain.NotificationPrefs{"nope": true}); !errors.Is(err, domain.ErrNotificationType) {
+		t.Fatalf("expected ErrNotificationType, got %v", err)
+	}
+}
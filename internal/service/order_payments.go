@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/payment"
+)
+
+var ErrPaymentsUnavailable = errors.New("Pagamentos indisponíveis.")
+
+// PaymentGateway é o provedor de pagamentos. payment.Fake implementa tudo
+// localmente; um provedor real entra aqui sem mudar o OrderService.
+type PaymentGateway interface {
+	// Authorize reserva o valor. Pode voltar pending (confirmação chega depois por
+	// callback) ou payment.ErrDeclined.
+	Authorize(req payment.AuthorizeRequest) (payment.Payment, error)
+	Capture(paymentID string) (payment.Payment, error)
+	Refund(paymentID string) (payment.Payment, error)
+	// Void cancela a reserva de um pagamento autorizado que não foi capturado.
+	Void(paymentID string) (payment.Payment, error)
+	// VerifyWebhook confere a assinatura do callback e devolve o evento.
+	VerifyWebhook(body []byte, signature string) (payment.Event, error)
+}
+
+// SetPaymentGateway liga os pagamentos: o pedido só passa a paid pelo provedor
+// (não mais pelo PATCH do comprador) e cancelar um pedido pago estorna.
+func (s *OrderService) SetPaymentGateway(g PaymentGateway) { s.payments = g }
+
+// Pay cobra o pedido pendente do comprador. Com aprovação imediata o pedido volta
+// paid; com confirmação atrasada volta pending com payment_status pending e o
+// callback termina o serviço. Pagar de novo um pedido já pago não cobra outra vez.
+func (s *OrderService) Pay(userID, orderID int, token string) (domain.Order, error) {
+	if s.payments == nil {
+		return domain.Order{}, ErrPaymentsUnavailable
+	}
+	o, role, err := s.order(userID, orderID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if role&roleBuyer == 0 {
+		return domain.Order{}, domain.ErrOrderTransition
+	}
+	if o.Status == domain.OrderPaid && o.PaymentStatus == payment.StatusCaptured {
+		return o, nil
+	}
+	if o.Status != domain.OrderPending {
+		return domain.Order{}, domain.ErrOrderTransition
+	}
+
+	p, err := s.payments.Authorize(payment.AuthorizeRequest{
+		Reference: fmt.Sprintf("order-%d", o.ID),
+		Amount:    o.Total,
+		Currency:  o.Currency,
+		Token:     token,
+	})
+	if errors.Is(err, payment.ErrDeclined) {
+		if _, serr := s.st.SetOrderPayment(o.ID, p.ID, payment.StatusDeclined); serr != nil {
+			return domain.Order{}, serr
+		}
+		return domain.Order{}, err
+	}
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if o, err = s.st.SetOrderPayment(o.ID, p.ID, p.Status); err != nil {
+		return domain.Order{}, err
+	}
+	if p.Status != payment.StatusAuthorized {
+		return o, nil // pending: espera o callback
+	}
+	return s.capture(o)
+}
+
+// capture cobra o pagamento autorizado e leva o pedido a paid. Se o pedido foi
+// cancelado enquanto o pagamento estava pendente, libera a reserva em vez de cobrar.
+func (s *OrderService) capture(o domain.Order) (domain.Order, error) {
+	if o.Status == domain.OrderCancelled {
+		o.PaymentStatus = payment.StatusAuthorized
+		return s.refund(o), nil
+	}
+	p, err := s.payments.Capture(o.PaymentID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if o, err = s.st.SetOrderPayment(o.ID, p.ID, p.Status); err != nil {
+		return domain.Order{}, err
+	}
+	if o.Status == domain.OrderCancelled {
+		return s.refund(o), nil
+	}
+	if o.Status != domain.OrderPending {
+		return o, nil
+	}
+	paid, err := s.st.UpdateOrderStatus(o.ID, domain.OrderPending, domain.OrderPaid, s.now())
+	if errors.Is(err, domain.ErrOrderTransition) {
+		// outro callback (ou um cancelamento) chegou antes: vale o que está gravado
+		current, _ := s.st.GetOrder(o.ID)
+		if current.Status == domain.OrderCancelled {
+			return s.refund(current), nil
+		}
+		return current, nil
+	}
+	return paid, err
+}
+
+// refund desfaz o pagamento de um pedido cancelado e devolve o pedido atualizado:
+// estorna o que foi capturado e cancela a reserva do que só foi autorizado.
+// Falhas ficam no log: o pedido continua com o payment_status de antes para tratar à mão.
+func (s *OrderService) refund(o domain.Order) domain.Order {
+	if s.payments == nil {
+		return o
+	}
+	var p payment.Payment
+	var err error
+	switch o.PaymentStatus {
+	case payment.StatusCaptured:
+		p, err = s.payments.Refund(o.PaymentID)
+	case payment.StatusAuthorized:
+		p, err = s.payments.Void(o.PaymentID)
+	default:
+		return o
+	}
+	if err != nil {
+		log.Printf("pagamentos: estorno do pedido %d: %v", o.ID, err)
+		return o
+	}
+	refunded, err := s.st.SetOrderPayment(o.ID, p.ID, p.Status)
+	if err != nil {
+		log.Printf("pagamentos: estorno do pedido %d: %v", o.ID, err)
+		return o
+	}
+	return refunded
+}
+
+// HandlePaymentCallback processa um callback do provedor. É idempotente: o evento
+// é reservado no store antes de qualquer efeito, então reentregas (inclusive
+// simultâneas) são ignoradas, e cada passo confere o estado atual antes de mudar.
+// Se o processamento falhar a reserva é desfeita e o provedor pode reenviar.
+// Eventos de pagamentos que não conhecemos são aceitos e descartados.
+func (s *OrderService) HandlePaymentCallback(body []byte, signature string) error {
+	if s.payments == nil {
+		return ErrPaymentsUnavailable
+	}
+	ev, err := s.payments.VerifyWebhook(body, signature)
+	if err != nil {
+		return err
+	}
+	claimed, err := s.st.ClaimPaymentEvent(ev.ID)
+	if err != nil || !claimed {
+		return err
+	}
+	if err := s.applyPaymentEvent(ev); err != nil {
+		if rerr := s.st.ReleasePaymentEvent(ev.ID); rerr != nil {
+			log.Printf("pagamentos: liberar evento %s: %v", ev.ID, rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *OrderService) applyPaymentEvent(ev payment.Event) error {
+	o, ok := s.st.OrderByPayment(ev.Payment.ID)
+	if !ok {
+		return nil
+	}
+	switch ev.Type {
+	case payment.EventAuthorized:
+		if o.PaymentStatus == payment.StatusPending || o.PaymentStatus == payment.StatusAuthorized {
+			if _, err := s.capture(o); err != nil {
+				return err
+			}
+		}
+	case payment.EventDeclined:
+		// o pedido continua pending: o comprador pode tentar outro meio
+		if o.PaymentStatus == payment.StatusPending {
+			if _, err := s.st.SetOrderPayment(o.ID, o.PaymentID, payment.StatusDeclined); err != nil {
+				return err
+			}
+		}
+	case payment.EventRefunded, payment.EventVoided:
+		status := payment.StatusRefunded
+		if ev.Type == payment.EventVoided {
+			status = payment.StatusVoided
+		}
+		if _, err := s.st.SetOrderPayment(o.ID, o.PaymentID, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
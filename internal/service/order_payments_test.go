@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/payment"
+)
+
+// paymentsFixture monta um pedido pendente do comprador 1 com o vendedor 2.
+func paymentsFixture(t *testing.T) (*OrderService, *payment.Fake, func() domain.Order) {
+	t.Helper()
+	st, ps := feedStore(t)
+	publishAt(t, ps, 2, 0, 0)
+	svc := NewOrderService(st)
+	gw := payment.NewFake("secret")
+	svc.SetPaymentGateway(gw)
+	place := func() domain.Order {
+		t.Helper()
+		if _, err := svc.AddToCart(1, 1, 1); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		orders, err := svc.Checkout(1, "")
+		if err != nil {
+			t.Fatalf("checkout: %v", err)
+		}
+		return orders[0]
+	}
+	return svc, gw, place
+}
+
+func TestOrderService_Pay(t *testing.T) {
+	svc, gw, place := paymentsFixture(t)
+	o := place()
+
+	if _, err := svc.Transition(1, o.ID, domain.OrderPaid); err != domain.ErrOrderTransition {
+		t.Fatalf("manual paid must be refused with a gateway, got %v", err)
+	}
+	if _, err := svc.Pay(2, o.ID, payment.TokenApprove); err != domain.ErrOrderTransition {
+		t.Fatalf("seller cannot pay, got %v", err)
+	}
+	if _, err := svc.Pay(1, o.ID, payment.TokenDecline); err != payment.ErrDeclined {
+		t.Fatalf("expected ErrDeclined, got %v", err)
+	}
+	if got, _ := svc.Get(1, o.ID); got.Status != domain.OrderPending || got.PaymentStatus != payment.StatusDeclined {
+		t.Fatalf("declined payment must keep the order pending: %+v", got)
+	}
+
+	paid, err := svc.Pay(1, o.ID, payment.TokenApprove)
+	if err != nil || paid.Status != domain.OrderPaid || paid.PaymentStatus != payment.StatusCaptured {
+		t.Fatalf("pay: %+v %v", paid, err)
+	}
+	if p, _ := gw.Get(paid.PaymentID); p.Amount != paid.Total || p.Currency != paid.Currency {
+		t.Fatalf("gateway must receive the exact total in cents: %+v vs %v", p, paid.Total)
+	}
+	if again, err := svc.Pay(1, o.ID, payment.TokenApprove); err != nil || again.PaymentID != paid.PaymentID {
+		t.Fatalf("paying twice must not charge again: %+v %v", again, err)
+	}
+
+	// cancelar um pedido cobrado estorna
+	cancelled, err := svc.Transition(2, o.ID, domain.OrderCancelled)
+	if err != nil || cancelled.PaymentStatus != payment.StatusRefunded {
+		t.Fatalf("cancel: %+v %v", cancelled, err)
+	}
+	if p, _ := gw.Get(paid.PaymentID); p.Status != payment.StatusRefunded {
+		t.Fatalf("gateway not refunded: %+v", p)
+	}
+}
+
+func TestOrderService_DelayedPaymentCallback(t *testing.T) {
+	svc, gw, place := paymentsFixture(t)
+	o := place()
+
+	pending, err := svc.Pay(1, o.ID, payment.TokenDelayed)
+	if err != nil || pending.Status != domain.OrderPending || pending.PaymentStatus != payment.StatusPending {
+		t.Fatalf("pay: %+v %v", pending, err)
+	}
+	if err := svc.HandlePaymentCallback([]byte(`{}`), "sha256=00"); err != payment.ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	body, sig, err := gw.Confirm(pending.PaymentID, true)
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	for i := 0; i < 2; i++ { // reentrega do mesmo evento
+		if err := svc.HandlePaymentCallback(body, sig); err != nil {
+			t.Fatalf("callback %d: %v", i, err)
+		}
+	}
+	got, _ := svc.Get(1, o.ID)
+	if got.Status != domain.OrderPaid || got.PaymentStatus != payment.StatusCaptured {
+		t.Fatalf("expected paid after callback, got %+v", got)
+	}
+
+	// recusa atrasada deixa o pedido pendente para tentar de novo
+	o2 := place()
+	p2, _ := svc.Pay(1, o2.ID, payment.TokenDelayed)
+	body, sig, _ = gw.Confirm(p2.PaymentID, false)
+	if err := svc.HandlePaymentCallback(body, sig); err != nil {
+		t.Fatalf("callback: %v", err)
+	}
+	if got, _ = svc.Get(1, o2.ID); got.Status != domain.OrderPending || got.PaymentStatus != payment.StatusDeclined {
+		t.Fatalf("unexpected order after late decline %+v", got)
+	}
+
+	// cancelado enquanto esperava: a aprovação tardia libera a reserva em vez de cobrar
+	o3 := place()
+	p3, _ := svc.Pay(1, o3.ID, payment.TokenDelayed)
+	if _, err := svc.Transition(1, o3.ID, domain.OrderCancelled); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	body, sig, _ = gw.Confirm(p3.PaymentID, true)
+	if err := svc.HandlePaymentCallback(body, sig); err != nil {
+		t.Fatalf("callback: %v", err)
+	}
+	if got, _ = svc.Get(1, o3.ID); got.Status != domain.OrderCancelled || got.PaymentStatus != payment.StatusVoided {
+		t.Fatalf("expected void for cancelled order, got %+v", got)
+	}
+	if p, _ := gw.Get(p3.PaymentID); p.Status != payment.StatusVoided {
+		t.Fatalf("gateway must not capture a cancelled order: %+v", p)
+	}
+}
+
+func TestOrderService_CancelVoidsAuthorizedPayment(t *testing.T) {
+	svc, fake, place := paymentsFixture(t)
+	gw := &countingGateway{Fake: fake}
+	svc.SetPaymentGateway(gw)
+
+	// autorizado, mas a captura falhou: o pedido fica pending com a reserva
+	o := place()
+	gw.failNext = true
+	if _, err := svc.Pay(1, o.ID, payment.TokenApprove); err == nil {
+		t.Fatalf("expected capture error")
+	}
+	got, _ := svc.Get(1, o.ID)
+	if got.Status != domain.OrderPending || got.PaymentStatus != payment.StatusAuthorized {
+		t.Fatalf("expected authorized pending order, got %+v", got)
+	}
+
+	cancelled, err := svc.Transition(1, o.ID, domain.OrderCancelled)
+	if err != nil || cancelled.PaymentStatus != payment.StatusVoided {
+		t.Fatalf("cancel: %+v %v", cancelled, err)
+	}
+	if p, _ := fake.Get(got.PaymentID); p.Status != payment.StatusVoided {
+		t.Fatalf("gateway authorization not voided: %+v", p)
+	}
+	if gw.captures != 0 {
+		t.Fatalf("expected no capture, got %d", gw.captures)
+	}
+}
+
+// countingGateway conta as capturas e pode falhar a próxima.
+type countingGateway struct {
+	*payment.Fake
+	mu       sync.Mutex
+	captures int
+	failNext bool
+}
+
+func (g *countingGateway) Capture(paymentID string) (payment.Payment, error) {
+	g.mu.Lock()
+	if g.failNext {
+		g.failNext = false
+		g.mu.Unlock()
+		return payment.Payment{}, errors.New("provedor fora do ar")
+	}
+	g.captures++
+	g.mu.Unlock()
+	return g.Fake.Capture(paymentID)
+}
+
+func TestOrderService_PaymentCallbackClaimsEvent(t *testing.T) {
+	svc, fake, place := paymentsFixture(t)
+	gw := &countingGateway{Fake: fake}
+	svc.SetPaymentGateway(gw)
+
+	// falha no processamento libera o evento: a reentrega tenta de novo
+	o := place()
+	pending, _ := svc.Pay(1, o.ID, payment.TokenDelayed)
+	body, sig, _ := fake.Confirm(pending.PaymentID, true)
+	gw.failNext = true
+	if err := svc.HandlePaymentCallback(body, sig); err == nil {
+		t.Fatalf("expected capture error")
+	}
+	if got, _ := svc.Get(1, o.ID); got.Status != domain.OrderPending {
+		t.Fatalf("order must stay pending after a failed callback: %+v", got)
+	}
+
+	// reentregas simultâneas: só uma processa
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.HandlePaymentCallback(body, sig)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("callback: %v", err)
+		}
+	}
+	if gw.captures != 1 {
+		t.Fatalf("expected a single capture, got %d", gw.captures)
+	}
+	if got, _ := svc.Get(1, o.ID); got.Status != domain.OrderPaid || got.PaymentStatus != payment.StatusCaptured {
+		t.Fatalf("expected paid after redelivery, got %+v", got)
+	}
+}
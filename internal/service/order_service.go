@@ -0,0 +1,327 @@
+package service
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// orderTransitions é a máquina de estados dos pedidos: de cada status, para onde
+// pode ir. delivered e cancelled são finais.
+var orderTransitions = map[string][]string{
+	domain.OrderPending: {domain.OrderPaid, domain.OrderCancelled},
+	domain.OrderPaid:    {domain.OrderShipped, domain.OrderCancelled},
+	domain.OrderShipped: {domain.OrderDelivered},
+}
+
+// quem pode levar o pedido a cada status
+const (
+	roleBuyer = 1 << iota
+	roleSeller
+)
+
+var orderTransitionRoles = map[string]int{
+	domain.OrderPaid:      roleBuyer,
+	domain.OrderShipped:   roleSeller,
+	domain.OrderDelivered: roleBuyer | roleSeller,
+	domain.OrderCancelled: roleBuyer | roleSeller,
+}
+
+func canTransitionOrder(from, to string) bool {
+	for _, s := range orderTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderService cuida do carrinho, do checkout e do ciclo de vida dos pedidos.
+type OrderService struct {
+	st       store.Store
+	payments PaymentGateway
+	coupons  *CouponService
+	now      func() time.Time
+}
+
+func NewOrderService(st store.Store) *OrderService {
+	return &OrderService{st: st, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// SetCoupons habilita cupons no carrinho e no checkout.
+func (s *OrderService) SetCoupons(c *CouponService) { s.coupons = c }
+
+// Cart devolve o carrinho precificado com o FinalPrice atual de cada post e, com
+// couponCode, o desconto que o cupom daria (sem consumir usos).
+func (s *OrderService) Cart(userID int, couponCode string) (domain.Cart, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.Cart{}, err
+	}
+	_, cart, err := s.cart(userID)
+	if err != nil || couponCode == "" {
+		return cart, err
+	}
+	cart, _, err = s.applyCoupon(userID, couponCode, cart)
+	return cart, err
+}
+
+func (s *OrderService) applyCoupon(userID int, code string, cart domain.Cart) (domain.Cart, domain.Coupon, error) {
+	if s.coupons == nil {
+		return domain.Cart{}, domain.Coupon{}, store.ErrCouponNotFound
+	}
+	return s.coupons.Apply(userID, code, cart)
+}
+
+// cart carrega as linhas do carrinho com os posts atuais.
+func (s *OrderService) cart(userID int) ([]domain.Post, domain.Cart, error) {
+	lines, err := s.st.CartLines(userID)
+	if err != nil {
+		return nil, domain.Cart{}, err
+	}
+	cart := domain.Cart{Items: []domain.CartItem{}}
+	var posts []domain.Post
+	for _, l := range lines {
+		p, ok := s.st.GetPost(l.PostID)
+		if !ok {
+			continue // apagado entre a leitura das linhas e a do post
+		}
+		item := domain.CartItem{
+			PostID:    p.PostID,
+			SellerID:  p.UserID,
+			Product:   withImages([]domain.Post{p})[0].Product,
+			UnitPrice: p.FinalPrice,
+			Quantity:  l.Quantity,
+			Subtotal:  domain.LineTotal(p.FinalPrice, l.Quantity),
+		}
+		cart.Items = append(cart.Items, item)
+		cart.Currency = p.Currency
+		cart.Count += item.Quantity
+		cart.Subtotal += item.Subtotal
+		posts = append(posts, p)
+	}
+	cart.Total = cart.Subtotal
+	return posts, cart, nil
+}
+
+// AddToCart soma quantity ao que já houver do post no carrinho.
+func (s *OrderService) AddToCart(userID, postID, quantity int) (domain.Cart, error) {
+	if err := domain.ValidateCartQuantity(quantity); err != nil {
+		return domain.Cart{}, err
+	}
+	lines, err := s.st.CartLines(userID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	for _, l := range lines {
+		if l.PostID == postID {
+			quantity += l.Quantity
+		}
+	}
+	return s.SetCartQuantity(userID, postID, quantity)
+}
+
+// SetCartQuantity troca a quantidade do post no carrinho; 0 tira o post.
+func (s *OrderService) SetCartQuantity(userID, postID, quantity int) (domain.Cart, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.Cart{}, err
+	}
+	if err := domain.ValidateID(postID); err != nil {
+		return domain.Cart{}, err
+	}
+	if quantity != 0 {
+		if err := domain.ValidateCartQuantity(quantity); err != nil {
+			return domain.Cart{}, err
+		}
+		p, ok := s.st.GetPost(postID)
+		if !ok {
+			return domain.Cart{}, store.ErrPostNotFound
+		}
+		if p.UserID == userID {
+			return domain.Cart{}, domain.ErrCartOwnPost
+		}
+		if err := s.checkCartCurrency(userID, p); err != nil {
+			return domain.Cart{}, err
+		}
+	}
+	if err := s.st.SetCartQuantity(userID, postID, quantity); err != nil {
+		return domain.Cart{}, err
+	}
+	_, cart, err := s.cart(userID)
+	return cart, err
+}
+
+// checkCartCurrency recusa p se o carrinho já tiver outro post em moeda diferente
+// (os totais são somas simples, sem conversão).
+func (s *OrderService) checkCartCurrency(userID int, p domain.Post) error {
+	lines, err := s.st.CartLines(userID)
+	if err != nil {
+		return err
+	}
+	for _, l := range lines {
+		if l.PostID == p.PostID {
+			continue
+		}
+		if other, ok := s.st.GetPost(l.PostID); ok && other.Currency != p.Currency {
+			return domain.ErrCartCurrency
+		}
+	}
+	return nil
+}
+
+// Checkout transforma o carrinho em pedidos pendentes, um por vendedor, com cópia
+// do produto e dos preços de agora, e esvazia o carrinho. Com couponCode, o
+// desconto vai para o pedido do vendedor do cupom e um uso é consumido.
+func (s *OrderService) Checkout(userID int, couponCode string) ([]domain.Order, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, err
+	}
+	posts, cart, err := s.cart(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, domain.ErrCartEmpty
+	}
+	var coupon domain.Coupon
+	if couponCode != "" {
+		if cart, coupon, err = s.applyCoupon(userID, couponCode, cart); err != nil {
+			return nil, err
+		}
+	}
+
+	bySeller := map[int]*domain.Order{}
+	var sellers []int
+	for i, item := range cart.Items {
+		p := posts[i]
+		snapshot := p.Product
+		snapshot.Images = nil // as variantes são derivadas de image_url na leitura
+		o, ok := bySeller[p.UserID]
+		if !ok {
+			o = &domain.Order{SellerID: p.UserID, Status: domain.OrderPending, Currency: p.Currency}
+			bySeller[p.UserID] = o
+			sellers = append(sellers, p.UserID)
+		}
+		o.Items = append(o.Items, domain.OrderItem{
+			PostID:    p.PostID,
+			Product:   snapshot,
+			Price:     p.Price,
+			HasPromo:  p.HasPromo,
+			Discount:  p.Discount,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+			Subtotal:  item.Subtotal,
+		})
+		o.Subtotal += item.Subtotal
+	}
+	sort.Ints(sellers)
+	orders := make([]domain.Order, len(sellers))
+	for i, id := range sellers {
+		o := bySeller[id]
+		if coupon.ID != 0 && id == coupon.SellerID {
+			o.CouponCode, o.Discount = coupon.Code, cart.Discount
+		}
+		o.Total = o.Subtotal - o.Discount
+		orders[i] = *o
+	}
+
+	if coupon.ID == 0 {
+		return s.st.PlaceOrders(userID, orders)
+	}
+	if err := s.coupons.Redeem(userID, coupon); err != nil {
+		return nil, err
+	}
+	placed, err := s.st.PlaceOrders(userID, orders)
+	if err != nil {
+		if rerr := s.coupons.Release(userID, coupon); rerr != nil {
+			log.Printf("cupons: devolver uso de %s: %v", coupon.Code, rerr)
+		}
+		return nil, err
+	}
+	return placed, nil
+}
+
+// Get devolve o pedido para o comprador ou o vendedor; para os demais ele não existe.
+func (s *OrderService) Get(userID, orderID int) (domain.Order, error) {
+	o, _, err := s.order(userID, orderID)
+	return o, err
+}
+
+// order busca o pedido e diz o papel do usuário nele.
+func (s *OrderService) order(userID, orderID int) (domain.Order, int, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.Order{}, 0, err
+	}
+	if err := domain.ValidateID(orderID); err != nil {
+		return domain.Order{}, 0, err
+	}
+	o, ok := s.st.GetOrder(orderID)
+	if !ok {
+		return domain.Order{}, 0, store.ErrOrderNotFound
+	}
+	role := 0
+	if o.BuyerID == userID {
+		role |= roleBuyer
+	}
+	if o.SellerID == userID {
+		role |= roleSeller
+	}
+	if role == 0 {
+		return domain.Order{}, 0, store.ErrOrderNotFound
+	}
+	return o, role, nil
+}
+
+// Transition leva o pedido ao status to. O comprador paga (com provedor de
+// pagamentos, só via Pay); o vendedor envia; qualquer um dos dois confirma a
+// entrega ou cancela antes do envio, e cancelar um pedido cobrado estorna.
+func (s *OrderService) Transition(userID, orderID int, to string) (domain.Order, error) {
+	if err := domain.ValidateOrderStatus(to); err != nil || to == "" {
+		return domain.Order{}, domain.ErrOrderStatus
+	}
+	o, role, err := s.order(userID, orderID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if !canTransitionOrder(o.Status, to) || orderTransitionRoles[to]&role == 0 {
+		return domain.Order{}, domain.ErrOrderTransition
+	}
+	if to == domain.OrderPaid && s.payments != nil {
+		return domain.Order{}, domain.ErrOrderTransition // só o provedor confirma pagamento (Pay)
+	}
+	updated, err := s.st.UpdateOrderStatus(orderID, o.Status, to, s.now())
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if to == domain.OrderCancelled {
+		updated = s.refund(updated)
+		if s.coupons != nil {
+			if err := s.coupons.ReleaseOrder(updated); err != nil {
+				log.Printf("cupons: devolver uso do pedido %d: %v", updated.ID, err)
+			}
+		}
+	}
+	return updated, nil
+}
+
+// Purchases pagina os pedidos do comprador. page começa em 1.
+func (s *OrderService) Purchases(userID int, status string, page, limit int) ([]domain.Order, int, error) {
+	return s.list(domain.OrderFilter{BuyerID: userID, Status: status}, userID, page, limit)
+}
+
+// Sales pagina os pedidos recebidos pelo vendedor. page começa em 1.
+func (s *OrderService) Sales(userID int, status string, page, limit int) ([]domain.Order, int, error) {
+	return s.list(domain.OrderFilter{SellerID: userID, Status: status}, userID, page, limit)
+}
+
+func (s *OrderService) list(f domain.OrderFilter, userID, page, limit int) ([]domain.Order, int, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, 0, err
+	}
+	if err := domain.ValidateOrderStatus(f.Status); err != nil {
+		return nil, 0, err
+	}
+	return s.st.Orders(f, limit, (page-1)*limit)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func TestOrderService_CartAndCheckout(t *testing.T) {
+	st, ps := feedStore(t)
+	publishAt(t, ps, 2, 0, 0)  // post 1: 100
+	publishAt(t, ps, 3, 0, 10) // post 2: 90 em promoção
+	publishAt(t, ps, 2, 0, 50) // post 3: 50 em promoção
+	svc := NewOrderService(st)
+
+	if _, err := svc.AddToCart(2, 1, 1); err != domain.ErrCartOwnPost {
+		t.Fatalf("expected ErrCartOwnPost, got %v", err)
+	}
+	if _, err := svc.AddToCart(1, 1, 0); err != domain.ErrCartQuantity {
+		t.Fatalf("expected ErrCartQuantity, got %v", err)
+	}
+	if _, err := svc.AddToCart(1, 99, 1); err != store.ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	if _, err := svc.Checkout(1, ""); err != domain.ErrCartEmpty {
+		t.Fatalf("expected ErrCartEmpty, got %v", err)
+	}
+
+	_, _ = svc.AddToCart(1, 1, 1)
+	_, _ = svc.AddToCart(1, 1, 1) // soma
+	_, _ = svc.AddToCart(1, 2, 3)
+	cart, err := svc.AddToCart(1, 3, 1)
+	if err != nil || cart.Count != 6 || cart.Total != 520_00 {
+		t.Fatalf("unexpected cart %+v %v", cart, err)
+	}
+	if _, err := svc.AddToCart(1, 1, domain.MaxCartQuantity); err != domain.ErrCartQuantity {
+		t.Fatalf("expected ErrCartQuantity past the limit, got %v", err)
+	}
+	if cart, _ = svc.SetCartQuantity(1, 2, 1); cart.Total != 340_00 || cart.Items[1].Subtotal != 90_00 {
+		t.Fatalf("unexpected cart after set %+v", cart)
+	}
+
+	orders, err := svc.Checkout(1, "")
+	if err != nil || len(orders) != 2 {
+		t.Fatalf("checkout: %+v %v", orders, err)
+	}
+	// um pedido por vendedor, em ordem de vendedor
+	if orders[0].SellerID != 2 || len(orders[0].Items) != 2 || orders[0].Total != 250_00 || orders[0].Status != domain.OrderPending {
+		t.Fatalf("unexpected first order %+v", orders[0])
+	}
+	if it := orders[1].Items[0]; it.UnitPrice != 90_00 || it.Price != 100_00 || !it.HasPromo || it.Product.ProductName != "Mouse Gamer" {
+		t.Fatalf("unexpected snapshot %+v", it)
+	}
+	if cart, _ = svc.Cart(1, ""); len(cart.Items) != 0 {
+		t.Fatalf("cart not emptied: %+v", cart)
+	}
+
+	// a cópia não muda quando o post some
+	if err := ps.DeleteMyPost(3, 2); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if o, err := svc.Get(1, orders[1].ID); err != nil || o.Items[0].Product.ProductName != "Mouse Gamer" {
+		t.Fatalf("order changed with the post: %+v %v", o, err)
+	}
+}
+
+func TestOrderService_Transitions(t *testing.T) {
+	st, ps := feedStore(t)
+	publishAt(t, ps, 2, 0, 0)
+	svc := NewOrderService(st)
+
+	place := func() domain.Order {
+		t.Helper()
+		if _, err := svc.AddToCart(1, 1, 1); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		orders, err := svc.Checkout(1, "")
+		if err != nil {
+			t.Fatalf("checkout: %v", err)
+		}
+		return orders[0]
+	}
+	o := place()
+
+	steps := []struct {
+		user int
+		to   string
+		want error
+	}{
+		{4, domain.OrderPaid, store.ErrOrderNotFound}, // quem não participa nem vê o pedido
+		{1, "lost", domain.ErrOrderStatus},
+		{1, domain.OrderShipped, domain.ErrOrderTransition}, // pular o pagamento
+		{2, domain.OrderPaid, domain.ErrOrderTransition},    // só o comprador paga
+		{1, domain.OrderPaid, nil},
+		{1, domain.OrderShipped, domain.ErrOrderTransition}, // só o vendedor envia
+		{2, domain.OrderShipped, nil},
+		{1, domain.OrderCancelled, domain.ErrOrderTransition}, // já enviado
+		{1, domain.OrderDelivered, nil},
+		{2, domain.OrderPending, domain.ErrOrderTransition}, // final
+	}
+	for i, s := range steps {
+		if _, err := svc.Transition(s.user, o.ID, s.to); err != s.want {
+			t.Fatalf("step %d (%d -> %s): expected %v, got %v", i, s.user, s.to, s.want, err)
+		}
+	}
+
+	// o vendedor pode cancelar um pedido pago
+	o2 := place()
+	_, _ = svc.Transition(1, o2.ID, domain.OrderPaid)
+	if got, err := svc.Transition(2, o2.ID, domain.OrderCancelled); err != nil || got.Status != domain.OrderCancelled {
+		t.Fatalf("cancel: %+v %v", got, err)
+	}
+
+	buys, total, err := svc.Purchases(1, domain.OrderDelivered, 1, 10)
+	if err != nil || total != 1 || buys[0].ID != o.ID {
+		t.Fatalf("unexpected purchases %+v %d %v", buys, total, err)
+	}
+	if sales, total, _ := svc.Sales(2, "", 1, 10); total != 2 || sales[0].ID != o2.ID {
+		t.Fatalf("unexpected sales %+v %d", sales, total)
+	}
+	if _, _, err := svc.Sales(2, "lost", 1, 10); err != domain.ErrOrderStatus {
+		t.Fatalf("expected ErrOrderStatus, got %v", err)
+	}
+}
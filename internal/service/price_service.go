@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// PriceService mantém o histórico de preços dos posts e os alertas de queda.
+// As mudanças feitas pelo vendedor são verificadas na hora; as que vêm só do
+// relógio (promoção agendada que começa) são pegas por Run.
+type PriceService struct {
+	st       store.Store
+	notifier *NotificationService
+	now      func() time.Time
+}
+
+func NewPriceService(st store.Store) *PriceService {
+	return &PriceService{st: st, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// SetNotifications faz os alertas disparados virarem notificações price_drop.
+func (s *PriceService) SetNotifications(n *NotificationService) { s.notifier = n }
+
+// Record guarda a configuração de preço de p (HasPromo configurado, não avaliado)
+// e confere os alertas do post. Falhas são só logadas: o preço já mudou.
+func (s *PriceService) Record(p domain.Post) {
+	if err := s.st.AddPriceChange(domain.PriceChangeOf(p, s.now())); err != nil {
+		log.Printf("preços: histórico do post %d: %v", p.PostID, err)
+		return
+	}
+	if _, err := s.check(p.PostID); err != nil {
+		log.Printf("preços: alertas do post %d: %v", p.PostID, err)
+	}
+}
+
+// History devolve a linha do tempo do preço final do post e o menor preço dos
+// últimos 30 dias. Posts sem mudanças registradas mostram só o preço atual.
+func (s *PriceService) History(postID int) (domain.PriceHistory, error) {
+	if err := domain.ValidateID(postID); err != nil {
+		return domain.PriceHistory{}, err
+	}
+	p, ok := s.st.GetPost(postID)
+	if !ok {
+		return domain.PriceHistory{}, store.ErrPostNotFound
+	}
+	changes, err := s.st.PriceChanges(postID)
+	if err != nil {
+		return domain.PriceHistory{}, err
+	}
+	now := s.now()
+	if len(changes) == 0 {
+		// o post lido vem avaliado agora; a promoção configurada é a que tem desconto
+		p.HasPromo = p.Discount > 0
+		changes = []domain.PriceChange{domain.PriceChangeOf(p, p.Date)}
+	}
+	points := domain.PriceTimeline(changes, now)
+	return domain.PriceHistory{
+		PostID:     postID,
+		FinalPrice: p.FinalPrice,
+		Lowest30d:  domain.LowestSince(points, now.Add(-domain.PriceLowWindow)),
+		Points:     points,
+	}, nil
+}
+
+// Subscribe cria (ou troca e rearma) o alerta do usuário para o post.
+func (s *PriceService) Subscribe(userID, postID int, threshold domain.Money) (domain.PriceAlert, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.PriceAlert{}, err
+	}
+	if err := domain.ValidateID(postID); err != nil {
+		return domain.PriceAlert{}, err
+	}
+	if threshold <= 0 {
+		return domain.PriceAlert{}, domain.ErrPriceAlertThreshold
+	}
+	p, ok := s.st.GetPost(postID)
+	if !ok {
+		return domain.PriceAlert{}, store.ErrPostNotFound
+	}
+	if p.FinalPrice < threshold {
+		return domain.PriceAlert{}, domain.ErrPriceAlertBelow
+	}
+	return s.st.UpsertPriceAlert(domain.PriceAlert{UserID: userID, PostID: postID, Threshold: threshold})
+}
+
+func (s *PriceService) Unsubscribe(userID, postID int) error {
+	if err := domain.ValidateID(postID); err != nil {
+		return err
+	}
+	return s.st.DeletePriceAlert(userID, postID)
+}
+
+func (s *PriceService) Alerts(userID int) ([]domain.PriceAlert, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, err
+	}
+	return s.st.PriceAlertsOf(userID)
+}
+
+// CheckDue confere todos os alertas pendentes e devolve quantos dispararam.
+func (s *PriceService) CheckDue() (int, error) {
+	return s.check(0)
+}
+
+// check dispara os alertas pendentes do post (0 = todos) cujo preço final atual
+// ficou abaixo do valor pedido. Cada alerta dispara uma vez, mesmo com vários
+// verificadores ao mesmo tempo (MarkPriceAlertTriggered é compare-and-set).
+func (s *PriceService) check(postID int) (int, error) {
+	alerts, err := s.st.PendingPriceAlerts(postID)
+	if err != nil {
+		return 0, err
+	}
+	posts := map[int]domain.Post{}
+	fired := 0
+	var errs []error
+	for _, a := range alerts {
+		p, ok := posts[a.PostID]
+		if !ok {
+			if p, ok = s.st.GetPost(a.PostID); !ok {
+				continue
+			}
+			posts[a.PostID] = p
+		}
+		if p.FinalPrice >= a.Threshold {
+			continue
+		}
+		marked, err := s.st.MarkPriceAlertTriggered(a.ID, s.now())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alerta %d: %w", a.ID, err))
+			continue
+		}
+		if !marked {
+			continue
+		}
+		fired++
+		if s.notifier != nil {
+			logNotifyErr(s.notifier.PriceDropped(a, p))
+		}
+	}
+	return fired, errors.Join(errs...)
+}
+
+// Run confere os alertas a cada every (promoções agendadas mudam o preço sem
+// ninguém mexer no post).
+func (s *PriceService) Run(ctx context.Context, every time.Duration, logf func(format string, args ...any)) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			n, err := s.CheckDue()
+			if err != nil {
+				logf("preços: %v", err)
+			}
+			if n > 0 {
+				logf("preços: %d alertas disparados", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func priceStore(t *testing.T) (*store.MemoryStore, *ProductService, *PriceService, *NotificationService) {
+	t.Helper()
+	st, ps, _, ns := notificationStore(t)
+	prices := NewPriceService(st)
+	prices.SetNotifications(ns)
+	ps.SetPrices(prices)
+	return st, ps, prices, ns
+}
+
+func priceDrops(t *testing.T, ns *NotificationService, userID int) []domain.Notification {
+	t.Helper()
+	items, _, _, err := ns.List(userID, false, 1, 50)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var out []domain.Notification
+	for _, n := range items {
+		if n.Type == domain.NotificationPriceDrop {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func TestPriceService_AlertFiresOnce(t *testing.T) {
+	_, ps, prices, ns := priceStore(t)
+	p := validPayload()
+	p.UserID = 2
+	id, err := ps.Publish(p)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if _, err := prices.Subscribe(3, id, 0); err != domain.ErrPriceAlertThreshold {
+		t.Fatalf("expected ErrPriceAlertThreshold, got %v", err)
+	}
+	if _, err := prices.Subscribe(3, id, 150_00); err != domain.ErrPriceAlertBelow {
+		t.Fatalf("expected ErrPriceAlertBelow, got %v", err)
+	}
+	if _, err := prices.Subscribe(3, 99, 50_00); err != store.ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	if _, err := prices.Subscribe(3, id, 85_00); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	// 10% não basta; 20% passa do alerta
+	if _, err := ps.SchedulePromo(2, id, domain.PromoSchedule{Discount: 10}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if got := priceDrops(t, ns, 3); len(got) != 0 {
+		t.Fatalf("90 is not below 85, got %+v", got)
+	}
+	if _, err := ps.SchedulePromo(2, id, domain.PromoSchedule{Discount: 20}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	got := priceDrops(t, ns, 3)
+	if len(got) != 1 || got[0].PostID != id || got[0].Message != "Mouse Gamer baixou para R$ 80,00 (seu alerta: abaixo de R$ 85,00)." {
+		t.Fatalf("unexpected notifications %+v", got)
+	}
+	if _, err := ps.SchedulePromo(2, id, domain.PromoSchedule{Discount: 30}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if n, _ := prices.CheckDue(); n != 0 || len(priceDrops(t, ns, 3)) != 1 {
+		t.Fatalf("alert must fire only once")
+	}
+	alerts, _ := prices.Alerts(3)
+	if len(alerts) != 1 || alerts[0].TriggeredAt == nil {
+		t.Fatalf("expected triggered alert, got %+v", alerts)
+	}
+
+	if err := prices.Unsubscribe(3, id); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if err := prices.Unsubscribe(3, id); err != store.ErrPriceAlertNotFound {
+		t.Fatalf("expected ErrPriceAlertNotFound, got %v", err)
+	}
+}
+
+func TestPriceService_CheckDueCatchesScheduledPromo(t *testing.T) {
+	st, ps, prices, ns := priceStore(t)
+	p := validPayload()
+	p.UserID = 2
+	id, _ := ps.Publish(p)
+	if _, err := prices.Subscribe(3, id, 95_00); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	// promoção que começa pelo relógio, sem passar pelo serviço de produtos
+	if _, err := st.SetPostPromo(id, domain.PromoSchedule{Discount: 10}); err != nil {
+		t.Fatalf("set promo: %v", err)
+	}
+	if n, err := prices.CheckDue(); err != nil || n != 1 {
+		t.Fatalf("expected 1 alert, got %d (%v)", n, err)
+	}
+	if len(priceDrops(t, ns, 3)) != 1 {
+		t.Fatalf("expected price_drop notification")
+	}
+}
+
+func TestPriceService_History(t *testing.T) {
+	st, ps, prices, _ := priceStore(t)
+	p := validPayload()
+	p.UserID = 2
+	id, _ := ps.Publish(p)
+
+	hour := time.Now().Add(time.Hour)
+	if _, err := ps.SchedulePromo(2, id, domain.PromoSchedule{Discount: 40, EndsAt: &hour}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if _, err := ps.EndPromo(2, id); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	h, err := prices.History(id)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if h.FinalPrice != 100_00 || h.Lowest30d != 60_00 || len(h.Points) != 3 {
+		t.Fatalf("unexpected history %+v", h)
+	}
+	if _, err := prices.History(99); err != store.ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+
+	// post sem histórico (anterior ao recurso) mostra o preço atual
+	old, _ := st.AddPost(domain.Post{UserID: 2, Price: 50_00, HasPromo: true, Discount: 10, Date: time.Now().Add(-48 * time.Hour)})
+	h, err = prices.History(old)
+	if err != nil || len(h.Points) != 1 || h.Points[0].FinalPrice != 45_00 || h.Lowest30d != 45_00 {
+		t.Fatalf("unexpected fallback history %+v %v", h, err)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// withCounts preenche likes_count e favorites_count (uma consulta para a página toda).
+func withCounts(st store.Store, posts []domain.Post) ([]domain.Post, error) {
+	if len(posts) == 0 {
+		return posts, nil
+	}
+	ids := make([]int, len(posts))
+	for i, p := range posts {
+		ids[i] = p.PostID
+	}
+	counts, err := st.PostCounts(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range posts {
+		c := counts[posts[i].PostID]
+		posts[i].LikesCount, posts[i].FavoritesCount = c.Likes, c.Favorites
+	}
+	return posts, nil
+}
+
+// presentPosts prepara os posts para a resposta: variantes de imagem e contadores.
+func presentPosts(st store.Store, posts []domain.Post) ([]domain.Post, error) {
+	return withCounts(st, withImages(posts))
+}
+
+// SetLike curte (liked=true) ou descurte o post e devolve os contadores atualizados.
+// Repetir a mesma ação não muda nada. Um like novo notifica o dono do post.
+func (s *ProductService) SetLike(userID, postID int, liked bool) (domain.PostCounts, error) {
+	added := false
+	like := func(userID, postID int) (err error) {
+		added, err = s.st.LikePost(userID, postID)
+		return err
+	}
+	counts, err := s.react(userID, postID, liked, like, s.st.UnlikePost)
+	if err == nil && added && s.notifier != nil {
+		logNotifyErr(s.notifier.PostLiked(userID, postID))
+	}
+	return counts, err
+}
+
+// SetFavorite põe ou tira o post da lista de favoritos (privada) do usuário.
+func (s *ProductService) SetFavorite(userID, postID int, favorite bool) (domain.PostCounts, error) {
+	return s.react(userID, postID, favorite, s.st.FavoritePost, s.st.UnfavoritePost)
+}
+
+func (s *ProductService) react(userID, postID int, on bool, add, remove func(userID, postID int) error) (domain.PostCounts, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.PostCounts{}, err
+	}
+	if err := domain.ValidateID(postID); err != nil {
+		return domain.PostCounts{}, err
+	}
+	op := remove
+	if on {
+		op = add
+	}
+	if err := op(userID, postID); err != nil {
+		return domain.PostCounts{}, err
+	}
+	counts, err := s.st.PostCounts([]int{postID})
+	if err != nil {
+		return domain.PostCounts{}, err
+	}
+	return counts[postID], nil
+}
+
+// Favorites lista os favoritos do usuário, do mais recente para o mais antigo. page começa em 1.
+func (s *ProductService) Favorites(userID, page, limit int) ([]domain.Post, int, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, 0, err
+	}
+	posts, total, err := s.st.Favorites(userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, err = presentPosts(s.st, posts)
+	return posts, total, err
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"socialmeli/internal/store"
+)
+
+func TestProductService_LikesAndFavorites(t *testing.T) {
+	st, ps := feedStore(t)
+	us := NewUserService(st)
+	publishAt(t, ps, 2, 0, 0)
+	publishAt(t, ps, 3, 1, 10)
+
+	c, err := ps.SetLike(1, 1, true)
+	if err != nil || c.Likes != 1 {
+		t.Fatalf("like: %+v %v", c, err)
+	}
+	if c, _ = ps.SetLike(4, 1, true); c.Likes != 2 {
+		t.Fatalf("expected 2 likes, got %+v", c)
+	}
+	if c, _ = ps.SetLike(4, 1, false); c.Likes != 1 {
+		t.Fatalf("expected 1 like after unlike, got %+v", c)
+	}
+	if _, err := ps.SetLike(1, 99, true); err != store.ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	if _, err := ps.SetFavorite(1, 2, true); err != nil {
+		t.Fatalf("favorite: %v", err)
+	}
+
+	// contadores aparecem nas respostas de posts
+	page, err := ps.Feed(FeedRequest{UserID: 1})
+	if err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	for _, p := range page.Posts {
+		if (p.PostID == 1 && p.LikesCount != 1) || (p.PostID == 2 && p.FavoritesCount != 1) {
+			t.Fatalf("missing counts in feed: %+v", p)
+		}
+	}
+	if posts, _ := us.PostsByUser(2, "date_desc"); posts[0].LikesCount != 1 {
+		t.Fatalf("missing counts in user posts: %+v", posts[0])
+	}
+
+	favs, total, err := ps.Favorites(1, 1, 10)
+	if err != nil || total != 1 || favs[0].PostID != 2 || favs[0].FavoritesCount != 1 {
+		t.Fatalf("unexpected favorites %+v %d %v", favs, total, err)
+	}
+
+	if err := ps.DeleteMyPost(3, 2); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, total, _ := ps.Favorites(1, 1, 10); total != 0 {
+		t.Fatalf("deleted post still favorited")
+	}
+}
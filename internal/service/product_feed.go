@@ -0,0 +1,191 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+const (
+	FeedModeRecent = "recent"
+	FeedModeRanked = "ranked"
+)
+
+// FeedRequest descreve uma página do feed de quem o usuário segue.
+// Since (se não zero) tem precedência sobre Days; Limit 0 devolve tudo.
+type FeedRequest struct {
+	UserID int
+	Since  time.Time
+	Days   int
+	Ranked bool
+	Cursor string
+	Limit  int
+	Filter domain.PostFilter
+}
+
+type FeedPage struct {
+	Mode       string            `json:"mode"`
+	Since      string            `json:"since"`
+	Posts      []domain.Post     `json:"posts"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Facets     domain.PostFacets `json:"facets"`
+}
+
+// feedCursor é opaco para o cliente. T fixa o "agora" da primeira página, então a
+// janela e os scores do modo ranked não mudam entre páginas.
+type feedCursor struct {
+	Mode  string  `json:"m"`
+	T     int64   `json:"t"`
+	Date  int64   `json:"d,omitempty"`
+	Score float64 `json:"s,omitempty"`
+	ID    int     `json:"i"`
+}
+
+func encodeFeedCursor(c feedCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeFeedCursor(s, mode string) (feedCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return feedCursor{}, domain.ErrFeedCursor
+	}
+	var c feedCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Mode != mode || c.T <= 0 || c.ID <= 0 {
+		return feedCursor{}, domain.ErrFeedCursor
+	}
+	return c, nil
+}
+
+type scoredPost struct {
+	post  domain.Post
+	score float64
+}
+
+// Feed lista os posts dos vendedores seguidos numa janela configurável, do mais
+// recente para o mais antigo ou, com Ranked, pelo domain.FeedScore.
+func (s *ProductService) Feed(req FeedRequest) (FeedPage, error) {
+	if err := domain.ValidateID(req.UserID); err != nil {
+		return FeedPage{}, err
+	}
+	mode := FeedModeRecent
+	if req.Ranked {
+		mode = FeedModeRanked
+	}
+
+	now := time.Now().Truncate(time.Second) // o cursor guarda segundos
+	var cur *feedCursor
+	if req.Cursor != "" {
+		c, err := decodeFeedCursor(req.Cursor, mode)
+		if err != nil {
+			return FeedPage{}, err
+		}
+		cur = &c
+		now = time.Unix(c.T, 0)
+	}
+
+	since := req.Since
+	if since.IsZero() {
+		days := req.Days
+		if days == 0 {
+			days = domain.DefaultFeedDays
+		}
+		if err := domain.ValidateFeedDays(days); err != nil {
+			return FeedPage{}, err
+		}
+		since = now.AddDate(0, 0, -days)
+	}
+
+	posts, sellerIDs, err := s.feedPosts(req.UserID, since, req.Filter)
+	if err != nil {
+		return FeedPage{}, err
+	}
+
+	items := make([]scoredPost, len(posts))
+	for i, p := range posts {
+		items[i] = scoredPost{post: p}
+	}
+	if req.Ranked {
+		followers, err := s.st.FollowerCounts(sellerIDs)
+		if err != nil {
+			return FeedPage{}, err
+		}
+		for i := range items {
+			items[i].score = domain.FeedScore(items[i].post, followers[items[i].post.UserID], now)
+		}
+	}
+	// ordem total (desempate pelo id) para o cursor ser estável
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if req.Ranked && a.score != b.score {
+			return a.score > b.score
+		}
+		if !a.post.Date.Equal(b.post.Date) {
+			return a.post.Date.After(b.post.Date)
+		}
+		return a.post.PostID > b.post.PostID
+	})
+
+	page := FeedPage{
+		Mode:   mode,
+		Since:  since.Format("02-01-2006"),
+		Facets: domain.ComputeFacets(posts),
+		Posts:  []domain.Post{},
+	}
+
+	start := 0
+	if cur != nil {
+		start = sort.Search(len(items), func(i int) bool { return afterCursor(items[i], *cur, req.Ranked) })
+	}
+	end := len(items)
+	if req.Limit > 0 && start+req.Limit < end {
+		end = start + req.Limit
+		last := items[end-1]
+		page.NextCursor = encodeFeedCursor(feedCursor{
+			Mode: mode, T: now.Unix(), Date: last.post.Date.UnixNano(), Score: last.score, ID: last.post.PostID,
+		})
+	}
+	for _, it := range items[start:end] {
+		page.Posts = append(page.Posts, it.post)
+	}
+	if page.Posts, err = presentPosts(s.st, page.Posts); err != nil {
+		return FeedPage{}, err
+	}
+	return page, nil
+}
+
+// feedPosts lê da timeline materializada quando ligada; senão consulta os posts
+// de todos os vendedores seguidos. Devolve também os ids seguidos.
+func (s *ProductService) feedPosts(userID int, since time.Time, f domain.PostFilter) ([]domain.Post, []int, error) {
+	if s.timeline != nil {
+		return s.timeline.Posts(userID, since, f)
+	}
+	followed, err := s.st.FollowedBy(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sellerIDs := make([]int, 0, len(followed))
+	for _, u := range followed {
+		sellerIDs = append(sellerIDs, u.ID)
+	}
+	posts, err := s.st.QueryPosts(domain.PostQuery{SellerIDs: sellerIDs, Since: since, Filter: f})
+	if err != nil {
+		return nil, nil, err
+	}
+	return posts, sellerIDs, nil
+}
+
+// afterCursor diz se o item vem depois do último item da página anterior.
+func afterCursor(it scoredPost, c feedCursor, ranked bool) bool {
+	if ranked && it.score != c.Score {
+		return it.score < c.Score
+	}
+	if d := it.post.Date.UnixNano(); d != c.Date {
+		return d < c.Date
+	}
+	return it.post.PostID < c.ID
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// feedStore: usuário 1 segue 2 e 3; 3 tem mais seguidores.
+func feedStore(t *testing.T) (*store.MemoryStore, *ProductService) {
+	t.Helper()
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{
+		{ID: 1, Name: "Buyer"},
+		{ID: 2, Name: "SellerA", IsSeller: true},
+		{ID: 3, Name: "SellerB", IsSeller: true},
+		{ID: 4, Name: "Other"},
+	})
+	for _, f := range [][2]int{{1, 2}, {1, 3}, {4, 3}} {
+		if err := st.Follow(f[0], f[1]); err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+	}
+	return st, NewProductService(st)
+}
+
+func publishAt(t *testing.T, svc *ProductService, seller int, daysAgo int, discount float64) {
+	t.Helper()
+	p := validPayload()
+	p.UserID = seller
+	p.Date = time.Now().AddDate(0, 0, -daysAgo).Format("02-01-2006")
+	if discount > 0 {
+		p.HasPromo, p.Discount = true, discount
+	}
+	if _, err := svc.Publish(p); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+}
+
+func TestProductService_Feed_CursorPagination(t *testing.T) {
+	_, svc := feedStore(t)
+	for i := 0; i < 5; i++ {
+		publishAt(t, svc, 2+i%2, i, 0)
+	}
+	publishAt(t, svc, 2, 20, 0) // fora da janela padrão
+
+	seen := map[int]bool{}
+	cursor := ""
+	pages := 0
+	var prev time.Time
+	for {
+		page, err := svc.Feed(FeedRequest{UserID: 1, Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("feed: %v", err)
+		}
+		pages++
+		for _, p := range page.Posts {
+			if seen[p.PostID] {
+				t.Fatalf("post %d repeated", p.PostID)
+			}
+			if !prev.IsZero() && p.Date.After(prev) {
+				t.Fatalf("feed out of order")
+			}
+			seen[p.PostID], prev = true, p.Date
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != 5 || pages != 3 {
+		t.Fatalf("expected 5 posts in 3 pages, got %d in %d", len(seen), pages)
+	}
+
+	page, err := svc.Feed(FeedRequest{UserID: 1, Days: 30})
+	if err != nil || len(page.Posts) != 6 {
+		t.Fatalf("expected 6 posts in 30 days, got %d (%v)", len(page.Posts), err)
+	}
+}
+
+func TestProductService_Feed_Ranked(t *testing.T) {
+	_, svc := feedStore(t)
+	publishAt(t, svc, 2, 0, 0)  // recente, sem desconto, vendedor com 1 seguidor
+	publishAt(t, svc, 3, 1, 90) // de ontem, mas com 90% de desconto
+	publishAt(t, svc, 2, 10, 0) // antigo
+
+	page, err := svc.Feed(FeedRequest{UserID: 1, Ranked: true, Limit: 2})
+	if err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+	if page.Mode != FeedModeRanked || page.Posts[0].Discount != 90 {
+		t.Fatalf("discounted post should lead: %+v", page.Posts)
+	}
+	next, err := svc.Feed(FeedRequest{UserID: 1, Ranked: true, Limit: 2, Cursor: page.NextCursor})
+	if err != nil || len(next.Posts) != 1 || next.NextCursor != "" {
+		t.Fatalf("unexpected last page: %+v (%v)", next, err)
+	}
+
+	// cursor de outro modo não vale
+	if _, err := svc.Feed(FeedRequest{UserID: 1, Cursor: page.NextCursor}); !errors.Is(err, domain.ErrFeedCursor) {
+		t.Fatalf("expected ErrFeedCursor, got %v", err)
+	}
+	if _, err := svc.Feed(FeedRequest{UserID: 1, Cursor: "!!"}); !errors.Is(err, domain.ErrFeedCursor) {
+		t.Fatalf("expected ErrFeedCursor, got %v", err)
+	}
+	if _, err := svc.Feed(FeedRequest{UserID: 1, Days: 91}); !errors.Is(err, domain.ErrFeedWindow) {
+		t.Fatalf("expected ErrFeedWindow, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// SchedulePromo configura (ou troca) a promoção de um post já publicado, sem
+// republicar. Sem datas vale desde já; com elas o preço volta sozinho ao fim.
+func (s *ProductService) SchedulePromo(userID, postID int, promo domain.PromoSchedule) (domain.Post, error) {
+	if err := domain.ValidatePromoSchedule(promo, time.Now()); err != nil {
+		return domain.Post{}, err
+	}
+	return s.setPromo(userID, postID, promo.UTC())
+}
+
+// EndPromo encerra agora a promoção do post (o preço volta ao cheio).
+func (s *ProductService) EndPromo(userID, postID int) (domain.Post, error) {
+	return s.setPromo(userID, postID, domain.PromoSchedule{})
+}
+
+func (s *ProductService) setPromo(userID, postID int, promo domain.PromoSchedule) (domain.Post, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.Post{}, err
+	}
+	if err := domain.ValidateID(postID); err != nil {
+		return domain.Post{}, err
+	}
+	before, ok := s.st.GetPost(postID)
+	if !ok {
+		return domain.Post{}, store.ErrPostNotFound
+	}
+	if before.UserID != userID {
+		return domain.Post{}, domain.ErrPromoForbidden
+	}
+	p, err := s.st.SetPostPromo(postID, promo)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	if s.prices != nil {
+		configured := p
+		configured.HasPromo = promo.Discount > 0
+		s.prices.Record(configured)
+	}
+
+	// só avisa os seguidores quando a promoção passa a valer agora; as agendadas são
+	// avisadas por AnnounceStartedPromos quando a janela abre
+	return s.promoChanged(p, p.HasPromo && !before.HasPromo), nil
+}
+
+// promoChanged anuncia a nova configuração de preço de p no stream do feed e nos
+// webhooks e, com notify, avisa os seguidores.
+func (s *ProductService) promoChanged(p domain.Post, notify bool) domain.Post {
+	view := withImages([]domain.Post{p})[0]
+	s.hub.Publish(FeedEvent{Type: FeedEventUpdated, SellerID: p.UserID, PostID: p.PostID, Post: &view})
+	if s.notifier != nil && notify {
+		logNotifyErr(s.notifier.PromoPublished(p))
+	}
+	if s.webhooks != nil {
+		s.webhooks.Dispatch(p.UserID, domain.WebhookPostUpdated, &view)
+	}
+	return view
+}
+
+// AnnounceStartedPromos anuncia as promoções agendadas cuja janela abriu até at,
+// como se o vendedor tivesse acabado de ligá-las: seguidores, stream e webhooks.
+// Cada uma é anunciada uma vez, mesmo com vários verificadores ao mesmo tempo
+// (MarkPromoAnnounced é compare-and-set). Devolve quantas foram anunciadas.
+func (s *ProductService) AnnounceStartedPromos(at time.Time) (int, error) {
+	posts, err := s.st.StartedPromos(at)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	var errs []error
+	for _, p := range posts {
+		marked, err := s.st.MarkPromoAnnounced(p.PostID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("post %d: %w", p.PostID, err))
+			continue
+		}
+		// janela que já fechou antes da verificação: nada a anunciar
+		if !marked || !p.HasPromo {
+			continue
+		}
+		s.promoChanged(p, true)
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+// RunScheduledPromos confere a cada every as promoções agendadas que começaram.
+func (s *ProductService) RunScheduledPromos(ctx context.Context, every time.Duration, logf func(format string, args ...any)) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			n, err := s.AnnounceStartedPromos(time.Now())
+			if err != nil {
+				logf("promoções: %v", err)
+			}
+			if n > 0 {
+				logf("promoções: %d agendadas começaram", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func TestProductService_ScheduledPromo(t *testing.T) {
+	_, ps, _, ns := notificationStore(t)
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	p := validPayload()
+	p.UserID = 2
+	p.HasPromo, p.Discount, p.PromoStartsAt = true, 20, &tomorrow
+	id, err := ps.Publish(p)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, n, _ := ps.PromoCount(2); n != 0 {
+		t.Fatalf("scheduled promo must not count yet, got %d", n)
+	}
+	if _, total, _, _ := ns.List(1, false, 1, 10); total != 0 {
+		t.Fatalf("scheduled promo must not notify, got %d", total)
+	}
+
+	// agenda sobre o post existente, valendo já
+	ends := time.Now().Add(time.Hour)
+	post, err := ps.SchedulePromo(2, id, domain.PromoSchedule{Discount: 10, EndsAt: &ends})
+	if err != nil || !post.HasPromo || post.FinalPrice != 90_00 || post.PromoEndsAt == nil {
+		t.Fatalf("schedule: %+v %v", post, err)
+	}
+	if _, n, _ := ps.PromoCount(2); n != 1 {
+		t.Fatalf("expected 1 promo, got %d", n)
+	}
+	if _, total, _, _ := ns.List(1, false, 1, 10); total != 1 {
+		t.Fatalf("promo starting now must notify followers, got %d", total)
+	}
+
+	if _, err := ps.SchedulePromo(3, id, domain.PromoSchedule{Discount: 10}); err != domain.ErrPromoForbidden {
+		t.Fatalf("expected ErrPromoForbidden, got %v", err)
+	}
+	if _, err := ps.SchedulePromo(2, 99, domain.PromoSchedule{Discount: 10}); err != store.ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if _, err := ps.SchedulePromo(2, id, domain.PromoSchedule{Discount: 10, EndsAt: &past}); err != domain.ErrPromoEnded {
+		t.Fatalf("expected ErrPromoEnded, got %v", err)
+	}
+
+	post, err = ps.EndPromo(2, id)
+	if err != nil || post.HasPromo || post.FinalPrice != 100_00 {
+		t.Fatalf("end: %+v %v", post, err)
+	}
+	if _, n, _ := ps.PromoCount(2); n != 0 {
+		t.Fatalf("expected no promos after ending, got %d", n)
+	}
+}
+
+func TestProductService_SchedulePromoEvents(t *testing.T) {
+	_, ps := feedStore(t)
+	publishAt(t, ps, 2, 0, 0)
+	sub := ps.Hub().Subscribe([]int{2}, 0)
+	defer sub.Close()
+
+	if _, err := ps.SchedulePromo(2, 1, domain.PromoSchedule{Discount: 30}); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	select {
+	case ev := <-sub.Events():
+		if ev.Type != FeedEventUpdated || ev.PostID != 1 || ev.Post == nil || ev.Post.FinalPrice != 70_00 {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no feed event")
+	}
+}
+
+func TestProductService_AnnounceStartedPromos(t *testing.T) {
+	st, ps, _, ns := notificationStore(t)
+	ws := NewWebhookService(st)
+	ps.SetWebhooks(ws)
+	if _, err := ws.Create(2, "https://hooks.example.com/x", []string{domain.WebhookPostUpdated}); err != nil {
+		t.Fatalf("webhook: %v", err)
+	}
+	sub := ps.Hub().Subscribe([]int{2}, 0)
+	defer sub.Close()
+
+	in1h := time.Now().Add(time.Hour)
+	p := validPayload()
+	p.UserID = 2
+	p.HasPromo, p.Discount, p.PromoStartsAt = true, 20, &in1h
+	id, err := ps.Publish(p)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	<-sub.Events() // created
+
+	if n, err := ps.AnnounceStartedPromos(time.Now()); err != nil || n != 0 {
+		t.Fatalf("nothing started yet, got %d (%v)", n, err)
+	}
+	opened := in1h.Add(time.Minute)
+	if n, err := ps.AnnounceStartedPromos(opened); err != nil || n != 1 {
+		t.Fatalf("expected 1 announced, got %d (%v)", n, err)
+	}
+	if n, _ := ps.AnnounceStartedPromos(opened); n != 0 {
+		t.Fatalf("promo must be announced once, got %d", n)
+	}
+
+	items, total, _, _ := ns.List(1, false, 1, 10)
+	if total != 1 || items[0].Type != domain.NotificationPromoStarted || items[0].PostID != id {
+		t.Fatalf("expected followers notified, got %d %+v", total, items)
+	}
+	select {
+	case ev := <-sub.Events():
+		if ev.Type != FeedEventUpdated || ev.PostID != id || ev.Post == nil || ev.Post.FinalPrice != 80_00 {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no feed event")
+	}
+	hooks, _ := ws.List(2)
+	if ds, _, _ := ws.Deliveries(2, hooks[0].ID, "", 1, 10); len(ds) != 1 || ds[0].Event != domain.WebhookPostUpdated {
+		t.Fatalf("expected post.updated delivery, got %+v", ds)
+	}
+}
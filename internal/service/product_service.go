@@ -2,7 +2,6 @@ package service
 
 import (
 	"errors"
-	"math"
 	"time"
 
 	"socialmeli/internal/domain"
@@ -12,10 +11,32 @@ import (
 var ErrDateFormat = errors.New("Data inválida. Use dd-MM-aaaa")
 
 type ProductService struct {
-	st store.Store
+	st       store.Store
+	timeline *Timeline
+	hub      *FeedHub
+	notifier *NotificationService
+	webhooks *WebhookService
+	prices   *PriceService
 }
 
-func NewProductService(st store.Store) *ProductService { return &ProductService{st: st} }
+func NewProductService(st store.Store) *ProductService {
+	return &ProductService{st: st, hub: NewFeedHub(DefaultFeedHistory, DefaultFeedBuffer)}
+}
+
+// SetWebhooks faz Publish e DeleteMyPost dispararem post.created e post.deleted nos webhooks do vendedor.
+func (s *ProductService) SetWebhooks(w *WebhookService) { s.webhooks = w }
+
+// SetPrices faz Publish e as mudanças de promoção entrarem no histórico de preços.
+func (s *ProductService) SetPrices(p *PriceService) { s.prices = p }
+
+// SetNotifications faz as promoções publicadas notificarem os seguidores.
+func (s *ProductService) SetNotifications(n *NotificationService) { s.notifier = n }
+
+// Hub é onde Publish e DeleteMyPost anunciam mudanças do feed.
+func (s *ProductService) Hub() *FeedHub { return s.hub }
+
+// SetTimeline liga o feed materializado: Publish passa a fazer fan-out e Feed lê a timeline.
+func (s *ProductService) SetTimeline(t *Timeline) { s.timeline = t }
 
 func parseDate(dateStr string) (time.Time, error) {
 	if dateStr == "" {
@@ -33,17 +54,24 @@ type PublishPayload struct {
 	Date     string         `json:"date"`
 	Product  domain.Product `json:"product"`
 	Category int            `json:"category"`
-	Price    float64        `json:"price"`
-	HasPromo bool           `json:"has_promo"`
-	Discount float64        `json:"discount"`
+	Price    domain.Money   `json:"price"`
+	// código ISO 4217; vazio = domain.BaseCurrency
+	Currency string  `json:"currency"`
+	HasPromo bool    `json:"has_promo"`
+	Discount float64 `json:"discount"`
+	// janela opcional da promoção; fora dela vale o preço cheio
+	PromoStartsAt *time.Time `json:"promo_starts_at"`
+	PromoEndsAt   *time.Time `json:"promo_ends_at"`
 }
 
-func calcFinalPrice(price, discount float64, hasPromo bool) float64 {
-	if !hasPromo {
-		return price
+// withImages preenche product.images quando image_url veio do nosso upload.
+func withImages(posts []domain.Post) []domain.Post {
+	for i := range posts {
+		if set, ok := domain.ImageVariants(posts[i].Product.ImageURL); ok {
+			posts[i].Product.Images = &set
+		}
 	}
-	// discount em percentual (ex.: 10 = 10%)
-	return math.Round((price*(1-(discount/100)))*100) / 100
+	return posts
 }
 
 func (s *ProductService) Publish(payload PublishPayload) (int, error) {
@@ -81,55 +109,102 @@ func (s *ProductService) Publish(payload PublishPayload) (int, error) {
 	if err := domain.ValidatePrice(payload.Price); err != nil {
 		return 0, err
 	}
+	currency := domain.BaseCurrency
+	if payload.Currency != "" {
+		currency = domain.NormalizeCurrency(payload.Currency)
+		if err := domain.ValidateCurrency(currency); err != nil {
+			return 0, err
+		}
+	}
 
-	// valida desconto quando for promocao
+	// valida desconto e janela quando for promocao
+	now := time.Now()
 	if payload.HasPromo {
-		if payload.Discount <= 0 {
-			return 0, errors.New("Desconto deve ser maior que zero")
-		}
-		if payload.Discount > 100 {
-			return 0, errors.New("Desconto deve ser menor ou igual a 100")
+		promo := domain.PromoSchedule{Discount: payload.Discount, StartsAt: payload.PromoStartsAt, EndsAt: payload.PromoEndsAt}
+		if err := domain.ValidatePromoSchedule(promo, now); err != nil {
+			return 0, err
 		}
+		payload.PromoStartsAt, payload.PromoEndsAt = domain.UTCTime(payload.PromoStartsAt), domain.UTCTime(payload.PromoEndsAt)
 	} else {
 		payload.Discount = 0
+		payload.PromoStartsAt, payload.PromoEndsAt = nil, nil
 	}
 
+	// imagens enviadas por nós são sempre referenciadas pelo tamanho large
+	payload.Product.ImageURL = domain.CanonicalImageURL(payload.Product.ImageURL)
+
 	p := domain.Post{
-		UserID:     payload.UserID,
-		Date:       dt,
-		DateStr:    payload.Date,
-		Product:    payload.Product,
-		Category:   payload.Category,
-		Price:      payload.Price,
-		HasPromo:   payload.HasPromo,
-		Discount:   payload.Discount,
-		FinalPrice: calcFinalPrice(payload.Price, payload.Discount, payload.HasPromo),
-	}
-	return s.st.AddPost(p)
+		UserID:        payload.UserID,
+		Date:          dt,
+		DateStr:       payload.Date,
+		Product:       payload.Product,
+		Category:      payload.Category,
+		Price:         payload.Price,
+		Currency:      currency,
+		HasPromo:      payload.HasPromo,
+		Discount:      payload.Discount,
+		PromoStartsAt: payload.PromoStartsAt,
+		PromoEndsAt:   payload.PromoEndsAt,
+	}
+	id, err := s.st.AddPost(p)
+	if err != nil {
+		return 0, err
+	}
+	p.PostID = id
+	if s.prices != nil {
+		s.prices.Record(p)
+	}
+	// daqui em diante o post é o que os compradores veem agora (promoção agendada
+	// ainda não é promoção)
+	p = p.AtTime(now)
+	if s.timeline != nil {
+		s.timeline.Enqueue(p)
+	}
+	ev := FeedEvent{Type: FeedEventPost, SellerID: p.UserID, PostID: id, Post: &withImages([]domain.Post{p})[0]}
+	if p.HasPromo {
+		ev.Type = FeedEventPromo
+	}
+	s.hub.Publish(ev)
+	if s.notifier != nil {
+		logNotifyErr(s.notifier.PromoPublished(p))
+	}
+	if s.webhooks != nil {
+		s.webhooks.Dispatch(p.UserID, domain.WebhookPostCreated, ev.Post)
+	}
+	return id, nil
 }
 
 func (s *ProductService) FollowedLastTwoWeeks(userID int, order string) ([]domain.Post, error) {
-	if err := domain.ValidateID(userID); err != nil {
-		return nil, err
-	}
+	posts, _, err := s.FollowedFiltered(userID, order, domain.PostFilter{})
+	return posts, err
+}
+
+// FollowedFiltered é o feed das últimas 2 semanas com filtro e facetas
+// (as facetas contam os resultados já filtrados). Embrulha Feed sem paginação.
+func (s *ProductService) FollowedFiltered(userID int, order string, f domain.PostFilter) ([]domain.Post, domain.PostFacets, error) {
 	if err := domain.ValidateOrderForPosts(order); err != nil {
-		return nil, err
+		return nil, domain.PostFacets{}, err
 	}
-
-	followed, err := s.st.FollowedBy(userID)
+	page, err := s.Feed(FeedRequest{UserID: userID, Days: domain.DefaultFeedDays, Filter: f})
 	if err != nil {
-		return nil, err
+		return nil, domain.PostFacets{}, err
 	}
+	domain.SortPostsByDate(page.Posts, order)
+	return page.Posts, page.Facets, nil
+}
 
-	sellerIDs := make([]int, 0, len(followed))
-	for _, u := range followed {
-		sellerIDs = append(sellerIDs, u.ID)
+func (s *ProductService) queryWithFacets(q domain.PostQuery, order string) ([]domain.Post, domain.PostFacets, error) {
+	posts, err := s.st.QueryPosts(q)
+	if err != nil {
+		return nil, domain.PostFacets{}, err
+	}
+	facets, err := s.st.PostFacets(q)
+	if err != nil {
+		return nil, domain.PostFacets{}, err
 	}
-
-	since := time.Now().AddDate(0, 0, -14)
-	posts := s.st.PostsFromSellersSince(sellerIDs, since)
 	domain.SortPostsByDate(posts, order)
-	return posts, nil
+	posts, err = presentPosts(s.st, posts)
+	return posts, facets, err
 }
 
 func (s *ProductService) PromoCount(userID int) (domain.User, int, error) {
@@ -145,16 +220,40 @@ func (s *ProductService) PromoCount(userID int) (domain.User, int, error) {
 }
 
 func (s *ProductService) PromoList(userID int) (domain.User, []domain.Post, error) {
+	u, posts, _, err := s.PromoListFiltered(userID, domain.PostFilter{})
+	return u, posts, err
+}
+
+// PromoListFiltered lista as promoções do vendedor com filtro e facetas.
+func (s *ProductService) PromoListFiltered(userID int, f domain.PostFilter) (domain.User, []domain.Post, domain.PostFacets, error) {
 	if err := domain.ValidateID(userID); err != nil {
-		return domain.User{}, nil, err
+		return domain.User{}, nil, domain.PostFacets{}, err
 	}
 	u, ok := s.st.GetUser(userID)
 	if !ok {
-		return domain.User{}, nil, store.ErrUserNotFound
+		return domain.User{}, nil, domain.PostFacets{}, store.ErrUserNotFound
 	}
-	posts := s.st.PromoPostsBySeller(userID)
-	domain.SortPostsByDate(posts, domain.DateDesc)
-	return u, posts, nil
+	f.PromoOnly = true
+	posts, facets, err := s.queryWithFacets(domain.PostQuery{SellerIDs: []int{userID}, Filter: f}, domain.DateDesc)
+	if err != nil {
+		return domain.User{}, nil, domain.PostFacets{}, err
+	}
+	return u, posts, facets, nil
+}
+
+// Search faz a busca textual nos produtos (nome, marca, tipo, cor e notas),
+// ignorando acentos e maiúsculas. page começa em 1.
+func (s *ProductService) Search(q string, page, limit int) ([]domain.Post, int, error) {
+	terms, err := domain.ParseSearchQuery(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, total, err := s.st.SearchPosts(terms, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, err = presentPosts(s.st, posts)
+	return posts, total, err
 }
 
 func (s *ProductService) DeleteMyPost(userID, postID int) error {
@@ -164,5 +263,29 @@ func (s *ProductService) DeleteMyPost(userID, postID int) error {
 	if err := domain.ValidateID(postID); err != nil {
 		return err
 	}
-	return s.st.DeletePost(userID, postID)
+	if err := s.st.DeletePost(userID, postID); err != nil {
+		return err
+	}
+	s.hub.Publish(FeedEvent{Type: FeedEventDeleted, SellerID: userID, PostID: postID})
+	if s.webhooks != nil {
+		s.webhooks.Dispatch(userID, domain.WebhookPostDeleted, PostDeletedData{PostID: postID})
+	}
+	return nil
+}
+
+// SubscribeFeed assina, em tempo real, as mudanças nos posts de quem o usuário segue.
+// lastEventID (0 = nenhum) retoma do histórico do hub.
+func (s *ProductService) SubscribeFeed(userID int, lastEventID uint64) (*FeedSubscription, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, err
+	}
+	followed, err := s.st.FollowedBy(userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(followed))
+	for i, u := range followed {
+		ids[i] = u.ID
+	}
+	return s.hub.Subscribe(ids, lastEventID), nil
 }
@@ -16,7 +16,7 @@ func TestProductService_DeleteMyPost_Success(t *testing.T) {
 		UserID:   1,
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 	})
 
 	svc := NewProductService(st)
@@ -48,3 +48,44 @@ func TestProductService_DeleteMyPost_InvalidPostID(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestProductService_PromoList_IncludesImageSizes(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{
+		{ID: 1, Name: "User", IsSeller: true},
+	})
+	_, _ = st.AddPost(domain.Post{
+		UserID:   1,
+		Product:  domain.Product{ProductID: 1, ProductName: "P1", ImageURL: "/static/products/1-5_large.jpg"},
+		Category: 1,
+		Price:    10_00,
+		HasPromo: true,
+		Discount: 10,
+	})
+	_, _ = st.AddPost(domain.Post{
+		UserID:   1,
+		Product:  domain.Product{ProductID: 2, ProductName: "P2", ImageURL: "https://cdn.example.com/x.jpg"},
+		Category: 1,
+		Price:    10_00,
+		HasPromo: true,
+		Discount: 10,
+	})
+
+	svc := NewProductService(st)
+	_, posts, err := svc.PromoList(1)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	for _, p := range posts {
+		switch p.Product.ProductID {
+		case 1:
+			if p.Product.Images == nil || p.Product.Images.Thumb != "/static/products/1-5_thumb.jpg" {
+				t.Fatalf("expected images for uploaded url, got %+v", p.Product.Images)
+			}
+		case 2:
+			if p.Product.Images != nil {
+				t.Fatalf("expected no images for external url, got %+v", p.Product.Images)
+			}
+		}
+	}
+}
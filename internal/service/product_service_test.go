@@ -30,7 +30,7 @@ func validPayload() PublishPayload {
 			Notes:       "",
 		},
 		Category: 1,
-		Price:    100.0,
+		Price:    100_00,
 		HasPromo: false,
 		Discount: 0,
 	}
@@ -232,14 +232,14 @@ func TestFollowedLastTwoWeeks_Success_SortsDesc(t *testing.T) {
 		Date:     now.Add(-2 * 24 * time.Hour),
 		DateStr:  "x",
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
-		Category: 1, Price: 10,
+		Category: 1, Price: 10_00,
 	})
 	_, _ = st.AddPost(domain.Post{
 		UserID:   3,
 		Date:     now.Add(-1 * 24 * time.Hour),
 		DateStr:  "x",
 		Product:  domain.Product{ProductID: 2, ProductName: "P2", Type: "t", Brand: "b", Color: "c"},
-		Category: 1, Price: 10,
+		Category: 1, Price: 10_00,
 	})
 
 	posts, err := svc.FollowedLastTwoWeeks(1, domain.DateDesc)
@@ -290,7 +290,7 @@ func TestPromoCount_Success(t *testing.T) {
 		DateStr:  "x",
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 		HasPromo: true,
 		Discount: 5,
 	})
@@ -300,7 +300,7 @@ func TestPromoCount_Success(t *testing.T) {
 		DateStr:  "x",
 		Product:  domain.Product{ProductID: 2, ProductName: "P2", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 		HasPromo: false,
 		Discount: 0,
 	})
@@ -353,7 +353,7 @@ func TestPromoList_Success_SortsDateDesc(t *testing.T) {
 		DateStr:  "x",
 		Product:  domain.Product{ProductID: 1, ProductName: "Old", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 		HasPromo: true,
 		Discount: 5,
 	})
@@ -363,7 +363,7 @@ func TestPromoList_Success_SortsDateDesc(t *testing.T) {
 		DateStr:  "x",
 		Product:  domain.Product{ProductID: 2, ProductName: "New", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 		HasPromo: true,
 		Discount: 2,
 	})
@@ -380,3 +380,64 @@ func TestPromoList_Success_SortsDateDesc(t *testing.T) {
 		t.Fatalf("expected DateDesc order, got %v then %v", posts[0].Date, posts[1].Date)
 	}
 }
+
+func TestProductService_Search(t *testing.T) {
+	st := store.NewMemoryStore()
+	seedUsersForProduct(st)
+	svc := NewProductService(st)
+
+	p := validPayload()
+	p.Product.ProductName = "Teclado Mecânico"
+	if _, err := svc.Publish(p); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	posts, total, err := svc.Search("MECANICO", 1, 10)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if total != 1 || len(posts) != 1 || posts[0].Product.ProductName != "Teclado Mecânico" {
+		t.Fatalf("unexpected result: %d %+v", total, posts)
+	}
+
+	if _, _, err := svc.Search("", 1, 10); err != domain.ErrSearchQueryEmpty {
+		t.Fatalf("expected ErrSearchQueryEmpty, got %v", err)
+	}
+}
+
+func TestProductService_FollowedFiltered(t *testing.T) {
+	st := store.NewMemoryStore()
+	seedUsersForProduct(st)
+	svc := NewProductService(st)
+	if err := st.Follow(1, 2); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+
+	today := time.Now().Format("02-01-2006")
+	for _, brand := range []string{"Nike", "Adidas", "Nike"} {
+		p := validPayload()
+		p.UserID = 2
+		p.Date = today
+		p.Product.Brand = brand
+		if _, err := svc.Publish(p); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	posts, facets, err := svc.FollowedFiltered(1, "", domain.PostFilter{Brands: []string{"nike"}})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("expected 2 posts, got %d", len(posts))
+	}
+	if len(facets.Brands) != 1 || facets.Brands[0].Count != 2 {
+		t.Fatalf("unexpected facets: %+v", facets.Brands)
+	}
+
+	// sem filtro continua igual ao feed original
+	all, err := svc.FollowedLastTwoWeeks(1, "")
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 posts, got %d (%v)", len(all), err)
+	}
+}
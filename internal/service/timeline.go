@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// DefaultPopularSeller é a partir de quantos seguidores o vendedor deixa de ter fan-out:
+// copiar cada post para milhares de timelines custa mais que ler na hora.
+const DefaultPopularSeller = 5000
+
+// Timeline mantém o feed materializado. Cada post publicado vai, por uma fila
+// atendida por workers, para a timeline de quem segue o vendedor; posts de
+// vendedores populares não são copiados e entram no feed por leitura direta (pull).
+//
+// Um vendedor marcado como popular continua popular até o processo reiniciar.
+// A marca fica gravada no store: no Start, quem deixou de ser popular tem os posts
+// recentes copiados para a timeline dos seguidores antes de parar de ser lido na hora.
+type Timeline struct {
+	st        store.Store
+	popularAt int
+	logf      func(format string, args ...any)
+
+	mu      sync.RWMutex
+	popular map[int]bool
+	jobs    chan domain.Post
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+func NewTimeline(st store.Store, popularAt int) *Timeline {
+	if popularAt <= 0 {
+		popularAt = DefaultPopularSeller
+	}
+	return &Timeline{st: st, popularAt: popularAt, logf: log.Printf, popular: map[int]bool{}}
+}
+
+// Start carrega os vendedores populares e sobe os workers do fan-out.
+// Sem Start, Enqueue faz o fan-out na hora.
+func (t *Timeline) Start(workers, queue int) error {
+	ids, err := t.st.PopularSellers(t.popularAt)
+	if err != nil {
+		return err
+	}
+	pulled, err := t.st.PulledSellers()
+	if err != nil {
+		return err
+	}
+	popular := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		popular[id] = true
+	}
+	wasPulled := make(map[int]bool, len(pulled))
+	for _, id := range pulled {
+		wasPulled[id] = true
+		if popular[id] {
+			continue
+		}
+		// os posts dele não passaram pelo fan-out: sem a cópia, sumiriam do feed
+		if err := t.materialize(id); err != nil {
+			t.logf("timeline: vendedor %d continua lido na hora: %v", id, err)
+			popular[id] = true
+		}
+	}
+	for _, id := range ids {
+		if !wasPulled[id] {
+			if err := t.st.SetSellerPulled(id, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id := range popular {
+		t.popular[id] = true
+	}
+	if workers <= 0 || t.jobs != nil || t.closed {
+		return nil
+	}
+	t.jobs = make(chan domain.Post, queue)
+	for i := 0; i < workers; i++ {
+		t.wg.Add(1)
+		go t.worker(t.jobs)
+	}
+	return nil
+}
+
+// Close deixa de aceitar posts na fila e espera os workers terminarem o que já entrou.
+func (t *Timeline) Close() {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.closed = true
+	if t.jobs != nil {
+		close(t.jobs)
+	}
+	t.mu.Unlock()
+	t.wg.Wait()
+}
+
+func (t *Timeline) worker(jobs <-chan domain.Post) {
+	defer t.wg.Done()
+	for p := range jobs {
+		if err := t.fanout(p); err != nil {
+			t.logf("timeline: fan-out do post %d: %v", p.PostID, err)
+		}
+	}
+}
+
+// Enqueue agenda o fan-out de um post recém-gravado. Com a fila cheia (ou sem
+// workers) o fan-out roda no próprio chamador: publicar fica mais lento, mas
+// nenhum post some do feed.
+func (t *Timeline) Enqueue(p domain.Post) {
+	t.mu.RLock()
+	if t.jobs != nil && !t.closed {
+		select {
+		case t.jobs <- p:
+			t.mu.RUnlock()
+			return
+		default:
+		}
+	}
+	t.mu.RUnlock()
+	if err := t.fanout(p); err != nil {
+		t.logf("timeline: fan-out do post %d: %v", p.PostID, err)
+	}
+}
+
+func (t *Timeline) isPopular(sellerID int) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.popular[sellerID]
+}
+
+func (t *Timeline) fanout(p domain.Post) error {
+	if t.isPopular(p.UserID) {
+		return nil
+	}
+	followers, err := t.st.FollowersOf(p.UserID)
+	if err != nil {
+		return err
+	}
+	if len(followers) >= t.popularAt {
+		// sem a marca gravada o vendedor não pode sair do fan-out: após um restart
+		// abaixo do limite, os posts dele não estariam em lugar nenhum
+		err := t.st.SetSellerPulled(p.UserID, true)
+		if err == nil {
+			t.mu.Lock()
+			t.popular[p.UserID] = true
+			t.mu.Unlock()
+			return nil
+		}
+		t.logf("timeline: marcar vendedor %d como popular: %v", p.UserID, err)
+	}
+	ids := make([]int, len(followers))
+	for i, u := range followers {
+		ids[i] = u.ID
+	}
+	return t.st.AppendTimeline(ids, p)
+}
+
+// materialize copia os posts recentes do vendedor para a timeline de cada seguidor
+// e só então tira a marca de popular.
+func (t *Timeline) materialize(sellerID int) error {
+	followers, err := t.st.FollowersOf(sellerID)
+	if err != nil {
+		return err
+	}
+	since := time.Now().AddDate(0, 0, -domain.MaxFeedDays)
+	for _, u := range followers {
+		if err := t.st.BackfillTimeline(u.ID, sellerID, since); err != nil {
+			return err
+		}
+	}
+	return t.st.SetSellerPulled(sellerID, false)
+}
+
+// Followed traz para a timeline os posts recentes do vendedor recém-seguido.
+// Falhas só ficam no log: o follow já foi gravado.
+func (t *Timeline) Followed(userID, sellerID int) {
+	if t.isPopular(sellerID) {
+		return
+	}
+	since := time.Now().AddDate(0, 0, -domain.MaxFeedDays)
+	if err := t.st.BackfillTimeline(userID, sellerID, since); err != nil {
+		t.logf("timeline: backfill %d <- %d: %v", userID, sellerID, err)
+	}
+}
+
+// Unfollowed limpa a timeline. Se falhar, a leitura ainda descarta vendedores não seguidos.
+func (t *Timeline) Unfollowed(userID, sellerID int) {
+	if err := t.st.PruneTimeline(userID, sellerID); err != nil {
+		t.logf("timeline: limpeza %d -x- %d: %v", userID, sellerID, err)
+	}
+}
+
+// Posts monta o feed do usuário desde since: timeline materializada mais os posts
+// dos vendedores populares que ele segue. Devolve também os ids seguidos.
+func (t *Timeline) Posts(userID int, since time.Time, f domain.PostFilter) ([]domain.Post, []int, error) {
+	followed, err := t.st.FollowedBy(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sellerIDs := make([]int, 0, len(followed))
+	following := make(map[int]bool, len(followed))
+	var pull []int
+	for _, u := range followed {
+		sellerIDs = append(sellerIDs, u.ID)
+		following[u.ID] = true
+		if t.isPopular(u.ID) {
+			pull = append(pull, u.ID)
+		}
+	}
+
+	materialized, err := t.st.TimelinePosts(userID, since, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	seen := map[int]bool{}
+	out := make([]domain.Post, 0, len(materialized))
+	for _, p := range materialized {
+		if following[p.UserID] && !seen[p.PostID] {
+			seen[p.PostID] = true
+			out = append(out, p)
+		}
+	}
+
+	if len(pull) > 0 {
+		pulled, err := t.st.QueryPosts(domain.PostQuery{SellerIDs: pull, Since: since, Filter: f})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range pulled {
+			if !seen[p.PostID] {
+				seen[p.PostID] = true
+				out = append(out, p)
+			}
+		}
+	}
+	return out, sellerIDs, nil
+}
+
+// Rebuild refaz a timeline de todo mundo a partir do grafo de follows (janela de
+// MaxFeedDays). Serve para popular a timeline pela primeira vez ou depois que os
+// posts foram gravados sem passar pelo Publish (seed). Devolve quantos follows copiou.
+func (t *Timeline) Rebuild() (int, error) {
+	users, err := t.st.ListUsers(domain.NameAsc)
+	if err != nil {
+		return 0, err
+	}
+	since := time.Now().AddDate(0, 0, -domain.MaxFeedDays)
+	n := 0
+	for _, u := range users {
+		followed, err := t.st.FollowedBy(u.ID)
+		if err != nil {
+			return n, err
+		}
+		for _, seller := range followed {
+			if t.isPopular(seller.ID) {
+				continue
+			}
+			if err := t.st.BackfillTimeline(u.ID, seller.ID, since); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// RunExpiry apaga, a cada intervalo, entradas mais antigas que a maior janela do feed.
+func (t *Timeline) RunExpiry(ctx context.Context, every time.Duration, logf func(format string, args ...any)) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+			n, err := t.st.ExpireTimeline(time.Now().AddDate(0, 0, -domain.MaxFeedDays))
+			if err != nil {
+				logf("timeline: expiração: %v", err)
+			} else if n > 0 {
+				logf("timeline: %d entradas expiradas", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// timelineStore liga o feed materializado no feedStore (3 tem 2 seguidores).
+func timelineStore(t *testing.T, popularAt int) (*store.MemoryStore, *ProductService, *UserService, *Timeline) {
+	t.Helper()
+	st, ps := feedStore(t)
+	tl := NewTimeline(st, popularAt)
+	tl.logf = t.Logf
+	ps.SetTimeline(tl)
+	us := NewUserService(st)
+	us.SetTimeline(tl)
+	return st, ps, us, tl
+}
+
+func timelineIDs(t *testing.T, st store.Store, userID int) map[int]bool {
+	t.Helper()
+	posts, err := st.TimelinePosts(userID, time.Time{}, domain.PostFilter{})
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	ids := map[int]bool{}
+	for _, p := range posts {
+		ids[p.PostID] = true
+	}
+	return ids
+}
+
+func TestTimeline_FanoutOnPublish(t *testing.T) {
+	st, ps, _, _ := timelineStore(t, 0)
+	publishAt(t, ps, 2, 0, 0)
+	publishAt(t, ps, 3, 1, 0)
+
+	if ids := timelineIDs(t, st, 1); len(ids) != 2 {
+		t.Fatalf("expected both posts in buyer timeline, got %v", ids)
+	}
+	if ids := timelineIDs(t, st, 4); len(ids) != 1 {
+		t.Fatalf("expected only seller 3 post in user 4 timeline, got %v", ids)
+	}
+
+	// o feed lido da timeline é o mesmo do modo pull
+	page, err := ps.Feed(FeedRequest{UserID: 1})
+	if err != nil || len(page.Posts) != 2 {
+		t.Fatalf("expected 2 posts in feed, got %d (%v)", len(page.Posts), err)
+	}
+	pull, _ := NewProductService(st).Feed(FeedRequest{UserID: 1})
+	for i := range pull.Posts {
+		if pull.Posts[i].PostID != page.Posts[i].PostID {
+			t.Fatalf("materialized feed differs from pull feed")
+		}
+	}
+}
+
+func TestTimeline_WorkerPool(t *testing.T) {
+	st, ps, _, tl := timelineStore(t, 0)
+	if err := tl.Start(3, 2); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		publishAt(t, ps, 2, 0, 0)
+	}
+	tl.Close()
+	tl.Close() // idempotente
+
+	if ids := timelineIDs(t, st, 1); len(ids) != 10 {
+		t.Fatalf("expected 10 posts after drain, got %d", len(ids))
+	}
+	// fechado, o fan-out volta a ser síncrono
+	publishAt(t, ps, 2, 0, 0)
+	if ids := timelineIDs(t, st, 1); len(ids) != 11 {
+		t.Fatalf("expected 11 posts, got %d", len(ids))
+	}
+}
+
+func TestTimeline_PopularSellerIsPulled(t *testing.T) {
+	st, ps, _, tl := timelineStore(t, 2)
+	if err := tl.Start(0, 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if !tl.isPopular(3) || tl.isPopular(2) {
+		t.Fatalf("expected only seller 3 to be popular")
+	}
+	publishAt(t, ps, 3, 0, 0)
+	publishAt(t, ps, 2, 0, 0)
+
+	if ids := timelineIDs(t, st, 1); len(ids) != 1 {
+		t.Fatalf("popular seller must not be fanned out, got %v", ids)
+	}
+	page, err := ps.Feed(FeedRequest{UserID: 1})
+	if err != nil || len(page.Posts) != 2 {
+		t.Fatalf("expected popular post pulled into feed, got %d (%v)", len(page.Posts), err)
+	}
+	page, _ = ps.Feed(FeedRequest{UserID: 4})
+	if len(page.Posts) != 1 || page.Posts[0].UserID != 3 {
+		t.Fatalf("expected only seller 3 post for user 4, got %+v", page.Posts)
+	}
+}
+
+func TestTimeline_FormerPopularSellerMaterializedOnRestart(t *testing.T) {
+	st, ps, _, tl := timelineStore(t, 2)
+	if err := tl.Start(0, 0); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	publishAt(t, ps, 3, 0, 0) // popular: fica fora da timeline
+	if ids, _ := st.PulledSellers(); len(ids) != 1 || ids[0] != 3 {
+		t.Fatalf("expected seller 3 marked as pulled, got %v", ids)
+	}
+
+	// perde um seguidor e o processo reinicia abaixo do limite
+	if err := st.Unfollow(4, 3); err != nil {
+		t.Fatalf("unfollow: %v", err)
+	}
+	restarted := NewTimeline(st, 2)
+	restarted.logf = t.Logf
+	if err := restarted.Start(0, 0); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if restarted.isPopular(3) {
+		t.Fatalf("seller 3 should no longer be popular")
+	}
+	if ids := timelineIDs(t, st, 1); len(ids) != 1 {
+		t.Fatalf("expected old popular post copied to timeline, got %v", ids)
+	}
+	if ids, _ := st.PulledSellers(); len(ids) != 0 {
+		t.Fatalf("expected pulled mark cleared, got %v", ids)
+	}
+	ps.SetTimeline(restarted)
+	page, err := ps.Feed(FeedRequest{UserID: 1})
+	if err != nil || len(page.Posts) != 1 || page.Posts[0].UserID != 3 {
+		t.Fatalf("expected seller 3 post in feed, got %+v (%v)", page.Posts, err)
+	}
+}
+
+func TestTimeline_SellerTurningPopularIsMarked(t *testing.T) {
+	st, ps, us, _ := timelineStore(t, 3)
+	if err := us.Follow(2, 3); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	publishAt(t, ps, 3, 0, 0)
+	if ids, _ := st.PulledSellers(); len(ids) != 1 || ids[0] != 3 {
+		t.Fatalf("expected seller 3 marked once it crossed the threshold, got %v", ids)
+	}
+}
+
+func TestTimeline_FollowBackfillAndUnfollowCleanup(t *testing.T) {
+	st, ps, us, _ := timelineStore(t, 0)
+	publishAt(t, ps, 2, 3, 0)
+	publishAt(t, ps, 2, 100, 0) // fora da maior janela
+
+	if err := us.Follow(4, 2); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if ids := timelineIDs(t, st, 4); len(ids) != 1 {
+		t.Fatalf("expected recent post backfilled, got %v", ids)
+	}
+
+	if err := us.Unfollow(1, 2); err != nil {
+		t.Fatalf("unfollow: %v", err)
+	}
+	if ids := timelineIDs(t, st, 1); len(ids) != 0 {
+		t.Fatalf("expected timeline cleaned after unfollow, got %v", ids)
+	}
+	page, _ := ps.Feed(FeedRequest{UserID: 1, Days: domain.MaxFeedDays})
+	if len(page.Posts) != 0 {
+		t.Fatalf("expected empty feed, got %+v", page.Posts)
+	}
+}
+
+func TestTimeline_Rebuild(t *testing.T) {
+	st, svc := feedStore(t)
+	publishAt(t, svc, 2, 0, 0) // sem timeline ligada
+	publishAt(t, svc, 3, 0, 0)
+
+	tl := NewTimeline(st, 0)
+	n, err := tl.Rebuild()
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 follows rebuilt, got %d (%v)", n, err)
+	}
+	if ids := timelineIDs(t, st, 1); len(ids) != 2 {
+		t.Fatalf("expected 2 posts after rebuild, got %v", ids)
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/media"
+	"socialmeli/internal/store"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("Cota de armazenamento excedida")
+	ErrUploadBusy    = errors.New("A mesma imagem está sendo removida; tente novamente")
+)
+
+// defaultCollectWait é quanto SaveImage espera a coleta terminar de apagar um
+// upload idêntico antes de gravar os arquivos de novo.
+const (
+	defaultCollectWait = 5 * time.Second
+	collectWaitStep    = 50 * time.Millisecond
+)
+
+// Quota limita os uploads de cada conta (quantidade de imagens e bytes gravados).
+// Zero = sem limite.
+type Quota struct {
+	MaxCount int
+	MaxBytes int64
+}
+
+var DefaultQuota = Quota{MaxCount: 200, MaxBytes: 200 << 20}
+
+// UploadService grava imagens no BlobStore e mantém o registro usado por cotas e pela coleta de órfãos.
+type UploadService struct {
+	st    store.Store
+	blobs store.BlobStore
+	quota Quota
+	now   func() time.Time
+	// collectWait: ver defaultCollectWait
+	collectWait time.Duration
+}
+
+// NewUploadService aceita st nil: nesse caso os arquivos são gravados sem registro
+// (sem cota, sem deduplicação e nunca coletados).
+func NewUploadService(st store.Store, blobs store.BlobStore) *UploadService {
+	return &UploadService{st: st, blobs: blobs, quota: DefaultQuota, now: time.Now, collectWait: defaultCollectWait}
+}
+
+func (s *UploadService) Blobs() store.BlobStore { return s.blobs }
+
+func (s *UploadService) SetQuota(q Quota) { s.quota = q }
+
+// imageBlobPrefix: todas as imagens ficam num namespace endereçado por conteúdo
+const imageBlobPrefix = "images"
+
+// SaveImage processa a imagem (ver media.Process) e grava as variantes como
+// images/<sha256>_<size><ext>. Se o mesmo conteúdo já foi enviado, reaproveita os
+// arquivos e só registra o novo dono. O ID do upload é a chave do tamanho large.
+// Se a coleta de órfãos já pegou o upload idêntico, espera ela apagar os arquivos
+// e grava tudo de novo: as chaves são as mesmas.
+func (s *UploadService) SaveImage(ownerID int, r io.Reader, maxBytes int64) (domain.ImageSet, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return domain.ImageSet{}, err
+	}
+	if int64(len(data)) > maxBytes {
+		return domain.ImageSet{}, media.ErrTooLarge
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	var owned []domain.Upload
+	if s.st != nil {
+		if owned, err = s.st.UploadsByOwner(ownerID); err != nil {
+			return domain.ImageSet{}, err
+		}
+		if existing, ok := s.st.GetUploadByHash(hash); ok {
+			if set, ok := domain.ImageVariants(existing.URL); ok {
+				if !ownsUpload(owned, existing.ID) {
+					if err := s.checkQuota(owned, existing.Size); err != nil {
+						return domain.ImageSet{}, err
+					}
+				}
+				err := s.st.ReuseUpload(existing.ID, ownerID, s.now().UTC())
+				if err == nil {
+					return set, nil
+				}
+				if !errors.Is(err, store.ErrUploadCollecting) && !errors.Is(err, store.ErrUploadNotFound) {
+					return domain.ImageSet{}, err
+				}
+				if err := s.awaitCollected(existing.ID); err != nil {
+					return domain.ImageSet{}, err
+				}
+			}
+		}
+	}
+
+	res, err := media.Process(bytes.NewReader(data), maxBytes)
+	if err != nil {
+		return domain.ImageSet{}, err
+	}
+
+	up := domain.Upload{Hash: hash, OwnerID: ownerID, CreatedAt: s.now().UTC()}
+	for _, v := range res.Variants {
+		up.Size += int64(len(v.Data))
+	}
+	if s.st != nil {
+		if err := s.checkQuota(owned, up.Size); err != nil {
+			return domain.ImageSet{}, err
+		}
+	}
+
+	var set domain.ImageSet
+	for _, v := range res.Variants {
+		key := imageBlobPrefix + "/" + domain.ImageVariantName(hash, string(v.Size), v.Ext)
+		if err := s.blobs.Put(key, v.Data, v.ContentType); err != nil {
+			return domain.ImageSet{}, err
+		}
+		up.Keys = append(up.Keys, key)
+		switch v.Size {
+		case media.SizeThumb:
+			set.Thumb = s.blobs.URL(key)
+		case media.SizeMedium:
+			set.Medium = s.blobs.URL(key)
+		case media.SizeLarge:
+			set.Large = s.blobs.URL(key)
+			up.ID = key
+		}
+	}
+	up.URL = set.Large
+
+	if s.st != nil {
+		if err := s.st.RecordUpload(up); err != nil {
+			return domain.ImageSet{}, err
+		}
+	}
+	return set, nil
+}
+
+// awaitCollected espera o registro do upload sumir: a coleta só o remove depois de
+// apagar os arquivos, então a partir daí gravar as mesmas chaves é seguro.
+func (s *UploadService) awaitCollected(id string) error {
+	for waited := time.Duration(0); waited < s.collectWait; waited += collectWaitStep {
+		if _, ok := s.st.GetUpload(id); !ok {
+			return nil
+		}
+		time.Sleep(collectWaitStep)
+	}
+	return ErrUploadBusy
+}
+
+func ownsUpload(owned []domain.Upload, id string) bool {
+	for _, u := range owned {
+		if u.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// checkQuota é uma checagem "soft": uploads simultâneos da mesma conta podem
+// ultrapassar a cota por um arquivo, o que é aceitável aqui.
+func (s *UploadService) checkQuota(owned []domain.Upload, extra int64) error {
+	var used int64
+	for _, u := range owned {
+		used += u.Size
+	}
+	if s.quota.MaxCount > 0 && len(owned)+1 > s.quota.MaxCount {
+		return fmt.Errorf("%w: limite de %d imagens atingido", ErrQuotaExceeded, s.quota.MaxCount)
+	}
+	if s.quota.MaxBytes > 0 && used+extra > s.quota.MaxBytes {
+		return fmt.Errorf("%w: limite de %d bytes (em uso: %d)", ErrQuotaExceeded, s.quota.MaxBytes, used)
+	}
+	return nil
+}
+
+// Usage devolve o consumo da conta frente à cota.
+func (s *UploadService) Usage(userID int) (domain.StorageUsage, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.StorageUsage{}, err
+	}
+	usage := domain.StorageUsage{MaxCount: s.quota.MaxCount, MaxBytes: s.quota.MaxBytes}
+	if s.st == nil {
+		return usage, nil
+	}
+	owned, err := s.st.UploadsByOwner(userID)
+	if err != nil {
+		return domain.StorageUsage{}, err
+	}
+	usage.Count = len(owned)
+	for _, u := range owned {
+		usage.Bytes += u.Size
+	}
+	return usage, nil
+}
+
+type CollectReport struct {
+	Scanned        int   `json:"scanned"`
+	Deleted        int   `json:"deleted"`
+	Failed         int   `json:"failed"`
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
+}
+
+// CollectOrphans apaga uploads que estão sem referência há mais de grace. Cada
+// passada marca quando viu o upload sem uso pela primeira vez e a carência conta
+// daí: cobre o intervalo entre o upload e o publish do post e também um post
+// apagado agora, mesmo que a imagem seja antiga. Itens de pedidos contam como uso.
+// Com dryRun apenas reporta o que seria apagado (sem marcar).
+func (s *UploadService) CollectOrphans(grace time.Duration, dryRun bool) (CollectReport, error) {
+	if s.st == nil {
+		return CollectReport{}, errors.New("coleta de uploads requer store")
+	}
+	now := s.now().UTC()
+	if !dryRun {
+		if err := s.st.MarkUnreferencedUploads(now); err != nil {
+			return CollectReport{}, err
+		}
+	}
+	cutoff := now.Add(-grace)
+	orphans, err := s.st.UnreferencedUploads(cutoff)
+	if err != nil {
+		return CollectReport{}, err
+	}
+
+	rep := CollectReport{Scanned: len(orphans)}
+	for _, u := range orphans {
+		if dryRun {
+			rep.Deleted++
+			rep.ReclaimedBytes += u.Size
+			continue
+		}
+		// marca o registro primeiro (condicional): se alguém passou a usar ou reenviou,
+		// não apaga arquivos; marcado, SaveImage não o reaproveita mais
+		if err := s.st.ClaimUnreferencedUpload(u.ID, cutoff, now); err != nil {
+			if !errors.Is(err, store.ErrUploadInUse) && !errors.Is(err, store.ErrUploadNotFound) {
+				rep.Failed++
+			}
+			continue
+		}
+		failed := false
+		for _, k := range u.Keys {
+			if err := s.blobs.Delete(k); err != nil {
+				failed = true
+			}
+		}
+		// com falha o registro fica marcado e a próxima passada tenta de novo
+		if failed {
+			rep.Failed++
+			continue
+		}
+		if err := s.st.DeleteCollectedUpload(u.ID); err != nil {
+			rep.Failed++
+			continue
+		}
+		rep.Deleted++
+		rep.ReclaimedBytes += u.Size
+	}
+	return rep, nil
+}
+
+// RunCollector executa CollectOrphans a cada intervalo até ctx ser cancelado.
+func (s *UploadService) RunCollector(ctx context.Context, every, grace time.Duration, logf func(format string, args ...any)) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			rep, err := s.CollectOrphans(grace, false)
+			if err != nil {
+				logf("upload gc: %v", err)
+				continue
+			}
+			if rep.Deleted > 0 || rep.Failed > 0 {
+				logf("upload gc: %d apagados, %d falhas, %d bytes recuperados", rep.Deleted, rep.Failed, rep.ReclaimedBytes)
+			}
+		}
+	}
+}
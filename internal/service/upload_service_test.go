@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func pngReader(t *testing.T) *bytes.Reader {
+	t.Helper()
+	return pngReaderSized(t, 32, 32)
+}
+
+func pngReaderSized(t *testing.T, w, h int) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatalf("png: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestUploadService_SaveImage_RecordsUpload(t *testing.T) {
+	st := store.NewMemoryStore()
+	blobs := store.NewMemoryBlobStore("/static/")
+	svc := NewUploadService(st, blobs)
+
+	set, err := svc.SaveImage(7, pngReader(t), 1<<20)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !strings.HasPrefix(set.Large, "/static/images/") || !strings.HasSuffix(set.Large, "_large.png") {
+		t.Fatalf("unexpected set: %+v", set)
+	}
+	u, ok := st.GetUpload(strings.TrimPrefix(set.Large, "/static/"))
+	if !ok {
+		t.Fatalf("expected upload recorded")
+	}
+	if u.OwnerID != 7 || u.URL != set.Large || len(u.Keys) != 3 || u.Size <= 0 {
+		t.Fatalf("unexpected upload: %+v", u)
+	}
+}
+
+func TestUploadService_CollectOrphans(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Seller", IsSeller: true}})
+	blobs := store.NewMemoryBlobStore("/static/")
+	svc := NewUploadService(st, blobs)
+
+	used, _ := svc.SaveImage(1, pngReaderSized(t, 32, 32), 1<<20)
+	orphanSet, _ := svc.SaveImage(1, pngReaderSized(t, 16, 16), 1<<20)
+	orphanID := strings.TrimPrefix(orphanSet.Large, "/static/")
+	usedID := strings.TrimPrefix(used.Large, "/static/")
+
+	// post referencia a imagem pelo thumb: o publish normaliza para o large
+	ps := NewProductService(st)
+	p := validPayload()
+	p.Product.ImageURL = used.Thumb
+	if _, err := ps.Publish(p); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// dentro da carência nada é apagado
+	rep, err := svc.CollectOrphans(time.Hour, false)
+	if err != nil || rep.Deleted != 0 {
+		t.Fatalf("expected nothing collected, got %+v %v", rep, err)
+	}
+
+	// "envelhece" os uploads
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	orphan, _ := st.GetUpload(orphanID)
+	dry, err := svc.CollectOrphans(time.Hour, true)
+	if err != nil || dry.Deleted != 1 || dry.ReclaimedBytes != orphan.Size {
+		t.Fatalf("unexpected dry-run report: %+v %v", dry, err)
+	}
+	if len(blobs.Keys()) != 6 {
+		t.Fatalf("dry-run must not delete, keys=%v", blobs.Keys())
+	}
+
+	rep, err = svc.CollectOrphans(time.Hour, false)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if rep.Deleted != 1 || rep.ReclaimedBytes != orphan.Size || rep.Failed != 0 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+	if len(blobs.Keys()) != 3 {
+		t.Fatalf("expected only used variants left, keys=%v", blobs.Keys())
+	}
+	if _, ok := st.GetUpload(orphanID); ok {
+		t.Fatalf("orphan record should be gone")
+	}
+	if u, ok := st.GetUpload(usedID); !ok || u.RefCount != 1 {
+		t.Fatalf("used upload should stay referenced: %+v", u)
+	}
+}
+
+func TestUploadService_CollectOrphans_GraceFromDeref(t *testing.T) {
+	st := store.NewMemoryStore()
+	st.SeedUsers([]domain.User{{ID: 1, Name: "Seller", IsSeller: true}})
+	svc := NewUploadService(st, store.NewMemoryBlobStore("/static/"))
+	set, _ := svc.SaveImage(1, pngReaderSized(t, 32, 32), 1<<20)
+	ps := NewProductService(st)
+	p := validPayload()
+	p.Product.ImageURL = set.Large
+	postID, err := ps.Publish(p)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// dias depois o post é apagado: a imagem é velha, mas só agora ficou sem uso
+	start := time.Now()
+	svc.now = func() time.Time { return start.Add(72 * time.Hour) }
+	if _, err := svc.CollectOrphans(time.Hour, false); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if err := ps.DeleteMyPost(1, postID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	svc.now = func() time.Time { return start.Add(72*time.Hour + time.Minute) }
+	if rep, _ := svc.CollectOrphans(time.Hour, false); rep.Deleted != 0 {
+		t.Fatalf("image must survive the grace after losing its post, got %+v", rep)
+	}
+	svc.now = func() time.Time { return start.Add(74 * time.Hour) }
+	if rep, _ := svc.CollectOrphans(time.Hour, false); rep.Deleted != 1 {
+		t.Fatalf("expected the image collected after the grace, got %+v", rep)
+	}
+}
+
+func TestUploadService_CollectOrphans_RequiresStore(t *testing.T) {
+	svc := NewUploadService(nil, store.NewMemoryBlobStore("/static/"))
+	if _, err := svc.CollectOrphans(time.Hour, false); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestUploadService_DeduplicatesByContentHash(t *testing.T) {
+	st := store.NewMemoryStore()
+	blobs := store.NewMemoryBlobStore("/static/")
+	svc := NewUploadService(st, blobs)
+
+	a, err := svc.SaveImage(1, pngReader(t), 1<<20)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	b, err := svc.SaveImage(1, pngReader(t), 1<<20)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	c, err := svc.SaveImage(2, pngReader(t), 1<<20)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if a != b || a != c {
+		t.Fatalf("identical content must share urls: %+v %+v %+v", a, b, c)
+	}
+	if len(blobs.Keys()) != 3 {
+		t.Fatalf("expected a single set of variants, keys=%v", blobs.Keys())
+	}
+
+	// o mesmo arquivo conta uma vez para cada dono
+	u1, _ := svc.Usage(1)
+	u2, _ := svc.Usage(2)
+	if u1.Count != 1 || u2.Count != 1 || u1.Bytes != u2.Bytes || u1.Bytes <= 0 {
+		t.Fatalf("unexpected usage: %+v %+v", u1, u2)
+	}
+}
+
+// gatedBlobs segura o primeiro Delete até release fechar (coleta no meio).
+type gatedBlobs struct {
+	store.BlobStore
+	once     sync.Once
+	deleting chan struct{}
+	release  chan struct{}
+}
+
+func (g *gatedBlobs) Delete(key string) error {
+	g.once.Do(func() {
+		close(g.deleting)
+		<-g.release
+	})
+	return g.BlobStore.Delete(key)
+}
+
+func TestUploadService_DedupeDuringCollection(t *testing.T) {
+	st := store.NewMemoryStore()
+	blobs := store.NewMemoryBlobStore("/static/")
+	svc := NewUploadService(st, blobs)
+	gated := &gatedBlobs{BlobStore: blobs, deleting: make(chan struct{}), release: make(chan struct{})}
+	gc := NewUploadService(st, gated)
+
+	set, err := svc.SaveImage(1, pngReader(t), 1<<20)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := gc.CollectOrphans(time.Hour, false); err != nil {
+		t.Fatalf("mark: %v", err)
+	}
+	gc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	collected := make(chan CollectReport)
+	go func() {
+		rep, _ := gc.CollectOrphans(time.Hour, false)
+		collected <- rep
+	}()
+	<-gated.deleting
+
+	// o mesmo conteúdo chega enquanto a coleta apaga os arquivos
+	saved := make(chan error)
+	var again domain.ImageSet
+	go func() {
+		var err error
+		again, err = svc.SaveImage(2, pngReader(t), 1<<20)
+		saved <- err
+	}()
+	time.Sleep(2 * collectWaitStep)
+	close(gated.release)
+
+	if rep := <-collected; rep.Deleted != 1 {
+		t.Fatalf("expected the orphan collected, got %+v", rep)
+	}
+	if err := <-saved; err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	if again != set {
+		t.Fatalf("expected the same urls, got %+v", again)
+	}
+	if len(blobs.Keys()) != 3 {
+		t.Fatalf("new upload must have its files, keys=%v", blobs.Keys())
+	}
+	u, ok := st.GetUpload(strings.TrimPrefix(set.Large, "/static/"))
+	if !ok || u.OwnerID != 2 || u.CollectingSince != nil {
+		t.Fatalf("expected a fresh record for user 2, got %+v ok=%v", u, ok)
+	}
+}
+
+func TestUploadService_DedupeWaitsForCollectorOrGivesUp(t *testing.T) {
+	st := store.NewMemoryStore()
+	svc := NewUploadService(st, store.NewMemoryBlobStore("/static/"))
+	svc.collectWait = 2 * collectWaitStep
+	set, err := svc.SaveImage(1, pngReader(t), 1<<20)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	id := strings.TrimPrefix(set.Large, "/static/")
+	now := time.Now()
+	_ = st.MarkUnreferencedUploads(now.Add(-time.Hour))
+	if err := st.ClaimUnreferencedUpload(id, now, now); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+
+	// a coleta parou no meio (e não volta a tempo): não reaproveita nem regrava
+	if _, err := svc.SaveImage(2, pngReader(t), 1<<20); !errors.Is(err, ErrUploadBusy) {
+		t.Fatalf("expected ErrUploadBusy, got %v", err)
+	}
+	if owned, _ := st.UploadsByOwner(2); len(owned) != 0 {
+		t.Fatalf("upload in collection must not gain owners, got %+v", owned)
+	}
+}
+
+func TestUploadService_Quota(t *testing.T) {
+	st := store.NewMemoryStore()
+	svc := NewUploadService(st, store.NewMemoryBlobStore("/static/"))
+	svc.SetQuota(Quota{MaxCount: 2})
+
+	if _, err := svc.SaveImage(1, pngReaderSized(t, 10, 10), 1<<20); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if _, err := svc.SaveImage(1, pngReaderSized(t, 11, 11), 1<<20); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	// reenviar algo que já é seu não consome cota
+	if _, err := svc.SaveImage(1, pngReaderSized(t, 10, 10), 1<<20); err != nil {
+		t.Fatalf("expected nil for duplicate, got %v", err)
+	}
+	_, err := svc.SaveImage(1, pngReaderSized(t, 12, 12), 1<<20)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	// cota em bytes
+	usage, _ := svc.Usage(1)
+	svc.SetQuota(Quota{MaxBytes: usage.Bytes + 1})
+	_, err = svc.SaveImage(1, pngReaderSized(t, 13, 13), 1<<20)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded for bytes, got %v", err)
+	}
+
+	usage, err = svc.Usage(1)
+	if err != nil || usage.Count != 2 || usage.MaxBytes == 0 {
+		t.Fatalf("unexpected usage: %+v %v", usage, err)
+	}
+	if _, err := svc.Usage(0); err == nil {
+		t.Fatalf("expected error for invalid id")
+	}
+}
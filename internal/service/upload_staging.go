@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+var (
+	ErrChunkOffset   = errors.New("offset do pedaço não confere com o já recebido")
+	ErrChunkOverflow = errors.New("pedaço ultrapassa o tamanho declarado")
+	ErrStagingID     = errors.New("identificador de upload inválido")
+	ErrStagingEmpty  = errors.New("upload sem pedaços recebidos")
+)
+
+// stagingPrefix separa os pedaços dos arquivos publicados no blob store.
+const stagingPrefix = "staging/"
+
+// ChunkStager guarda os pedaços de uploads retomáveis até o arquivo ficar completo.
+// Cada pedaço vira um objeto no blob store e os offsets ficam no store, então
+// qualquer instância atrás do balanceador continua o upload de onde ele parou e o
+// que já foi recebido sobrevive a quedas de conexão do cliente.
+type ChunkStager struct {
+	st    store.Store
+	blobs store.BlobStore
+	now   func() time.Time
+}
+
+func NewChunkStager(st store.Store, blobs store.BlobStore) *ChunkStager {
+	return &ChunkStager{st: st, blobs: blobs, now: time.Now}
+}
+
+func validStagingID(id string) error {
+	if id == "" || len(id) > 64 {
+		return ErrStagingID
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return ErrStagingID
+		}
+	}
+	return nil
+}
+
+// partKey é único por tentativa: quem perde a corrida pelo offset apaga só o seu objeto.
+func partKey(id string, start int64) (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s/%d-%s", stagingPrefix, id, start, hex.EncodeToString(b)), nil
+}
+
+func partsEnd(parts []domain.UploadPart) int64 {
+	if n := len(parts); n > 0 {
+		return parts[n-1].Start + parts[n-1].Size
+	}
+	return 0
+}
+
+// Offset devolve quantos bytes do upload já foram recebidos (0 se nenhum).
+func (s *ChunkStager) Offset(id string) (int64, error) {
+	if err := validStagingID(id); err != nil {
+		return 0, err
+	}
+	parts, err := s.st.UploadParts(id)
+	if err != nil {
+		return 0, err
+	}
+	return partsEnd(parts), nil
+}
+
+// Append grava r a partir de offset, que precisa ser exatamente o que já foi recebido.
+// Nunca deixa o upload passar de total. Se a leitura de r falhar no meio, o que
+// chegou fica gravado e o cliente retoma do novo offset.
+func (s *ChunkStager) Append(id string, offset, total int64, r io.Reader) (int64, error) {
+	size, err := s.Offset(id)
+	if err != nil {
+		return 0, err
+	}
+	if offset != size {
+		return size, ErrChunkOffset
+	}
+
+	data, readErr := io.ReadAll(io.LimitReader(r, total-size+1))
+	if size+int64(len(data)) > total {
+		// descarta o pedaço inteiro: o cliente mandou mais do que assinou
+		return size, ErrChunkOverflow
+	}
+	if len(data) == 0 {
+		return size, readErr
+	}
+
+	key, err := partKey(id, size)
+	if err != nil {
+		return size, err
+	}
+	if err := s.blobs.Put(key, data, "application/octet-stream"); err != nil {
+		return size, err
+	}
+	ok, err := s.st.AddUploadPart(domain.UploadPart{
+		UploadID: id, Start: size, Size: int64(len(data)), Key: key, CreatedAt: s.now().UTC(),
+	})
+	if err != nil || !ok {
+		_ = s.blobs.Delete(key)
+		if err != nil {
+			return size, err
+		}
+		// retry do mesmo pedaço em outra instância chegou antes
+		current, _ := s.Offset(id)
+		return current, ErrChunkOffset
+	}
+	return size + int64(len(data)), readErr
+}
+
+// Open junta os pedaços recebidos para leitura.
+func (s *ChunkStager) Open(id string) (io.ReadSeeker, error) {
+	if err := validStagingID(id); err != nil {
+		return nil, err
+	}
+	parts, err := s.st.UploadParts(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, ErrStagingEmpty
+	}
+	var buf bytes.Buffer
+	for _, p := range parts {
+		if p.Start != int64(buf.Len()) {
+			return nil, fmt.Errorf("upload %s: pedaço fora de ordem em %d", id, p.Start)
+		}
+		data, _, err := s.blobs.Get(p.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+// Remove apaga os pedaços do upload (objetos e offsets).
+func (s *ChunkStager) Remove(id string) error {
+	if err := validStagingID(id); err != nil {
+		return err
+	}
+	parts, err := s.st.UploadParts(id)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if err := s.blobs.Delete(p.Key); err != nil {
+			return err
+		}
+	}
+	return s.st.DeleteUploadParts(id)
+}
+
+// Sweep apaga uploads parciais sem atividade desde olderThan (URL expirada e
+// cliente que desistiu). Devolve quantos foram removidos.
+func (s *ChunkStager) Sweep(olderThan time.Time) (int, error) {
+	ids, err := s.st.StaleUploadParts(olderThan)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, id := range ids {
+		if err := s.Remove(id); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
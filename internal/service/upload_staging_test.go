@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"socialmeli/internal/store"
+)
+
+func newTestStager() (*ChunkStager, *store.MemoryBlobStore) {
+	blobs := store.NewMemoryBlobStore("/static/")
+	return NewChunkStager(store.NewMemoryStore(), blobs), blobs
+}
+
+func TestChunkStager_AppendResumeAndOverflow(t *testing.T) {
+	s, blobs := newTestStager()
+	id := "abc123"
+
+	n, err := s.Append(id, 0, 10, strings.NewReader("hello"))
+	if err != nil || n != 5 {
+		t.Fatalf("expected 5/nil, got %d/%v", n, err)
+	}
+
+	// offset fora de ordem (pedaço repetido): não grava e informa o recebido
+	n, err = s.Append(id, 0, 10, strings.NewReader("hello"))
+	if !errors.Is(err, ErrChunkOffset) || n != 5 {
+		t.Fatalf("expected ErrChunkOffset/5, got %d/%v", n, err)
+	}
+
+	// passa do total: descarta o pedaço
+	n, err = s.Append(id, 5, 10, strings.NewReader("world!!"))
+	if !errors.Is(err, ErrChunkOverflow) || n != 5 {
+		t.Fatalf("expected ErrChunkOverflow/5, got %d/%v", n, err)
+	}
+
+	n, err = s.Append(id, 5, 10, strings.NewReader("world"))
+	if err != nil || n != 10 {
+		t.Fatalf("expected 10/nil, got %d/%v", n, err)
+	}
+	f, err := s.Open(id)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	data, _ := io.ReadAll(f)
+	if string(data) != "helloworld" {
+		t.Fatalf("unexpected content %q", data)
+	}
+	if len(blobs.Keys()) != 2 {
+		t.Fatalf("expected 2 parts in the blob store, got %v", blobs.Keys())
+	}
+
+	if err := s.Remove(id); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if off, _ := s.Offset(id); off != 0 {
+		t.Fatalf("expected 0 after remove, got %d", off)
+	}
+	if len(blobs.Keys()) != 0 {
+		t.Fatalf("expected parts removed from the blob store, got %v", blobs.Keys())
+	}
+}
+
+// duas instâncias com o mesmo store e blob store: cada PUT pode cair numa delas
+func TestChunkStager_SharedAcrossInstances(t *testing.T) {
+	st := store.NewMemoryStore()
+	blobs := store.NewMemoryBlobStore("/static/")
+	a, b := NewChunkStager(st, blobs), NewChunkStager(st, blobs)
+	id := "cafe01"
+
+	if n, err := a.Append(id, 0, 10, strings.NewReader("hello")); err != nil || n != 5 {
+		t.Fatalf("append on a: %d/%v", n, err)
+	}
+	if off, _ := b.Offset(id); off != 5 {
+		t.Fatalf("b must see a's offset, got %d", off)
+	}
+	// retry do primeiro pedaço em b: recusa e informa o recebido
+	if n, err := b.Append(id, 0, 10, strings.NewReader("hello")); !errors.Is(err, ErrChunkOffset) || n != 5 {
+		t.Fatalf("expected ErrChunkOffset/5 on b, got %d/%v", n, err)
+	}
+	if n, err := b.Append(id, 5, 10, strings.NewReader("world")); err != nil || n != 10 {
+		t.Fatalf("append on b: %d/%v", n, err)
+	}
+	f, err := a.Open(id)
+	if err != nil {
+		t.Fatalf("open on a: %v", err)
+	}
+	if data, _ := io.ReadAll(f); string(data) != "helloworld" {
+		t.Fatalf("unexpected content %q", data)
+	}
+	if _, err := a.Open("beef"); !errors.Is(err, ErrStagingEmpty) {
+		t.Fatalf("expected ErrStagingEmpty, got %v", err)
+	}
+}
+
+func TestChunkStager_RejectsInvalidID(t *testing.T) {
+	s, _ := newTestStager()
+	if _, err := s.Append("../x", 0, 1, strings.NewReader("a")); !errors.Is(err, ErrStagingID) {
+		t.Fatalf("expected ErrStagingID, got %v", err)
+	}
+}
+
+func TestChunkStager_Sweep(t *testing.T) {
+	s, blobs := newTestStager()
+	s.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	if _, err := s.Append("aa", 0, 4, strings.NewReader("ab")); err != nil {
+		t.Fatal(err)
+	}
+	s.now = time.Now
+	if _, err := s.Append("bb", 0, 4, strings.NewReader("ab")); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.Sweep(time.Now().Add(-time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1/nil, got %d/%v", n, err)
+	}
+	if off, _ := s.Offset("bb"); off != 2 {
+		t.Fatalf("recent upload should survive, got offset %d", off)
+	}
+	if len(blobs.Keys()) != 1 {
+		t.Fatalf("expected only bb's part left, got %v", blobs.Keys())
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// Review grava (ou edita) a avaliação do comprador para o vendedor. created indica
+// se é a primeira avaliação do par.
+func (s *UserService) Review(buyerID, sellerID, rating int, text string) (domain.Review, bool, error) {
+	if err := domain.ValidateID(buyerID); err != nil {
+		return domain.Review{}, false, err
+	}
+	if err := domain.ValidateID(sellerID); err != nil {
+		return domain.Review{}, false, err
+	}
+	if buyerID == sellerID {
+		return domain.Review{}, false, domain.ErrReviewSelf
+	}
+	if err := domain.ValidateRating(rating); err != nil {
+		return domain.Review{}, false, err
+	}
+	text = domain.NormalizeCommentText(text)
+	if err := domain.ValidateReviewText(text); err != nil {
+		return domain.Review{}, false, err
+	}
+	seller, ok := s.st.GetUser(sellerID)
+	if !ok {
+		return domain.Review{}, false, store.ErrUserNotFound
+	}
+	if !seller.IsSeller {
+		return domain.Review{}, false, domain.ErrReviewNotSeller
+	}
+	return s.st.UpsertReview(domain.Review{SellerID: sellerID, BuyerID: buyerID, Rating: rating, Text: text})
+}
+
+// ReplyReview grava (ou troca) a resposta do vendedor a uma avaliação que recebeu.
+func (s *UserService) ReplyReview(sellerID, reviewID int, text string) (domain.Review, error) {
+	if err := domain.ValidateID(sellerID); err != nil {
+		return domain.Review{}, err
+	}
+	if err := domain.ValidateID(reviewID); err != nil {
+		return domain.Review{}, err
+	}
+	r, ok := s.st.GetReview(reviewID)
+	if !ok {
+		return domain.Review{}, store.ErrReviewNotFound
+	}
+	if r.SellerID != sellerID {
+		return domain.Review{}, domain.ErrReviewForbidden
+	}
+	text = domain.NormalizeCommentText(text)
+	if err := domain.ValidateReviewText(text); err != nil {
+		return domain.Review{}, err
+	}
+	return s.st.SetReviewReply(reviewID, text, time.Now().UTC())
+}
+
+// Reviews pagina as avaliações do vendedor (alteradas mais recentemente primeiro)
+// junto com o resumo das notas. page começa em 1.
+func (s *UserService) Reviews(sellerID, page, limit int) ([]domain.Review, int, domain.RatingSummary, error) {
+	if err := domain.ValidateID(sellerID); err != nil {
+		return nil, 0, domain.RatingSummary{}, err
+	}
+	if _, ok := s.st.GetUser(sellerID); !ok {
+		return nil, 0, domain.RatingSummary{}, store.ErrUserNotFound
+	}
+	items, total, err := s.st.ReviewsOf(sellerID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, domain.RatingSummary{}, err
+	}
+	summary, err := s.st.RatingSummary(sellerID)
+	if err != nil {
+		return nil, 0, domain.RatingSummary{}, err
+	}
+	return items, total, summary, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+func TestUserService_Reviews(t *testing.T) {
+	st := store.NewMemoryStore()
+	for _, a := range []struct {
+		name   string
+		seller bool
+	}{{"Buyer", false}, {"Seller", true}, {"Other", false}} {
+		if _, err := st.CreateAccount(a.name, a.name+"@example.com", "hash", a.seller); err != nil {
+			t.Fatalf("account: %v", err)
+		}
+	}
+	svc := NewUserService(st)
+
+	if _, _, err := svc.Review(2, 2, 5, "Eu mesmo"); err != domain.ErrReviewSelf {
+		t.Fatalf("expected ErrReviewSelf, got %v", err)
+	}
+	if _, _, err := svc.Review(1, 3, 5, "Bom"); err != domain.ErrReviewNotSeller {
+		t.Fatalf("expected ErrReviewNotSeller, got %v", err)
+	}
+	if _, _, err := svc.Review(1, 2, 6, "Bom"); err != domain.ErrReviewRating {
+		t.Fatalf("expected ErrReviewRating, got %v", err)
+	}
+	if _, _, err := svc.Review(1, 2, 5, ""); err != domain.ErrFieldEmpty {
+		t.Fatalf("expected ErrFieldEmpty, got %v", err)
+	}
+
+	r, created, err := svc.Review(1, 2, 2, "Atrasou")
+	if err != nil || !created {
+		t.Fatalf("review: %+v %v %v", r, created, err)
+	}
+	if _, created, _ = svc.Review(1, 2, 4, "Resolveram rápido"); created {
+		t.Fatalf("second review from the same buyer must edit")
+	}
+	_, _, _ = svc.Review(3, 2, 5, "Perfeito")
+
+	if _, err := svc.ReplyReview(1, r.ID, "Não sou o vendedor"); err != domain.ErrReviewForbidden {
+		t.Fatalf("expected ErrReviewForbidden, got %v", err)
+	}
+	if got, err := svc.ReplyReview(2, r.ID, "Obrigado!"); err != nil || got.Reply != "Obrigado!" || got.RepliedAt == nil {
+		t.Fatalf("reply: %+v %v", got, err)
+	}
+
+	prof, err := svc.GetProfile(2)
+	if err != nil || prof.Rating.Count != 2 || prof.Rating.Average != 4.5 || prof.Rating.Histogram[2] != 0 {
+		t.Fatalf("unexpected profile rating %+v %v", prof.Rating, err)
+	}
+	items, total, sum, err := svc.Reviews(2, 1, 10)
+	if err != nil || total != 2 || len(items) != 2 || sum.Count != 2 {
+		t.Fatalf("unexpected reviews %+v %d %+v %v", items, total, sum, err)
+	}
+}
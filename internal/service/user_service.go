@@ -6,11 +6,27 @@ import (
 )
 
 type UserService struct {
-	st store.Store
+	st       store.Store
+	timeline *Timeline
+	events   *EventBus
+	notifier *NotificationService
+	webhooks *WebhookService
 }
 
 func NewUserService(st store.Store) *UserService { return &UserService{st: st} }
 
+// SetTimeline faz follow/unfollow manterem o feed materializado.
+func (s *UserService) SetTimeline(t *Timeline) { s.timeline = t }
+
+// SetWebhooks faz Follow disparar follower.new nos webhooks do vendedor.
+func (s *UserService) SetWebhooks(w *WebhookService) { s.webhooks = w }
+
+// SetNotifications faz Follow deixar uma notificação para o vendedor.
+func (s *UserService) SetNotifications(n *NotificationService) { s.notifier = n }
+
+// SetEventBus faz Follow avisar o vendedor (EventNewFollower).
+func (s *UserService) SetEventBus(b *EventBus) { s.events = b }
+
 func (s *UserService) Follow(userID, sellerID int) error {
 	if err := domain.ValidateID(userID); err != nil {
 		return err
@@ -21,7 +37,24 @@ func (s *UserService) Follow(userID, sellerID int) error {
 	if userID == sellerID {
 		return domain.ErrIDGreaterThanZero // reusa erro generico
 	}
-	return s.st.Follow(userID, sellerID)
+	if err := s.st.Follow(userID, sellerID); err != nil {
+		return err
+	}
+	if s.timeline != nil {
+		s.timeline.Followed(userID, sellerID)
+	}
+	follower, _ := s.st.GetUser(userID)
+	data := FollowerEventData{FollowerID: userID, FollowerName: follower.Name}
+	if s.events != nil {
+		s.events.Publish(Event{Type: EventNewFollower, UserID: sellerID, Data: data})
+	}
+	if s.notifier != nil {
+		logNotifyErr(s.notifier.FollowerAdded(userID, sellerID))
+	}
+	if s.webhooks != nil {
+		s.webhooks.Dispatch(sellerID, domain.WebhookFollowerNew, data)
+	}
+	return nil
 }
 
 func (s *UserService) Unfollow(userID, sellerID int) error {
@@ -31,7 +64,13 @@ func (s *UserService) Unfollow(userID, sellerID int) error {
 	if err := domain.ValidateID(sellerID); err != nil {
 		return err
 	}
-	return s.st.Unfollow(userID, sellerID)
+	if err := s.st.Unfollow(userID, sellerID); err != nil {
+		return err
+	}
+	if s.timeline != nil {
+		s.timeline.Unfollowed(userID, sellerID)
+	}
+	return nil
 }
 
 func (s *UserService) FollowersCount(sellerID int) (domain.User, int, error) {
@@ -107,6 +146,25 @@ func (s *UserService) CreateUser(name string, isSeller bool) (domain.User, error
 	return s.st.CreateUser(name, isSeller)
 }
 
+// SearchUsers busca usuários pelo nome (prefixo de qualquer palavra, sem acento).
+func (s *UserService) SearchUsers(q string, sellersOnly bool, page, limit int) ([]domain.UserSummary, int, error) {
+	terms, err := domain.ParseSearchQuery(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.st.SearchUsers(terms, sellersOnly, limit, (page-1)*limit)
+}
+
+// Typeahead devolve as primeiras sugestões para o que já foi digitado.
+func (s *UserService) Typeahead(q string, sellersOnly bool, limit int) ([]domain.UserSummary, error) {
+	terms, err := domain.ParseSearchQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	users, _, err := s.st.SearchUsers(terms, sellersOnly, limit, 0)
+	return users, err
+}
+
 // Profile
 
 type Profile struct {
@@ -114,6 +172,8 @@ type Profile struct {
 	FollowersCount  int            `json:"followers_count"`
 	FollowedCount   int            `json:"followed_count"`
 	PublicationsCnt int            `json:"publications_count"`
+	// Rating resume as avaliações recebidas (zerado para quem nunca foi avaliado).
+	Rating domain.RatingSummary `json:"rating"`
 }
 
 func (s *UserService) GetProfile(userID int) (Profile, error) {
@@ -127,7 +187,11 @@ func (s *UserService) GetProfile(userID int) (Profile, error) {
 	followers, _ := s.st.FollowersOf(userID)
 	followed, _ := s.st.FollowedBy(userID)
 	posts := s.st.PostsByUser(userID)
-	return Profile{User: a, FollowersCount: len(followers), FollowedCount: len(followed), PublicationsCnt: len(posts)}, nil
+	rating, err := s.st.RatingSummary(userID)
+	if err != nil {
+		return Profile{}, err
+	}
+	return Profile{User: a, FollowersCount: len(followers), FollowedCount: len(followed), PublicationsCnt: len(posts), Rating: rating}, nil
 }
 
 func (s *UserService) PostsByUser(userID int, order string) ([]domain.Post, error) {
@@ -139,7 +203,7 @@ func (s *UserService) PostsByUser(userID int, order string) ([]domain.Post, erro
 	}
 	posts := s.st.PostsByUser(userID)
 	domain.SortPostsByDate(posts, order)
-	return posts, nil
+	return presentPosts(s.st, posts)
 }
 
 func (s *UserService) UpdateAvatar(userID int, avatarURL string) (domain.Account, error) {
@@ -50,7 +50,7 @@ func TestUserService_GetProfile_Success(t *testing.T) {
 		DateStr:  "01-01-2026",
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 	})
 
 	svc := NewUserService(st)
@@ -84,7 +84,7 @@ func TestUserService_PostsByUser_Success(t *testing.T) {
 		DateStr:  "01-01-2026",
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 	})
 	_, _ = st.AddPost(domain.Post{
 		UserID:   acc.ID,
@@ -92,7 +92,7 @@ func TestUserService_PostsByUser_Success(t *testing.T) {
 		DateStr:  "02-01-2026",
 		Product:  domain.Product{ProductID: 2, ProductName: "P2", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    20,
+		Price:    20_00,
 	})
 
 	svc := NewUserService(st)
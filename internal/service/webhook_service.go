@@ -0,0 +1,423 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+const (
+	// DefaultWebhookAttempts tentativas antes de a entrega ir para a dead-letter.
+	DefaultWebhookAttempts = 8
+	// DefaultWebhookBackoff é a espera depois da primeira falha; dobra a cada nova falha
+	// (30s, 1m, 2m, ... ~1h no total com 8 tentativas), até maxWebhookBackoff.
+	DefaultWebhookBackoff = 30 * time.Second
+	maxWebhookBackoff     = 6 * time.Hour
+
+	webhookTimeout = 10 * time.Second
+	// webhookBatch entregas são reservadas de uma vez e feitas em sequência.
+	webhookBatch = 10
+	// webhookLease segura as entregas reservadas por um worker: cobre o lote inteiro
+	// no pior caso (todo POST esperando o timeout), com folga, para outra instância
+	// não pegar de novo uma entrega ainda em andamento.
+	webhookLease = webhookBatch*webhookTimeout + 30*time.Second
+)
+
+// Headers enviados em cada entrega. A assinatura é
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + corpo)).
+const (
+	WebhookEventHeader     = "X-SocialMeli-Event"
+	WebhookDeliveryHeader  = "X-SocialMeli-Delivery"
+	WebhookTimestampHeader = "X-SocialMeli-Timestamp"
+	WebhookSignatureHeader = "X-SocialMeli-Signature"
+)
+
+// WebhookPayload é o corpo JSON enviado ao receptor.
+type WebhookPayload struct {
+	Event     string    `json:"event"`
+	UserID    int       `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// PostDeletedData é o Data de post.deleted.
+type PostDeletedData struct {
+	PostID int `json:"post_id"`
+}
+
+// WebhookService guarda as assinaturas dos usuários e entrega os eventos delas.
+// Dispatch só enfileira no store; o worker (Run) faz os POSTs, com backoff exponencial
+// entre falhas, e manda para a dead-letter quem esgota as tentativas.
+type WebhookService struct {
+	st           store.Store
+	client       *http.Client
+	allowPrivate bool
+	attempts     int
+	backoff      time.Duration
+	now          func() time.Time
+	logf         func(format string, args ...any)
+	wake         chan struct{}
+}
+
+func NewWebhookService(st store.Store) *WebhookService {
+	return &WebhookService{
+		st:       st,
+		client:   webhookClient(false),
+		attempts: DefaultWebhookAttempts,
+		backoff:  DefaultWebhookBackoff,
+		now:      time.Now,
+		logf:     log.Printf,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// errWebhookAddress é o erro de conexão para destinos internos; aparece em last_error.
+var errWebhookAddress = errors.New("endereço de destino não permitido")
+
+// webhookClient monta o cliente das entregas. Sem allowPrivate a conexão com
+// endereços internos é recusada no dial, depois da resolução de nomes: checar só a
+// URL deixaria passar um DNS que responde 127.0.0.1 (ou muda de resposta depois
+// do cadastro). Proxy fica desligado pelo mesmo motivo: o dial seria para ele.
+func webhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || internalIP(ip) {
+				return errWebhookAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		// redirecionamento conta como falha: o receptor precisa responder na URL cadastrada
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// sharedAddressSpace é a faixa 100.64.0.0/10 (CGNAT), interna em muitas nuvens.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// internalIP diz se o endereço é de loopback, rede privada, link-local (inclusive o
+// 169.254.169.254 de metadados das nuvens), não especificado ou multicast.
+func internalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip)
+}
+
+// SetAllowPrivate libera entregas para endereços internos (loopback, redes privadas
+// etc.). Só para desenvolvimento e testes com receptor local.
+func (s *WebhookService) SetAllowPrivate(allow bool) {
+	s.allowPrivate = allow
+	s.client = webhookClient(allow)
+}
+
+// internalHost recusa já no cadastro a URL com IP interno literal; nomes só são
+// conferidos na conexão.
+func internalHost(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && internalIP(ip)
+}
+
+// SetRetry ajusta o número de tentativas e a espera depois da primeira falha.
+func (s *WebhookService) SetRetry(attempts int, backoff time.Duration) {
+	if attempts > 0 {
+		s.attempts = attempts
+	}
+	if backoff > 0 {
+		s.backoff = backoff
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// SignWebhook calcula o valor do header de assinatura.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Create cadastra o webhook e devolve o segredo (a única vez em que ele aparece).
+func (s *WebhookService) Create(userID int, url string, events []string) (domain.Webhook, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return domain.Webhook{}, err
+	}
+	if err := domain.ValidateWebhook(url, events); err != nil {
+		return domain.Webhook{}, err
+	}
+	if !s.allowPrivate && internalHost(url) {
+		return domain.Webhook{}, domain.ErrWebhookURL
+	}
+	existing, err := s.st.WebhooksByUser(userID)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	if len(existing) >= domain.MaxWebhooksPerUser {
+		return domain.Webhook{}, domain.ErrWebhookLimit
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	// sem repetidos, na ordem de domain.WebhookEvents
+	want := map[string]bool{}
+	for _, e := range events {
+		want[e] = true
+	}
+	var evs []string
+	for _, e := range domain.WebhookEvents {
+		if want[e] {
+			evs = append(evs, e)
+		}
+	}
+	return s.st.CreateWebhook(domain.Webhook{UserID: userID, URL: url, Events: evs, Secret: secret})
+}
+
+// List devolve os webhooks do usuário, sem os segredos.
+func (s *WebhookService) List(userID int) ([]domain.Webhook, error) {
+	if err := domain.ValidateID(userID); err != nil {
+		return nil, err
+	}
+	ws, err := s.st.WebhooksByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ws {
+		ws[i].Secret = ""
+	}
+	return ws, nil
+}
+
+func (s *WebhookService) Delete(userID, id int) error {
+	if err := domain.ValidateID(id); err != nil {
+		return err
+	}
+	return s.st.DeleteWebhook(userID, id)
+}
+
+// owned devolve o webhook se for do usuário (de outro usuário = inexistente).
+func (s *WebhookService) owned(userID, id int) (domain.Webhook, error) {
+	if err := domain.ValidateID(id); err != nil {
+		return domain.Webhook{}, err
+	}
+	w, ok := s.st.GetWebhook(id)
+	if !ok || w.UserID != userID {
+		return domain.Webhook{}, store.ErrWebhookNotFound
+	}
+	return w, nil
+}
+
+// Deliveries lista as tentativas de entrega do webhook (status "dead" = dead-letter).
+func (s *WebhookService) Deliveries(userID, webhookID int, status string, page, limit int) ([]domain.WebhookDelivery, int, error) {
+	if err := domain.ValidateDeliveryStatus(status); err != nil {
+		return nil, 0, err
+	}
+	if _, err := s.owned(userID, webhookID); err != nil {
+		return nil, 0, err
+	}
+	return s.st.WebhookDeliveries(webhookID, status, limit, (page-1)*limit)
+}
+
+// Redeliver tira a entrega da dead-letter e a coloca de volta na fila, com as tentativas zeradas.
+func (s *WebhookService) Redeliver(userID, webhookID, deliveryID int) (domain.WebhookDelivery, error) {
+	if _, err := s.owned(userID, webhookID); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	d, ok := s.st.GetWebhookDelivery(deliveryID)
+	if !ok || d.WebhookID != webhookID {
+		return domain.WebhookDelivery{}, store.ErrDeliveryNotFound
+	}
+	if d.Status != domain.DeliveryDead {
+		return domain.WebhookDelivery{}, domain.ErrNotDead
+	}
+	d.Status, d.Attempts, d.NextAttemptAt = domain.DeliveryPending, 0, s.now()
+	if err := s.st.UpdateWebhookDelivery(d); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	s.kick()
+	return d, nil
+}
+
+// Dispatch enfileira o evento para os webhooks de userID que o assinam.
+// Falhas só são registradas no log: não desfazem a ação que gerou o evento.
+func (s *WebhookService) Dispatch(userID int, event string, data any) {
+	hooks, err := s.st.WebhooksByUser(userID)
+	if err != nil {
+		s.logf("webhooks: %s do usuário %d: %v", event, userID, err)
+		return
+	}
+	now := s.now()
+	var body []byte
+	var ds []domain.WebhookDelivery
+	for _, w := range hooks {
+		if !w.Subscribes(event) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(WebhookPayload{Event: event, UserID: userID, CreatedAt: now.UTC(), Data: data}); err != nil {
+				s.logf("webhooks: %s do usuário %d: %v", event, userID, err)
+				return
+			}
+		}
+		ds = append(ds, domain.WebhookDelivery{WebhookID: w.ID, Event: event, Payload: body, Status: domain.DeliveryPending, NextAttemptAt: now})
+	}
+	if len(ds) == 0 {
+		return
+	}
+	if err := s.st.AddWebhookDeliveries(ds); err != nil {
+		s.logf("webhooks: %s do usuário %d: %v", event, userID, err)
+		return
+	}
+	s.kick()
+}
+
+// kick acorda o worker sem esperar o próximo tick.
+func (s *WebhookService) kick() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// backoffAfter é a espera depois da n-ésima falha.
+func (s *WebhookService) backoffAfter(n int) time.Duration {
+	d := s.backoff
+	for i := 1; i < n && d < maxWebhookBackoff; i++ {
+		d *= 2
+	}
+	if d > maxWebhookBackoff {
+		d = maxWebhookBackoff
+	}
+	return d
+}
+
+// ProcessDue tenta as entregas vencidas (em lotes) e devolve quantas foram tentadas.
+func (s *WebhookService) ProcessDue() (int, error) {
+	n := 0
+	for {
+		now := s.now()
+		leaseUntil := now.Add(webhookLease)
+		batch, err := s.st.ClaimWebhookDeliveries(now, leaseUntil, webhookBatch)
+		if err != nil {
+			return n, err
+		}
+		for i, d := range batch {
+			// só começa o POST se ele termina dentro da reserva; o resto do lote volta
+			// para a fila quando a reserva vence
+			if s.now().Add(webhookTimeout).After(leaseUntil) {
+				s.logf("webhooks: reserva vencendo, %d entregas ficam para depois", len(batch)-i)
+				return n, nil
+			}
+			if err := s.st.UpdateWebhookDelivery(s.attempt(d)); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(batch) < webhookBatch {
+			return n, nil
+		}
+	}
+}
+
+// attempt faz o POST e devolve a entrega com o resultado e o próximo passo.
+func (s *WebhookService) attempt(d domain.WebhookDelivery) domain.WebhookDelivery {
+	d.Attempts++
+	d.LastStatus, d.LastError = 0, ""
+
+	w, ok := s.st.GetWebhook(d.WebhookID)
+	if !ok { // apagado no meio do caminho
+		d.Status, d.LastError = domain.DeliveryDead, store.ErrWebhookNotFound.Error()
+		return d
+	}
+
+	ts := s.now().Unix()
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "SocialMeli-Webhooks/1.0")
+		req.Header.Set(WebhookEventHeader, d.Event)
+		req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(d.ID))
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(w.Secret, ts, d.Payload))
+		var resp *http.Response
+		if resp, err = s.client.Do(req); err == nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			d.LastStatus = resp.StatusCode
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				d.Status = domain.DeliverySucceeded
+				return d
+			}
+			err = fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+	}
+
+	d.LastError = err.Error()
+	if len(d.LastError) > 500 {
+		d.LastError = d.LastError[:500]
+	}
+	if d.Attempts >= s.attempts {
+		d.Status = domain.DeliveryDead
+		s.logf("webhooks: entrega %d para a dead-letter após %d tentativas: %s", d.ID, d.Attempts, d.LastError)
+		return d
+	}
+	d.Status = domain.DeliveryPending
+	d.NextAttemptAt = s.now().Add(s.backoffAfter(d.Attempts))
+	return d
+}
+
+// Run processa a fila a cada every, ou logo que algo é enfileirado.
+func (s *WebhookService) Run(ctx context.Context, every time.Duration) {
+	tk := time.NewTicker(every)
+	defer tk.Stop()
+	for {
+		if _, err := s.ProcessDue(); err != nil {
+			s.logf("webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		case <-s.wake:
+		}
+	}
+}
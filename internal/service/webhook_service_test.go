@@ -0,0 +1,278 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+	"socialmeli/internal/store"
+)
+
+// webhookReceiver registra as requisições recebidas e responde com o status da vez.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int // consumidos em ordem; acabou = 200
+	reqs     []*http.Request
+	bodies   [][]byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reqs = append(r.reqs, req)
+	r.bodies = append(r.bodies, body)
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func webhookStore(t *testing.T) (*store.MemoryStore, *WebhookService, *webhookReceiver, *httptest.Server, *time.Time) {
+	t.Helper()
+	st, _ := feedStore(t)
+	recv := &webhookReceiver{}
+	srv := httptest.NewServer(recv)
+	t.Cleanup(srv.Close)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ws := NewWebhookService(st)
+	ws.now = func() time.Time { return now }
+	ws.logf = t.Logf
+	ws.SetRetry(3, time.Minute)
+	ws.SetAllowPrivate(true) // o receptor de teste escuta em 127.0.0.1
+	return st, ws, recv, srv, &now
+}
+
+func TestWebhooks_SignedDelivery(t *testing.T) {
+	st, ws, recv, srv, _ := webhookStore(t)
+	hook, err := ws.Create(2, srv.URL+"/hook", []string{domain.WebhookPostDeleted, domain.WebhookFollowerNew, domain.WebhookFollowerNew})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if hook.Secret == "" || len(hook.Events) != 2 || hook.Events[0] != domain.WebhookFollowerNew {
+		t.Fatalf("unexpected webhook %+v", hook)
+	}
+	if list, _ := ws.List(2); list[0].Secret != "" {
+		t.Fatalf("secret must not be listed")
+	}
+
+	us := NewUserService(st)
+	us.SetWebhooks(ws)
+	ps := NewProductService(st)
+	ps.SetWebhooks(ws)
+	if err := us.Follow(4, 2); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	publishAt(t, ps, 2, 0, 0) // post.created não foi assinado
+
+	if n, err := ws.ProcessDue(); err != nil || n != 1 {
+		t.Fatalf("expected 1 delivery, got %d (%v)", n, err)
+	}
+	req, body := recv.reqs[0], recv.bodies[0]
+	ts, _ := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	if req.URL.Path != "/hook" || req.Header.Get(WebhookEventHeader) != domain.WebhookFollowerNew {
+		t.Fatalf("unexpected request %s %v", req.URL, req.Header)
+	}
+	if req.Header.Get(WebhookSignatureHeader) != SignWebhook(hook.Secret, ts, body) {
+		t.Fatalf("bad signature")
+	}
+	var payload struct {
+		Event  string            `json:"event"`
+		UserID int               `json:"user_id"`
+		Data   FollowerEventData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.UserID != 2 || payload.Data.FollowerID != 4 {
+		t.Fatalf("unexpected payload %s (%v)", body, err)
+	}
+	ds, _, _ := ws.Deliveries(2, hook.ID, domain.DeliverySucceeded, 1, 10)
+	if len(ds) != 1 || ds[0].Attempts != 1 || ds[0].LastStatus != 200 {
+		t.Fatalf("unexpected deliveries %+v", ds)
+	}
+	if _, _, err := ws.Deliveries(3, hook.ID, "", 1, 10); !errors.Is(err, store.ErrWebhookNotFound) {
+		t.Fatalf("another user's webhook must look missing, got %v", err)
+	}
+}
+
+func TestWebhooks_RetryBackoffAndDeadLetter(t *testing.T) {
+	_, ws, recv, srv, now := webhookStore(t)
+	recv.statuses = []int{500, 503, 500, 500}
+	hook, _ := ws.Create(2, srv.URL, []string{domain.WebhookPostDeleted})
+	ws.Dispatch(2, domain.WebhookPostDeleted, PostDeletedData{PostID: 9})
+
+	// 1ª tentativa falha: volta em 1m
+	ws.ProcessDue()
+	ds, _, _ := ws.Deliveries(2, hook.ID, "", 1, 10)
+	if ds[0].Status != domain.DeliveryPending || ds[0].Attempts != 1 || !ds[0].NextAttemptAt.Equal(now.Add(time.Minute)) || ds[0].LastError != "HTTP 500" {
+		t.Fatalf("unexpected after first failure %+v", ds[0])
+	}
+	if n, _ := ws.ProcessDue(); n != 0 {
+		t.Fatalf("retry must wait for backoff, got %d", n)
+	}
+
+	// 2ª falha: espera dobra
+	*now = now.Add(time.Minute)
+	ws.ProcessDue()
+	ds, _, _ = ws.Deliveries(2, hook.ID, "", 1, 10)
+	if ds[0].Attempts != 2 || !ds[0].NextAttemptAt.Equal(now.Add(2*time.Minute)) || ds[0].LastStatus != 503 {
+		t.Fatalf("unexpected after second failure %+v", ds[0])
+	}
+
+	// 3ª falha: dead-letter
+	*now = now.Add(2 * time.Minute)
+	ws.ProcessDue()
+	dead, total, _ := ws.Deliveries(2, hook.ID, domain.DeliveryDead, 1, 10)
+	if total != 1 || dead[0].Attempts != 3 {
+		t.Fatalf("expected dead letter, got %+v", dead)
+	}
+	if _, err := ws.Redeliver(2, hook.ID, 999); !errors.Is(err, store.ErrDeliveryNotFound) {
+		t.Fatalf("expected ErrDeliveryNotFound, got %v", err)
+	}
+
+	// reenvio manual: volta para a fila com as tentativas zeradas; falha de novo e depois entrega
+	if _, err := ws.Redeliver(2, hook.ID, dead[0].ID); err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	if _, err := ws.Redeliver(2, hook.ID, dead[0].ID); !errors.Is(err, domain.ErrNotDead) {
+		t.Fatalf("expected ErrNotDead, got %v", err)
+	}
+	ws.ProcessDue()
+	*now = now.Add(time.Minute)
+	ws.ProcessDue()
+	if ok, _, _ := ws.Deliveries(2, hook.ID, domain.DeliverySucceeded, 1, 10); len(ok) != 1 || ok[0].Attempts != 2 {
+		t.Fatalf("expected delivered after redeliver, got %+v", ok)
+	}
+	if len(recv.reqs) != 5 {
+		t.Fatalf("expected 5 requests, got %d", len(recv.reqs))
+	}
+}
+
+// receptor lento: cada POST leva 50s no relógio do serviço. Uma segunda instância
+// rodando no meio do lote (durante o segundo POST) não pode pegar de novo entregas
+// ainda reservadas.
+func TestWebhooks_SlowReceiverKeepsLease(t *testing.T) {
+	st, ws, _, _, start := webhookStore(t)
+	var mu sync.Mutex
+	now := *start
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	ws.now = clock
+	other := NewWebhookService(st)
+	other.now, other.logf = clock, t.Logf
+	other.SetAllowPrivate(true)
+
+	received := map[string]int{}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		received[req.Header.Get(WebhookDeliveryHeader)]++
+		now = now.Add(50 * time.Second)
+		calls++
+		runOther := calls == 2
+		mu.Unlock()
+		if runOther {
+			if _, err := other.ProcessDue(); err != nil {
+				t.Errorf("other instance: %v", err)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	if _, err := ws.Create(2, srv.URL, []string{domain.WebhookFollowerNew}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	total := webhookBatch + 2
+	for i := 0; i < total; i++ {
+		ws.Dispatch(2, domain.WebhookFollowerNew, FollowerEventData{FollowerID: i})
+	}
+
+	for i := 0; i < 2*total; i++ {
+		if _, err := ws.ProcessDue(); err != nil {
+			t.Fatalf("process: %v", err)
+		}
+		mu.Lock()
+		done := len(received) == total
+		now = now.Add(webhookLease) // reservas abandonadas vencem
+		mu.Unlock()
+		if done {
+			break
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != total {
+		t.Fatalf("expected %d deliveries, got %d", total, len(received))
+	}
+	for id, n := range received {
+		if n != 1 {
+			t.Fatalf("delivery %s sent %d times", id, n)
+		}
+	}
+}
+
+func TestWebhooks_InternalAddressesRefused(t *testing.T) {
+	st, ws, recv, srv, _ := webhookStore(t)
+	ws.SetAllowPrivate(false)
+	for _, u := range []string{srv.URL, "http://169.254.169.254/latest/meta-data", "http://10.0.0.5/hook", "http://[::1]:5432"} {
+		if _, err := ws.Create(2, u, []string{domain.WebhookPostDeleted}); !errors.Is(err, domain.ErrWebhookURL) {
+			t.Fatalf("%s: expected ErrWebhookURL, got %v", u, err)
+		}
+	}
+
+	// nomes só são resolvidos na conexão: localhost passa no cadastro e cai no dial,
+	// assim como uma URL interna gravada antes da regra
+	byName := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	if _, err := ws.Create(2, byName, []string{domain.WebhookPostDeleted}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := st.CreateWebhook(domain.Webhook{UserID: 2, URL: srv.URL, Events: []string{domain.WebhookPostDeleted}, Secret: "s"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	ws.Dispatch(2, domain.WebhookPostDeleted, PostDeletedData{PostID: 9})
+	if n, err := ws.ProcessDue(); err != nil || n != 2 {
+		t.Fatalf("expected 2 attempts, got %d (%v)", n, err)
+	}
+	hooks, _ := ws.List(2)
+	for _, h := range hooks {
+		ds, _, _ := ws.Deliveries(2, h.ID, "", 1, 10)
+		if len(ds) != 1 || ds[0].LastStatus != 0 || !strings.Contains(ds[0].LastError, errWebhookAddress.Error()) {
+			t.Fatalf("%s: unexpected delivery %+v", h.URL, ds)
+		}
+	}
+	if len(recv.reqs) != 0 {
+		t.Fatalf("internal receiver must not be reached, got %d requests", len(recv.reqs))
+	}
+}
+
+func TestWebhooks_Validation(t *testing.T) {
+	_, ws, _, _, _ := webhookStore(t)
+	if _, err := ws.Create(2, "ftp://x", []string{domain.WebhookPostCreated}); !errors.Is(err, domain.ErrWebhookURL) {
+		t.Fatalf("expected ErrWebhookURL, got %v", err)
+	}
+	if _, err := ws.Create(2, "https://x", []string{"post.liked"}); !errors.Is(err, domain.ErrWebhookEvents) {
+		t.Fatalf("expected ErrWebhookEvents, got %v", err)
+	}
+	for i := 0; i < domain.MaxWebhooksPerUser; i++ {
+		if _, err := ws.Create(2, "https://x", []string{domain.WebhookPostCreated}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	if _, err := ws.Create(2, "https://x", []string{domain.WebhookPostCreated}); !errors.Is(err, domain.ErrWebhookLimit) {
+		t.Fatalf("expected ErrWebhookLimit, got %v", err)
+	}
+	if ws.backoffAfter(30) != maxWebhookBackoff {
+		t.Fatalf("backoff must be capped")
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+var (
+	ErrBlobNotFound   = errors.New("arquivo inexistente")
+	ErrInvalidBlobKey = errors.New("chave de arquivo inválida")
+)
+
+// BlobStore guarda os arquivos enviados (avatares, imagens de produtos).
+// As chaves são caminhos relativos com "/" (ex.: products/12-99_large.jpg).
+type BlobStore interface {
+	Put(key string, data []byte, contentType string) error
+	// Get devolve o conteúdo e o content-type gravado.
+	Get(key string) ([]byte, string, error)
+	// Delete não falha se a chave não existir.
+	Delete(key string) error
+	// URL é o endereço público para o cliente baixar o arquivo.
+	URL(key string) string
+}
+
+// cleanBlobKey rejeita chaves absolutas ou que tentem sair da raiz.
+func cleanBlobKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidBlobKey
+	}
+	clean := path.Clean(key)
+	if clean != key || clean == "." || strings.HasPrefix(clean, "../") || clean == ".." {
+		return "", ErrInvalidBlobKey
+	}
+	return clean, nil
+}
+
+// NewBlobStoreFromEnv escolhe a implementação por BLOB_STORE (local | memory | s3).
+// Sem configuração mantém o comportamento antigo: disco em ./uploads servido em /static/.
+func NewBlobStoreFromEnv() (BlobStore, error) {
+	switch strings.ToLower(os.Getenv("BLOB_STORE")) {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		return NewLocalBlobStore(dir, "/static/"), nil
+	case "memory":
+		return NewMemoryBlobStore("/static/"), nil
+	case "s3":
+		cfg := S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		}
+		return NewS3BlobStore(cfg)
+	default:
+		return nil, fmt.Errorf("BLOB_STORE desconhecido: %q", os.Getenv("BLOB_STORE"))
+	}
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"errors"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// LocalBlobStore grava no disco local. Serve para uma instância só
+// (ou várias compartilhando um volume).
+type LocalBlobStore struct {
+	root      string
+	urlPrefix string
+}
+
+func NewLocalBlobStore(root, urlPrefix string) *LocalBlobStore {
+	return &LocalBlobStore{root: root, urlPrefix: urlPrefix}
+}
+
+func (s *LocalBlobStore) path(key string) (string, error) {
+	k, err := cleanBlobKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(k)), nil
+}
+
+func (s *LocalBlobStore) Put(key string, data []byte, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	// escreve em arquivo temporario e renomeia: leitores nunca veem arquivo pela metade
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalBlobStore) Get(key string) ([]byte, string, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", ErrBlobNotFound
+		}
+		return nil, "", err
+	}
+	ct := mime.TypeByExtension(filepath.Ext(p))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return b, ct, nil
+}
+
+func (s *LocalBlobStore) Delete(key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) URL(key string) string { return s.urlPrefix + key }
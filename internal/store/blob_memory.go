@@ -0,0 +1,69 @@
+package store
+
+import "sync"
+
+type memoryBlob struct {
+	data        []byte
+	contentType string
+}
+
+// MemoryBlobStore mantém os arquivos em memória (testes e desenvolvimento).
+type MemoryBlobStore struct {
+	mu        sync.RWMutex
+	blobs     map[string]memoryBlob
+	urlPrefix string
+}
+
+func NewMemoryBlobStore(urlPrefix string) *MemoryBlobStore {
+	return &MemoryBlobStore{blobs: map[string]memoryBlob{}, urlPrefix: urlPrefix}
+}
+
+func (s *MemoryBlobStore) Put(key string, data []byte, contentType string) error {
+	k, err := cleanBlobKey(key)
+	if err != nil {
+		return err
+	}
+	cp := append([]byte(nil), data...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[k] = memoryBlob{data: cp, contentType: contentType}
+	return nil
+}
+
+func (s *MemoryBlobStore) Get(key string) ([]byte, string, error) {
+	k, err := cleanBlobKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.blobs[k]
+	if !ok {
+		return nil, "", ErrBlobNotFound
+	}
+	return append([]byte(nil), b.data...), b.contentType, nil
+}
+
+func (s *MemoryBlobStore) Delete(key string) error {
+	k, err := cleanBlobKey(key)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blobs, k)
+	return nil
+}
+
+func (s *MemoryBlobStore) URL(key string) string { return s.urlPrefix + key }
+
+// Keys lista as chaves gravadas (útil em testes).
+func (s *MemoryBlobStore) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]string, 0, len(s.blobs))
+	for k := range s.blobs {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config aponta para qualquer serviço compatível com S3 (AWS, MinIO, R2...).
+// O endereçamento é path-style: {Endpoint}/{Bucket}/{key}.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL é a base usada por URL(); vazio = {Endpoint}/{Bucket}
+	PublicURL string
+}
+
+// S3BlobStore fala a API REST do S3 assinando com AWS Signature V4, sem SDK.
+type S3BlobStore struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3BlobStore(cfg S3Config) (*S3BlobStore, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3: endpoint, bucket e credenciais são obrigatórios")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &S3BlobStore{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+func (s *S3BlobStore) objectURL(key string) string {
+	return s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + s3EscapePath(key)
+}
+
+func (s *S3BlobStore) do(method, key string, body []byte, contentType string) (*http.Response, error) {
+	k, err := cleanBlobKey(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, s.objectURL(k), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	signS3Request(req, body, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, s.now().UTC())
+	return s.client.Do(req)
+}
+
+func (s *S3BlobStore) Put(key string, data []byte, contentType string) error {
+	res, err := s.do(http.MethodPut, key, data, contentType)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return s3Error(res)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Get(key string) ([]byte, string, error) {
+	res, err := s.do(http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, "", ErrBlobNotFound
+	}
+	if res.StatusCode/100 != 2 {
+		return nil, "", s3Error(res)
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, res.Header.Get("Content-Type"), nil
+}
+
+func (s *S3BlobStore) Delete(key string) error {
+	res, err := s.do(http.MethodDelete, key, nil, "")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 && res.StatusCode != http.StatusNotFound {
+		return s3Error(res)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) URL(key string) string {
+	if s.cfg.PublicURL != "" {
+		return s.cfg.PublicURL + "/" + s3EscapePath(key)
+	}
+	return s.objectURL(key)
+}
+
+func s3Error(res *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return fmt.Errorf("S3: status %d: %s", res.StatusCode, strings.TrimSpace(string(b)))
+}
+
+// s3EscapePath codifica cada segmento (RFC 3986) mantendo as barras.
+func s3EscapePath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(url.PathEscape(p), "+", "%2B")
+	}
+	return strings.Join(parts, "/")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+func sha256Hex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// signS3Request adiciona os headers x-amz-* e Authorization (SigV4, serviço "s3").
+func signS3Request(req *http.Request, body []byte, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	scope := day + "/" + region + "/s3/aws4_request"
+	signed, canonical := s3CanonicalRequest(req, payloadHash)
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+sig)
+}
+
+func s3CanonicalRequest(req *http.Request, payloadHash string) (signedHeaders, canonical string) {
+	headers := map[string]string{"host": req.Host}
+	if headers["host"] == "" {
+		headers["host"] = req.URL.Host
+	}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-amz-") || lk == "content-type" {
+			headers[lk] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var ch strings.Builder
+	for _, k := range names {
+		ch.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders = strings.Join(names, ";")
+
+	uri := req.URL.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
+	canonical = strings.Join([]string{
+		req.Method,
+		uri,
+		req.URL.Query().Encode(),
+		ch.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	return signedHeaders, canonical
+}
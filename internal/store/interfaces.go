@@ -10,6 +10,9 @@ type Store interface {
 	GetUser(id int) (domain.User, bool)
 	ListUsers(order string) ([]domain.User, error)
 	CreateUser(name string, isSeller bool) (domain.User, error)
+	// SearchUsers busca pelo nome: cada termo (normalizado) precisa ser prefixo de
+	// alguma palavra. Quem começa pelo primeiro termo vem antes; depois ordem alfabética.
+	SearchUsers(terms []string, sellersOnly bool, limit, offset int) ([]domain.UserSummary, int, error)
 
 	// auth/accounts
 	GetAccount(id int) (domain.Account, bool)
@@ -22,12 +25,221 @@ type Store interface {
 	Unfollow(userID, sellerID int) error
 	FollowersOf(sellerID int) ([]domain.User, error)
 	FollowedBy(userID int) ([]domain.User, error)
+	// FollowerCounts conta seguidores de vários vendedores numa consulta (ausente = 0).
+	FollowerCounts(sellerIDs []int) (map[int]int, error)
 
 	// posts
 	AddPost(p domain.Post) (int, error)
 	// DeletePost remove uma publicacao do usuario. Se o post nao existir ou nao pertencer ao usuario, retorna erro.
 	DeletePost(userID, postID int) error
 	PostsFromSellersSince(sellerIDs []int, since time.Time) []domain.Post
+	// SetPostPromo troca a promoção de um post (Discount 0 tira); ErrPostNotFound se não existir.
+	// Os posts lidos do store já vêm com HasPromo e FinalPrice avaliados no momento da leitura.
+	SetPostPromo(postID int, promo domain.PromoSchedule) (domain.Post, error)
+	// PromoPostsBySeller lista as promoções do vendedor que valem agora.
 	PromoPostsBySeller(sellerID int) []domain.Post
 	PostsByUser(userID int) []domain.Post
+	GetPost(id int) (domain.Post, bool)
+	// SearchPosts busca posts que contenham todos os termos (normalizados, casando por prefixo)
+	// ordenados por relevância. Devolve a página pedida e o total de resultados.
+	SearchPosts(terms []string, limit, offset int) ([]domain.Post, int, error)
+	// QueryPosts aplica o filtro no próprio store (sem ordenação garantida).
+	QueryPosts(q domain.PostQuery) ([]domain.Post, error)
+	// PostFacets conta, sobre os posts de q, marcas, categorias e faixas de preço.
+	PostFacets(q domain.PostQuery) (domain.PostFacets, error)
+
+	// likes e favoritos (os dois são idempotentes; ErrPostNotFound se o post não existir).
+	// LikePost devolve true quando o like é novo.
+	LikePost(userID, postID int) (bool, error)
+	UnlikePost(userID, postID int) error
+	FavoritePost(userID, postID int) error
+	UnfavoritePost(userID, postID int) error
+	// PostCounts conta likes e favoritos de cada post (ausente = zeros).
+	PostCounts(postIDs []int) (map[int]domain.PostCounts, error)
+	// StartedPromos lista os posts com promoção agendada cuja janela já abriu em at e
+	// que ainda não foram anunciados (em ordem de id).
+	StartedPromos(at time.Time) ([]domain.Post, error)
+	// MarkPromoAnnounced tira a marca de agendada do post; devolve false se outro
+	// verificador (ou uma nova configuração da promoção) chegou antes.
+	MarkPromoAnnounced(postID int) (bool, error)
+	// Favorites lista os favoritos do usuário, do mais recente para o mais antigo, e o total.
+	Favorites(userID int, limit, offset int) ([]domain.Post, int, error)
+
+	// comentários (perguntas e respostas de um nível)
+	// AddComment grava o comentário (ID e CreatedAt são preenchidos pelo store);
+	// ErrPostNotFound se o post não existir.
+	AddComment(c domain.Comment) (domain.Comment, error)
+	GetComment(id int) (domain.Comment, bool)
+	UpdateComment(id int, text string, editedAt time.Time) (domain.Comment, error)
+	// DeleteComment apaga o comentário e, se for pergunta, as respostas dela.
+	DeleteComment(id int) error
+	// SetCommentAnswer marca answerID como a resposta da pergunta questionID (0 = desmarca).
+	SetCommentAnswer(questionID, answerID int) error
+	// Comments pagina as perguntas do post (mais recentes primeiro), cada uma com as
+	// respostas em ordem cronológica, e devolve o total de perguntas.
+	Comments(postID int, limit, offset int) ([]domain.Comment, int, error)
+
+	// avaliações de vendedores
+	// UpsertReview grava a avaliação do par (vendedor, comprador) ou, se já existir,
+	// troca nota e texto. created indica se foi a primeira.
+	UpsertReview(r domain.Review) (review domain.Review, created bool, err error)
+	GetReview(id int) (domain.Review, bool)
+	SetReviewReply(id int, reply string, at time.Time) (domain.Review, error)
+	// ReviewsOf pagina as avaliações do vendedor, das alteradas mais recentemente primeiro.
+	ReviewsOf(sellerID int, limit, offset int) ([]domain.Review, int, error)
+	RatingSummary(sellerID int) (domain.RatingSummary, error)
+
+	// carrinho e pedidos
+	CartLines(userID int) ([]domain.CartLine, error)
+	// SetCartQuantity grava a quantidade do post no carrinho (0 remove);
+	// ErrPostNotFound se o post não existir.
+	SetCartQuantity(userID, postID, quantity int) error
+	// PlaceOrders grava os pedidos do checkout e esvazia o carrinho do comprador (tudo ou nada).
+	// Se o carrinho não tiver mais exatamente as linhas dos pedidos (outro checkout
+	// chegou antes, ou algo foi adicionado no meio), nada é gravado e volta
+	// domain.ErrCartEmpty ou domain.ErrCartChanged.
+	PlaceOrders(buyerID int, orders []domain.Order) ([]domain.Order, error)
+	GetOrder(id int) (domain.Order, bool)
+	// UpdateOrderStatus troca o status só se ele ainda for from; se outro pedido de
+	// mudança chegou antes, devolve domain.ErrOrderTransition.
+	UpdateOrderStatus(id int, from, to string, at time.Time) (domain.Order, error)
+	// Orders pagina os pedidos do filtro, mais recentes primeiro.
+	Orders(f domain.OrderFilter, limit, offset int) ([]domain.Order, int, error)
+	// SetOrderPayment guarda o pagamento do pedido e o status dele no provedor.
+	SetOrderPayment(orderID int, paymentID, status string) (domain.Order, error)
+	OrderByPayment(paymentID string) (domain.Order, bool)
+	// callbacks do provedor (para ignorar reentregas). ClaimPaymentEvent reserva o
+	// evento e devolve false se ele já foi reservado; ReleasePaymentEvent desfaz a
+	// reserva quando o processamento falha, para a reentrega tentar de novo.
+	ClaimPaymentEvent(eventID string) (bool, error)
+	ReleasePaymentEvent(eventID string) error
+
+	// cupons de vendedores
+	// CreateCoupon devolve domain.ErrCouponCodeTaken se o código já existir.
+	CreateCoupon(c domain.Coupon) (domain.Coupon, error)
+	GetCoupon(id int) (domain.Coupon, bool)
+	CouponByCode(code string) (domain.Coupon, bool)
+	CouponsBySeller(sellerID int) ([]domain.Coupon, error)
+	DeleteCoupon(id int) error
+	// CouponUsesBy conta quantas vezes o usuário já usou o cupom.
+	CouponUsesBy(couponID, userID int) (int, error)
+	// RedeemCoupon consome um uso do cupom pelo usuário, conferindo os limites
+	// total e por usuário de forma atômica (domain.ErrCouponExhausted /
+	// domain.ErrCouponUserLimit). ReleaseCoupon devolve o uso (checkout que falhou).
+	RedeemCoupon(couponID, userID int) error
+	ReleaseCoupon(couponID, userID int) error
+
+	// histórico de preços e alertas de queda
+	// AddPriceChange registra a configuração de preço do post a partir de c.ChangedAt.
+	AddPriceChange(c domain.PriceChange) error
+	// PriceChanges lista as mudanças do post, da mais antiga para a mais recente.
+	PriceChanges(postID int) ([]domain.PriceChange, error)
+	// UpsertPriceAlert cria ou troca o alerta do par (usuário, post) e o rearma;
+	// ErrPostNotFound se o post não existir.
+	UpsertPriceAlert(a domain.PriceAlert) (domain.PriceAlert, error)
+	DeletePriceAlert(userID, postID int) error
+	// PriceAlertsOf lista os alertas do usuário, dos mais recentes para os mais antigos.
+	PriceAlertsOf(userID int) ([]domain.PriceAlert, error)
+	// PendingPriceAlerts lista os alertas ainda não disparados do post (0 = de todos os posts).
+	PendingPriceAlerts(postID int) ([]domain.PriceAlert, error)
+	// MarkPriceAlertTriggered marca o disparo só se o alerta ainda estava pendente
+	// (false = outro verificador chegou antes).
+	MarkPriceAlertTriggered(id int, at time.Time) (bool, error)
+
+	// cotações (quanto vale 1 unidade de cada moeda na moeda base)
+	// ExchangeRates lista a tabela ordenada pelo código da moeda.
+	ExchangeRates() ([]domain.ExchangeRate, error)
+	// SetExchangeRates cria ou troca as cotações, todas ou nenhuma.
+	SetExchangeRates(rates []domain.ExchangeRate) error
+	DeleteExchangeRate(currency string) error
+
+	// timeline (feed materializado por fan-out na escrita)
+	// AppendTimeline coloca o post na timeline de cada usuário (repetidos são ignorados).
+	AppendTimeline(userIDs []int, p domain.Post) error
+	// TimelinePosts lê a timeline do usuário desde since, já filtrada (sem ordenação garantida).
+	TimelinePosts(userID int, since time.Time, f domain.PostFilter) ([]domain.Post, error)
+	// BackfillTimeline copia para a timeline os posts do vendedor desde since (novo follow).
+	BackfillTimeline(userID, sellerID int, since time.Time) error
+	// PruneTimeline tira da timeline do usuário os posts do vendedor (unfollow).
+	PruneTimeline(userID, sellerID int) error
+	// ExpireTimeline apaga entradas de posts anteriores a before e devolve quantas foram.
+	ExpireTimeline(before time.Time) (int, error)
+	// PopularSellers lista os vendedores com pelo menos minFollowers seguidores.
+	PopularSellers(minFollowers int) ([]int, error)
+	// PulledSellers lista os vendedores marcados como fora do fan-out (lidos na hora).
+	PulledSellers() ([]int, error)
+	// SetSellerPulled marca (ou desmarca) o vendedor como fora do fan-out.
+	SetSellerPulled(sellerID int, pulled bool) error
+
+	// notifications
+	// AddNotifications grava as notificações e devolve as linhas gravadas, com ID e
+	// CreatedAt preenchidos pelo store.
+	AddNotifications(ns []domain.Notification) ([]domain.Notification, error)
+	// Notifications lista a caixa do usuário, mais recentes primeiro, e o total.
+	Notifications(userID int, unreadOnly bool, limit, offset int) ([]domain.Notification, int, error)
+	UnreadNotifications(userID int) (int, error)
+	// MarkNotificationsRead marca como lidas as notificações do usuário (ids vazio = todas)
+	// e devolve quantas mudaram.
+	MarkNotificationsRead(userID int, ids []int) (int, error)
+	NotificationPrefs(userID int) (domain.NotificationPrefs, error)
+	SetNotificationPrefs(userID int, prefs domain.NotificationPrefs) error
+	// NotificationOptOuts devolve, dentre userIDs, quem desligou o tipo.
+	NotificationOptOuts(typ string, userIDs []int) (map[int]bool, error)
+
+	// digests (resumo semanal por e-mail)
+	// LastDigestAt devolve quando o último resumo do usuário foi processado (zero = nunca).
+	LastDigestAt(userID int) (time.Time, error)
+	SetLastDigestAt(userID int, at time.Time) error
+
+	// webhooks
+	// CreateWebhook grava a assinatura (ID e CreatedAt são preenchidos pelo store).
+	CreateWebhook(w domain.Webhook) (domain.Webhook, error)
+	GetWebhook(id int) (domain.Webhook, bool)
+	WebhooksByUser(userID int) ([]domain.Webhook, error)
+	// DeleteWebhook apaga o webhook do usuário e suas entregas (ErrWebhookNotFound se não for dele).
+	DeleteWebhook(userID, id int) error
+	// AddWebhookDeliveries enfileira entregas (ID, CreatedAt e UpdatedAt são preenchidos pelo store).
+	AddWebhookDeliveries(ds []domain.WebhookDelivery) error
+	// ClaimWebhookDeliveries reserva até limit entregas pendentes com próxima tentativa
+	// até now, adiando-as para leaseUntil para que outro worker não pegue as mesmas.
+	ClaimWebhookDeliveries(now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error)
+	// UpdateWebhookDelivery grava o resultado de uma tentativa (status, tentativas, próximo horário).
+	UpdateWebhookDelivery(d domain.WebhookDelivery) error
+	GetWebhookDelivery(id int) (domain.WebhookDelivery, bool)
+	// WebhookDeliveries lista as entregas do webhook, mais recentes primeiro (status vazio = todas).
+	WebhookDeliveries(webhookID int, status string, limit, offset int) ([]domain.WebhookDelivery, int, error)
+
+	// uploads
+	// RecordUpload grava (ou renova, pelo ID) o upload e adiciona u.OwnerID como dono.
+	RecordUpload(u domain.Upload) error
+	GetUpload(id string) (domain.Upload, bool)
+	GetUploadByHash(hash string) (domain.Upload, bool)
+	// ReuseUpload adiciona ownerID como dono de um upload já gravado e renova a carência.
+	// Falha com ErrUploadNotFound ou ErrUploadCollecting se a coleta já o pegou.
+	ReuseUpload(id string, ownerID int, at time.Time) error
+	// UploadsByOwner lista os uploads de que o usuário é dono (usado nas cotas).
+	UploadsByOwner(userID int) ([]domain.Upload, error)
+	// MarkUnreferencedUploads anota at como UnreferencedSince dos uploads que perderam
+	// (ou nunca tiveram) referências e ainda não tinham marca, e limpa a marca dos que
+	// voltaram a ser usados (menos os que já estão em coleta).
+	MarkUnreferencedUploads(at time.Time) error
+	// UnreferencedUploads lista uploads sem referência (post, avatar ou item de pedido)
+	// marcados antes de olderThan, mais os que ficaram com a coleta pela metade.
+	UnreferencedUploads(olderThan time.Time) ([]domain.Upload, error)
+	// ClaimUnreferencedUpload marca o upload como em coleta (CollectingSince = at) se
+	// continuar sem referências e marcado antes de olderThan; a partir daí ReuseUpload
+	// recusa o upload e os arquivos podem ser apagados.
+	ClaimUnreferencedUpload(id string, olderThan, at time.Time) error
+	// DeleteCollectedUpload remove o registro de um upload em coleta, depois dos arquivos.
+	DeleteCollectedUpload(id string) error
+
+	// pedaços de uploads retomáveis (o conteúdo fica no blob store)
+	// UploadParts lista os pedaços do upload em ordem de Start.
+	UploadParts(uploadID string) ([]domain.UploadPart, error)
+	// AddUploadPart grava o pedaço só se p.Start for exatamente o fim do último;
+	// devolve false se o offset não confere (outro pedaço chegou antes).
+	AddUploadPart(p domain.UploadPart) (bool, error)
+	DeleteUploadParts(uploadID string) error
+	// StaleUploadParts lista os uploads cujo último pedaço chegou antes de before.
+	StaleUploadParts(before time.Time) ([]string, error)
 }
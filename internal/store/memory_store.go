@@ -11,17 +11,29 @@ import (
 )
 
 var (
-	ErrUserNotFound    = errors.New("Usuário inexistente.")
-	ErrPostNotFound    = errors.New("Publicação inexistente.")
-	ErrPostForbidden   = errors.New("Você não pode apagar uma publicação que não é sua.")
-	ErrEmailTaken      = errors.New("E-mail já cadastrado.")
-	ErrAccountNotFound = errors.New("Conta inexistente.")
+	ErrUserNotFound         = errors.New("Usuário inexistente.")
+	ErrPostNotFound         = errors.New("Publicação inexistente.")
+	ErrPostForbidden        = errors.New("Você não pode apagar uma publicação que não é sua.")
+	ErrEmailTaken           = errors.New("E-mail já cadastrado.")
+	ErrAccountNotFound      = errors.New("Conta inexistente.")
+	ErrUploadNotFound       = errors.New("Upload inexistente.")
+	ErrUploadInUse          = errors.New("Upload ainda referenciado.")
+	ErrUploadCollecting     = errors.New("Upload sendo removido.")
+	ErrWebhookNotFound      = errors.New("Webhook inexistente.")
+	ErrDeliveryNotFound     = errors.New("Entrega inexistente.")
+	ErrCommentNotFound      = errors.New("Comentário inexistente.")
+	ErrReviewNotFound       = errors.New("Avaliação inexistente.")
+	ErrOrderNotFound        = errors.New("Pedido inexistente.")
+	ErrCouponNotFound       = errors.New("Cupom inexistente.")
+	ErrPriceAlertNotFound   = errors.New("Alerta de preço inexistente.")
+	ErrExchangeRateNotFound = errors.New("Cotação inexistente.")
 )
 
 type MemoryStore struct {
 	mu sync.RWMutex
 
 	users map[int]domain.User
+	names nameIndex
 	// contas com credenciais
 	accounts       map[int]domain.Account
 	accountByEmail map[string]int
@@ -34,18 +46,108 @@ type MemoryStore struct {
 
 	posts      []domain.Post
 	nextPostID int
+	// promoPending: posts com promoção agendada ainda não anunciada
+	promoPending map[int]struct{}
+	search       *searchIndex
+	// timelines: userId -> últimos posts de quem ele segue (fan-out na escrita)
+	timelines map[int]*timelineRing
+	// pulled: vendedores fora do fan-out
+	pulled map[int]struct{}
+
+	notifications      []domain.Notification
+	nextNotificationID int
+	notificationPrefs  map[int]domain.NotificationPrefs
+	// digests: userId -> último resumo semanal processado
+	digests map[int]time.Time
+
+	// likes: postId -> set(userId)
+	likes map[int]map[int]struct{}
+	// favorites: userId -> postId -> quando favoritou; favoritedBy é o índice inverso
+	favorites   map[int]map[int]time.Time
+	favoritedBy map[int]map[int]struct{}
+
+	comments      map[int]domain.Comment
+	nextCommentID int
+
+	reviews      map[int]domain.Review
+	reviewByPair map[[2]int]int // (vendedor, comprador) -> review
+	nextReviewID int
+
+	carts       map[int]map[int]domain.CartLine // comprador -> post -> linha
+	orders      map[int]domain.Order
+	nextOrderID int
+	payEvents   map[string]struct{}
+
+	coupons      map[int]domain.Coupon
+	couponByCode map[string]int
+	couponUses   map[[2]int]int // (cupom, usuário) -> usos
+	nextCouponID int
+
+	priceChanges     map[int][]domain.PriceChange // post -> mudanças em ordem
+	priceAlerts      map[int]domain.PriceAlert
+	priceAlertByPair map[[2]int]int // (usuário, post) -> alerta
+	exchangeRates    map[string]domain.ExchangeRate
+	nextPriceAlertID int
+
+	webhooks       map[int]domain.Webhook
+	nextWebhookID  int
+	deliveries     map[int]domain.WebhookDelivery
+	nextDeliveryID int
+
+	uploads map[string]domain.Upload
+	// pedaços de uploads retomáveis, por upload, em ordem de Start
+	uploadParts map[string][]domain.UploadPart
+	// uploadOwners: uploadId -> userId -> data em que passou a ser dono
+	uploadOwners map[string]map[int]time.Time
+	uploadByHash map[string]string
 }
 
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		users:          map[int]domain.User{},
-		accounts:       map[int]domain.Account{},
-		accountByEmail: map[string]int{},
-		nextUserID:     1,
-		followers:      map[int]map[int]struct{}{},
-		followed:       map[int]map[int]struct{}{},
-		posts:          []domain.Post{},
-		nextPostID:     1,
+		users:              map[int]domain.User{},
+		accounts:           map[int]domain.Account{},
+		accountByEmail:     map[string]int{},
+		nextUserID:         1,
+		followers:          map[int]map[int]struct{}{},
+		followed:           map[int]map[int]struct{}{},
+		posts:              []domain.Post{},
+		nextPostID:         1,
+		promoPending:       map[int]struct{}{},
+		search:             newSearchIndex(),
+		timelines:          map[int]*timelineRing{},
+		pulled:             map[int]struct{}{},
+		nextNotificationID: 1,
+		notificationPrefs:  map[int]domain.NotificationPrefs{},
+		digests:            map[int]time.Time{},
+		likes:              map[int]map[int]struct{}{},
+		favorites:          map[int]map[int]time.Time{},
+		favoritedBy:        map[int]map[int]struct{}{},
+		comments:           map[int]domain.Comment{},
+		nextCommentID:      1,
+		reviews:            map[int]domain.Review{},
+		reviewByPair:       map[[2]int]int{},
+		nextReviewID:       1,
+		carts:              map[int]map[int]domain.CartLine{},
+		orders:             map[int]domain.Order{},
+		nextOrderID:        1,
+		payEvents:          map[string]struct{}{},
+		coupons:            map[int]domain.Coupon{},
+		couponByCode:       map[string]int{},
+		couponUses:         map[[2]int]int{},
+		nextCouponID:       1,
+		priceChanges:       map[int][]domain.PriceChange{},
+		priceAlerts:        map[int]domain.PriceAlert{},
+		priceAlertByPair:   map[[2]int]int{},
+		exchangeRates:      map[string]domain.ExchangeRate{},
+		nextPriceAlertID:   1,
+		webhooks:           map[int]domain.Webhook{},
+		nextWebhookID:      1,
+		deliveries:         map[int]domain.WebhookDelivery{},
+		nextDeliveryID:     1,
+		uploads:            map[string]domain.Upload{},
+		uploadParts:        map[string][]domain.UploadPart{},
+		uploadOwners:       map[string]map[int]time.Time{},
+		uploadByHash:       map[string]string{},
 	}
 }
 
@@ -53,7 +155,7 @@ func (s *MemoryStore) SeedUsers(users []domain.User) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	for _, u := range users {
-		s.users[u.ID] = u
+		s.putUser(u)
 		if u.ID >= s.nextUserID {
 			s.nextUserID = u.ID + 1
 		}
@@ -92,7 +194,7 @@ func (s *MemoryStore) CreateUser(name string, isSeller bool) (domain.User, error
 	id := s.nextUserID
 	s.nextUserID++
 	u := domain.User{ID: id, Name: name, IsSeller: isSeller}
-	s.users[id] = u
+	s.putUser(u)
 	return u, nil
 }
 
@@ -137,7 +239,7 @@ func (s *MemoryStore) CreateAccount(name, email, passwordHash string, isSeller b
 	s.accountByEmail[normEmail] = id
 
 	// também cria o User “social” para follow/list
-	s.users[id] = domain.User{ID: id, Name: name, IsSeller: isSeller}
+	s.putUser(domain.User{ID: id, Name: name, IsSeller: isSeller})
 	return acc, nil
 }
 
@@ -214,6 +316,19 @@ func (s *MemoryStore) FollowersOf(sellerID int) ([]domain.User, error) {
 	return out, nil
 }
 
+func (s *MemoryStore) FollowerCounts(sellerIDs []int) (map[int]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[int]int, len(sellerIDs))
+	for _, id := range sellerIDs {
+		if n := len(s.followers[id]); n > 0 {
+			out[id] = n
+		}
+	}
+	return out, nil
+}
+
 func (s *MemoryStore) FollowedBy(userID int) ([]domain.User, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -242,7 +357,12 @@ func (s *MemoryStore) AddPost(p domain.Post) (int, error) {
 
 	p.PostID = s.nextPostID
 	s.nextPostID++
+	p.Currency = postCurrency(p)
 	s.posts = append(s.posts, p)
+	if p.PromoScheduledAt(time.Now()) {
+		s.promoPending[p.PostID] = struct{}{}
+	}
+	s.search.add(p)
 	return p.PostID, nil
 }
 
@@ -256,9 +376,10 @@ func (s *MemoryStore) PostsFromSellersSince(sellerIDs []int, since time.Time) []
 	}
 
 	out := []domain.Post{}
+	now := time.Now()
 	for _, p := range s.posts {
 		if _, ok := set[p.UserID]; ok && (p.Date.Equal(since) || p.Date.After(since)) {
-			out = append(out, p)
+			out = append(out, p.AtTime(now))
 		}
 	}
 	return out
@@ -268,9 +389,10 @@ func (s *MemoryStore) PromoPostsBySeller(sellerID int) []domain.Post {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	out := []domain.Post{}
+	now := time.Now()
 	for _, p := range s.posts {
-		if p.UserID == sellerID && p.HasPromo {
-			out = append(out, p)
+		if p.UserID == sellerID && p.PromoActiveAt(now) {
+			out = append(out, p.AtTime(now))
 		}
 	}
 	return out
@@ -280,14 +402,60 @@ func (s *MemoryStore) PostsByUser(userID int) []domain.Post {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	out := []domain.Post{}
+	now := time.Now()
 	for _, p := range s.posts {
 		if p.UserID == userID {
-			out = append(out, p)
+			out = append(out, p.AtTime(now))
 		}
 	}
 	return out
 }
 
+func (s *MemoryStore) SetPostPromo(postID int, promo domain.PromoSchedule) (domain.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.postIndex(postID)
+	if !ok {
+		return domain.Post{}, ErrPostNotFound
+	}
+	p := &s.posts[i]
+	p.HasPromo = promo.Discount > 0
+	p.Discount = promo.Discount
+	p.PromoStartsAt, p.PromoEndsAt = promo.StartsAt, promo.EndsAt
+	if !p.HasPromo {
+		p.PromoStartsAt, p.PromoEndsAt = nil, nil
+	}
+	now := time.Now()
+	delete(s.promoPending, postID)
+	if p.PromoScheduledAt(now) {
+		s.promoPending[postID] = struct{}{}
+	}
+	return p.AtTime(now), nil
+}
+
+func (s *MemoryStore) StartedPromos(at time.Time) ([]domain.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.Post{}
+	for id := range s.promoPending {
+		if i, ok := s.postIndex(id); ok && !s.posts[i].PromoScheduledAt(at) {
+			out = append(out, s.posts[i].AtTime(at))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PostID < out[j].PostID })
+	return out, nil
+}
+
+func (s *MemoryStore) MarkPromoAnnounced(postID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.promoPending[postID]; !ok {
+		return false, nil
+	}
+	delete(s.promoPending, postID)
+	return true, nil
+}
+
 func (s *MemoryStore) DeletePost(userID, postID int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -310,6 +478,15 @@ func (s *MemoryStore) DeletePost(userID, postID int) error {
 	if idx < 0 {
 		return ErrPostNotFound
 	}
+	s.search.remove(s.posts[idx])
+	s.dropPostNotifications(postID)
+	s.dropPostEngagement(postID)
+	s.dropPostComments(postID)
+	s.dropPostPrices(postID)
+	delete(s.promoPending, postID)
+	for _, cart := range s.carts {
+		delete(cart, postID)
+	}
 	// remove mantendo ordem
 	s.posts = append(s.posts[:idx], s.posts[idx+1:]...)
 	return nil
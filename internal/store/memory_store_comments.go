@@ -0,0 +1,143 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) GetPost(id int) (domain.Post, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.postByID(id)
+}
+
+func (s *MemoryStore) AddComment(c domain.Comment) (domain.Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postByID(c.PostID); !ok {
+		return domain.Comment{}, ErrPostNotFound
+	}
+	if c.ParentID != 0 {
+		if _, ok := s.comments[c.ParentID]; !ok {
+			return domain.Comment{}, ErrCommentNotFound
+		}
+	}
+	c.ID = s.nextCommentID
+	s.nextCommentID++
+	c.CreatedAt = time.Now().UTC()
+	c.Replies = nil
+	s.comments[c.ID] = c
+	return s.presentComment(c), nil
+}
+
+// presentComment preenche os campos derivados. Chamado com s.mu travado.
+func (s *MemoryStore) presentComment(c domain.Comment) domain.Comment {
+	c.UserName = s.users[c.UserID].Name
+	if p, ok := s.postByID(c.PostID); ok {
+		c.BySeller = c.UserID == p.UserID
+	}
+	return c
+}
+
+func (s *MemoryStore) GetComment(id int) (domain.Comment, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.comments[id]
+	if !ok {
+		return domain.Comment{}, false
+	}
+	return s.presentComment(c), true
+}
+
+func (s *MemoryStore) UpdateComment(id int, text string, editedAt time.Time) (domain.Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.comments[id]
+	if !ok {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	c.Text, c.EditedAt = text, &editedAt
+	s.comments[id] = c
+	return s.presentComment(c), nil
+}
+
+func (s *MemoryStore) DeleteComment(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.comments[id]; !ok {
+		return ErrCommentNotFound
+	}
+	delete(s.comments, id)
+	for cid, c := range s.comments {
+		if c.ParentID == id {
+			delete(s.comments, cid)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) SetCommentAnswer(questionID, answerID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.comments[questionID]; !ok {
+		return ErrCommentNotFound
+	}
+	if answerID != 0 {
+		if a, ok := s.comments[answerID]; !ok || a.ParentID != questionID {
+			return ErrCommentNotFound
+		}
+	}
+	for cid, c := range s.comments {
+		if c.ParentID == questionID && c.IsAnswer != (cid == answerID) {
+			c.IsAnswer = cid == answerID
+			s.comments[cid] = c
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Comments(postID int, limit, offset int) ([]domain.Comment, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.postByID(postID); !ok {
+		return nil, 0, ErrPostNotFound
+	}
+	var questions []domain.Comment
+	replies := map[int][]domain.Comment{}
+	for _, c := range s.comments {
+		if c.PostID != postID {
+			continue
+		}
+		if c.ParentID == 0 {
+			questions = append(questions, c)
+		} else {
+			replies[c.ParentID] = append(replies[c.ParentID], s.presentComment(c))
+		}
+	}
+	sort.Slice(questions, func(i, j int) bool { return questions[i].ID > questions[j].ID })
+
+	total := len(questions)
+	out := []domain.Comment{}
+	for i := offset; i < total && len(out) < limit; i++ {
+		q := s.presentComment(questions[i])
+		q.Replies = replies[q.ID]
+		sort.Slice(q.Replies, func(a, b int) bool { return q.Replies[a].ID < q.Replies[b].ID })
+		for _, r := range q.Replies {
+			q.Answered = q.Answered || r.IsAnswer
+		}
+		out = append(out, q)
+	}
+	return out, total, nil
+}
+
+// dropPostComments apaga os comentários do post (como o ON DELETE CASCADE do SQL).
+// Chamado com s.mu travado.
+func (s *MemoryStore) dropPostComments(postID int) {
+	for id, c := range s.comments {
+		if c.PostID == postID {
+			delete(s.comments, id)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_Comments(t *testing.T) {
+	s := newStoreSeeded()
+	p, _ := s.AddPost(domain.Post{UserID: 2})
+
+	if _, err := s.AddComment(domain.Comment{PostID: 99, UserID: 1, Text: "oi"}); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	q1, _ := s.AddComment(domain.Comment{PostID: p, UserID: 1, Text: "Tem 42?"})
+	q2, _ := s.AddComment(domain.Comment{PostID: p, UserID: 3, Text: "Entrega?"})
+	r1, _ := s.AddComment(domain.Comment{PostID: p, UserID: 3, ParentID: q1.ID, Text: "Acho que sim"})
+	r2, err := s.AddComment(domain.Comment{PostID: p, UserID: 2, ParentID: q1.ID, Text: "Tem sim"})
+	if err != nil || r2.UserName != "SellerA" || !r2.BySeller {
+		t.Fatalf("unexpected reply %+v %v", r2, err)
+	}
+
+	if err := s.SetCommentAnswer(q1.ID, r1.ID); err != nil {
+		t.Fatalf("answer: %v", err)
+	}
+	if err := s.SetCommentAnswer(q1.ID, r2.ID); err != nil { // substitui a anterior
+		t.Fatalf("answer: %v", err)
+	}
+	if err := s.SetCommentAnswer(q2.ID, r2.ID); err != ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound for reply of another question, got %v", err)
+	}
+
+	list, total, err := s.Comments(p, 10, 0)
+	if err != nil || total != 2 || list[0].ID != q2.ID || list[1].ID != q1.ID {
+		t.Fatalf("expected newest question first: %+v %d %v", list, total, err)
+	}
+	q := list[1]
+	if !q.Answered || len(q.Replies) != 2 || q.Replies[0].ID != r1.ID || q.Replies[0].IsAnswer || !q.Replies[1].IsAnswer {
+		t.Fatalf("unexpected thread %+v", q)
+	}
+	if page, total, _ := s.Comments(p, 1, 1); total != 2 || len(page) != 1 || page[0].ID != q1.ID {
+		t.Fatalf("unexpected page %+v", page)
+	}
+
+	at := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if c, err := s.UpdateComment(q2.ID, "Entrega em SP?", at); err != nil || c.Text != "Entrega em SP?" || !c.EditedAt.Equal(at) {
+		t.Fatalf("update: %+v %v", c, err)
+	}
+
+	// apagar a pergunta leva as respostas
+	if err := s.DeleteComment(q1.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := s.GetComment(r1.ID); ok {
+		t.Fatalf("reply survived its question")
+	}
+	if err := s.DeletePost(2, p); err != nil {
+		t.Fatalf("delete post: %v", err)
+	}
+	if _, ok := s.GetComment(q2.ID); ok {
+		t.Fatalf("comment survived its post")
+	}
+}
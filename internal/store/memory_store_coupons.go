@@ -0,0 +1,115 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) CreateCoupon(c domain.Coupon) (domain.Coupon, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.couponByCode[c.Code]; ok {
+		return domain.Coupon{}, domain.ErrCouponCodeTaken
+	}
+	c.ID = s.nextCouponID
+	s.nextCouponID++
+	c.Uses = 0
+	c.CreatedAt = time.Now().UTC()
+	s.coupons[c.ID] = c
+	s.couponByCode[c.Code] = c.ID
+	return c, nil
+}
+
+func (s *MemoryStore) GetCoupon(id int) (domain.Coupon, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.coupons[id]
+	return c, ok
+}
+
+func (s *MemoryStore) CouponByCode(code string) (domain.Coupon, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.couponByCode[code]
+	if !ok {
+		return domain.Coupon{}, false
+	}
+	return s.coupons[id], true
+}
+
+func (s *MemoryStore) CouponsBySeller(sellerID int) ([]domain.Coupon, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.Coupon{}
+	for _, c := range s.coupons {
+		if c.SellerID == sellerID {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+func (s *MemoryStore) DeleteCoupon(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.coupons[id]
+	if !ok {
+		return ErrCouponNotFound
+	}
+	delete(s.coupons, id)
+	delete(s.couponByCode, c.Code)
+	for k := range s.couponUses {
+		if k[0] == id {
+			delete(s.couponUses, k)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) CouponUsesBy(couponID, userID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.couponUses[[2]int{couponID, userID}], nil
+}
+
+func (s *MemoryStore) RedeemCoupon(couponID, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.coupons[couponID]
+	if !ok {
+		return ErrCouponNotFound
+	}
+	key := [2]int{couponID, userID}
+	if c.MaxUses > 0 && c.Uses >= c.MaxUses {
+		return domain.ErrCouponExhausted
+	}
+	if c.MaxUsesPerUser > 0 && s.couponUses[key] >= c.MaxUsesPerUser {
+		return domain.ErrCouponUserLimit
+	}
+	c.Uses++
+	s.coupons[couponID] = c
+	s.couponUses[key]++
+	return nil
+}
+
+func (s *MemoryStore) ReleaseCoupon(couponID, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.coupons[couponID]
+	if !ok {
+		return ErrCouponNotFound
+	}
+	key := [2]int{couponID, userID}
+	if s.couponUses[key] == 0 {
+		return nil
+	}
+	c.Uses--
+	s.coupons[couponID] = c
+	if s.couponUses[key]--; s.couponUses[key] == 0 {
+		delete(s.couponUses, key)
+	}
+	return nil
+}
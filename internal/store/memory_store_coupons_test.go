@@ -0,0 +1,79 @@
+package store
+
+import (
+	"sync"
+	"testing"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_Coupons(t *testing.T) {
+	s := newStoreSeeded()
+	c, err := s.CreateCoupon(domain.Coupon{SellerID: 2, Code: "PROMO10", Kind: domain.CouponPercent, Percent: 10})
+	if err != nil || c.ID == 0 || c.CreatedAt.IsZero() {
+		t.Fatalf("create: %+v %v", c, err)
+	}
+	if _, err := s.CreateCoupon(domain.Coupon{SellerID: 3, Code: "PROMO10"}); err != domain.ErrCouponCodeTaken {
+		t.Fatalf("expected ErrCouponCodeTaken, got %v", err)
+	}
+	_, _ = s.CreateCoupon(domain.Coupon{SellerID: 2, Code: "FRETE", Kind: domain.CouponFixed, Amount: 5_00})
+	if got, ok := s.CouponByCode("PROMO10"); !ok || got.ID != c.ID {
+		t.Fatalf("by code: %+v %v", got, ok)
+	}
+	if list, _ := s.CouponsBySeller(2); len(list) != 2 || list[0].Code != "FRETE" {
+		t.Fatalf("unexpected list %+v", list)
+	}
+
+	if err := s.RedeemCoupon(c.ID, 1); err != nil {
+		t.Fatalf("redeem: %v", err)
+	}
+	if n, _ := s.CouponUsesBy(c.ID, 1); n != 1 {
+		t.Fatalf("expected 1 use, got %d", n)
+	}
+	_ = s.ReleaseCoupon(c.ID, 1)
+	_ = s.ReleaseCoupon(c.ID, 1) // sem uso para devolver
+	if got, _ := s.GetCoupon(c.ID); got.Uses != 0 {
+		t.Fatalf("expected uses back to 0, got %d", got.Uses)
+	}
+
+	if err := s.DeleteCoupon(c.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := s.CouponByCode("PROMO10"); ok {
+		t.Fatalf("code still resolves after delete")
+	}
+	if err := s.RedeemCoupon(c.ID, 1); err != ErrCouponNotFound {
+		t.Fatalf("expected ErrCouponNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore_RedeemCouponConcurrent(t *testing.T) {
+	s := newStoreSeeded()
+	c, _ := s.CreateCoupon(domain.Coupon{SellerID: 2, Code: "FLASH", Kind: domain.CouponFixed, Amount: 5_00, MaxUses: 5, MaxUsesPerUser: 2})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	redeemed := 0
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(user int) {
+			defer wg.Done()
+			if s.RedeemCoupon(c.ID, user) == nil {
+				mu.Lock()
+				redeemed++
+				mu.Unlock()
+			}
+		}(i%4 + 1)
+	}
+	wg.Wait()
+
+	got, _ := s.GetCoupon(c.ID)
+	if redeemed != 5 || got.Uses != 5 {
+		t.Fatalf("expected exactly 5 redemptions, got %d (uses %d)", redeemed, got.Uses)
+	}
+	for user := 1; user <= 4; user++ {
+		if n, _ := s.CouponUsesBy(c.ID, user); n > 2 {
+			t.Fatalf("user %d went past the per-user limit: %d", user, n)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package store
+
+import "socialmeli/internal/domain"
+
+func (s *MemoryStore) ExchangeRates() ([]domain.ExchangeRate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]domain.ExchangeRate, 0, len(s.exchangeRates))
+	for _, r := range s.exchangeRates {
+		out = append(out, r)
+	}
+	domain.SortExchangeRates(out)
+	return out, nil
+}
+
+func (s *MemoryStore) SetExchangeRates(rates []domain.ExchangeRate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range rates {
+		s.exchangeRates[r.Currency] = r
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteExchangeRate(currency string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.exchangeRates[currency]; !ok {
+		return ErrExchangeRateNotFound
+	}
+	delete(s.exchangeRates, currency)
+	return nil
+}
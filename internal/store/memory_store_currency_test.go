@@ -0,0 +1,38 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_ExchangeRates(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Now().UTC()
+	_ = s.SetExchangeRates([]domain.ExchangeRate{{Currency: "MXN", Rate: 0.3, UpdatedAt: now}, {Currency: "ARS", Rate: 0.0055, UpdatedAt: now}})
+	_ = s.SetExchangeRates([]domain.ExchangeRate{{Currency: "MXN", Rate: 0.29, UpdatedAt: now}})
+
+	rates, _ := s.ExchangeRates()
+	if len(rates) != 2 || rates[0].Currency != "ARS" || rates[1].Rate != 0.29 {
+		t.Fatalf("expected ARS then updated MXN, got %+v", rates)
+	}
+	if err := s.DeleteExchangeRate("ARS"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := s.DeleteExchangeRate("ARS"); err != ErrExchangeRateNotFound {
+		t.Fatalf("expected ErrExchangeRateNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore_AddPost_DefaultCurrency(t *testing.T) {
+	s := newStoreSeeded()
+	id, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00})
+	id2, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00, Currency: "ARS"})
+	if p, _ := s.GetPost(id); p.Currency != domain.BaseCurrency {
+		t.Fatalf("expected BRL, got %q", p.Currency)
+	}
+	if p, _ := s.GetPost(id2); p.Currency != "ARS" {
+		t.Fatalf("expected ARS, got %q", p.Currency)
+	}
+}
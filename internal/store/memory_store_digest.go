@@ -0,0 +1,19 @@
+package store
+
+import "time"
+
+func (s *MemoryStore) LastDigestAt(userID int) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.digests[userID], nil
+}
+
+func (s *MemoryStore) SetLastDigestAt(userID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[userID]; !ok {
+		return ErrUserNotFound
+	}
+	s.digests[userID] = at
+	return nil
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) LikePost(userID, postID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postByID(postID); !ok {
+		return false, ErrPostNotFound
+	}
+	if s.likes[postID] == nil {
+		s.likes[postID] = map[int]struct{}{}
+	}
+	if _, ok := s.likes[postID][userID]; ok {
+		return false, nil
+	}
+	s.likes[postID][userID] = struct{}{}
+	return true, nil
+}
+
+func (s *MemoryStore) UnlikePost(userID, postID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postByID(postID); !ok {
+		return ErrPostNotFound
+	}
+	delete(s.likes[postID], userID)
+	return nil
+}
+
+func (s *MemoryStore) FavoritePost(userID, postID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postByID(postID); !ok {
+		return ErrPostNotFound
+	}
+	if _, ok := s.favorites[userID][postID]; ok {
+		return nil // mantém a data original
+	}
+	if s.favorites[userID] == nil {
+		s.favorites[userID] = map[int]time.Time{}
+	}
+	if s.favoritedBy[postID] == nil {
+		s.favoritedBy[postID] = map[int]struct{}{}
+	}
+	s.favorites[userID][postID] = time.Now()
+	s.favoritedBy[postID][userID] = struct{}{}
+	return nil
+}
+
+func (s *MemoryStore) UnfavoritePost(userID, postID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postByID(postID); !ok {
+		return ErrPostNotFound
+	}
+	delete(s.favorites[userID], postID)
+	delete(s.favoritedBy[postID], userID)
+	return nil
+}
+
+func (s *MemoryStore) PostCounts(postIDs []int) (map[int]domain.PostCounts, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[int]domain.PostCounts, len(postIDs))
+	for _, id := range postIDs {
+		if c := (domain.PostCounts{Likes: len(s.likes[id]), Favorites: len(s.favoritedBy[id])}); c != (domain.PostCounts{}) {
+			out[id] = c
+		}
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) Favorites(userID int, limit, offset int) ([]domain.Post, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	type fav struct {
+		post domain.Post
+		at   time.Time
+	}
+	all := make([]fav, 0, len(s.favorites[userID]))
+	for id, at := range s.favorites[userID] {
+		if p, ok := s.postByID(id); ok {
+			all = append(all, fav{p, at})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].at.Equal(all[j].at) {
+			return all[i].at.After(all[j].at)
+		}
+		return all[i].post.PostID > all[j].post.PostID
+	})
+	total := len(all)
+	out := []domain.Post{}
+	for i := offset; i < total && len(out) < limit; i++ {
+		out = append(out, all[i].post)
+	}
+	return out, total, nil
+}
+
+// dropPostEngagement apaga likes e favoritos do post (como o ON DELETE CASCADE do SQL).
+// Chamado com s.mu travado.
+func (s *MemoryStore) dropPostEngagement(postID int) {
+	delete(s.likes, postID)
+	for uid := range s.favoritedBy[postID] {
+		delete(s.favorites[uid], postID)
+	}
+	delete(s.favoritedBy, postID)
+}
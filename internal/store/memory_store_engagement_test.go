@@ -0,0 +1,57 @@
+package store
+
+import (
+	"testing"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_LikesAndFavorites(t *testing.T) {
+	s := newStoreSeeded()
+	p1, _ := s.AddPost(domain.Post{UserID: 2})
+	p2, _ := s.AddPost(domain.Post{UserID: 3})
+
+	if _, err := s.LikePost(1, 99); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	for i, u := range []int{1, 1, 3} { // repetido não conta duas vezes
+		added, err := s.LikePost(u, p1)
+		if err != nil || added != (i != 1) {
+			t.Fatalf("like %d: %v %v", i, added, err)
+		}
+	}
+	_ = s.FavoritePost(1, p1)
+	_ = s.FavoritePost(1, p2)
+	_ = s.FavoritePost(3, p2)
+
+	counts, _ := s.PostCounts([]int{p1, p2, 99})
+	if counts[p1] != (domain.PostCounts{Likes: 2, Favorites: 1}) || counts[p2] != (domain.PostCounts{Favorites: 2}) {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+	if _, ok := counts[99]; ok {
+		t.Fatalf("posts without engagement must be absent")
+	}
+
+	favs, total, _ := s.Favorites(1, 10, 0)
+	if total != 2 || favs[0].PostID != p2 {
+		t.Fatalf("expected newest favorite first, got %d %+v", total, favs)
+	}
+
+	_ = s.UnlikePost(3, p1)
+	_ = s.UnfavoritePost(3, p2)
+	counts, _ = s.PostCounts([]int{p1, p2})
+	if counts[p1].Likes != 1 || counts[p2].Favorites != 1 {
+		t.Fatalf("unexpected counts after undo %v", counts)
+	}
+
+	// apagar o post leva likes e favoritos junto
+	if err := s.DeletePost(3, p2); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if favs, total, _ := s.Favorites(1, 10, 0); total != 1 || favs[0].PostID != p1 {
+		t.Fatalf("deleted post still in favorites: %d %+v", total, favs)
+	}
+	if counts, _ := s.PostCounts([]int{p2}); len(counts) != 0 {
+		t.Fatalf("deleted post still counted: %v", counts)
+	}
+}
@@ -173,7 +173,7 @@ func TestMemoryStore_PostsByUser_Success(t *testing.T) {
 		DateStr:  "01-01-2026",
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 	})
 	_, _ = s.AddPost(domain.Post{
 		UserID:   1,
@@ -181,7 +181,7 @@ func TestMemoryStore_PostsByUser_Success(t *testing.T) {
 		DateStr:  "02-01-2026",
 		Product:  domain.Product{ProductID: 2, ProductName: "P2", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    20,
+		Price:    20_00,
 	})
 
 	posts := s.PostsByUser(1)
@@ -212,7 +212,7 @@ func TestMemoryStore_DeletePost_Success(t *testing.T) {
 		UserID:   1,
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 	})
 
 	err := s.DeletePost(1, postID)
@@ -249,7 +249,7 @@ func TestMemoryStore_DeletePost_WrongUser(t *testing.T) {
 		UserID:   1,
 		Product:  domain.Product{ProductID: 1, ProductName: "P1", Type: "t", Brand: "b", Color: "c"},
 		Category: 1,
-		Price:    10,
+		Price:    10_00,
 	})
 
 	err := s.DeletePost(2, postID)
@@ -257,3 +257,18 @@ func TestMemoryStore_DeletePost_WrongUser(t *testing.T) {
 		t.Fatalf("expected ErrPostForbidden, got %v", err)
 	}
 }
+
+func TestMemoryStore_FollowerCounts(t *testing.T) {
+	s := newStoreSeeded()
+	_ = s.Follow(1, 2)
+	_ = s.Follow(3, 2)
+	_ = s.Follow(1, 3)
+
+	counts, err := s.FollowerCounts([]int{2, 3, 99})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if counts[2] != 2 || counts[3] != 1 || counts[99] != 0 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) QueryPosts(q domain.PostQuery) ([]domain.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sellers := map[int]struct{}{}
+	for _, id := range q.SellerIDs {
+		sellers[id] = struct{}{}
+	}
+
+	out := []domain.Post{}
+	now := time.Now()
+	for _, p := range s.posts {
+		if _, ok := sellers[p.UserID]; !ok {
+			continue
+		}
+		p = p.AtTime(now)
+		if !q.Since.IsZero() && p.Date.Before(q.Since) {
+			continue
+		}
+		if q.Filter.Match(p) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) PostFacets(q domain.PostQuery) (domain.PostFacets, error) {
+	posts, err := s.QueryPosts(q)
+	if err != nil {
+		return domain.PostFacets{}, err
+	}
+	return domain.ComputeFacets(posts), nil
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_QueryPostsAndFacets(t *testing.T) {
+	s := newStoreSeeded()
+	now := time.Now()
+
+	add := func(user, cat int, brand string, price domain.Money, promo bool, date time.Time) {
+		t.Helper()
+		p := searchPost(user, "Produto", brand, "", date)
+		p.Category, p.Price, p.FinalPrice, p.HasPromo = cat, price, price, promo
+		if _, err := s.AddPost(p); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	add(2, 1, "Nike", 40_00, false, now)
+	add(2, 2, "nike", 120_00, true, now)
+	add(2, 1, "Adidas", 80_00, true, now.AddDate(0, 0, -30))
+	add(3, 1, "Nike", 10_00, true, now)
+
+	q := domain.PostQuery{SellerIDs: []int{2}, Since: now.AddDate(0, 0, -14)}
+	posts, err := s.QueryPosts(q)
+	if err != nil || len(posts) != 2 {
+		t.Fatalf("expected 2 recent posts of seller 2, got %d (%v)", len(posts), err)
+	}
+
+	q.Filter = domain.PostFilter{Brands: []string{"NIKE"}, PromoOnly: true}
+	posts, _ = s.QueryPosts(q)
+	if len(posts) != 1 || posts[0].Price != 120_00 {
+		t.Fatalf("unexpected filtered posts: %+v", posts)
+	}
+
+	facets, err := s.PostFacets(domain.PostQuery{SellerIDs: []int{2, 3}})
+	if err != nil {
+		t.Fatalf("facets: %v", err)
+	}
+	if facets.Categories[0].Category != 1 || facets.Categories[0].Count != 3 {
+		t.Fatalf("unexpected categories: %+v", facets.Categories)
+	}
+	if facets.Prices[0].Count != 2 || facets.Prices[1].Count != 1 || facets.Prices[2].Count != 1 {
+		t.Fatalf("unexpected prices: %+v", facets.Prices)
+	}
+}
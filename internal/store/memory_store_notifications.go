@@ -0,0 +1,125 @@
+package store
+
+import (
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) AddNotifications(ns []domain.Notification) ([]domain.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	out := make([]domain.Notification, len(ns))
+	for i, n := range ns {
+		n.ID = s.nextNotificationID
+		s.nextNotificationID++
+		if n.CreatedAt.IsZero() {
+			n.CreatedAt = now
+		}
+		s.notifications = append(s.notifications, n)
+		out[i] = n
+	}
+	return out, nil
+}
+
+// Notifications percorre de trás para frente: a lista está em ordem de inserção.
+func (s *MemoryStore) Notifications(userID int, unreadOnly bool, limit, offset int) ([]domain.Notification, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.Notification{}
+	total := 0
+	for i := len(s.notifications) - 1; i >= 0; i-- {
+		n := s.notifications[i]
+		if n.UserID != userID || (unreadOnly && n.Read) {
+			continue
+		}
+		if total >= offset && len(out) < limit {
+			out = append(out, n)
+		}
+		total++
+	}
+	return out, total, nil
+}
+
+func (s *MemoryStore) UnreadNotifications(userID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, nt := range s.notifications {
+		if nt.UserID == userID && !nt.Read {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *MemoryStore) MarkNotificationsRead(userID int, ids []int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	want := map[int]bool{}
+	for _, id := range ids {
+		want[id] = true
+	}
+	n := 0
+	for i := range s.notifications {
+		nt := &s.notifications[i]
+		if nt.UserID != userID || nt.Read || (len(ids) > 0 && !want[nt.ID]) {
+			continue
+		}
+		nt.Read = true
+		n++
+	}
+	return n, nil
+}
+
+func (s *MemoryStore) NotificationPrefs(userID int) (domain.NotificationPrefs, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := domain.NotificationPrefs{}
+	for t, v := range s.notificationPrefs[userID] {
+		out[t] = v
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) SetNotificationPrefs(userID int, prefs domain.NotificationPrefs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[userID]; !ok {
+		return ErrUserNotFound
+	}
+	cur := s.notificationPrefs[userID]
+	if cur == nil {
+		cur = domain.NotificationPrefs{}
+		s.notificationPrefs[userID] = cur
+	}
+	for t, v := range prefs {
+		cur[t] = v
+	}
+	return nil
+}
+
+func (s *MemoryStore) NotificationOptOuts(typ string, userIDs []int) (map[int]bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := map[int]bool{}
+	for _, id := range userIDs {
+		if enabled, ok := s.notificationPrefs[id][typ]; ok && !enabled {
+			out[id] = true
+		}
+	}
+	return out, nil
+}
+
+// dropPostNotifications apaga as notificações do post (como o ON DELETE CASCADE do SQL).
+// Chamado com s.mu travado.
+func (s *MemoryStore) dropPostNotifications(postID int) {
+	kept := s.notifications[:0]
+	for _, n := range s.notifications {
+		if n.PostID != postID {
+			kept = append(kept, n)
+		}
+	}
+	s.notifications = kept
+}
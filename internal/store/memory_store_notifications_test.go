@@ -0,0 +1,104 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_Notifications(t *testing.T) {
+	s := newStoreSeeded()
+	added, err := s.AddNotifications([]domain.Notification{
+		{UserID: 1, Type: domain.NotificationNewFollower, ActorID: 2},
+		{UserID: 1, Type: domain.NotificationNewFollower, ActorID: 3},
+		{UserID: 2, Type: domain.NotificationNewFollower, ActorID: 1},
+	})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if len(added) != 3 || added[0].ID == 0 || added[1].ID != added[0].ID+1 || added[2].CreatedAt.IsZero() {
+		t.Fatalf("expected stored rows back, got %+v", added)
+	}
+
+	items, total, _ := s.Notifications(1, false, 1, 0)
+	if total != 2 || len(items) != 1 || items[0].ActorID != 3 || items[0].CreatedAt.IsZero() {
+		t.Fatalf("expected newest first, got total=%d %+v", total, items)
+	}
+	if items, _, _ = s.Notifications(1, false, 10, 1); len(items) != 1 || items[0].ActorID != 2 {
+		t.Fatalf("expected second page with actor 2, got %+v", items)
+	}
+
+	if n, _ := s.MarkNotificationsRead(1, []int{items[0].ID, 3}); n != 1 {
+		t.Fatalf("expected 1 marked (id 3 is another user's), got %d", n)
+	}
+	if n, _ := s.UnreadNotifications(1); n != 1 {
+		t.Fatalf("expected 1 unread, got %d", n)
+	}
+	if _, total, _ := s.Notifications(1, true, 10, 0); total != 1 {
+		t.Fatalf("expected 1 unread in list, got %d", total)
+	}
+	if n, _ := s.MarkNotificationsRead(1, nil); n != 1 {
+		t.Fatalf("expected all remaining marked, got %d", n)
+	}
+	if n, _ := s.UnreadNotifications(2); n != 1 {
+		t.Fatalf("other user untouched, got %d unread", n)
+	}
+}
+
+func TestMemoryStore_NotificationPrefs(t *testing.T) {
+	s := newStoreSeeded()
+	if err := s.SetNotificationPrefs(99, domain.NotificationPrefs{"x": false}); err != ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := s.SetNotificationPrefs(1, domain.NotificationPrefs{domain.NotificationPromoStarted: false}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := s.SetNotificationPrefs(1, domain.NotificationPrefs{domain.NotificationNewFollower: true}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	prefs, _ := s.NotificationPrefs(1)
+	if len(prefs) != 2 || prefs[domain.NotificationPromoStarted] {
+		t.Fatalf("expected merged prefs, got %v", prefs)
+	}
+
+	out, _ := s.NotificationOptOuts(domain.NotificationPromoStarted, []int{1, 2})
+	if !out[1] || out[2] {
+		t.Fatalf("expected only user 1 opted out, got %v", out)
+	}
+}
+
+func TestMemoryStore_DeletePostDropsNotifications(t *testing.T) {
+	s := newStoreSeeded()
+	id, err := s.AddPost(domain.Post{UserID: 2})
+	if err != nil {
+		t.Fatalf("add post: %v", err)
+	}
+	_, _ = s.AddNotifications([]domain.Notification{
+		{UserID: 1, Type: domain.NotificationPromoStarted, ActorID: 2, PostID: id},
+		{UserID: 1, Type: domain.NotificationNewFollower, ActorID: 3},
+	})
+	if err := s.DeletePost(2, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, total, _ := s.Notifications(1, false, 10, 0); total != 1 {
+		t.Fatalf("expected promo notification gone, got %d", total)
+	}
+}
+
+func TestMemoryStore_LastDigestAt(t *testing.T) {
+	s := newStoreSeeded()
+	if at, err := s.LastDigestAt(1); err != nil || !at.IsZero() {
+		t.Fatalf("expected zero, got %v %v", at, err)
+	}
+	now := time.Now()
+	if err := s.SetLastDigestAt(1, now); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if at, _ := s.LastDigestAt(1); !at.Equal(now) {
+		t.Fatalf("expected %v, got %v", now, at)
+	}
+	if err := s.SetLastDigestAt(99, now); err != ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) CartLines(userID int) ([]domain.CartLine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.CartLine{}
+	for _, l := range s.carts[userID] {
+		out = append(out, l)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].AddedAt.Equal(out[j].AddedAt) {
+			return out[i].AddedAt.Before(out[j].AddedAt)
+		}
+		return out[i].PostID < out[j].PostID
+	})
+	return out, nil
+}
+
+func (s *MemoryStore) SetCartQuantity(userID, postID, quantity int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postByID(postID); !ok {
+		return ErrPostNotFound
+	}
+	cart := s.carts[userID]
+	if quantity <= 0 {
+		delete(cart, postID)
+		return nil
+	}
+	if cart == nil {
+		cart = map[int]domain.CartLine{}
+		s.carts[userID] = cart
+	}
+	l, ok := cart[postID]
+	if !ok {
+		l = domain.CartLine{PostID: postID, AddedAt: time.Now().UTC()}
+	}
+	l.Quantity = quantity
+	cart[postID] = l
+	return nil
+}
+
+// checkoutLines soma as quantidades por post dos itens dos pedidos.
+func checkoutLines(orders []domain.Order) map[int]int {
+	out := map[int]int{}
+	for _, o := range orders {
+		for _, it := range o.Items {
+			out[it.PostID] += it.Quantity
+		}
+	}
+	return out
+}
+
+// checkCartUnchanged compara o carrinho atual com o que foi precificado.
+func checkCartUnchanged(cart, priced map[int]int) error {
+	if len(cart) == 0 {
+		return domain.ErrCartEmpty
+	}
+	if len(cart) != len(priced) {
+		return domain.ErrCartChanged
+	}
+	for postID, q := range priced {
+		if cart[postID] != q {
+			return domain.ErrCartChanged
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) PlaceOrders(buyerID int, orders []domain.Order) ([]domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cart := map[int]int{}
+	for postID, l := range s.carts[buyerID] {
+		cart[postID] = l.Quantity
+	}
+	if err := checkCartUnchanged(cart, checkoutLines(orders)); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	out := make([]domain.Order, len(orders))
+	for i, o := range orders {
+		o.ID = s.nextOrderID
+		s.nextOrderID++
+		o.BuyerID = buyerID
+		o.CreatedAt, o.UpdatedAt = now, now
+		o.Items = append([]domain.OrderItem(nil), o.Items...)
+		s.orders[o.ID] = o
+		out[i] = o
+	}
+	delete(s.carts, buyerID)
+	return out, nil
+}
+
+func (s *MemoryStore) GetOrder(id int) (domain.Order, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	o, ok := s.orders[id]
+	return o, ok
+}
+
+func (s *MemoryStore) UpdateOrderStatus(id int, from, to string, at time.Time) (domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[id]
+	if !ok {
+		return domain.Order{}, ErrOrderNotFound
+	}
+	if o.Status != from {
+		return domain.Order{}, domain.ErrOrderTransition
+	}
+	o.Status, o.UpdatedAt = to, at
+	s.orders[id] = o
+	return o, nil
+}
+
+func (s *MemoryStore) Orders(f domain.OrderFilter, limit, offset int) ([]domain.Order, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var all []domain.Order
+	for _, o := range s.orders {
+		if (f.BuyerID != 0 && o.BuyerID != f.BuyerID) || (f.SellerID != 0 && o.SellerID != f.SellerID) {
+			continue
+		}
+		if f.Status != "" && o.Status != f.Status {
+			continue
+		}
+		all = append(all, o)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+	out := []domain.Order{}
+	for i := offset; i < len(all) && len(out) < limit; i++ {
+		out = append(out, all[i])
+	}
+	return out, len(all), nil
+}
+
+func (s *MemoryStore) SetOrderPayment(orderID int, paymentID, status string) (domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[orderID]
+	if !ok {
+		return domain.Order{}, ErrOrderNotFound
+	}
+	o.PaymentID, o.PaymentStatus, o.UpdatedAt = paymentID, status, time.Now().UTC()
+	s.orders[orderID] = o
+	return o, nil
+}
+
+func (s *MemoryStore) OrderByPayment(paymentID string) (domain.Order, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, o := range s.orders {
+		if o.PaymentID == paymentID {
+			return o, true
+		}
+	}
+	return domain.Order{}, false
+}
+
+func (s *MemoryStore) ClaimPaymentEvent(eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.payEvents[eventID]; ok {
+		return false, nil
+	}
+	s.payEvents[eventID] = struct{}{}
+	return true, nil
+}
+
+func (s *MemoryStore) ReleasePaymentEvent(eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.payEvents, eventID)
+	return nil
+}
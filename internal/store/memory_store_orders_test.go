@@ -0,0 +1,78 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_CartAndOrders(t *testing.T) {
+	s := newStoreSeeded()
+	p1, _ := s.AddPost(domain.Post{UserID: 2})
+	p2, _ := s.AddPost(domain.Post{UserID: 3})
+
+	if err := s.SetCartQuantity(1, 99, 1); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	_ = s.SetCartQuantity(1, p1, 2)
+	_ = s.SetCartQuantity(1, p2, 1)
+	_ = s.SetCartQuantity(1, p1, 3) // troca, não soma
+	lines, _ := s.CartLines(1)
+	if len(lines) != 2 || lines[0].PostID != p1 || lines[0].Quantity != 3 {
+		t.Fatalf("unexpected cart %+v", lines)
+	}
+	_ = s.SetCartQuantity(1, p2, 0)
+	if lines, _ = s.CartLines(1); len(lines) != 1 {
+		t.Fatalf("expected p2 removed, got %+v", lines)
+	}
+
+	// precificado com quantidade antiga: nada é gravado e o carrinho fica
+	if _, err := s.PlaceOrders(1, []domain.Order{{SellerID: 2, Items: []domain.OrderItem{{PostID: p1, Quantity: 2}}}}); err != domain.ErrCartChanged {
+		t.Fatalf("expected ErrCartChanged, got %v", err)
+	}
+	orders, err := s.PlaceOrders(1, []domain.Order{
+		{SellerID: 2, Status: domain.OrderPending, Items: []domain.OrderItem{{PostID: p1, Quantity: 3}}},
+		{SellerID: 3, Status: domain.OrderPending},
+	})
+	if err != nil || len(orders) != 2 || orders[0].ID == 0 || orders[0].BuyerID != 1 {
+		t.Fatalf("place: %+v %v", orders, err)
+	}
+	if lines, _ = s.CartLines(1); len(lines) != 0 {
+		t.Fatalf("checkout must empty the cart, got %+v", lines)
+	}
+	// segundo clique no checkout
+	if _, err := s.PlaceOrders(1, orders[:1]); err != domain.ErrCartEmpty {
+		t.Fatalf("expected ErrCartEmpty, got %v", err)
+	}
+
+	at := time.Now().UTC()
+	if _, err := s.UpdateOrderStatus(orders[0].ID, domain.OrderPending, domain.OrderPaid, at); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	// status mudou no meio do caminho
+	if _, err := s.UpdateOrderStatus(orders[0].ID, domain.OrderPending, domain.OrderCancelled, at); err != domain.ErrOrderTransition {
+		t.Fatalf("expected ErrOrderTransition, got %v", err)
+	}
+	if _, err := s.UpdateOrderStatus(99, domain.OrderPending, domain.OrderPaid, at); err != ErrOrderNotFound {
+		t.Fatalf("expected ErrOrderNotFound, got %v", err)
+	}
+
+	list, total, _ := s.Orders(domain.OrderFilter{BuyerID: 1}, 10, 0)
+	if total != 2 || list[0].ID != orders[1].ID {
+		t.Fatalf("expected newest first: %+v", list)
+	}
+	if list, total, _ = s.Orders(domain.OrderFilter{SellerID: 2, Status: domain.OrderPaid}, 10, 0); total != 1 || list[0].Items[0].PostID != p1 {
+		t.Fatalf("unexpected seller view %+v", list)
+	}
+
+	// apagar o post tira do carrinho, mas o pedido continua com a cópia
+	_ = s.SetCartQuantity(3, p1, 1)
+	_ = s.DeletePost(2, p1)
+	if lines, _ = s.CartLines(3); len(lines) != 0 {
+		t.Fatalf("deleted post still in cart")
+	}
+	if o, ok := s.GetOrder(orders[0].ID); !ok || len(o.Items) != 1 {
+		t.Fatalf("order lost its items: %+v", o)
+	}
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) AddPriceChange(c domain.PriceChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postIndex(c.PostID); !ok {
+		return ErrPostNotFound
+	}
+	s.priceChanges[c.PostID] = append(s.priceChanges[c.PostID], c)
+	return nil
+}
+
+func (s *MemoryStore) PriceChanges(postID int) ([]domain.PriceChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := append([]domain.PriceChange{}, s.priceChanges[postID]...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].ChangedAt.Before(out[j].ChangedAt) })
+	return out, nil
+}
+
+func (s *MemoryStore) UpsertPriceAlert(a domain.PriceAlert) (domain.PriceAlert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.postIndex(a.PostID); !ok {
+		return domain.PriceAlert{}, ErrPostNotFound
+	}
+	key := [2]int{a.UserID, a.PostID}
+	if id, ok := s.priceAlertByPair[key]; ok {
+		a.ID = id
+	} else {
+		a.ID = s.nextPriceAlertID
+		s.nextPriceAlertID++
+		s.priceAlertByPair[key] = a.ID
+	}
+	a.CreatedAt = time.Now().UTC()
+	a.TriggeredAt = nil
+	s.priceAlerts[a.ID] = a
+	return a, nil
+}
+
+func (s *MemoryStore) DeletePriceAlert(userID, postID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := [2]int{userID, postID}
+	id, ok := s.priceAlertByPair[key]
+	if !ok {
+		return ErrPriceAlertNotFound
+	}
+	delete(s.priceAlerts, id)
+	delete(s.priceAlertByPair, key)
+	return nil
+}
+
+func (s *MemoryStore) PriceAlertsOf(userID int) ([]domain.PriceAlert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.PriceAlert{}
+	for _, a := range s.priceAlerts {
+		if a.UserID == userID {
+			out = append(out, a)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+func (s *MemoryStore) PendingPriceAlerts(postID int) ([]domain.PriceAlert, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.PriceAlert{}
+	for _, a := range s.priceAlerts {
+		if a.TriggeredAt == nil && (postID == 0 || a.PostID == postID) {
+			out = append(out, a)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (s *MemoryStore) MarkPriceAlertTriggered(id int, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.priceAlerts[id]
+	if !ok {
+		return false, ErrPriceAlertNotFound
+	}
+	if a.TriggeredAt != nil {
+		return false, nil
+	}
+	a.TriggeredAt = &at
+	s.priceAlerts[id] = a
+	return true, nil
+}
+
+// dropPostPrices apaga histórico e alertas de um post removido (chamado com o lock).
+func (s *MemoryStore) dropPostPrices(postID int) {
+	delete(s.priceChanges, postID)
+	for key, id := range s.priceAlertByPair {
+		if key[1] == postID {
+			delete(s.priceAlerts, id)
+			delete(s.priceAlertByPair, key)
+		}
+	}
+}
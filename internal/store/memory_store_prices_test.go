@@ -0,0 +1,58 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_PriceChangesAndAlerts(t *testing.T) {
+	s := newStoreSeeded()
+	p1, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00})
+	now := time.Now().UTC()
+
+	if err := s.AddPriceChange(domain.PriceChange{PostID: 99}); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	_ = s.AddPriceChange(domain.PriceChange{PostID: p1, Price: 90_00, ChangedAt: now})
+	_ = s.AddPriceChange(domain.PriceChange{PostID: p1, Price: 100_00, ChangedAt: now.Add(-time.Hour)})
+	if changes, _ := s.PriceChanges(p1); len(changes) != 2 || changes[0].Price != 100_00 {
+		t.Fatalf("expected oldest first, got %+v", changes)
+	}
+
+	a, err := s.UpsertPriceAlert(domain.PriceAlert{UserID: 1, PostID: p1, Threshold: 80_00})
+	if err != nil || a.ID == 0 {
+		t.Fatalf("upsert: %+v %v", a, err)
+	}
+	if ok, _ := s.MarkPriceAlertTriggered(a.ID, now); !ok {
+		t.Fatalf("first mark must win")
+	}
+	if ok, _ := s.MarkPriceAlertTriggered(a.ID, now); ok {
+		t.Fatalf("second mark must lose")
+	}
+	if pending, _ := s.PendingPriceAlerts(0); len(pending) != 0 {
+		t.Fatalf("expected nothing pending, got %+v", pending)
+	}
+	// assinar de novo rearma com o mesmo id
+	again, _ := s.UpsertPriceAlert(domain.PriceAlert{UserID: 1, PostID: p1, Threshold: 70_00})
+	if again.ID != a.ID || again.TriggeredAt != nil {
+		t.Fatalf("unexpected re-subscribe %+v", again)
+	}
+	if pending, _ := s.PendingPriceAlerts(p1); len(pending) != 1 || pending[0].Threshold != 70_00 {
+		t.Fatalf("unexpected pending %+v", pending)
+	}
+
+	if err := s.DeletePost(2, p1); err != nil {
+		t.Fatalf("delete post: %v", err)
+	}
+	if alerts, _ := s.PriceAlertsOf(1); len(alerts) != 0 {
+		t.Fatalf("alerts must go with the post, got %+v", alerts)
+	}
+	if changes, _ := s.PriceChanges(p1); len(changes) != 0 {
+		t.Fatalf("history must go with the post, got %+v", changes)
+	}
+	if err := s.DeletePriceAlert(1, p1); err != ErrPriceAlertNotFound {
+		t.Fatalf("expected ErrPriceAlertNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_PromoWindow(t *testing.T) {
+	s := newStoreSeeded()
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	ended, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00, HasPromo: true, Discount: 10, PromoEndsAt: &past})
+	running, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00, HasPromo: true, Discount: 10, PromoEndsAt: &future})
+	scheduled, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00, HasPromo: true, Discount: 10, PromoStartsAt: &future})
+
+	promos := s.PromoPostsBySeller(2)
+	if len(promos) != 1 || promos[0].PostID != running || promos[0].FinalPrice != 90_00 {
+		t.Fatalf("only the running promo counts, got %+v", promos)
+	}
+	if p, _ := s.GetPost(ended); p.HasPromo || p.FinalPrice != 100_00 {
+		t.Fatalf("ended promo must be back to full price: %+v", p)
+	}
+	onlyPromo, _ := s.QueryPosts(domain.PostQuery{SellerIDs: []int{2}, Filter: domain.PostFilter{PromoOnly: true}})
+	if len(onlyPromo) != 1 || onlyPromo[0].PostID != running {
+		t.Fatalf("unexpected promo filter result %+v", onlyPromo)
+	}
+
+	p, err := s.SetPostPromo(scheduled, domain.PromoSchedule{Discount: 50})
+	if err != nil || !p.HasPromo || p.FinalPrice != 50_00 || p.PromoStartsAt != nil {
+		t.Fatalf("set promo: %+v %v", p, err)
+	}
+	p, _ = s.SetPostPromo(scheduled, domain.PromoSchedule{})
+	if p.HasPromo || p.Discount != 0 || p.FinalPrice != 100_00 {
+		t.Fatalf("promo not removed: %+v", p)
+	}
+	if _, err := s.SetPostPromo(99, domain.PromoSchedule{Discount: 5}); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore_StartedPromos(t *testing.T) {
+	s := newStoreSeeded()
+	now := time.Now()
+	in1h, in2h := now.Add(time.Hour), now.Add(2*time.Hour)
+	soon, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00, HasPromo: true, Discount: 10, PromoStartsAt: &in1h})
+	later, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00, HasPromo: true, Discount: 10, PromoStartsAt: &in2h})
+	_, _ = s.AddPost(domain.Post{UserID: 2, Price: 100_00, HasPromo: true, Discount: 10}) // já vale: nada a anunciar
+	rescheduled, _ := s.AddPost(domain.Post{UserID: 2, Price: 100_00, HasPromo: true, Discount: 10, PromoStartsAt: &in1h})
+	_, _ = s.SetPostPromo(rescheduled, domain.PromoSchedule{Discount: 5}) // passou a valer já
+
+	if started, _ := s.StartedPromos(now); len(started) != 0 {
+		t.Fatalf("nothing started yet, got %+v", started)
+	}
+	started, _ := s.StartedPromos(now.Add(90 * time.Minute))
+	if len(started) != 1 || started[0].PostID != soon || !started[0].HasPromo || started[0].FinalPrice != 90_00 {
+		t.Fatalf("expected only the opened window, got %+v", started)
+	}
+	if ok, _ := s.MarkPromoAnnounced(soon); !ok {
+		t.Fatalf("first mark must win")
+	}
+	if ok, _ := s.MarkPromoAnnounced(soon); ok {
+		t.Fatalf("second mark must lose")
+	}
+	started, _ = s.StartedPromos(now.Add(3 * time.Hour))
+	if len(started) != 1 || started[0].PostID != later {
+		t.Fatalf("announced promo must not come back, got %+v", started)
+	}
+	_ = s.DeletePost(2, later)
+	if started, _ = s.StartedPromos(now.Add(3 * time.Hour)); len(started) != 0 {
+		t.Fatalf("deleted post still pending: %+v", started)
+	}
+}
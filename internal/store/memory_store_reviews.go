@@ -0,0 +1,96 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) UpsertReview(r domain.Review) (domain.Review, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[r.SellerID]; !ok {
+		return domain.Review{}, false, ErrUserNotFound
+	}
+	if _, ok := s.users[r.BuyerID]; !ok {
+		return domain.Review{}, false, ErrUserNotFound
+	}
+	now := time.Now().UTC()
+	key := [2]int{r.SellerID, r.BuyerID}
+	if id, ok := s.reviewByPair[key]; ok {
+		cur := s.reviews[id]
+		cur.Rating, cur.Text, cur.UpdatedAt = r.Rating, r.Text, now
+		s.reviews[id] = cur
+		return s.presentReview(cur), false, nil
+	}
+	r.ID = s.nextReviewID
+	s.nextReviewID++
+	r.Reply, r.RepliedAt = "", nil
+	r.CreatedAt, r.UpdatedAt = now, now
+	s.reviews[r.ID] = r
+	s.reviewByPair[key] = r.ID
+	return s.presentReview(r), true, nil
+}
+
+// presentReview preenche o nome do comprador. Chamado com s.mu travado.
+func (s *MemoryStore) presentReview(r domain.Review) domain.Review {
+	r.BuyerName = s.users[r.BuyerID].Name
+	return r
+}
+
+func (s *MemoryStore) GetReview(id int) (domain.Review, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.reviews[id]
+	if !ok {
+		return domain.Review{}, false
+	}
+	return s.presentReview(r), true
+}
+
+func (s *MemoryStore) SetReviewReply(id int, reply string, at time.Time) (domain.Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reviews[id]
+	if !ok {
+		return domain.Review{}, ErrReviewNotFound
+	}
+	r.Reply, r.RepliedAt = reply, &at
+	s.reviews[id] = r
+	return s.presentReview(r), nil
+}
+
+func (s *MemoryStore) ReviewsOf(sellerID int, limit, offset int) ([]domain.Review, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var all []domain.Review
+	for _, r := range s.reviews {
+		if r.SellerID == sellerID {
+			all = append(all, r)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].UpdatedAt.Equal(all[j].UpdatedAt) {
+			return all[i].UpdatedAt.After(all[j].UpdatedAt)
+		}
+		return all[i].ID > all[j].ID
+	})
+	out := []domain.Review{}
+	for i := offset; i < len(all) && len(out) < limit; i++ {
+		out = append(out, s.presentReview(all[i]))
+	}
+	return out, len(all), nil
+}
+
+func (s *MemoryStore) RatingSummary(sellerID int) (domain.RatingSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	hist := map[int]int{}
+	for _, r := range s.reviews {
+		if r.SellerID == sellerID {
+			hist[r.Rating]++
+		}
+	}
+	return domain.NewRatingSummary(hist), nil
+}
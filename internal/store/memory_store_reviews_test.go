@@ -0,0 +1,46 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_Reviews(t *testing.T) {
+	s := newStoreSeeded()
+
+	r, created, err := s.UpsertReview(domain.Review{SellerID: 2, BuyerID: 1, Rating: 3, Text: "Demorou"})
+	if err != nil || !created || r.BuyerName != "Buyer" {
+		t.Fatalf("create: %+v %v %v", r, created, err)
+	}
+	time.Sleep(time.Millisecond)
+	_, _, _ = s.UpsertReview(domain.Review{SellerID: 2, BuyerID: 3, Rating: 5, Text: "Ótimo"})
+	time.Sleep(time.Millisecond)
+
+	// o mesmo par edita em vez de criar outra
+	edited, created, err := s.UpsertReview(domain.Review{SellerID: 2, BuyerID: 1, Rating: 4, Text: "Chegou"})
+	if err != nil || created || edited.ID != r.ID || edited.Rating != 4 || !edited.CreatedAt.Equal(r.CreatedAt) {
+		t.Fatalf("edit: %+v %v %v", edited, created, err)
+	}
+	if _, _, err := s.UpsertReview(domain.Review{SellerID: 99, BuyerID: 1, Rating: 4}); err != ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	list, total, _ := s.ReviewsOf(2, 10, 0)
+	if total != 2 || list[0].ID != r.ID {
+		t.Fatalf("expected edited review first, got %d %+v", total, list)
+	}
+	sum, _ := s.RatingSummary(2)
+	if sum.Count != 2 || sum.Average != 4.5 || sum.Histogram[4] != 1 || sum.Histogram[5] != 1 {
+		t.Fatalf("unexpected summary %+v", sum)
+	}
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got, err := s.SetReviewReply(r.ID, "Obrigado", at); err != nil || got.Reply != "Obrigado" || !got.RepliedAt.Equal(at) {
+		t.Fatalf("reply: %+v %v", got, err)
+	}
+	if _, err := s.SetReviewReply(99, "x", at); err != ErrReviewNotFound {
+		t.Fatalf("expected ErrReviewNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+// searchIndex é um índice invertido termo -> post -> peso, mantido por AddPost/DeletePost.
+// terms fica ordenado para achar por prefixo ("tenis" casa "tenista") com busca binária.
+type searchIndex struct {
+	postings map[string]map[int]float64
+	terms    []string
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{postings: map[string]map[int]float64{}}
+}
+
+// postTerms soma o peso de cada termo nos campos do produto (repetições contam).
+func postTerms(p domain.Post) map[string]float64 {
+	out := map[string]float64{}
+	add := func(text string, w float64) {
+		for _, t := range domain.SearchTerms(text) {
+			out[t] += w
+		}
+	}
+	add(p.Product.ProductName, domain.SearchWeightName)
+	add(p.Product.Brand, domain.SearchWeightBrand)
+	add(p.Product.Type, domain.SearchWeightKind)
+	add(p.Product.Color, domain.SearchWeightKind)
+	add(p.Product.Notes, domain.SearchWeightNotes)
+	return out
+}
+
+func (ix *searchIndex) add(p domain.Post) {
+	for t, w := range postTerms(p) {
+		posts, ok := ix.postings[t]
+		if !ok {
+			posts = map[int]float64{}
+			ix.postings[t] = posts
+			i := sort.SearchStrings(ix.terms, t)
+			ix.terms = append(ix.terms, "")
+			copy(ix.terms[i+1:], ix.terms[i:])
+			ix.terms[i] = t
+		}
+		posts[p.PostID] = w
+	}
+}
+
+func (ix *searchIndex) remove(p domain.Post) {
+	for t := range postTerms(p) {
+		posts, ok := ix.postings[t]
+		if !ok {
+			continue
+		}
+		delete(posts, p.PostID)
+		if len(posts) == 0 {
+			delete(ix.postings, t)
+			i := sort.SearchStrings(ix.terms, t)
+			if i < len(ix.terms) && ix.terms[i] == t {
+				ix.terms = append(ix.terms[:i], ix.terms[i+1:]...)
+			}
+		}
+	}
+}
+
+// match devolve post -> score dos posts que contêm todos os termos (por prefixo).
+func (ix *searchIndex) match(terms []string) map[int]float64 {
+	var scores map[int]float64
+	for _, q := range terms {
+		hit := map[int]float64{}
+		for i := sort.SearchStrings(ix.terms, q); i < len(ix.terms) && strings.HasPrefix(ix.terms[i], q); i++ {
+			for id, w := range ix.postings[ix.terms[i]] {
+				hit[id] += w
+			}
+		}
+		if scores == nil {
+			scores = hit
+			continue
+		}
+		for id := range scores {
+			if w, ok := hit[id]; ok {
+				scores[id] += w
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	return scores
+}
+
+func (s *MemoryStore) SearchPosts(terms []string, limit, offset int) ([]domain.Post, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scores := s.search.match(terms)
+	hits := []domain.Post{}
+	now := time.Now()
+	for _, p := range s.posts {
+		if _, ok := scores[p.PostID]; ok {
+			hits = append(hits, p.AtTime(now))
+		}
+	}
+	// relevância, depois mais recentes
+	sort.SliceStable(hits, func(i, j int) bool {
+		si, sj := scores[hits[i].PostID], scores[hits[j].PostID]
+		if si != sj {
+			return si > sj
+		}
+		if !hits[i].Date.Equal(hits[j].Date) {
+			return hits[i].Date.After(hits[j].Date)
+		}
+		return hits[i].PostID > hits[j].PostID
+	})
+
+	total := len(hits)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return hits[offset:end], total, nil
+}
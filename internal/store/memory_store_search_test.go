@@ -0,0 +1,72 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func searchPost(userID int, name, brand, notes string, date time.Time) domain.Post {
+	return domain.Post{
+		UserID: userID,
+		Date:   date,
+		Product: domain.Product{
+			ProductID: 1, ProductName: name, Type: "calcado", Brand: brand, Color: "Preto", Notes: notes,
+		},
+		Category: 1,
+		Price:    100_00,
+	}
+}
+
+func TestMemoryStore_SearchPosts_RankingAndAccents(t *testing.T) {
+	s := newStoreSeeded()
+	now := time.Now()
+
+	inNotes, _ := s.AddPost(searchPost(2, "Sapato Social", "Couro", "combina com tênis", now))
+	inName, _ := s.AddPost(searchPost(2, "Tênis Corrida", "Marca", "", now.Add(-time.Hour)))
+	_, _ = s.AddPost(searchPost(3, "Camiseta", "Marca", "", now))
+
+	posts, total, err := s.SearchPosts([]string{"tenis"}, 10, 0)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if total != 2 || len(posts) != 2 {
+		t.Fatalf("expected 2 results, got %d (%d)", total, len(posts))
+	}
+	// match no nome pesa mais que nas notas, mesmo sendo mais antigo
+	if posts[0].PostID != inName || posts[1].PostID != inNotes {
+		t.Fatalf("unexpected order: %d, %d", posts[0].PostID, posts[1].PostID)
+	}
+
+	// prefixo e todos os termos obrigatórios
+	posts, _, _ = s.SearchPosts([]string{"corr", "marc"}, 10, 0)
+	if len(posts) != 1 || posts[0].PostID != inName {
+		t.Fatalf("expected only post %d, got %+v", inName, posts)
+	}
+	if posts, _, _ := s.SearchPosts([]string{"tenis", "camiseta"}, 10, 0); len(posts) != 0 {
+		t.Fatalf("expected no results, got %d", len(posts))
+	}
+
+	// paginação
+	posts, total, _ = s.SearchPosts([]string{"preto"}, 2, 2)
+	if total != 3 || len(posts) != 1 {
+		t.Fatalf("expected total 3 and 1 on last page, got %d/%d", total, len(posts))
+	}
+}
+
+func TestMemoryStore_SearchPosts_DeleteRemovesFromIndex(t *testing.T) {
+	s := newStoreSeeded()
+	id, _ := s.AddPost(searchPost(2, "Relógio Dourado", "Marca", "", time.Now()))
+
+	if err := s.DeletePost(2, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	posts, total, _ := s.SearchPosts([]string{"relogio"}, 10, 0)
+	if total != 0 || len(posts) != 0 {
+		t.Fatalf("deleted post still indexed: %+v", posts)
+	}
+	if len(s.search.terms) != 0 || len(s.search.postings) != 0 {
+		t.Fatalf("index should be empty, got %v", s.search.terms)
+	}
+}
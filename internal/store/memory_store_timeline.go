@@ -0,0 +1,190 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+// TimelineCapacity é quantas entradas a timeline em memória guarda por usuário;
+// ao encher, a mais antiga (por ordem de chegada) sai.
+const TimelineCapacity = 1000
+
+// timelineEntry referencia o post pelo id: a timeline não guarda cópia, então posts
+// apagados simplesmente deixam de aparecer na leitura.
+type timelineEntry struct {
+	postID   int
+	sellerID int
+	date     time.Time
+}
+
+// timelineRing é um buffer circular limitado com índice dos ids presentes.
+type timelineRing struct {
+	buf   []timelineEntry
+	start int // posição da entrada mais antiga
+	n     int
+	ids   map[int]struct{}
+}
+
+func newTimelineRing(capacity int) *timelineRing {
+	return &timelineRing{buf: make([]timelineEntry, capacity), ids: map[int]struct{}{}}
+}
+
+func (r *timelineRing) push(e timelineEntry) {
+	if _, ok := r.ids[e.postID]; ok {
+		return
+	}
+	if r.n == len(r.buf) {
+		delete(r.ids, r.buf[r.start].postID)
+		r.buf[r.start] = e
+		r.start = (r.start + 1) % len(r.buf)
+	} else {
+		r.buf[(r.start+r.n)%len(r.buf)] = e
+		r.n++
+	}
+	r.ids[e.postID] = struct{}{}
+}
+
+func (r *timelineRing) each(fn func(timelineEntry)) {
+	for i := 0; i < r.n; i++ {
+		fn(r.buf[(r.start+i)%len(r.buf)])
+	}
+}
+
+// removeIf compacta o buffer mantendo a ordem de chegada e devolve quantas saíram.
+func (r *timelineRing) removeIf(drop func(timelineEntry) bool) int {
+	kept := make([]timelineEntry, 0, r.n)
+	r.each(func(e timelineEntry) {
+		if drop(e) {
+			delete(r.ids, e.postID)
+		} else {
+			kept = append(kept, e)
+		}
+	})
+	removed := r.n - len(kept)
+	if removed > 0 {
+		copy(r.buf, kept)
+		r.start, r.n = 0, len(kept)
+	}
+	return removed
+}
+
+func (s *MemoryStore) timelineOf(userID int) *timelineRing {
+	r, ok := s.timelines[userID]
+	if !ok {
+		r = newTimelineRing(TimelineCapacity)
+		s.timelines[userID] = r
+	}
+	return r
+}
+
+// postByID usa que s.posts está em ordem crescente de id (AddPost só acrescenta e
+// DeletePost preserva a ordem). O post volta com a promoção avaliada agora.
+func (s *MemoryStore) postByID(id int) (domain.Post, bool) {
+	if i, ok := s.postIndex(id); ok {
+		return s.posts[i].AtTime(time.Now()), true
+	}
+	return domain.Post{}, false
+}
+
+func (s *MemoryStore) postIndex(id int) (int, bool) {
+	i := sort.Search(len(s.posts), func(i int) bool { return s.posts[i].PostID >= id })
+	return i, i < len(s.posts) && s.posts[i].PostID == id
+}
+
+func (s *MemoryStore) AppendTimeline(userIDs []int, p domain.Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := timelineEntry{postID: p.PostID, sellerID: p.UserID, date: p.Date}
+	for _, uid := range userIDs {
+		s.timelineOf(uid).push(e)
+	}
+	return nil
+}
+
+func (s *MemoryStore) TimelinePosts(userID int, since time.Time, f domain.PostFilter) ([]domain.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := []domain.Post{}
+	r, ok := s.timelines[userID]
+	if !ok {
+		return out, nil
+	}
+	r.each(func(e timelineEntry) {
+		if e.date.Before(since) {
+			return
+		}
+		if p, ok := s.postByID(e.postID); ok && f.Match(p) {
+			out = append(out, p)
+		}
+	})
+	return out, nil
+}
+
+func (s *MemoryStore) BackfillTimeline(userID, sellerID int, since time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.timelineOf(userID)
+	for _, p := range s.posts {
+		if p.UserID == sellerID && !p.Date.Before(since) {
+			r.push(timelineEntry{postID: p.PostID, sellerID: p.UserID, date: p.Date})
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) PruneTimeline(userID, sellerID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.timelines[userID]; ok {
+		r.removeIf(func(e timelineEntry) bool { return e.sellerID == sellerID })
+	}
+	return nil
+}
+
+func (s *MemoryStore) ExpireTimeline(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.timelines {
+		n += r.removeIf(func(e timelineEntry) bool { return e.date.Before(before) })
+	}
+	return n, nil
+}
+
+func (s *MemoryStore) PopularSellers(minFollowers int) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []int{}
+	for id, set := range s.followers {
+		if len(set) >= minFollowers {
+			out = append(out, id)
+		}
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+func (s *MemoryStore) PulledSellers() ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]int, 0, len(s.pulled))
+	for id := range s.pulled {
+		out = append(out, id)
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+func (s *MemoryStore) SetSellerPulled(sellerID int, pulled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pulled {
+		s.pulled[sellerID] = struct{}{}
+	} else {
+		delete(s.pulled, sellerID)
+	}
+	return nil
+}
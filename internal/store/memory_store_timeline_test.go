@@ -0,0 +1,121 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestTimelineRing_BoundedAndDeduped(t *testing.T) {
+	r := newTimelineRing(3)
+	for id := 1; id <= 4; id++ {
+		r.push(timelineEntry{postID: id})
+	}
+	r.push(timelineEntry{postID: 4}) // repetido
+
+	var ids []int
+	r.each(func(e timelineEntry) { ids = append(ids, e.postID) })
+	if len(ids) != 3 || ids[0] != 2 || ids[2] != 4 {
+		t.Fatalf("expected [2 3 4], got %v", ids)
+	}
+
+	if n := r.removeIf(func(e timelineEntry) bool { return e.postID == 3 }); n != 1 {
+		t.Fatalf("expected 1 removed, got %d", n)
+	}
+	r.push(timelineEntry{postID: 1}) // 1 tinha sido descartado: volta
+	ids = nil
+	r.each(func(e timelineEntry) { ids = append(ids, e.postID) })
+	if len(ids) != 3 || ids[0] != 2 || ids[1] != 4 || ids[2] != 1 {
+		t.Fatalf("expected [2 4 1], got %v", ids)
+	}
+}
+
+func TestMemoryStore_Timeline(t *testing.T) {
+	s := newStoreSeeded()
+	now := time.Now()
+
+	add := func(user int, brand string, date time.Time) domain.Post {
+		t.Helper()
+		p := searchPost(user, "Produto", brand, "", date)
+		id, err := s.AddPost(p)
+		if err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		p.PostID = id
+		return p
+	}
+	p1 := add(2, "Nike", now)
+	p2 := add(3, "Adidas", now)
+	old := add(2, "Nike", now.AddDate(0, 0, -30))
+
+	for _, p := range []domain.Post{p1, p2, old} {
+		if err := s.AppendTimeline([]int{1}, p); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	posts, err := s.TimelinePosts(1, now.AddDate(0, 0, -14), domain.PostFilter{})
+	if err != nil || len(posts) != 2 {
+		t.Fatalf("expected 2 recent posts, got %d (%v)", len(posts), err)
+	}
+	posts, _ = s.TimelinePosts(1, time.Time{}, domain.PostFilter{Brands: []string{"nike"}})
+	if len(posts) != 2 {
+		t.Fatalf("expected 2 nike posts, got %+v", posts)
+	}
+
+	// post apagado some da leitura
+	if err := s.DeletePost(3, p2.PostID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	posts, _ = s.TimelinePosts(1, time.Time{}, domain.PostFilter{})
+	if len(posts) != 2 {
+		t.Fatalf("expected deleted post to disappear, got %+v", posts)
+	}
+
+	if err := s.PruneTimeline(1, 2); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if posts, _ = s.TimelinePosts(1, time.Time{}, domain.PostFilter{}); len(posts) != 0 {
+		t.Fatalf("expected empty timeline after prune, got %+v", posts)
+	}
+
+	if err := s.BackfillTimeline(1, 2, now.AddDate(0, 0, -14)); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	posts, _ = s.TimelinePosts(1, time.Time{}, domain.PostFilter{})
+	if len(posts) != 1 || posts[0].PostID != p1.PostID {
+		t.Fatalf("expected only the recent post after backfill, got %+v", posts)
+	}
+
+	// a entrada do post apagado também conta: ela só sai por expiração/prune
+	if n, err := s.ExpireTimeline(now.Add(time.Hour)); err != nil || n != 2 {
+		t.Fatalf("expected 2 expired entries, got %d (%v)", n, err)
+	}
+}
+
+func TestMemoryStore_PopularSellers(t *testing.T) {
+	s := newStoreSeeded()
+	_ = s.Follow(1, 2)
+	_ = s.Follow(3, 2)
+	_ = s.Follow(1, 3)
+
+	ids, err := s.PopularSellers(2)
+	if err != nil || len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("expected [2], got %v (%v)", ids, err)
+	}
+}
+
+func TestMemoryStore_PulledSellers(t *testing.T) {
+	s := newStoreSeeded()
+	_ = s.SetSellerPulled(3, true)
+	_ = s.SetSellerPulled(2, true)
+	_ = s.SetSellerPulled(2, true)
+	if ids, err := s.PulledSellers(); err != nil || len(ids) != 2 || ids[0] != 2 {
+		t.Fatalf("expected [2 3], got %v (%v)", ids, err)
+	}
+	_ = s.SetSellerPulled(2, false)
+	if ids, _ := s.PulledSellers(); len(ids) != 1 || ids[0] != 3 {
+		t.Fatalf("expected [3], got %v", ids)
+	}
+}
@@ -0,0 +1,236 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) RecordUpload(u domain.Upload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u.RefCount = 0
+	u.UnreferencedSince = nil // reenvio reinicia a carência
+	u.Keys = append([]string(nil), u.Keys...)
+	owner := u.OwnerID
+	if prev, ok := s.uploads[u.ID]; ok {
+		// mantém o primeiro dono; só renova dados e created_at
+		u.OwnerID = prev.OwnerID
+	}
+	s.uploads[u.ID] = u
+	if u.Hash != "" {
+		s.uploadByHash[u.Hash] = u.ID
+	}
+	owners := s.uploadOwners[u.ID]
+	if owners == nil {
+		owners = map[int]time.Time{}
+		s.uploadOwners[u.ID] = owners
+	}
+	if _, ok := owners[owner]; !ok {
+		owners[owner] = u.CreatedAt
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetUpload(id string) (domain.Upload, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return domain.Upload{}, false
+	}
+	u.RefCount = s.uploadRefsLocked(u.URL)
+	return u, true
+}
+
+func (s *MemoryStore) GetUploadByHash(hash string) (domain.Upload, bool) {
+	s.mu.RLock()
+	id, ok := s.uploadByHash[hash]
+	s.mu.RUnlock()
+	if !ok {
+		return domain.Upload{}, false
+	}
+	return s.GetUpload(id)
+}
+
+func (s *MemoryStore) ReuseUpload(id string, ownerID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return ErrUploadNotFound
+	}
+	if u.CollectingSince != nil {
+		return ErrUploadCollecting
+	}
+	u.CreatedAt = at
+	u.UnreferencedSince = nil
+	s.uploads[id] = u
+	owners := s.uploadOwners[id]
+	if owners == nil {
+		owners = map[int]time.Time{}
+		s.uploadOwners[id] = owners
+	}
+	if _, ok := owners[ownerID]; !ok {
+		owners[ownerID] = at
+	}
+	return nil
+}
+
+func (s *MemoryStore) UploadsByOwner(userID int) ([]domain.Upload, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.Upload{}
+	for id, owners := range s.uploadOwners {
+		if _, ok := owners[userID]; ok {
+			u := s.uploads[id]
+			u.RefCount = s.uploadRefsLocked(u.URL)
+			out = append(out, u)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *MemoryStore) MarkUnreferencedUploads(at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, u := range s.uploads {
+		if u.CollectingSince != nil {
+			continue
+		}
+		unused := s.uploadRefsLocked(u.URL) == 0
+		switch {
+		case unused && u.UnreferencedSince == nil:
+			t := at
+			u.UnreferencedSince = &t
+		case !unused && u.UnreferencedSince != nil:
+			u.UnreferencedSince = nil
+		default:
+			continue
+		}
+		s.uploads[id] = u
+	}
+	return nil
+}
+
+// collectableLocked: já em coleta, ou sem referências e marcado antes de olderThan.
+// Chamar com mu travado.
+func (s *MemoryStore) collectableLocked(u domain.Upload, olderThan time.Time) bool {
+	if u.CollectingSince != nil {
+		return true
+	}
+	return u.UnreferencedSince != nil && u.UnreferencedSince.Before(olderThan) && s.uploadRefsLocked(u.URL) == 0
+}
+
+func (s *MemoryStore) UnreferencedUploads(olderThan time.Time) ([]domain.Upload, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.Upload{}
+	for _, u := range s.uploads {
+		if s.collectableLocked(u, olderThan) {
+			out = append(out, u)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UnreferencedSince.Before(*out[j].UnreferencedSince) })
+	return out, nil
+}
+
+func (s *MemoryStore) ClaimUnreferencedUpload(id string, olderThan, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return ErrUploadNotFound
+	}
+	if !s.collectableLocked(u, olderThan) {
+		return ErrUploadInUse
+	}
+	if u.CollectingSince == nil {
+		t := at
+		u.CollectingSince = &t
+		s.uploads[id] = u
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteCollectedUpload(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok || u.CollectingSince == nil {
+		return ErrUploadNotFound
+	}
+	delete(s.uploads, id)
+	delete(s.uploadOwners, id)
+	if u.Hash != "" {
+		delete(s.uploadByHash, u.Hash)
+	}
+	return nil
+}
+
+// uploadRefsLocked conta posts, avatares e itens de pedidos (cópia do produto na
+// compra) que usam a URL. Chamar com mu travado.
+func (s *MemoryStore) uploadRefsLocked(url string) int {
+	n := 0
+	for _, p := range s.posts {
+		if p.Product.ImageURL == url {
+			n++
+		}
+	}
+	for _, a := range s.accounts {
+		if a.AvatarURL == url {
+			n++
+		}
+	}
+	for _, o := range s.orders {
+		for _, it := range o.Items {
+			if it.Product.ImageURL == url {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (s *MemoryStore) UploadParts(uploadID string) ([]domain.UploadPart, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]domain.UploadPart{}, s.uploadParts[uploadID]...), nil
+}
+
+func (s *MemoryStore) AddUploadPart(p domain.UploadPart) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parts := s.uploadParts[p.UploadID]
+	var end int64
+	if n := len(parts); n > 0 {
+		end = parts[n-1].Start + parts[n-1].Size
+	}
+	if p.Start != end {
+		return false, nil
+	}
+	s.uploadParts[p.UploadID] = append(parts, p)
+	return true, nil
+}
+
+func (s *MemoryStore) DeleteUploadParts(uploadID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uploadParts, uploadID)
+	return nil
+}
+
+func (s *MemoryStore) StaleUploadParts(before time.Time) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []string{}
+	for id, parts := range s.uploadParts {
+		if n := len(parts); n > 0 && parts[n-1].CreatedAt.Before(before) {
+			out = append(out, id)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
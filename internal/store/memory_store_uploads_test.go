@@ -0,0 +1,146 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_Uploads_RefCountAndCollection(t *testing.T) {
+	s := NewMemoryStore()
+	acc, _ := s.CreateAccount("Seller", "s@example.com", "hash", true)
+	old := time.Now().Add(-48 * time.Hour)
+
+	_ = s.RecordUpload(domain.Upload{ID: "products/a_large.jpg", OwnerID: acc.ID, URL: "/static/products/a_large.jpg", Keys: []string{"products/a_thumb.jpg", "products/a_large.jpg"}, Size: 10, CreatedAt: old})
+	_ = s.RecordUpload(domain.Upload{ID: "products/b_large.jpg", OwnerID: acc.ID, URL: "/static/products/b_large.jpg", Size: 20, CreatedAt: old})
+	_ = s.RecordUpload(domain.Upload{ID: "avatars/1_large.png", OwnerID: acc.ID, URL: "/static/avatars/1_large.png", Size: 30, CreatedAt: old})
+	_ = s.RecordUpload(domain.Upload{ID: "products/new_large.jpg", OwnerID: acc.ID, URL: "/static/products/new_large.jpg", Size: 40, CreatedAt: time.Now()})
+
+	postID, _ := s.AddPost(domain.Post{UserID: acc.ID, Product: domain.Product{ProductID: 1, ImageURL: "/static/products/a_large.jpg"}})
+	_, _ = s.UpdateAvatar(acc.ID, "/static/avatars/1_large.png")
+
+	u, ok := s.GetUpload("products/a_large.jpg")
+	if !ok || u.RefCount != 1 || len(u.Keys) != 2 {
+		t.Fatalf("unexpected upload: %+v ok=%v", u, ok)
+	}
+
+	// sem marca da coleta nada é apagável, por mais antigo que seja o upload
+	if orphans, _ := s.UnreferencedUploads(time.Now()); len(orphans) != 0 {
+		t.Fatalf("expected no orphans before marking, got %+v", orphans)
+	}
+	_ = s.MarkUnreferencedUploads(old)
+	orphans, err := s.UnreferencedUploads(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(orphans) != 2 || orphans[0].UnreferencedSince == nil {
+		t.Fatalf("expected b and new as orphans, got %+v", orphans)
+	}
+
+	if err := s.ClaimUnreferencedUpload("products/a_large.jpg", time.Now(), time.Now()); err != ErrUploadInUse {
+		t.Fatalf("expected ErrUploadInUse, got %v", err)
+	}
+
+	// apagar o post libera a imagem, mas a carência conta de quando a coleta viu
+	// a imagem sem uso, não do upload (criado há 48h)
+	if err := s.DeletePost(acc.ID, postID); err != nil {
+		t.Fatalf("delete post: %v", err)
+	}
+	if err := s.ClaimUnreferencedUpload("products/a_large.jpg", time.Now(), time.Now()); err != ErrUploadInUse {
+		t.Fatalf("expected ErrUploadInUse right after the delete, got %v", err)
+	}
+	now := time.Now()
+	_ = s.MarkUnreferencedUploads(now)
+	if err := s.ClaimUnreferencedUpload("products/a_large.jpg", now.Add(-24*time.Hour), time.Now()); err != ErrUploadInUse {
+		t.Fatalf("expected ErrUploadInUse within grace, got %v", err)
+	}
+	if err := s.ClaimUnreferencedUpload("products/a_large.jpg", now.Add(time.Minute), now); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	// em coleta: não é mais reaproveitado
+	if err := s.ReuseUpload("products/a_large.jpg", acc.ID, now); err != ErrUploadCollecting {
+		t.Fatalf("expected ErrUploadCollecting, got %v", err)
+	}
+	if err := s.DeleteCollectedUpload("products/a_large.jpg"); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := s.ClaimUnreferencedUpload("products/a_large.jpg", time.Now(), now); err != ErrUploadNotFound {
+		t.Fatalf("expected ErrUploadNotFound, got %v", err)
+	}
+	if err := s.ReuseUpload("products/a_large.jpg", acc.ID, now); err != ErrUploadNotFound {
+		t.Fatalf("expected ErrUploadNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStore_Uploads_OwnersAndHash(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Now()
+
+	_ = s.RecordUpload(domain.Upload{ID: "images/h_large.png", Hash: "h", OwnerID: 1, URL: "/static/images/h_large.png", Size: 10, CreatedAt: now.Add(-time.Hour)})
+	_ = s.RecordUpload(domain.Upload{ID: "images/h_large.png", Hash: "h", OwnerID: 2, URL: "/static/images/h_large.png", Size: 10, CreatedAt: now})
+
+	u, ok := s.GetUploadByHash("h")
+	if !ok || u.OwnerID != 1 || !u.CreatedAt.Equal(now) {
+		t.Fatalf("expected first owner kept and created_at renewed: %+v", u)
+	}
+	for _, uid := range []int{1, 2} {
+		owned, _ := s.UploadsByOwner(uid)
+		if len(owned) != 1 {
+			t.Fatalf("user %d: expected 1 upload, got %d", uid, len(owned))
+		}
+	}
+
+	// reenviado depois da marca: a marca some e a coleta não apaga
+	_ = s.MarkUnreferencedUploads(now.Add(-time.Hour))
+	_ = s.RecordUpload(domain.Upload{ID: "images/h_large.png", Hash: "h", OwnerID: 2, URL: "/static/images/h_large.png", Size: 10, CreatedAt: now})
+	if err := s.ClaimUnreferencedUpload("images/h_large.png", now.Add(time.Minute), time.Now()); err != ErrUploadInUse {
+		t.Fatalf("expected ErrUploadInUse, got %v", err)
+	}
+	// reaproveitado (dedupe) depois da marca: idem
+	_ = s.MarkUnreferencedUploads(now.Add(-time.Hour))
+	if err := s.ReuseUpload("images/h_large.png", 3, now); err != nil {
+		t.Fatalf("reuse: %v", err)
+	}
+	if err := s.ClaimUnreferencedUpload("images/h_large.png", now.Add(time.Minute), time.Now()); err != ErrUploadInUse {
+		t.Fatalf("expected ErrUploadInUse after reuse, got %v", err)
+	}
+	if owned, _ := s.UploadsByOwner(3); len(owned) != 1 {
+		t.Fatalf("reuse should add the owner, got %+v", owned)
+	}
+	_ = s.MarkUnreferencedUploads(now)
+	if err := s.ClaimUnreferencedUpload("images/h_large.png", now.Add(time.Minute), now); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := s.DeleteCollectedUpload("images/h_large.png"); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if _, ok := s.GetUploadByHash("h"); ok {
+		t.Fatalf("hash index should be cleared")
+	}
+	if owned, _ := s.UploadsByOwner(2); len(owned) != 0 {
+		t.Fatalf("owners should be cleared, got %+v", owned)
+	}
+}
+
+func TestMemoryStore_Uploads_OrderItemsAreReferences(t *testing.T) {
+	s := newStoreSeeded()
+	url := "/static/images/p_large.jpg"
+	_ = s.RecordUpload(domain.Upload{ID: "images/p_large.jpg", OwnerID: 2, URL: url, Size: 10, CreatedAt: time.Now().Add(-48 * time.Hour)})
+	postID, _ := s.AddPost(domain.Post{UserID: 2})
+	_ = s.SetCartQuantity(1, postID, 1)
+	_, err := s.PlaceOrders(1, []domain.Order{{SellerID: 2, Status: domain.OrderPending, Items: []domain.OrderItem{{
+		PostID: postID, Product: domain.Product{ProductName: "Tênis", ImageURL: url}, Quantity: 1,
+	}}}})
+	if err != nil {
+		t.Fatalf("place: %v", err)
+	}
+
+	_ = s.MarkUnreferencedUploads(time.Now().Add(-24 * time.Hour))
+	if u, _ := s.GetUpload("images/p_large.jpg"); u.RefCount != 1 || u.UnreferencedSince != nil {
+		t.Fatalf("order snapshot must keep the image: %+v", u)
+	}
+	if err := s.ClaimUnreferencedUpload("images/p_large.jpg", time.Now(), time.Now()); err != ErrUploadInUse {
+		t.Fatalf("expected ErrUploadInUse, got %v", err)
+	}
+}
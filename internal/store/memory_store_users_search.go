@@ -0,0 +1,114 @@
+package store
+
+import (
+	"sort"
+	"strings"
+
+	"socialmeli/internal/domain"
+)
+
+// nameEntry é uma palavra (normalizada) do nome de um usuário.
+type nameEntry struct {
+	word string
+	id   int
+}
+
+// nameIndex mantém todas as palavras dos nomes ordenadas, para achar por prefixo
+// com busca binária ("jo" acha "João Silva" e "Maria José").
+type nameIndex struct {
+	entries []nameEntry
+}
+
+func (ix *nameIndex) less(a, b nameEntry) bool {
+	if a.word != b.word {
+		return a.word < b.word
+	}
+	return a.id < b.id
+}
+
+func (ix *nameIndex) add(u domain.User) {
+	for _, w := range domain.SearchTerms(u.Name) {
+		e := nameEntry{word: w, id: u.ID}
+		i := sort.Search(len(ix.entries), func(i int) bool { return !ix.less(ix.entries[i], e) })
+		if i < len(ix.entries) && ix.entries[i] == e {
+			continue
+		}
+		ix.entries = append(ix.entries, nameEntry{})
+		copy(ix.entries[i+1:], ix.entries[i:])
+		ix.entries[i] = e
+	}
+}
+
+func (ix *nameIndex) remove(u domain.User) {
+	for _, w := range domain.SearchTerms(u.Name) {
+		e := nameEntry{word: w, id: u.ID}
+		i := sort.Search(len(ix.entries), func(i int) bool { return !ix.less(ix.entries[i], e) })
+		if i < len(ix.entries) && ix.entries[i] == e {
+			ix.entries = append(ix.entries[:i], ix.entries[i+1:]...)
+		}
+	}
+}
+
+// prefix devolve os ids com alguma palavra começando por p.
+func (ix *nameIndex) prefix(p string) map[int]struct{} {
+	out := map[int]struct{}{}
+	i := sort.Search(len(ix.entries), func(i int) bool { return ix.entries[i].word >= p })
+	for ; i < len(ix.entries) && strings.HasPrefix(ix.entries[i].word, p); i++ {
+		out[ix.entries[i].id] = struct{}{}
+	}
+	return out
+}
+
+// putUser grava o usuário mantendo o índice de nomes (chamar com s.mu travado).
+func (s *MemoryStore) putUser(u domain.User) {
+	if old, ok := s.users[u.ID]; ok {
+		s.names.remove(old)
+	}
+	s.users[u.ID] = u
+	s.names.add(u)
+}
+
+func (s *MemoryStore) SearchUsers(terms []string, sellersOnly bool, limit, offset int) ([]domain.UserSummary, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// o primeiro termo usa o índice; os demais são conferidos no nome
+	hits := []domain.UserSummary{}
+	for id := range s.names.prefix(terms[0]) {
+		u := s.users[id]
+		if sellersOnly && !u.IsSeller {
+			continue
+		}
+		if !domain.NameMatches(u.Name, terms[1:]) {
+			continue
+		}
+		hits = append(hits, domain.UserSummary{ID: u.ID, Name: u.Name, IsSeller: u.IsSeller, AvatarURL: s.accounts[id].AvatarURL})
+	}
+
+	// quem começa com o termo vem antes; depois ordem alfabética (sem acento, como o collation do banco)
+	starts := func(u domain.UserSummary) bool {
+		words := domain.SearchTerms(u.Name)
+		return len(words) > 0 && strings.HasPrefix(words[0], terms[0])
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		si, sj := starts(hits[i]), starts(hits[j])
+		if si != sj {
+			return si
+		}
+		ni, nj := strings.Join(domain.SearchTerms(hits[i].Name), " "), strings.Join(domain.SearchTerms(hits[j].Name), " ")
+		if ni != nj {
+			return ni < nj
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	total := len(hits)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return hits[offset:end], total, nil
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"testing"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_SearchUsers(t *testing.T) {
+	s := NewMemoryStore()
+	s.SeedUsers([]domain.User{
+		{ID: 1, Name: "Ana Paula", IsSeller: false},
+		{ID: 2, Name: "Paulo Ângelo", IsSeller: true},
+		{ID: 3, Name: "Pedro", IsSeller: true},
+	})
+
+	users, total, err := s.SearchUsers([]string{"paul"}, false, 10, 0)
+	if err != nil || total != 2 {
+		t.Fatalf("expected 2 results, got %d (%v)", total, err)
+	}
+	if users[0].ID != 2 || users[1].ID != 1 {
+		t.Fatalf("prefix of the name must come first: %+v", users)
+	}
+
+	users, _, _ = s.SearchUsers([]string{"angelo", "pa"}, true, 10, 0)
+	if len(users) != 1 || users[0].ID != 2 {
+		t.Fatalf("unexpected result: %+v", users)
+	}
+	if users, _, _ := s.SearchUsers([]string{"paul"}, true, 10, 0); len(users) != 1 {
+		t.Fatalf("sellersOnly must drop non sellers: %+v", users)
+	}
+
+	// reseed com outro nome atualiza o índice
+	s.SeedUsers([]domain.User{{ID: 3, Name: "Carlos"}})
+	if _, total, _ := s.SearchUsers([]string{"pedro"}, false, 10, 0); total != 0 {
+		t.Fatalf("old name still indexed")
+	}
+	if _, total, _ := s.SearchUsers([]string{"car"}, false, 10, 0); total != 1 {
+		t.Fatalf("new name not indexed")
+	}
+}
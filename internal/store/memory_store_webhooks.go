@@ -0,0 +1,145 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *MemoryStore) CreateWebhook(w domain.Webhook) (domain.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[w.UserID]; !ok {
+		return domain.Webhook{}, ErrUserNotFound
+	}
+	w.ID = s.nextWebhookID
+	s.nextWebhookID++
+	w.Events = append([]string(nil), w.Events...)
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = time.Now().UTC()
+	}
+	s.webhooks[w.ID] = w
+	return w, nil
+}
+
+func (s *MemoryStore) GetWebhook(id int) (domain.Webhook, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	w, ok := s.webhooks[id]
+	return w, ok
+}
+
+func (s *MemoryStore) WebhooksByUser(userID int) ([]domain.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []domain.Webhook{}
+	for _, w := range s.webhooks {
+		if w.UserID == userID {
+			out = append(out, w)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (s *MemoryStore) DeleteWebhook(userID, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.webhooks[id]
+	if !ok || w.UserID != userID {
+		return ErrWebhookNotFound
+	}
+	delete(s.webhooks, id)
+	for did, d := range s.deliveries {
+		if d.WebhookID == id {
+			delete(s.deliveries, did)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) AddWebhookDeliveries(ds []domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	for _, d := range ds {
+		if _, ok := s.webhooks[d.WebhookID]; !ok {
+			return ErrWebhookNotFound
+		}
+	}
+	for _, d := range ds {
+		d.ID = s.nextDeliveryID
+		s.nextDeliveryID++
+		d.CreatedAt, d.UpdatedAt = now, now
+		s.deliveries[d.ID] = d
+	}
+	return nil
+}
+
+func (s *MemoryStore) ClaimWebhookDeliveries(now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := []domain.WebhookDelivery{}
+	for _, d := range s.deliveries {
+		if d.Status == domain.DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].NextAttemptAt = leaseUntil
+		s.deliveries[due[i].ID] = due[i]
+	}
+	return due, nil
+}
+
+func (s *MemoryStore) UpdateWebhookDelivery(d domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.deliveries[d.ID]
+	if !ok {
+		return ErrDeliveryNotFound
+	}
+	cur.Status, cur.Attempts, cur.NextAttemptAt = d.Status, d.Attempts, d.NextAttemptAt
+	cur.LastStatus, cur.LastError = d.LastStatus, d.LastError
+	cur.UpdatedAt = time.Now().UTC()
+	s.deliveries[d.ID] = cur
+	return nil
+}
+
+func (s *MemoryStore) GetWebhookDelivery(id int) (domain.WebhookDelivery, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.deliveries[id]
+	return d, ok
+}
+
+func (s *MemoryStore) WebhookDeliveries(webhookID int, status string, limit, offset int) ([]domain.WebhookDelivery, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	all := []domain.WebhookDelivery{}
+	for _, d := range s.deliveries {
+		if d.WebhookID == webhookID && (status == "" || d.Status == status) {
+			all = append(all, d)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+	total := len(all)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return all[offset:end], total, nil
+}
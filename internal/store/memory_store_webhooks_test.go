@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func TestMemoryStore_Webhooks(t *testing.T) {
+	s := newStoreSeeded()
+	if _, err := s.CreateWebhook(domain.Webhook{UserID: 99}); err != ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	w, err := s.CreateWebhook(domain.Webhook{UserID: 2, URL: "http://x", Events: []string{domain.WebhookPostCreated}, Secret: "s"})
+	if err != nil || w.ID != 1 || w.CreatedAt.IsZero() {
+		t.Fatalf("create: %+v %v", w, err)
+	}
+	if ws, _ := s.WebhooksByUser(2); len(ws) != 1 || ws[0].Secret != "s" {
+		t.Fatalf("unexpected list %+v", ws)
+	}
+	if err := s.DeleteWebhook(3, w.ID); err != ErrWebhookNotFound {
+		t.Fatalf("expected ErrWebhookNotFound for another user, got %v", err)
+	}
+
+	now := time.Now()
+	err = s.AddWebhookDeliveries([]domain.WebhookDelivery{
+		{WebhookID: w.ID, Event: domain.WebhookPostCreated, Status: domain.DeliveryPending, NextAttemptAt: now.Add(-time.Minute)},
+		{WebhookID: w.ID, Event: domain.WebhookPostCreated, Status: domain.DeliveryPending, NextAttemptAt: now.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("add deliveries: %v", err)
+	}
+	if err := s.AddWebhookDeliveries([]domain.WebhookDelivery{{WebhookID: 42}}); err != ErrWebhookNotFound {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+
+	claimed, _ := s.ClaimWebhookDeliveries(now, now.Add(time.Minute), 10)
+	if len(claimed) != 1 || claimed[0].ID != 1 {
+		t.Fatalf("expected only the due delivery, got %+v", claimed)
+	}
+	if again, _ := s.ClaimWebhookDeliveries(now, now.Add(time.Minute), 10); len(again) != 0 {
+		t.Fatalf("claimed delivery must be leased, got %+v", again)
+	}
+
+	claimed[0].Status, claimed[0].Attempts, claimed[0].LastStatus = domain.DeliveryDead, 3, 500
+	if err := s.UpdateWebhookDelivery(claimed[0]); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	dead, total, _ := s.WebhookDeliveries(w.ID, domain.DeliveryDead, 10, 0)
+	if total != 1 || dead[0].Attempts != 3 || dead[0].LastStatus != 500 {
+		t.Fatalf("unexpected dead letters %+v", dead)
+	}
+	if all, total, _ := s.WebhookDeliveries(w.ID, "", 1, 0); total != 2 || all[0].ID != 2 {
+		t.Fatalf("expected newest first, got %+v (%d)", all, total)
+	}
+
+	if err := s.DeleteWebhook(2, w.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := s.GetWebhookDelivery(1); ok {
+		t.Fatalf("deliveries must go with the webhook")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"math"
 	"strings"
 	"time"
 
@@ -43,6 +42,55 @@ func (s *SQLStore) DeletePost(userID, postID int) error {
 	return nil
 }
 
+func (s *SQLStore) SetPostPromo(postID int, promo domain.PromoSchedule) (domain.Post, error) {
+	if promo.Discount <= 0 {
+		promo = domain.PromoSchedule{}
+	}
+	// as colunas são TIMESTAMP sem fuso: o driver descarta o offset, então grava em UTC
+	promo = promo.UTC()
+	pending := domain.Post{HasPromo: promo.Discount > 0, PromoStartsAt: promo.StartsAt}.PromoScheduledAt(time.Now())
+	res, err := s.db.Exec(`
+		UPDATE posts SET has_promo=$2, discount=$3, promo_starts_at=$4, promo_ends_at=$5, promo_pending=$6
+		WHERE id=$1
+	`, postID, promo.Discount > 0, promo.Discount, promo.StartsAt, promo.EndsAt, pending)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.Post{}, ErrPostNotFound
+	}
+	p, ok := s.GetPost(postID)
+	if !ok {
+		return domain.Post{}, ErrPostNotFound
+	}
+	return p, nil
+}
+
+func (s *SQLStore) StartedPromos(at time.Time) ([]domain.Post, error) {
+	rows, err := s.db.Query(`
+		SELECT
+			id, user_id, date, date_str,
+			product_id, product_name, type, brand, color, notes, image_url,
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
+		FROM posts
+		WHERE promo_pending AND promo_starts_at <= $1
+		ORDER BY id
+	`, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
+
+func (s *SQLStore) MarkPromoAnnounced(postID int) (bool, error) {
+	res, err := s.db.Exec(`UPDATE posts SET promo_pending = FALSE WHERE id = $1 AND promo_pending`, postID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
 func NewSQLStore(dsn string) (*SQLStore, error) {
 	db, err := sql.Open("pgx", dsn)
 	if err != nil {
@@ -153,6 +201,39 @@ func (s *SQLStore) FollowedBy(userID int) ([]domain.User, error) {
 	return out, rows.Err()
 }
 
+func (s *SQLStore) FollowerCounts(sellerIDs []int) (map[int]int, error) {
+	out := make(map[int]int, len(sellerIDs))
+	if len(sellerIDs) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, 0, len(sellerIDs))
+	ph := make([]string, 0, len(sellerIDs))
+	for i, id := range sellerIDs {
+		args = append(args, id)
+		ph = append(ph, fmt.Sprintf("$%d", i+1))
+	}
+	rows, err := s.db.Query(`
+		SELECT seller_id, COUNT(*)
+		FROM follows
+		WHERE seller_id IN (`+strings.Join(ph, ",")+`)
+		GROUP BY seller_id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		out[id] = n
+	}
+	return out, rows.Err()
+}
+
 func (s *SQLStore) AddPost(p domain.Post) (int, error) {
 	if _, ok := s.GetUser(p.UserID); !ok {
 		return 0, ErrUserNotFound
@@ -163,17 +244,18 @@ func (s *SQLStore) AddPost(p domain.Post) (int, error) {
 		INSERT INTO posts (
 			user_id, date, date_str,
 				product_id, product_name, type, brand, color, notes, image_url,
-			category, price, has_promo, discount
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency, promo_pending
 		) VALUES (
 			$1,$2,$3,
 				$4,$5,$6,$7,$8,$9,$10,
-				$11,$12,$13,$14
+				$11,$12,$13,$14,$15,$16,$17,$18
 		)
 		RETURNING id
 	`,
 		p.UserID, p.Date, p.DateStr,
 		p.Product.ProductID, p.Product.ProductName, p.Product.Type, p.Product.Brand, p.Product.Color, p.Product.Notes, p.Product.ImageURL,
-		p.Category, p.Price, p.HasPromo, p.Discount,
+		p.Category, p.Price, p.HasPromo, p.Discount, domain.UTCTime(p.PromoStartsAt), domain.UTCTime(p.PromoEndsAt), postCurrency(p),
+		p.PromoScheduledAt(time.Now()),
 	).Scan(&id)
 
 	if err != nil {
@@ -201,7 +283,7 @@ func (s *SQLStore) PostsFromSellersSince(sellerIDs []int, since time.Time) []dom
 		SELECT
 			id, user_id, date, date_str,
 			product_id, product_name, type, brand, color, notes, image_url,
-			category, price, has_promo, discount
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
 		FROM posts
 		WHERE date >= $1 AND user_id IN (` + strings.Join(ph, ",") + `)
 	`
@@ -210,30 +292,11 @@ func (s *SQLStore) PostsFromSellersSince(sellerIDs []int, since time.Time) []dom
 	if err != nil {
 		return []domain.Post{}
 	}
-	defer rows.Close()
-
-	out := []domain.Post{}
-	for rows.Next() {
-		var p domain.Post
-		var price, discount float64
-		if err := rows.Scan(
-			&p.PostID, &p.UserID, &p.Date, &p.DateStr,
-			&p.Product.ProductID, &p.Product.ProductName, &p.Product.Type, &p.Product.Brand, &p.Product.Color, &p.Product.Notes, &p.Product.ImageURL,
-			&p.Category, &price, &p.HasPromo, &discount,
-		); err != nil {
-			return []domain.Post{}
-		}
-		p.Price = price
-		p.Discount = discount
-		// calcula o final para UI
-		if p.HasPromo {
-			p.FinalPrice = math.Round((p.Price*(1-(p.Discount/100)))*100) / 100
-		} else {
-			p.FinalPrice = p.Price
-		}
-		out = append(out, p)
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return []domain.Post{}
 	}
-	return out
+	return posts
 }
 
 func (s *SQLStore) PromoPostsBySeller(sellerID int) []domain.Post {
@@ -241,32 +304,17 @@ func (s *SQLStore) PromoPostsBySeller(sellerID int) []domain.Post {
 		SELECT
 			id, user_id, date, date_str,
 			product_id, product_name, type, brand, color, notes, image_url,
-			category, price, has_promo, discount
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
 		FROM posts
-		WHERE user_id=$1 AND has_promo=true
-	`, sellerID)
+		WHERE user_id=$1 AND `+sqlPromoActive, sellerID)
 	if err != nil {
 		return []domain.Post{}
 	}
-	defer rows.Close()
-
-	out := []domain.Post{}
-	for rows.Next() {
-		var p domain.Post
-		var price, discount float64
-		if err := rows.Scan(
-			&p.PostID, &p.UserID, &p.Date, &p.DateStr,
-			&p.Product.ProductID, &p.Product.ProductName, &p.Product.Type, &p.Product.Brand, &p.Product.Color, &p.Product.Notes, &p.Product.ImageURL,
-			&p.Category, &price, &p.HasPromo, &discount,
-		); err != nil {
-			return []domain.Post{}
-		}
-		p.Price = price
-		p.Discount = discount
-		p.FinalPrice = math.Round((p.Price*(1-(p.Discount/100)))*100) / 100
-		out = append(out, p)
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return []domain.Post{}
 	}
-	return out
+	return posts
 }
 
 func (s *SQLStore) ListUsers(order string) ([]domain.User, error) {
@@ -390,34 +438,16 @@ func (s *SQLStore) PostsByUser(userID int) []domain.Post {
 		SELECT
 			id, user_id, date, date_str,
 			product_id, product_name, type, brand, color, notes, image_url,
-			category, price, has_promo, discount
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
 		FROM posts
 		WHERE user_id=$1
 	`, userID)
 	if err != nil {
 		return []domain.Post{}
 	}
-	defer rows.Close()
-
-	out := []domain.Post{}
-	for rows.Next() {
-		var p domain.Post
-		var price, discount float64
-		if err := rows.Scan(
-			&p.PostID, &p.UserID, &p.Date, &p.DateStr,
-			&p.Product.ProductID, &p.Product.ProductName, &p.Product.Type, &p.Product.Brand, &p.Product.Color, &p.Product.Notes, &p.Product.ImageURL,
-			&p.Category, &price, &p.HasPromo, &discount,
-		); err != nil {
-			return []domain.Post{}
-		}
-		p.Price = price
-		p.Discount = discount
-		if p.HasPromo {
-			p.FinalPrice = math.Round((p.Price*(1-(p.Discount/100)))*100) / 100
-		} else {
-			p.FinalPrice = p.Price
-		}
-		out = append(out, p)
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return []domain.Post{}
 	}
-	return out
+	return posts
 }
@@ -0,0 +1,200 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *SQLStore) GetPost(id int) (domain.Post, bool) {
+	rows, err := s.db.Query(`
+		SELECT
+			id, user_id, date, date_str,
+			product_id, product_name, type, brand, color, notes, image_url,
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
+		FROM posts
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return domain.Post{}, false
+	}
+	posts, err := scanPosts(rows)
+	if err != nil || len(posts) == 0 {
+		return domain.Post{}, false
+	}
+	return posts[0], true
+}
+
+// commentSelect devolve o comentário já com nome do autor e o flag de vendedor.
+const commentSelect = `
+	SELECT c.id, c.post_id, c.user_id, u.name, COALESCE(c.parent_id, 0), c.text,
+		c.is_answer, c.created_at, c.edited_at, c.user_id = p.user_id
+	FROM comments c
+	JOIN users u ON u.id = c.user_id
+	JOIN posts p ON p.id = c.post_id
+`
+
+func scanComment(row interface{ Scan(...any) error }) (domain.Comment, error) {
+	var c domain.Comment
+	var edited sql.NullTime
+	if err := row.Scan(&c.ID, &c.PostID, &c.UserID, &c.UserName, &c.ParentID, &c.Text,
+		&c.IsAnswer, &c.CreatedAt, &edited, &c.BySeller); err != nil {
+		return domain.Comment{}, err
+	}
+	if edited.Valid {
+		t := edited.Time
+		c.EditedAt = &t
+	}
+	return c, nil
+}
+
+func (s *SQLStore) AddComment(c domain.Comment) (domain.Comment, error) {
+	if err := s.postExists(c.PostID); err != nil {
+		return domain.Comment{}, err
+	}
+	var parent any
+	if c.ParentID != 0 {
+		parent = c.ParentID
+	}
+	var id int
+	err := s.db.QueryRow(`
+		INSERT INTO comments (post_id, user_id, parent_id, text) VALUES ($1, $2, $3, $4) RETURNING id
+	`, c.PostID, c.UserID, parent, c.Text).Scan(&id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	out, ok := s.GetComment(id)
+	if !ok {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	return out, nil
+}
+
+func (s *SQLStore) GetComment(id int) (domain.Comment, bool) {
+	c, err := scanComment(s.db.QueryRow(commentSelect+`WHERE c.id = $1`, id))
+	if err != nil {
+		return domain.Comment{}, false
+	}
+	return c, true
+}
+
+func (s *SQLStore) UpdateComment(id int, text string, editedAt time.Time) (domain.Comment, error) {
+	res, err := s.db.Exec(`UPDATE comments SET text = $1, edited_at = $2 WHERE id = $3`, text, editedAt, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	c, ok := s.GetComment(id)
+	if !ok {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	return c, nil
+}
+
+func (s *SQLStore) DeleteComment(id int) error {
+	// as respostas vão junto pelo ON DELETE CASCADE de parent_id
+	res, err := s.db.Exec(`DELETE FROM comments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) SetCommentAnswer(questionID, answerID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// desmarca antes de marcar por causa do índice único de uma resposta por pergunta
+	if _, err := tx.Exec(`UPDATE comments SET is_answer = FALSE WHERE parent_id = $1 AND is_answer`, questionID); err != nil {
+		return err
+	}
+	if answerID != 0 {
+		res, err := tx.Exec(`UPDATE comments SET is_answer = TRUE WHERE id = $1 AND parent_id = $2`, answerID, questionID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrCommentNotFound
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) Comments(postID int, limit, offset int) ([]domain.Comment, int, error) {
+	if err := s.postExists(postID); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM comments WHERE post_id = $1 AND parent_id IS NULL`, postID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	out := []domain.Comment{}
+	if total == 0 {
+		return out, 0, nil
+	}
+
+	rows, err := s.db.Query(commentSelect+`
+		WHERE c.post_id = $1 AND c.parent_id IS NULL
+		ORDER BY c.id DESC
+		LIMIT $2 OFFSET $3
+	`, postID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	idx := map[int]int{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		idx[c.ID] = len(out)
+		out = append(out, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(out) == 0 {
+		return out, total, nil
+	}
+
+	ph := make([]string, len(out))
+	args := make([]any, len(out))
+	for i, c := range out {
+		ph[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = c.ID
+	}
+	rows, err = s.db.Query(commentSelect+`
+		WHERE c.parent_id IN (`+strings.Join(ph, ",")+`)
+		ORDER BY c.id
+	`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		r, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		q := &out[idx[r.ParentID]]
+		q.Replies = append(q.Replies, r)
+		q.Answered = q.Answered || r.IsAnswer
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
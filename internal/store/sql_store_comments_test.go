@@ -0,0 +1,91 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var commentCols = []string{"id", "post_id", "user_id", "name", "parent_id", "text", "is_answer", "created_at", "edited_at", "by_seller"}
+
+func TestSQLStore_AddComment(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`)).
+		WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO comments (post_id, user_id, parent_id, text) VALUES ($1, $2, $3, $4) RETURNING id`)).
+		WithArgs(7, 2, 3, "Tem sim").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(`FROM comments c\s+JOIN users u ON u.id = c.user_id\s+JOIN posts p ON p.id = c.post_id\s+WHERE c.id = \$1`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows(commentCols).AddRow(4, 7, 2, "Seller", 3, "Tem sim", false, now, nil, true))
+
+	c, err := s.AddComment(domain.Comment{PostID: 7, UserID: 2, ParentID: 3, Text: "Tem sim"})
+	if err != nil || c.ID != 4 || c.UserName != "Seller" || !c.BySeller || c.ParentID != 3 || c.EditedAt != nil {
+		t.Fatalf("unexpected: %+v %v", c, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_Comments(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`)).
+		WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM comments WHERE post_id = $1 AND parent_id IS NULL`)).
+		WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`WHERE c.post_id = \$1 AND c.parent_id IS NULL\s+ORDER BY c.id DESC\s+LIMIT \$2 OFFSET \$3`).
+		WithArgs(7, 2, 0).
+		WillReturnRows(sqlmock.NewRows(commentCols).
+			AddRow(9, 7, 1, "Buyer", 0, "Entrega?", false, now, nil, false).
+			AddRow(5, 7, 1, "Buyer", 0, "Tem 42?", false, now, now, false))
+	mock.ExpectQuery(`WHERE c.parent_id IN \(\$1,\$2\)\s+ORDER BY c.id`).
+		WithArgs(9, 5).
+		WillReturnRows(sqlmock.NewRows(commentCols).
+			AddRow(6, 7, 3, "Other", 5, "Acho que sim", false, now, nil, false).
+			AddRow(8, 7, 2, "Seller", 5, "Tem sim", true, now, nil, true))
+
+	list, total, err := s.Comments(7, 2, 0)
+	if err != nil || total != 3 || len(list) != 2 {
+		t.Fatalf("unexpected: %+v %d %v", list, total, err)
+	}
+	if len(list[0].Replies) != 0 || list[0].Answered {
+		t.Fatalf("unexpected first question %+v", list[0])
+	}
+	q := list[1]
+	if !q.Answered || q.EditedAt == nil || len(q.Replies) != 2 || !q.Replies[1].IsAnswer {
+		t.Fatalf("unexpected thread %+v", q)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_SetCommentAnswer(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE comments SET is_answer = FALSE WHERE parent_id = $1 AND is_answer`)).
+		WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE comments SET is_answer = TRUE WHERE id = $1 AND parent_id = $2`)).
+		WithArgs(8, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	if err := s.SetCommentAnswer(5, 8); err != ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
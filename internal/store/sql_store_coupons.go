@@ -0,0 +1,166 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+
+	"socialmeli/internal/domain"
+)
+
+const couponSelect = `
+	SELECT id, seller_id, code, kind, value, min_order, currency, expires_at, max_uses, max_uses_per_user, uses, created_at
+	FROM coupons
+`
+
+func scanCoupon(row interface{ Scan(...any) error }) (domain.Coupon, error) {
+	var c domain.Coupon
+	var value domain.Money
+	var expires sql.NullTime
+	if err := row.Scan(&c.ID, &c.SellerID, &c.Code, &c.Kind, &value, &c.MinOrder, &c.Currency, &expires,
+		&c.MaxUses, &c.MaxUsesPerUser, &c.Uses, &c.CreatedAt); err != nil {
+		return domain.Coupon{}, err
+	}
+	// value é NUMERIC(12,2) nos dois tipos: lido exato e separado conforme kind
+	if c.Kind == domain.CouponFixed {
+		c.Amount = value
+	} else {
+		c.Percent = value.Float64()
+	}
+	if expires.Valid {
+		t := expires.Time
+		c.ExpiresAt = &t
+	}
+	return c, nil
+}
+
+// couponValue é o que vai para a coluna value: o percentual ou o valor, conforme kind.
+func couponValue(c domain.Coupon) any {
+	if c.Kind == domain.CouponFixed {
+		return c.Amount
+	}
+	return c.Percent
+}
+
+func (s *SQLStore) CreateCoupon(c domain.Coupon) (domain.Coupon, error) {
+	var expires any
+	if c.ExpiresAt != nil {
+		expires = *c.ExpiresAt
+	}
+	err := s.db.QueryRow(`
+		INSERT INTO coupons (seller_id, code, kind, value, min_order, currency, expires_at, max_uses, max_uses_per_user)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING id, created_at
+	`, c.SellerID, c.Code, c.Kind, couponValue(c), c.MinOrder, c.Currency, expires, c.MaxUses, c.MaxUsesPerUser).Scan(&c.ID, &c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Coupon{}, domain.ErrCouponCodeTaken
+	}
+	if err != nil {
+		return domain.Coupon{}, err
+	}
+	c.Uses = 0
+	return c, nil
+}
+
+func (s *SQLStore) GetCoupon(id int) (domain.Coupon, bool) {
+	c, err := scanCoupon(s.db.QueryRow(couponSelect+`WHERE id = $1`, id))
+	return c, err == nil
+}
+
+func (s *SQLStore) CouponByCode(code string) (domain.Coupon, bool) {
+	c, err := scanCoupon(s.db.QueryRow(couponSelect+`WHERE code = $1`, code))
+	return c, err == nil
+}
+
+func (s *SQLStore) CouponsBySeller(sellerID int) ([]domain.Coupon, error) {
+	rows, err := s.db.Query(couponSelect+`WHERE seller_id = $1 ORDER BY id DESC`, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.Coupon{}
+	for rows.Next() {
+		c, err := scanCoupon(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) DeleteCoupon(id int) error {
+	res, err := s.db.Exec(`DELETE FROM coupons WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCouponNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) CouponUsesBy(couponID, userID int) (int, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COALESCE(SUM(uses), 0) FROM coupon_uses WHERE coupon_id = $1 AND user_id = $2`, couponID, userID).Scan(&n)
+	return n, err
+}
+
+func (s *SQLStore) RedeemCoupon(couponID, userID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// o cupom travado serializa os resgates concorrentes
+	var uses, maxUses, maxPerUser int
+	err = tx.QueryRow(`SELECT uses, max_uses, max_uses_per_user FROM coupons WHERE id = $1 FOR UPDATE`, couponID).
+		Scan(&uses, &maxUses, &maxPerUser)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCouponNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if maxUses > 0 && uses >= maxUses {
+		return domain.ErrCouponExhausted
+	}
+	var mine int
+	if err := tx.QueryRow(`SELECT COALESCE(SUM(uses), 0) FROM coupon_uses WHERE coupon_id = $1 AND user_id = $2`, couponID, userID).Scan(&mine); err != nil {
+		return err
+	}
+	if maxPerUser > 0 && mine >= maxPerUser {
+		return domain.ErrCouponUserLimit
+	}
+	if _, err := tx.Exec(`UPDATE coupons SET uses = uses + 1 WHERE id = $1`, couponID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO coupon_uses (coupon_id, user_id, uses) VALUES ($1, $2, 1)
+		ON CONFLICT (coupon_id, user_id) DO UPDATE SET uses = coupon_uses.uses + 1
+	`, couponID, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) ReleaseCoupon(couponID, userID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE coupon_uses SET uses = uses - 1 WHERE coupon_id = $1 AND user_id = $2 AND uses > 0`, couponID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(`UPDATE coupons SET uses = uses - 1 WHERE id = $1 AND uses > 0`, couponID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
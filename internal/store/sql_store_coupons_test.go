@@ -0,0 +1,90 @@
+package store
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_CreateCoupon(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	insert := `INSERT INTO coupons \(seller_id, code, kind, value, min_order, currency, expires_at, max_uses, max_uses_per_user\)\s+VALUES .*\s+ON CONFLICT \(code\) DO NOTHING`
+	mock.ExpectQuery(insert).
+		WithArgs(2, "PROMO10", "percent", 10.0, "50.00", "MXN", nil, 0, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+	mock.ExpectQuery(insert).WillReturnError(sql.ErrNoRows)
+
+	in := domain.Coupon{SellerID: 2, Code: "PROMO10", Kind: domain.CouponPercent, Percent: 10, MinOrder: 50_00, Currency: "MXN", MaxUsesPerUser: 1}
+	c, err := s.CreateCoupon(in)
+	if err != nil || c.ID != 7 || !c.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected: %+v %v", c, err)
+	}
+	if _, err := s.CreateCoupon(in); err != domain.ErrCouponCodeTaken {
+		t.Fatalf("expected ErrCouponCodeTaken, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_RedeemCoupon(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	lock := regexp.QuoteMeta(`SELECT uses, max_uses, max_uses_per_user FROM coupons WHERE id = $1 FOR UPDATE`)
+	mine := regexp.QuoteMeta(`SELECT COALESCE(SUM(uses), 0) FROM coupon_uses WHERE coupon_id = $1 AND user_id = $2`)
+
+	// resgate que passa
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"uses", "max_uses", "max_uses_per_user"}).AddRow(4, 5, 1))
+	mock.ExpectQuery(mine).WithArgs(7, 1).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE coupons SET uses = uses + 1 WHERE id = $1`)).
+		WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO coupon_uses .*\s+ON CONFLICT \(coupon_id, user_id\) DO UPDATE`).
+		WithArgs(7, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// esgotado: nada é gravado
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"uses", "max_uses", "max_uses_per_user"}).AddRow(5, 5, 1))
+	mock.ExpectRollback()
+
+	if err := s.RedeemCoupon(7, 1); err != nil {
+		t.Fatalf("redeem: %v", err)
+	}
+	if err := s.RedeemCoupon(7, 2); err != domain.ErrCouponExhausted {
+		t.Fatalf("expected ErrCouponExhausted, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_ReleaseCoupon(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE coupon_uses SET uses = uses - 1`).
+		WithArgs(7, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE coupons SET uses = uses - 1 WHERE id = $1 AND uses > 0`)).
+		WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := s.ReleaseCoupon(7, 1); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package store
+
+import "socialmeli/internal/domain"
+
+func (s *SQLStore) ExchangeRates() ([]domain.ExchangeRate, error) {
+	rows, err := s.db.Query(`SELECT currency, rate, updated_at FROM exchange_rates ORDER BY currency`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.ExchangeRate{}
+	for rows.Next() {
+		var r domain.ExchangeRate
+		if err := rows.Scan(&r.Currency, &r.Rate, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) SetExchangeRates(rates []domain.ExchangeRate) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, r := range rates {
+		if _, err := tx.Exec(`
+			INSERT INTO exchange_rates (currency, rate, updated_at) VALUES ($1, $2, $3)
+			ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at
+		`, r.Currency, r.Rate, r.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) DeleteExchangeRate(currency string) error {
+	res, err := s.db.Exec(`DELETE FROM exchange_rates WHERE currency = $1`, currency)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrExchangeRateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_ExchangeRates(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	upsert := `INSERT INTO exchange_rates \(currency, rate, updated_at\) VALUES \(\$1, \$2, \$3\)\s+ON CONFLICT \(currency\) DO UPDATE`
+	mock.ExpectExec(upsert).WithArgs("ARS", 0.0055, now).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(upsert).WithArgs("MXN", 0.3, now).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT currency, rate, updated_at FROM exchange_rates ORDER BY currency`)).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "rate", "updated_at"}).
+			AddRow("ARS", "0.00550000", now).
+			AddRow("MXN", "0.30000000", now))
+
+	err := s.SetExchangeRates([]domain.ExchangeRate{{Currency: "ARS", Rate: 0.0055, UpdatedAt: now}, {Currency: "MXN", Rate: 0.3, UpdatedAt: now}})
+	if err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	rates, err := s.ExchangeRates()
+	if err != nil || len(rates) != 2 || rates[0].Rate != 0.0055 || rates[1].Currency != "MXN" {
+		t.Fatalf("unexpected: %+v %v", rates, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_DeleteExchangeRate_NotFound(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM exchange_rates WHERE currency = $1`)).
+		WithArgs("ARS").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := s.DeleteExchangeRate("ARS"); err != ErrExchangeRateNotFound {
+		t.Fatalf("expected ErrExchangeRateNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+func (s *SQLStore) LastDigestAt(userID int) (time.Time, error) {
+	var at time.Time
+	err := s.db.QueryRow(`SELECT sent_at FROM digests WHERE user_id = $1`, userID).Scan(&at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	return at, err
+}
+
+func (s *SQLStore) SetLastDigestAt(userID int, at time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO digests (user_id, sent_at) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET sent_at = EXCLUDED.sent_at
+	`, userID, at)
+	return err
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"socialmeli/internal/domain"
+)
+
+// postExists diferencia "já estava assim" de post inexistente nos comandos idempotentes.
+func (s *SQLStore) postExists(postID int) error {
+	var ok bool
+	if err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, postID).Scan(&ok); err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) LikePost(userID, postID int) (bool, error) {
+	if err := s.postExists(postID); err != nil {
+		return false, err
+	}
+	res, err := s.db.Exec(`INSERT INTO post_likes (post_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, postID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *SQLStore) UnlikePost(userID, postID int) error {
+	if err := s.postExists(postID); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM post_likes WHERE post_id = $1 AND user_id = $2`, postID, userID)
+	return err
+}
+
+func (s *SQLStore) FavoritePost(userID, postID int) error {
+	if err := s.postExists(postID); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`INSERT INTO post_favorites (user_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userID, postID)
+	return err
+}
+
+func (s *SQLStore) UnfavoritePost(userID, postID int) error {
+	if err := s.postExists(postID); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM post_favorites WHERE user_id = $1 AND post_id = $2`, userID, postID)
+	return err
+}
+
+func (s *SQLStore) PostCounts(postIDs []int) (map[int]domain.PostCounts, error) {
+	out := map[int]domain.PostCounts{}
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	ph := make([]string, len(postIDs))
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		ph[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	in := strings.Join(ph, ",")
+	rows, err := s.db.Query(`
+		SELECT post_id, SUM(likes), SUM(favorites) FROM (
+			SELECT post_id, 1 AS likes, 0 AS favorites FROM post_likes WHERE post_id IN (`+in+`)
+			UNION ALL
+			SELECT post_id, 0, 1 FROM post_favorites WHERE post_id IN (`+in+`)
+		) t
+		GROUP BY post_id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var c domain.PostCounts
+		if err := rows.Scan(&id, &c.Likes, &c.Favorites); err != nil {
+			return nil, err
+		}
+		out[id] = c
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) Favorites(userID int, limit, offset int) ([]domain.Post, int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM post_favorites WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := s.db.Query(`
+		SELECT
+			p.id, p.user_id, p.date, p.date_str,
+			p.product_id, p.product_name, p.type, p.brand, p.color, p.notes, p.image_url,
+			p.category, p.price, p.has_promo, p.discount, p.promo_starts_at, p.promo_ends_at, p.currency
+		FROM post_favorites f
+		JOIN posts p ON p.id = f.post_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC, p.id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, err := scanPosts(rows)
+	return posts, total, err
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_LikePost(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	exists := regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`)
+	mock.ExpectQuery(exists).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_likes (post_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
+		WithArgs(7, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(exists).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_likes`)).
+		WithArgs(7, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(exists).WithArgs(8).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	if added, err := s.LikePost(1, 7); err != nil || !added {
+		t.Fatalf("like: %v %v", added, err)
+	}
+	if added, err := s.LikePost(1, 7); err != nil || added {
+		t.Fatalf("repeated like must not count as new: %v %v", added, err)
+	}
+	if _, err := s.LikePost(1, 8); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_PostCounts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT post_id, SUM\(likes\), SUM\(favorites\) FROM \(\s+SELECT post_id, 1 AS likes, 0 AS favorites FROM post_likes WHERE post_id IN \(\$1,\$2\)\s+UNION ALL\s+SELECT post_id, 0, 1 FROM post_favorites WHERE post_id IN \(\$1,\$2\)\s+\) t\s+GROUP BY post_id`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "likes", "favorites"}).AddRow(1, 3, 1))
+
+	counts, err := s.PostCounts([]int{1, 2})
+	if err != nil || counts[1] != (domain.PostCounts{Likes: 3, Favorites: 1}) || len(counts) != 1 {
+		t.Fatalf("unexpected: %v %v", counts, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_Favorites(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM post_favorites WHERE user_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery(`FROM post_favorites f\s+JOIN posts p ON p.id = f.post_id\s+WHERE f.user_id = \$1\s+ORDER BY f.created_at DESC, p.id DESC\s+LIMIT \$2 OFFSET \$3`).
+		WithArgs(1, 1, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "date", "date_str", "product_id", "product_name", "type", "brand", "color", "notes", "image_url", "category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency"}).
+			AddRow(5, 2, date, "01-01-2026", 1, "Tênis", "calcado", "Nike", "Preto", "", "", 1, 100.0, false, 0.0, nil, nil, "BRL"))
+
+	posts, total, err := s.Favorites(1, 1, 0)
+	if err != nil || total != 4 || len(posts) != 1 || posts[0].PostID != 5 {
+		t.Fatalf("unexpected: %+v %d %v", posts, total, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
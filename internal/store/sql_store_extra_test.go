@@ -232,14 +232,14 @@ func TestSQLStore_PostsByUser_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "user_id", "date", "date_str",
 		"product_id", "product_name", "type", "brand", "color", "notes", "image_url",
-		"category", "price", "has_promo", "discount",
+		"category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency",
 	}).AddRow(
 		1, 1, now, "01-01-2026",
 		10, "Product", "type", "brand", "color", "notes", "",
-		1, 100.0, false, 0.0,
+		1, 100.0, false, 0.0, nil, nil, "BRL",
 	)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, date, date_str, product_id, product_name, type, brand, color, notes, image_url, category, price, has_promo, discount FROM posts WHERE user_id=$1`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, date, date_str, product_id, product_name, type, brand, color, notes, image_url, category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency FROM posts WHERE user_id=$1`)).
 		WithArgs(1).
 		WillReturnRows(rows)
 
@@ -252,3 +252,20 @@ func TestSQLStore_PostsByUser_Success(t *testing.T) {
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestSQLStore_FollowerCounts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT seller_id, COUNT\(\*\)\s+FROM follows\s+WHERE seller_id IN \(\$1,\$2\)\s+GROUP BY seller_id`).
+		WithArgs(2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"seller_id", "count"}).AddRow(2, 5))
+
+	counts, err := s.FollowerCounts([]int{2, 3})
+	if err != nil || counts[2] != 5 || counts[3] != 0 {
+		t.Fatalf("unexpected: %v %v", err, counts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
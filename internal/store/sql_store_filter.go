@@ -0,0 +1,199 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+// sqlPromoActive replica Post.PromoActiveAt com o relógio do banco. A janela é
+// gravada em UTC numa coluna sem fuso, então NOW() também é levado para UTC (senão o
+// resultado dependeria do TimeZone da sessão).
+const sqlPromoActive = `(has_promo AND (promo_starts_at IS NULL OR promo_starts_at <= ` + sqlNowUTC + `) AND (promo_ends_at IS NULL OR promo_ends_at > ` + sqlNowUTC + `))`
+
+const sqlNowUTC = `(NOW() AT TIME ZONE 'UTC')`
+
+// sqlFinalPrice replica domain.FinalPrice (desconto só vale em promoção, 2 casas).
+const sqlFinalPrice = `(CASE WHEN ` + sqlPromoActive + ` THEN ROUND(price * (1 - discount / 100), 2) ELSE price END)`
+
+// postWhere monta o WHERE de uma PostQuery com placeholders posicionais.
+// Sem SellerIDs não restringe vendedor (quem chama decide se isso faz sentido).
+func postWhere(q domain.PostQuery) (string, []any) {
+	var conds []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	in := func(col string, vals []string) {
+		ph := make([]string, len(vals))
+		for i, v := range vals {
+			ph[i] = arg(strings.ToLower(v))
+		}
+		conds = append(conds, "LOWER("+col+") IN ("+strings.Join(ph, ",")+")")
+	}
+
+	if len(q.SellerIDs) > 0 {
+		ph := make([]string, len(q.SellerIDs))
+		for i, id := range q.SellerIDs {
+			ph[i] = arg(id)
+		}
+		conds = append(conds, "user_id IN ("+strings.Join(ph, ",")+")")
+	}
+	if !q.Since.IsZero() {
+		conds = append(conds, "date >= "+arg(q.Since))
+	}
+
+	f := q.Filter
+	if len(f.Categories) > 0 {
+		ph := make([]string, len(f.Categories))
+		for i, c := range f.Categories {
+			ph[i] = arg(c)
+		}
+		conds = append(conds, "category IN ("+strings.Join(ph, ",")+")")
+	}
+	if len(f.Brands) > 0 {
+		in("brand", f.Brands)
+	}
+	if len(f.Types) > 0 {
+		in("type", f.Types)
+	}
+	if len(f.Colors) > 0 {
+		in("color", f.Colors)
+	}
+	if f.MinPrice != nil {
+		conds = append(conds, sqlFinalPrice+" >= "+arg(*f.MinPrice))
+	}
+	if f.MaxPrice != nil {
+		conds = append(conds, sqlFinalPrice+" <= "+arg(*f.MaxPrice))
+	}
+	if f.PromoOnly {
+		conds = append(conds, sqlPromoActive)
+	}
+	if len(conds) == 0 {
+		return "TRUE", args
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func (s *SQLStore) QueryPosts(q domain.PostQuery) ([]domain.Post, error) {
+	if len(q.SellerIDs) == 0 {
+		return []domain.Post{}, nil
+	}
+	where, args := postWhere(q)
+	rows, err := s.db.Query(`
+		SELECT
+			id, user_id, date, date_str,
+			product_id, product_name, type, brand, color, notes, image_url,
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
+		FROM posts
+		WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
+
+// scanPosts lê as colunas na ordem do SELECT de QueryPosts e fecha rows.
+// HasPromo e FinalPrice saem avaliados agora (a janela da promoção decide).
+func scanPosts(rows *sql.Rows) ([]domain.Post, error) {
+	defer rows.Close()
+
+	out := []domain.Post{}
+	now := time.Now()
+	for rows.Next() {
+		var p domain.Post
+		var starts, ends sql.NullTime
+		if err := rows.Scan(
+			&p.PostID, &p.UserID, &p.Date, &p.DateStr,
+			&p.Product.ProductID, &p.Product.ProductName, &p.Product.Type, &p.Product.Brand, &p.Product.Color, &p.Product.Notes, &p.Product.ImageURL,
+			&p.Category, &p.Price, &p.HasPromo, &p.Discount, &starts, &ends, &p.Currency,
+		); err != nil {
+			return nil, err
+		}
+		if starts.Valid {
+			p.PromoStartsAt = &starts.Time
+		}
+		if ends.Valid {
+			p.PromoEndsAt = &ends.Time
+		}
+		out = append(out, p.AtTime(now))
+	}
+	return out, rows.Err()
+}
+
+// postCurrency é a moeda gravada do post (vazia = moeda base, como no MemoryStore).
+func postCurrency(p domain.Post) string {
+	if p.Currency == "" {
+		return domain.BaseCurrency
+	}
+	return p.Currency
+}
+
+// sqlPriceBucket numera as faixas de domain.PriceBucketEdges (0 = abaixo da primeira).
+func sqlPriceBucket() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for i, edge := range domain.PriceBucketEdges {
+		fmt.Fprintf(&b, " WHEN %s < %s THEN %d", sqlFinalPrice, edge, i)
+	}
+	fmt.Fprintf(&b, " ELSE %d END", len(domain.PriceBucketEdges))
+	return b.String()
+}
+
+func (s *SQLStore) PostFacets(q domain.PostQuery) (domain.PostFacets, error) {
+	f := domain.PostFacets{Brands: []domain.FacetCount{}, Categories: []domain.CategoryFacet{}}
+	if len(q.SellerIDs) == 0 {
+		f.Prices = domain.NewPriceBuckets(nil)
+		return f, nil
+	}
+	where, args := postWhere(q)
+
+	rows, err := s.db.Query(`SELECT brand, COUNT(*) FROM posts WHERE `+where+` GROUP BY brand`, args...)
+	if err != nil {
+		return domain.PostFacets{}, err
+	}
+	for rows.Next() {
+		var fc domain.FacetCount
+		if err := rows.Scan(&fc.Value, &fc.Count); err != nil {
+			rows.Close()
+			return domain.PostFacets{}, err
+		}
+		f.Brands = append(f.Brands, fc)
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(`SELECT category, COUNT(*) FROM posts WHERE `+where+` GROUP BY category`, args...)
+	if err != nil {
+		return domain.PostFacets{}, err
+	}
+	for rows.Next() {
+		var cf domain.CategoryFacet
+		if err := rows.Scan(&cf.Category, &cf.Count); err != nil {
+			rows.Close()
+			return domain.PostFacets{}, err
+		}
+		f.Categories = append(f.Categories, cf)
+	}
+	rows.Close()
+
+	rows, err = s.db.Query(`SELECT `+sqlPriceBucket()+` AS bucket, COUNT(*) FROM posts WHERE `+where+` GROUP BY bucket`, args...)
+	if err != nil {
+		return domain.PostFacets{}, err
+	}
+	defer rows.Close()
+	counts := map[int]int{}
+	for rows.Next() {
+		var bucket, n int
+		if err := rows.Scan(&bucket, &n); err != nil {
+			return domain.PostFacets{}, err
+		}
+		counts[bucket] = n
+	}
+	f.Prices = domain.NewPriceBuckets(counts)
+	domain.SortFacets(&f)
+	return f, rows.Err()
+}
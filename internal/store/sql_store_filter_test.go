@@ -0,0 +1,193 @@
+package store
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPostWhere(t *testing.T) {
+	min := domain.Money(10_00)
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	where, args := postWhere(domain.PostQuery{
+		SellerIDs: []int{2, 3},
+		Since:     since,
+		Filter:    domain.PostFilter{Categories: []int{1}, Brands: []string{"Nike"}, MinPrice: &min, PromoOnly: true},
+	})
+	want := "user_id IN ($1,$2) AND date >= $3 AND category IN ($4) AND LOWER(brand) IN ($5) AND " +
+		sqlFinalPrice + " >= $6 AND " + sqlPromoActive
+	if where != want {
+		t.Fatalf("where = %s\nwant   %s", where, want)
+	}
+	if len(args) != 6 || args[4] != "nike" || args[5] != domain.Money(10_00) {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestSQLStore_QueryPosts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	cols := []string{"id", "user_id", "date", "date_str", "product_id", "product_name", "type", "brand", "color", "notes", "image_url", "category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency"}
+	mock.ExpectQuery(`FROM posts\s+WHERE user_id IN \(\$1\) AND LOWER\(color\) IN \(\$2\)`).
+		WithArgs(2, "preto").
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(1, 2, time.Now(), "01-01-2026", 1, "Tênis", "calcado", "Nike", "Preto", "", "", 1, 100.0, false, 0.0, nil, nil, "BRL"))
+
+	posts, err := s.QueryPosts(domain.PostQuery{SellerIDs: []int{2}, Filter: domain.PostFilter{Colors: []string{"Preto"}}})
+	if err != nil || len(posts) != 1 || posts[0].FinalPrice != 100_00 {
+		t.Fatalf("unexpected: %v %+v", err, posts)
+	}
+
+	// sem vendedores nem vai ao banco
+	posts, err = s.QueryPosts(domain.PostQuery{})
+	if err != nil || len(posts) != 0 {
+		t.Fatalf("unexpected: %v %+v", err, posts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_PostFacets(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT brand, COUNT\(\*\) FROM posts WHERE user_id IN \(\$1\) GROUP BY brand`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"brand", "count"}).AddRow("Nike", 1).AddRow("Adidas", 3))
+	mock.ExpectQuery(`SELECT category, COUNT\(\*\) FROM posts WHERE user_id IN \(\$1\) GROUP BY category`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"category", "count"}).AddRow(1, 4))
+	mock.ExpectQuery(`SELECT CASE WHEN .* END AS bucket, COUNT\(\*\) FROM posts WHERE user_id IN \(\$1\) GROUP BY bucket`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(0, 3).AddRow(5, 1))
+
+	f, err := s.PostFacets(domain.PostQuery{SellerIDs: []int{2}})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if f.Brands[0].Value != "Adidas" || f.Categories[0].Count != 4 {
+		t.Fatalf("unexpected facets: %+v", f)
+	}
+	if len(f.Prices) != 6 || f.Prices[0].Count != 3 || f.Prices[5].Count != 1 {
+		t.Fatalf("unexpected prices: %+v", f.Prices)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_QueryPosts_PromoWindow(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	ended := time.Now().Add(-time.Hour)
+	cols := []string{"id", "user_id", "date", "date_str", "product_id", "product_name", "type", "brand", "color", "notes", "image_url", "category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency"}
+	mock.ExpectQuery(`FROM posts\s+WHERE user_id IN \(\$1\)`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(1, 2, time.Now(), "01-01-2026", 1, "Tênis", "calcado", "Nike", "Preto", "", "", 1, 100.0, true, 10.0, nil, ended, "BRL"))
+
+	posts, err := s.QueryPosts(domain.PostQuery{SellerIDs: []int{2}})
+	if err != nil || len(posts) != 1 || posts[0].HasPromo || posts[0].FinalPrice != 100_00 || posts[0].PromoEndsAt == nil {
+		t.Fatalf("ended promo must read as full price: %v %+v", err, posts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_SetPostPromo(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	ends := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	update := `UPDATE posts SET has_promo=\$2, discount=\$3, promo_starts_at=\$4, promo_ends_at=\$5, promo_pending=\$6\s+WHERE id=\$1`
+	mock.ExpectExec(update).
+		WithArgs(9, true, 15.0, nil, &ends, false).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if _, err := s.SetPostPromo(9, domain.PromoSchedule{Discount: 15, EndsAt: &ends}); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_PromoWindowStoredInUTC(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	// 10h em São Paulo são 13h UTC; gravar 10:00 sem o offset erraria por 3h
+	brt := time.FixedZone("BRT", -3*60*60)
+	starts := time.Date(2036, 3, 1, 10, 0, 0, 0, brt)
+	ends := time.Date(2036, 3, 2, 22, 0, 0, 0, brt)
+	wantStarts := time.Date(2036, 3, 1, 13, 0, 0, 0, time.UTC)
+	wantEnds := time.Date(2036, 3, 3, 1, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE posts SET has_promo=\$2`).
+		WithArgs(9, true, 15.0, wantStarts, wantEnds, true).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, is_seller FROM users WHERE id=$1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_seller"}).AddRow(2, "Seller", true))
+	mock.ExpectQuery(`INSERT INTO posts`).
+		WithArgs(2, sqlmock.AnyArg(), "", 0, "", "", "", "", "", "", 0, "100.00", true, 15.0, wantStarts, wantEnds, "BRL", true).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+
+	if _, err := s.SetPostPromo(9, domain.PromoSchedule{Discount: 15, StartsAt: &starts, EndsAt: &ends}); err != ErrPostNotFound {
+		t.Fatalf("expected ErrPostNotFound, got %v", err)
+	}
+	if _, err := s.AddPost(domain.Post{UserID: 2, Date: time.Now(), Price: 100_00, HasPromo: true, Discount: 15, PromoStartsAt: &starts, PromoEndsAt: &ends}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if starts.Location() != brt {
+		t.Fatalf("caller's time must not be modified")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLPromoActive_UsesUTCClock(t *testing.T) {
+	if strings.Count(sqlPromoActive, "NOW() AT TIME ZONE 'UTC'") != 2 || strings.Count(sqlPromoActive, "NOW()") != 2 {
+		t.Fatalf("promo window must be compared with the UTC clock: %s", sqlPromoActive)
+	}
+}
+
+func TestSQLStore_StartedPromos(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	brt := time.FixedZone("BRT", -3*60*60)
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, brt)
+	starts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cols := []string{"id", "user_id", "date", "date_str", "product_id", "product_name", "type", "brand", "color", "notes", "image_url", "category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency"}
+	mock.ExpectQuery(`FROM posts\s+WHERE promo_pending AND promo_starts_at <= \$1\s+ORDER BY id`).
+		WithArgs(at.UTC()).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(7, 2, starts, "01-03-2026", 1, "Tênis", "t", "b", "c", "", "", 1, "100.00", true, 10.0, starts, nil, "BRL"))
+	update := regexp.QuoteMeta(`UPDATE posts SET promo_pending = FALSE WHERE id = $1 AND promo_pending`)
+	mock.ExpectExec(update).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	posts, err := s.StartedPromos(at)
+	if err != nil || len(posts) != 1 || posts[0].PostID != 7 {
+		t.Fatalf("unexpected: %+v %v", posts, err)
+	}
+	if ok, err := s.MarkPromoAnnounced(7); err != nil || !ok {
+		t.Fatalf("first mark: %v %v", ok, err)
+	}
+	if ok, err := s.MarkPromoAnnounced(7); err != nil || ok {
+		t.Fatalf("second mark must lose: %v %v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+// notificationBatch limita as linhas por INSERT (6 parâmetros cada).
+const notificationBatch = 500
+
+func (s *SQLStore) AddNotifications(ns []domain.Notification) ([]domain.Notification, error) {
+	now := time.Now().UTC()
+	out := make([]domain.Notification, 0, len(ns))
+	for len(ns) > 0 {
+		n := len(ns)
+		if n > notificationBatch {
+			n = notificationBatch
+		}
+		rows := make([]string, n)
+		args := make([]any, 0, n*6)
+		for i, nt := range ns[:n] {
+			var postID any
+			if nt.PostID > 0 {
+				postID = nt.PostID
+			}
+			created := nt.CreatedAt
+			if created.IsZero() {
+				created = now
+			}
+			b := len(args)
+			rows[i] = fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d)", b+1, b+2, b+3, b+4, b+5, b+6)
+			args = append(args, nt.UserID, nt.Type, nt.ActorID, postID, nt.Message, created)
+		}
+		// o RETURNING de um INSERT com vários VALUES segue a ordem das linhas
+		rs, err := s.db.Query(`
+			INSERT INTO notifications (user_id, type, actor_id, post_id, message, created_at)
+			VALUES `+strings.Join(rows, ",")+`
+			RETURNING id, created_at`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, nt := range ns[:n] {
+			if !rs.Next() {
+				rs.Close()
+				if err := rs.Err(); err != nil {
+					return nil, err
+				}
+				return nil, fmt.Errorf("notificações: esperava %d linhas do RETURNING", n)
+			}
+			if err := rs.Scan(&nt.ID, &nt.CreatedAt); err != nil {
+				rs.Close()
+				return nil, err
+			}
+			nt.CreatedAt = nt.CreatedAt.UTC()
+			out = append(out, nt)
+		}
+		rs.Close()
+		if err := rs.Err(); err != nil {
+			return nil, err
+		}
+		ns = ns[n:]
+	}
+	return out, nil
+}
+
+func (s *SQLStore) Notifications(userID int, unreadOnly bool, limit, offset int) ([]domain.Notification, int, error) {
+	where := `user_id = $1`
+	if unreadOnly {
+		where += ` AND read_at IS NULL`
+	}
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE `+where, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, user_id, type, actor_id, post_id, message, read_at IS NOT NULL, created_at
+		FROM notifications
+		WHERE `+where+`
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := []domain.Notification{}
+	for rows.Next() {
+		var n domain.Notification
+		var postID sql.NullInt64
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.ActorID, &postID, &n.Message, &n.Read, &n.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		n.PostID = int(postID.Int64)
+		out = append(out, n)
+	}
+	return out, total, rows.Err()
+}
+
+func (s *SQLStore) UnreadNotifications(userID int) (int, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&n)
+	return n, err
+}
+
+func (s *SQLStore) MarkNotificationsRead(userID int, ids []int) (int, error) {
+	args := []any{userID}
+	where := `user_id = $1 AND read_at IS NULL`
+	if len(ids) > 0 {
+		ph := make([]string, len(ids))
+		for i, id := range ids {
+			args = append(args, id)
+			ph[i] = fmt.Sprintf("$%d", len(args))
+		}
+		where += ` AND id IN (` + strings.Join(ph, ",") + `)`
+	}
+	res, err := s.db.Exec(`UPDATE notifications SET read_at = NOW() WHERE `+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *SQLStore) NotificationPrefs(userID int) (domain.NotificationPrefs, error) {
+	rows, err := s.db.Query(`SELECT type, enabled FROM notification_prefs WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := domain.NotificationPrefs{}
+	for rows.Next() {
+		var t string
+		var enabled bool
+		if err := rows.Scan(&t, &enabled); err != nil {
+			return nil, err
+		}
+		out[t] = enabled
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) SetNotificationPrefs(userID int, prefs domain.NotificationPrefs) error {
+	if _, ok := s.GetUser(userID); !ok {
+		return ErrUserNotFound
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for t, enabled := range prefs {
+		if _, err := tx.Exec(`
+			INSERT INTO notification_prefs (user_id, type, enabled)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled
+		`, userID, t, enabled); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) NotificationOptOuts(typ string, userIDs []int) (map[int]bool, error) {
+	out := map[int]bool{}
+	for len(userIDs) > 0 {
+		n := len(userIDs)
+		if n > notificationBatch {
+			n = notificationBatch
+		}
+		args := []any{typ}
+		ph := make([]string, n)
+		for i, id := range userIDs[:n] {
+			args = append(args, id)
+			ph[i] = fmt.Sprintf("$%d", len(args))
+		}
+		if err := s.collectOptOuts(out, `
+			SELECT user_id FROM notification_prefs
+			WHERE type = $1 AND NOT enabled AND user_id IN (`+strings.Join(ph, ",")+`)
+		`, args); err != nil {
+			return nil, err
+		}
+		userIDs = userIDs[n:]
+	}
+	return out, nil
+}
+
+func (s *SQLStore) collectOptOuts(out map[int]bool, query string, args []any) error {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		out[id] = true
+	}
+	return rows.Err()
+}
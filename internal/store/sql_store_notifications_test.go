@@ -0,0 +1,120 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_AddNotifications(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO notifications \(user_id, type, actor_id, post_id, message, created_at\)\s+VALUES \(\$1,\$2,\$3,\$4,\$5,\$6\),\(\$7,\$8,\$9,\$10,\$11,\$12\)\s+RETURNING id, created_at`).
+		WithArgs(1, "promo_started", 2, 7, "promo", at, 3, "promo_started", 2, nil, "promo", at).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(41, at).AddRow(42, at))
+
+	added, err := s.AddNotifications([]domain.Notification{
+		{UserID: 1, Type: domain.NotificationPromoStarted, ActorID: 2, PostID: 7, Message: "promo", CreatedAt: at},
+		{UserID: 3, Type: domain.NotificationPromoStarted, ActorID: 2, Message: "promo", CreatedAt: at},
+	})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if len(added) != 2 || added[0].ID != 41 || added[1].ID != 42 || added[1].UserID != 3 || !added[0].CreatedAt.Equal(at) {
+		t.Fatalf("expected stored rows back, got %+v", added)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_Notifications(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectQuery(`FROM notifications\s+WHERE user_id = \$1 AND read_at IS NULL\s+ORDER BY id DESC\s+LIMIT \$2 OFFSET \$3`).
+		WithArgs(1, 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "actor_id", "post_id", "message", "read", "created_at"}).
+			AddRow(9, 1, "promo_started", 2, 7, "promo", false, at).
+			AddRow(8, 1, "new_follower", 3, nil, "follow", false, at))
+
+	items, total, err := s.Notifications(1, true, 2, 0)
+	if err != nil || total != 5 || len(items) != 2 || items[0].PostID != 7 || items[1].PostID != 0 {
+		t.Fatalf("unexpected: %v %d %+v", err, total, items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_MarkNotificationsRead(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL AND id IN ($2,$3)`)).
+		WithArgs(1, 4, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if n, err := s.MarkNotificationsRead(1, []int{4, 5}); err != nil || n != 2 {
+		t.Fatalf("mark ids: %d %v", n, err)
+	}
+	if n, err := s.MarkNotificationsRead(1, nil); err != nil || n != 3 {
+		t.Fatalf("mark all: %d %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_NotificationOptOuts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT user_id FROM notification_prefs\s+WHERE type = \$1 AND NOT enabled AND user_id IN \(\$2,\$3\)`).
+		WithArgs("promo_started", 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+
+	out, err := s.NotificationOptOuts(domain.NotificationPromoStarted, []int{1, 2})
+	if err != nil || len(out) != 1 || !out[2] {
+		t.Fatalf("unexpected: %v %v", out, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_LastDigestAt(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT sent_at FROM digests WHERE user_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"sent_at"}))
+	mock.ExpectExec(`INSERT INTO digests \(user_id, sent_at\) VALUES \(\$1, \$2\)\s+ON CONFLICT \(user_id\) DO UPDATE`).
+		WithArgs(1, at).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if got, err := s.LastDigestAt(1); err != nil || !got.IsZero() {
+		t.Fatalf("expected never sent, got %v %v", got, err)
+	}
+	if err := s.SetLastDigestAt(1, at); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
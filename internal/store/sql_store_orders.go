@@ -0,0 +1,269 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *SQLStore) CartLines(userID int) ([]domain.CartLine, error) {
+	rows, err := s.db.Query(`
+		SELECT post_id, quantity, added_at FROM cart_items
+		WHERE user_id = $1
+		ORDER BY added_at, post_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.CartLine{}
+	for rows.Next() {
+		var l domain.CartLine
+		if err := rows.Scan(&l.PostID, &l.Quantity, &l.AddedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) SetCartQuantity(userID, postID, quantity int) error {
+	if err := s.postExists(postID); err != nil {
+		return err
+	}
+	if quantity <= 0 {
+		_, err := s.db.Exec(`DELETE FROM cart_items WHERE user_id = $1 AND post_id = $2`, userID, postID)
+		return err
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO cart_items (user_id, post_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, post_id) DO UPDATE SET quantity = EXCLUDED.quantity
+	`, userID, postID, quantity)
+	return err
+}
+
+func (s *SQLStore) PlaceOrders(buyerID int, orders []domain.Order) ([]domain.Order, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// esvaziar primeiro trava as linhas: um checkout concorrente espera este
+	// terminar e encontra o carrinho vazio
+	rows, err := tx.Query(`DELETE FROM cart_items WHERE user_id = $1 RETURNING post_id, quantity`, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	cart := map[int]int{}
+	for rows.Next() {
+		var postID, quantity int
+		if err := rows.Scan(&postID, &quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		cart[postID] = quantity
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := checkCartUnchanged(cart, checkoutLines(orders)); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Order, len(orders))
+	for i, o := range orders {
+		o.BuyerID = buyerID
+		err := tx.QueryRow(`
+			INSERT INTO orders (buyer_id, seller_id, status, subtotal, coupon_code, discount, total, currency)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at, updated_at
+		`, buyerID, o.SellerID, o.Status, o.Subtotal, o.CouponCode, o.Discount, o.Total, o.Currency).Scan(&o.ID, &o.CreatedAt, &o.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		for line, it := range o.Items {
+			_, err := tx.Exec(`
+				INSERT INTO order_items (
+					order_id, line, post_id, product_id, product_name, type, brand, color, notes, image_url,
+					price, has_promo, discount, unit_price, quantity, subtotal
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			`, o.ID, line, it.PostID, it.Product.ProductID, it.Product.ProductName, it.Product.Type, it.Product.Brand,
+				it.Product.Color, it.Product.Notes, it.Product.ImageURL,
+				it.Price, it.HasPromo, it.Discount, it.UnitPrice, it.Quantity, it.Subtotal)
+			if err != nil {
+				return nil, err
+			}
+		}
+		out[i] = o
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const orderSelect = `
+	SELECT id, buyer_id, seller_id, status, subtotal, coupon_code, discount, total, currency, payment_id, payment_status, created_at, updated_at
+	FROM orders
+`
+
+func (s *SQLStore) GetOrder(id int) (domain.Order, bool) {
+	orders, err := s.queryOrders(orderSelect+`WHERE id = $1`, id)
+	if err != nil || len(orders) == 0 {
+		return domain.Order{}, false
+	}
+	return orders[0], true
+}
+
+func (s *SQLStore) UpdateOrderStatus(id int, from, to string, at time.Time) (domain.Order, error) {
+	res, err := s.db.Exec(`UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`, to, at, id, from)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, ok := s.GetOrder(id); !ok {
+			return domain.Order{}, ErrOrderNotFound
+		}
+		return domain.Order{}, domain.ErrOrderTransition
+	}
+	o, ok := s.GetOrder(id)
+	if !ok {
+		return domain.Order{}, ErrOrderNotFound
+	}
+	return o, nil
+}
+
+func (s *SQLStore) Orders(f domain.OrderFilter, limit, offset int) ([]domain.Order, int, error) {
+	var conds []string
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.BuyerID != 0 {
+		add("buyer_id = $%d", f.BuyerID)
+	}
+	if f.SellerID != 0 {
+		add("seller_id = $%d", f.SellerID)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM orders `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []domain.Order{}, 0, nil
+	}
+	args = append(args, limit, offset)
+	orders, err := s.queryOrders(orderSelect+where+fmt.Sprintf(` ORDER BY id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+func (s *SQLStore) SetOrderPayment(orderID int, paymentID, status string) (domain.Order, error) {
+	res, err := s.db.Exec(`UPDATE orders SET payment_id = $1, payment_status = $2, updated_at = NOW() WHERE id = $3`, paymentID, status, orderID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.Order{}, ErrOrderNotFound
+	}
+	o, ok := s.GetOrder(orderID)
+	if !ok {
+		return domain.Order{}, ErrOrderNotFound
+	}
+	return o, nil
+}
+
+func (s *SQLStore) OrderByPayment(paymentID string) (domain.Order, bool) {
+	orders, err := s.queryOrders(orderSelect+`WHERE payment_id = $1`, paymentID)
+	if err != nil || len(orders) == 0 {
+		return domain.Order{}, false
+	}
+	return orders[0], true
+}
+
+// ClaimPaymentEvent: a chave primária decide quem fica com o evento; entregas
+// simultâneas do mesmo evento veem 0 linhas afetadas.
+func (s *SQLStore) ClaimPaymentEvent(eventID string) (bool, error) {
+	res, err := s.db.Exec(`INSERT INTO payment_events (event_id) VALUES ($1) ON CONFLICT DO NOTHING`, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *SQLStore) ReleasePaymentEvent(eventID string) error {
+	_, err := s.db.Exec(`DELETE FROM payment_events WHERE event_id = $1`, eventID)
+	return err
+}
+
+// queryOrders roda a consulta de pedidos e carrega os itens de todos numa segunda consulta.
+func (s *SQLStore) queryOrders(query string, args ...any) ([]domain.Order, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	out := []domain.Order{}
+	idx := map[int]int{}
+	for rows.Next() {
+		var o domain.Order
+		if err := rows.Scan(&o.ID, &o.BuyerID, &o.SellerID, &o.Status, &o.Subtotal, &o.CouponCode, &o.Discount, &o.Total, &o.Currency, &o.PaymentID, &o.PaymentStatus, &o.CreatedAt, &o.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		idx[o.ID] = len(out)
+		out = append(out, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	ph := make([]string, len(out))
+	ids := make([]any, len(out))
+	for i, o := range out {
+		ph[i] = fmt.Sprintf("$%d", i+1)
+		ids[i] = o.ID
+	}
+	rows, err = s.db.Query(`
+		SELECT order_id, post_id, product_id, product_name, type, brand, color, notes, image_url,
+			price, has_promo, discount, unit_price, quantity, subtotal
+		FROM order_items
+		WHERE order_id IN (`+strings.Join(ph, ",")+`)
+		ORDER BY order_id, line
+	`, ids...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var orderID int
+		var it domain.OrderItem
+		if err := rows.Scan(&orderID, &it.PostID, &it.Product.ProductID, &it.Product.ProductName, &it.Product.Type,
+			&it.Product.Brand, &it.Product.Color, &it.Product.Notes, &it.Product.ImageURL,
+			&it.Price, &it.HasPromo, &it.Discount, &it.UnitPrice, &it.Quantity, &it.Subtotal); err != nil {
+			return nil, err
+		}
+		o := &out[idx[orderID]]
+		o.Items = append(o.Items, it)
+	}
+	return out, rows.Err()
+}
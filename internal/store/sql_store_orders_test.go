@@ -0,0 +1,144 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_PlaceOrders(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	deleteCart := regexp.QuoteMeta(`DELETE FROM cart_items WHERE user_id = $1 RETURNING post_id, quantity`)
+	mock.ExpectBegin()
+	mock.ExpectQuery(deleteCart).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "quantity"}).AddRow(5, 2))
+	mock.ExpectQuery(`INSERT INTO orders \(buyer_id, seller_id, status, subtotal, coupon_code, discount, total, currency\)\s+VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)\s+RETURNING id, created_at, updated_at`).
+		WithArgs(1, 2, domain.OrderPending, "200.00", "DEZ", "20.00", "180.00", "MXN").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(10, now, now))
+	mock.ExpectExec(`INSERT INTO order_items`).
+		WithArgs(10, 0, 5, 1, "Tênis", "calcado", "Nike", "Preto", "", "", "100.00", true, 10.0, "100.00", 2, "200.00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// carrinho ganhou uma linha depois de precificado: nada é gravado
+	mock.ExpectBegin()
+	mock.ExpectQuery(deleteCart).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "quantity"}).AddRow(5, 2).AddRow(6, 1))
+	mock.ExpectRollback()
+	// checkout repetido: o primeiro já esvaziou o carrinho
+	mock.ExpectBegin()
+	mock.ExpectQuery(deleteCart).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "quantity"}))
+	mock.ExpectRollback()
+
+	order := domain.Order{
+		SellerID:   2,
+		Status:     domain.OrderPending,
+		Subtotal:   200_00,
+		CouponCode: "DEZ",
+		Discount:   20_00,
+		Total:      180_00,
+		Currency:   "MXN",
+		Items: []domain.OrderItem{{
+			PostID:  5,
+			Product: domain.Product{ProductID: 1, ProductName: "Tênis", Type: "calcado", Brand: "Nike", Color: "Preto"},
+			Price:   100_00, HasPromo: true, Discount: 10, UnitPrice: 100_00, Quantity: 2, Subtotal: 200_00,
+		}},
+	}
+	orders, err := s.PlaceOrders(1, []domain.Order{order})
+	if err != nil || len(orders) != 1 || orders[0].ID != 10 || orders[0].BuyerID != 1 {
+		t.Fatalf("unexpected: %+v %v", orders, err)
+	}
+	if _, err := s.PlaceOrders(1, []domain.Order{order}); err != domain.ErrCartChanged {
+		t.Fatalf("expected ErrCartChanged, got %v", err)
+	}
+	if _, err := s.PlaceOrders(1, []domain.Order{order}); err != domain.ErrCartEmpty {
+		t.Fatalf("expected ErrCartEmpty, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_Orders(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM orders WHERE seller_id = $1 AND status = $2`)).
+		WithArgs(2, domain.OrderPaid).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`FROM orders\s+WHERE seller_id = \$1 AND status = \$2 ORDER BY id DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs(2, domain.OrderPaid, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "buyer_id", "seller_id", "status", "subtotal", "coupon_code", "discount", "total", "currency", "payment_id", "payment_status", "created_at", "updated_at"}).
+			AddRow(10, 1, 2, domain.OrderPaid, 180.0, "", 0.0, 180.0, "BRL", "pay_1", "captured", now, now))
+	mock.ExpectQuery(`FROM order_items\s+WHERE order_id IN \(\$1\)\s+ORDER BY order_id, line`).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "post_id", "product_id", "product_name", "type", "brand", "color", "notes", "image_url", "price", "has_promo", "discount", "unit_price", "quantity", "subtotal"}).
+			AddRow(10, 5, 1, "Tênis", "calcado", "Nike", "Preto", "", "", 100.0, true, 10.0, 90.0, 2, 180.0))
+
+	orders, total, err := s.Orders(domain.OrderFilter{SellerID: 2, Status: domain.OrderPaid}, 20, 0)
+	if err != nil || total != 1 || len(orders) != 1 || len(orders[0].Items) != 1 || orders[0].Items[0].Product.ProductName != "Tênis" || orders[0].Currency != "BRL" {
+		t.Fatalf("unexpected: %+v %d %v", orders, total, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_UpdateOrderStatus_Conflict(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`)).
+		WithArgs(domain.OrderCancelled, now, 10, domain.OrderPending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM orders\s+WHERE id = \$1`).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "buyer_id", "seller_id", "status", "subtotal", "coupon_code", "discount", "total", "currency", "payment_id", "payment_status", "created_at", "updated_at"}).
+			AddRow(10, 1, 2, domain.OrderPaid, 180.0, "", 0.0, 180.0, "BRL", "pay_1", "captured", now, now))
+	mock.ExpectQuery(`FROM order_items`).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id"}))
+
+	if _, err := s.UpdateOrderStatus(10, domain.OrderPending, domain.OrderCancelled, now); err != domain.ErrOrderTransition {
+		t.Fatalf("expected ErrOrderTransition, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_PaymentEvents(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	claim := regexp.QuoteMeta(`INSERT INTO payment_events (event_id) VALUES ($1) ON CONFLICT DO NOTHING`)
+	mock.ExpectExec(claim).WithArgs("evt_1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(claim).WithArgs("evt_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM payment_events WHERE event_id = $1`)).
+		WithArgs("evt_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if ok, err := s.ClaimPaymentEvent("evt_1"); err != nil || !ok {
+		t.Fatalf("expected claim, got %v %v", ok, err)
+	}
+	if ok, err := s.ClaimPaymentEvent("evt_1"); err != nil || ok {
+		t.Fatalf("expected event already claimed, got %v %v", ok, err)
+	}
+	if err := s.ReleasePaymentEvent("evt_1"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
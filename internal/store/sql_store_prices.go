@@ -0,0 +1,125 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+func (s *SQLStore) AddPriceChange(c domain.PriceChange) error {
+	if err := s.postExists(c.PostID); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO post_price_changes (post_id, price, has_promo, discount, promo_starts_at, promo_ends_at, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, c.PostID, c.Price, c.HasPromo, c.Discount, domain.UTCTime(c.PromoStartsAt), domain.UTCTime(c.PromoEndsAt), c.ChangedAt.UTC())
+	return err
+}
+
+func (s *SQLStore) PriceChanges(postID int) ([]domain.PriceChange, error) {
+	rows, err := s.db.Query(`
+		SELECT post_id, price, has_promo, discount, promo_starts_at, promo_ends_at, changed_at
+		FROM post_price_changes
+		WHERE post_id = $1
+		ORDER BY changed_at, id
+	`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.PriceChange{}
+	for rows.Next() {
+		var c domain.PriceChange
+		var starts, ends sql.NullTime
+		if err := rows.Scan(&c.PostID, &c.Price, &c.HasPromo, &c.Discount, &starts, &ends, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		if starts.Valid {
+			c.PromoStartsAt = &starts.Time
+		}
+		if ends.Valid {
+			c.PromoEndsAt = &ends.Time
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+const priceAlertSelect = `SELECT id, user_id, post_id, threshold, created_at, triggered_at FROM price_alerts `
+
+func scanPriceAlert(row interface{ Scan(...any) error }) (domain.PriceAlert, error) {
+	var a domain.PriceAlert
+	var triggered sql.NullTime
+	if err := row.Scan(&a.ID, &a.UserID, &a.PostID, &a.Threshold, &a.CreatedAt, &triggered); err != nil {
+		return domain.PriceAlert{}, err
+	}
+	if triggered.Valid {
+		a.TriggeredAt = &triggered.Time
+	}
+	return a, nil
+}
+
+func (s *SQLStore) queryPriceAlerts(where string, args ...any) ([]domain.PriceAlert, error) {
+	rows, err := s.db.Query(priceAlertSelect+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.PriceAlert{}
+	for rows.Next() {
+		a, err := scanPriceAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) UpsertPriceAlert(a domain.PriceAlert) (domain.PriceAlert, error) {
+	if err := s.postExists(a.PostID); err != nil {
+		return domain.PriceAlert{}, err
+	}
+	got, err := scanPriceAlert(s.db.QueryRow(`
+		INSERT INTO price_alerts (user_id, post_id, threshold) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, post_id) DO UPDATE SET threshold = EXCLUDED.threshold, created_at = NOW(), triggered_at = NULL
+		RETURNING id, user_id, post_id, threshold, created_at, triggered_at
+	`, a.UserID, a.PostID, a.Threshold))
+	if err != nil {
+		return domain.PriceAlert{}, err
+	}
+	return got, nil
+}
+
+func (s *SQLStore) DeletePriceAlert(userID, postID int) error {
+	res, err := s.db.Exec(`DELETE FROM price_alerts WHERE user_id = $1 AND post_id = $2`, userID, postID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPriceAlertNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) PriceAlertsOf(userID int) ([]domain.PriceAlert, error) {
+	return s.queryPriceAlerts(`WHERE user_id = $1 ORDER BY id DESC`, userID)
+}
+
+func (s *SQLStore) PendingPriceAlerts(postID int) ([]domain.PriceAlert, error) {
+	if postID == 0 {
+		return s.queryPriceAlerts(`WHERE triggered_at IS NULL ORDER BY id`)
+	}
+	return s.queryPriceAlerts(`WHERE triggered_at IS NULL AND post_id = $1 ORDER BY id`, postID)
+}
+
+func (s *SQLStore) MarkPriceAlertTriggered(id int, at time.Time) (bool, error) {
+	res, err := s.db.Exec(`UPDATE price_alerts SET triggered_at = $2 WHERE id = $1 AND triggered_at IS NULL`, id, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
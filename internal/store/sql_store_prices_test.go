@@ -0,0 +1,69 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_PriceChanges(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`)).
+		WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`INSERT INTO post_price_changes`).
+		WithArgs(4, "100.00", true, 10.0, nil, &at, at).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`FROM post_price_changes\s+WHERE post_id = \$1\s+ORDER BY changed_at, id`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "changed_at"}).
+			AddRow(4, "100.00", true, 10.0, nil, at, at))
+
+	if err := s.AddPriceChange(domain.PriceChange{PostID: 4, Price: 100_00, HasPromo: true, Discount: 10, PromoEndsAt: &at, ChangedAt: at}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	changes, err := s.PriceChanges(4)
+	if err != nil || len(changes) != 1 || changes[0].PromoEndsAt == nil || changes[0].PromoStartsAt != nil {
+		t.Fatalf("unexpected: %+v %v", changes, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_PriceAlerts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cols := []string{"id", "user_id", "post_id", "threshold", "created_at", "triggered_at"}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`)).
+		WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`INSERT INTO price_alerts .*\s+ON CONFLICT \(user_id, post_id\) DO UPDATE SET .*triggered_at = NULL`).
+		WithArgs(1, 4, "80.00").
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(3, 1, 4, 80.0, now, nil))
+	mock.ExpectQuery(`FROM price_alerts WHERE triggered_at IS NULL ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(3, 1, 4, 80.0, now, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE price_alerts SET triggered_at = $2 WHERE id = $1 AND triggered_at IS NULL`)).
+		WithArgs(3, now).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a, err := s.UpsertPriceAlert(domain.PriceAlert{UserID: 1, PostID: 4, Threshold: 80_00})
+	if err != nil || a.ID != 3 || a.TriggeredAt != nil {
+		t.Fatalf("upsert: %+v %v", a, err)
+	}
+	if pending, err := s.PendingPriceAlerts(0); err != nil || len(pending) != 1 {
+		t.Fatalf("pending: %+v %v", pending, err)
+	}
+	if ok, err := s.MarkPriceAlertTriggered(3, now); err != nil || ok {
+		t.Fatalf("already triggered must report false: %v %v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
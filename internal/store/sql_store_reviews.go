@@ -0,0 +1,120 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+const reviewSelect = `
+	SELECT r.id, r.seller_id, r.buyer_id, u.name, r.rating, r.text, r.reply, r.replied_at,
+		r.created_at, r.updated_at
+	FROM reviews r
+	JOIN users u ON u.id = r.buyer_id
+`
+
+func scanReview(row interface{ Scan(...any) error }) (domain.Review, error) {
+	var r domain.Review
+	var replied sql.NullTime
+	if err := row.Scan(&r.ID, &r.SellerID, &r.BuyerID, &r.BuyerName, &r.Rating, &r.Text, &r.Reply, &replied,
+		&r.CreatedAt, &r.UpdatedAt); err != nil {
+		return domain.Review{}, err
+	}
+	if replied.Valid {
+		t := replied.Time
+		r.RepliedAt = &t
+	}
+	return r, nil
+}
+
+func (s *SQLStore) UpsertReview(r domain.Review) (domain.Review, bool, error) {
+	var id int
+	var created bool
+	// xmax = 0 só na linha recém-inserida: diferencia criação de edição
+	err := s.db.QueryRow(`
+		INSERT INTO reviews (seller_id, buyer_id, rating, text) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (seller_id, buyer_id) DO UPDATE
+			SET rating = EXCLUDED.rating, text = EXCLUDED.text, updated_at = NOW()
+		RETURNING id, (xmax = 0)
+	`, r.SellerID, r.BuyerID, r.Rating, r.Text).Scan(&id, &created)
+	if err != nil {
+		return domain.Review{}, false, err
+	}
+	out, ok := s.GetReview(id)
+	if !ok {
+		return domain.Review{}, false, ErrReviewNotFound
+	}
+	return out, created, nil
+}
+
+func (s *SQLStore) GetReview(id int) (domain.Review, bool) {
+	r, err := scanReview(s.db.QueryRow(reviewSelect+`WHERE r.id = $1`, id))
+	if err != nil {
+		return domain.Review{}, false
+	}
+	return r, true
+}
+
+func (s *SQLStore) SetReviewReply(id int, reply string, at time.Time) (domain.Review, error) {
+	res, err := s.db.Exec(`UPDATE reviews SET reply = $1, replied_at = $2 WHERE id = $3`, reply, at, id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.Review{}, ErrReviewNotFound
+	}
+	r, ok := s.GetReview(id)
+	if !ok {
+		return domain.Review{}, ErrReviewNotFound
+	}
+	return r, nil
+}
+
+func (s *SQLStore) ReviewsOf(sellerID int, limit, offset int) ([]domain.Review, int, error) {
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM reviews WHERE seller_id = $1`, sellerID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	out := []domain.Review{}
+	if total == 0 {
+		return out, 0, nil
+	}
+	rows, err := s.db.Query(reviewSelect+`
+		WHERE r.seller_id = $1
+		ORDER BY r.updated_at DESC, r.id DESC
+		LIMIT $2 OFFSET $3
+	`, sellerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		r, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, r)
+	}
+	return out, total, rows.Err()
+}
+
+func (s *SQLStore) RatingSummary(sellerID int) (domain.RatingSummary, error) {
+	rows, err := s.db.Query(`SELECT rating, COUNT(*) FROM reviews WHERE seller_id = $1 GROUP BY rating`, sellerID)
+	if err != nil {
+		return domain.RatingSummary{}, err
+	}
+	defer rows.Close()
+	hist := map[int]int{}
+	for rows.Next() {
+		var stars, n int
+		if err := rows.Scan(&stars, &n); err != nil {
+			return domain.RatingSummary{}, err
+		}
+		hist[stars] = n
+	}
+	if err := rows.Err(); err != nil {
+		return domain.RatingSummary{}, err
+	}
+	return domain.NewRatingSummary(hist), nil
+}
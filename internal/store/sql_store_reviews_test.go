@@ -0,0 +1,71 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var reviewCols = []string{"id", "seller_id", "buyer_id", "name", "rating", "text", "reply", "replied_at", "created_at", "updated_at"}
+
+func TestSQLStore_UpsertReview(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO reviews \(seller_id, buyer_id, rating, text\) VALUES \(\$1, \$2, \$3, \$4\)\s+ON CONFLICT \(seller_id, buyer_id\) DO UPDATE`).
+		WithArgs(2, 1, 4, "Chegou").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(3, false))
+	mock.ExpectQuery(`FROM reviews r\s+JOIN users u ON u.id = r.buyer_id\s+WHERE r.id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(reviewCols).AddRow(3, 2, 1, "Buyer", 4, "Chegou", "Obrigado", now, now, now))
+
+	r, created, err := s.UpsertReview(domain.Review{SellerID: 2, BuyerID: 1, Rating: 4, Text: "Chegou"})
+	if err != nil || created || r.ID != 3 || r.BuyerName != "Buyer" || r.RepliedAt == nil {
+		t.Fatalf("unexpected: %+v %v %v", r, created, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_RatingSummary(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT rating, COUNT(*) FROM reviews WHERE seller_id = $1 GROUP BY rating`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"rating", "count"}).AddRow(5, 3).AddRow(1, 1))
+
+	sum, err := s.RatingSummary(2)
+	if err != nil || sum.Count != 4 || sum.Average != 4 || sum.Histogram[5] != 3 || sum.Histogram[3] != 0 {
+		t.Fatalf("unexpected: %+v %v", sum, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_ReviewsOf(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM reviews WHERE seller_id = $1`)).
+		WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectQuery(`WHERE r.seller_id = \$1\s+ORDER BY r.updated_at DESC, r.id DESC\s+LIMIT \$2 OFFSET \$3`).
+		WithArgs(2, 1, 2).
+		WillReturnRows(sqlmock.NewRows(reviewCols).AddRow(7, 2, 1, "Buyer", 5, "Ótimo", "", nil, now, now))
+
+	list, total, err := s.ReviewsOf(2, 1, 2)
+	if err != nil || total != 5 || len(list) != 1 || list[0].RepliedAt != nil {
+		t.Fatalf("unexpected: %+v %d %v", list, total, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"strings"
+
+	"socialmeli/internal/domain"
+)
+
+// searchTSQuery monta "termo1:* & termo2:*". Os termos já vêm normalizados por
+// domain.SearchTerms (só [a-z0-9]), então não há como injetar operadores do tsquery.
+func searchTSQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = t + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+func (s *SQLStore) SearchPosts(terms []string, limit, offset int) ([]domain.Post, int, error) {
+	q := searchTSQuery(terms)
+
+	var total int
+	if err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM posts WHERE search_vector @@ to_tsquery('simple', $1)
+	`, q).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 || offset >= total {
+		return []domain.Post{}, total, nil
+	}
+
+	rows, err := s.db.Query(`
+		SELECT
+			id, user_id, date, date_str,
+			product_id, product_name, type, brand, color, notes, image_url,
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
+		FROM posts, to_tsquery('simple', $1) query
+		WHERE search_vector @@ query
+		ORDER BY ts_rank(search_vector, query) DESC, date DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, q, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, err := scanPosts(rows)
+	return posts, total, err
+}
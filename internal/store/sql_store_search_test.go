@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_SearchPosts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM posts WHERE search_vector @@ to_tsquery\('simple', \$1\)`).
+		WithArgs("tenis:* & preto:*").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	cols := []string{"id", "user_id", "date", "date_str", "product_id", "product_name", "type", "brand", "color", "notes", "image_url", "category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency"}
+	mock.ExpectQuery(`ORDER BY ts_rank\(search_vector, query\) DESC, date DESC, id DESC\s+LIMIT \$2 OFFSET \$3`).
+		WithArgs("tenis:* & preto:*", 2, 0).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(7, 2, time.Now(), "01-01-2026", 1, "Tênis", "calcado", "Marca", "Preto", "", "", 1, 200.0, true, 10.0, nil, nil, "ARS"))
+
+	posts, total, err := s.SearchPosts([]string{"tenis", "preto"}, 2, 0)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if total != 3 || len(posts) != 1 || posts[0].PostID != 7 || posts[0].FinalPrice != 180_00 || posts[0].Currency != "ARS" {
+		t.Fatalf("unexpected result: total=%d posts=%+v", total, posts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_SearchPosts_PastLastPage(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM posts`).
+		WithArgs("x:*").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	posts, total, err := s.SearchPosts([]string{"x"}, 10, 10)
+	if err != nil || total != 1 || len(posts) != 0 {
+		t.Fatalf("unexpected: %v %d %d", err, total, len(posts))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -229,7 +229,7 @@ func TestSQLStore_AddPost_Success_ReturnsID(t *testing.T) {
 		Date:     time.Now(),
 		DateStr:  "01-01-2026",
 		Category: 1,
-		Price:    100.0,
+		Price:    100_00,
 		HasPromo: true,
 		Discount: 10.0,
 		Product: domain.Product{
@@ -246,18 +246,18 @@ func TestSQLStore_AddPost_Success_ReturnsID(t *testing.T) {
 		INSERT INTO posts (
 			user_id, date, date_str,
 			product_id, product_name, type, brand, color, notes, image_url,
-			category, price, has_promo, discount
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency, promo_pending
 		) VALUES (
 			$1,$2,$3,
 			$4,$5,$6,$7,$8,$9,$10,
-			$11,$12,$13,$14
+			$11,$12,$13,$14,$15,$16,$17,$18
 		)
 		RETURNING id
 	`)).
 		WithArgs(
 			p.UserID, p.Date, p.DateStr,
 			p.Product.ProductID, p.Product.ProductName, p.Product.Type, p.Product.Brand, p.Product.Color, p.Product.Notes, p.Product.ImageURL,
-			p.Category, p.Price, p.HasPromo, p.Discount,
+			p.Category, p.Price, p.HasPromo, p.Discount, p.PromoStartsAt, p.PromoEndsAt, "BRL", false,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(123))
 
@@ -293,14 +293,14 @@ func TestSQLStore_PromoPostsBySeller_Success(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"id", "user_id", "date", "date_str",
 		"product_id", "product_name", "type", "brand", "color", "notes", "image_url",
-		"category", "price", "has_promo", "discount",
+		"category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency",
 	}).AddRow(
 		1, 2, now, "01-01-2026",
 		10, "Mouse", "peripheral", "BrandX", "Black", "note", "/static/products/1.jpg",
-		1, 100.0, true, 10.0,
+		1, 100.0, true, 10.0, nil, nil, "BRL",
 	)
 
-	mock.ExpectQuery(`(?s)SELECT.*FROM posts.*WHERE user_id=\$1 AND has_promo=true`).
+	mock.ExpectQuery(`(?s)SELECT.*FROM posts.*WHERE user_id=\$1 AND \(has_promo AND \(promo_starts_at IS NULL OR promo_starts_at <= \(NOW\(\) AT TIME ZONE 'UTC'\)\)`).
 		WithArgs(2).
 		WillReturnRows(rows)
 
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+// timelineBatch limita as linhas por INSERT (4 parâmetros cada, bem abaixo do teto do Postgres).
+const timelineBatch = 500
+
+func (s *SQLStore) AppendTimeline(userIDs []int, p domain.Post) error {
+	for len(userIDs) > 0 {
+		n := len(userIDs)
+		if n > timelineBatch {
+			n = timelineBatch
+		}
+		rows := make([]string, n)
+		args := make([]any, 0, n+3)
+		args = append(args, p.PostID, p.UserID, p.Date)
+		for i, uid := range userIDs[:n] {
+			args = append(args, uid)
+			rows[i] = fmt.Sprintf("($%d,$1,$2,$3)", len(args))
+		}
+		if _, err := s.db.Exec(`
+			INSERT INTO timeline (user_id, post_id, seller_id, date)
+			VALUES `+strings.Join(rows, ",")+`
+			ON CONFLICT DO NOTHING
+		`, args...); err != nil {
+			return err
+		}
+		userIDs = userIDs[n:]
+	}
+	return nil
+}
+
+func (s *SQLStore) TimelinePosts(userID int, since time.Time, f domain.PostFilter) ([]domain.Post, error) {
+	where, args := postWhere(domain.PostQuery{Filter: f})
+	args = append(args, userID, since)
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT
+			id, user_id, date, date_str,
+			product_id, product_name, type, brand, color, notes, image_url,
+			category, price, has_promo, discount, promo_starts_at, promo_ends_at, currency
+		FROM posts
+		WHERE id IN (SELECT post_id FROM timeline WHERE user_id = $%d AND date >= $%d)
+		AND `, len(args)-1, len(args))+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
+
+func (s *SQLStore) BackfillTimeline(userID, sellerID int, since time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO timeline (user_id, post_id, seller_id, date)
+		SELECT $1, id, user_id, date FROM posts
+		WHERE user_id = $2 AND date >= $3
+		ON CONFLICT DO NOTHING
+	`, userID, sellerID, since)
+	return err
+}
+
+func (s *SQLStore) PruneTimeline(userID, sellerID int) error {
+	_, err := s.db.Exec(`DELETE FROM timeline WHERE user_id = $1 AND seller_id = $2`, userID, sellerID)
+	return err
+}
+
+func (s *SQLStore) ExpireTimeline(before time.Time) (int, error) {
+	res, err := s.db.Exec(`DELETE FROM timeline WHERE date < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *SQLStore) PopularSellers(minFollowers int) ([]int, error) {
+	rows, err := s.db.Query(`
+		SELECT seller_id FROM follows
+		GROUP BY seller_id
+		HAVING COUNT(*) >= $1
+		ORDER BY seller_id
+	`, minFollowers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) PulledSellers() ([]int, error) {
+	rows, err := s.db.Query(`SELECT seller_id FROM timeline_pulled ORDER BY seller_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) SetSellerPulled(sellerID int, pulled bool) error {
+	if !pulled {
+		_, err := s.db.Exec(`DELETE FROM timeline_pulled WHERE seller_id = $1`, sellerID)
+		return err
+	}
+	_, err := s.db.Exec(`INSERT INTO timeline_pulled (seller_id) VALUES ($1) ON CONFLICT DO NOTHING`, sellerID)
+	return err
+}
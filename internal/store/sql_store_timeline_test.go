@@ -0,0 +1,112 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_AppendTimeline(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`INSERT INTO timeline \(user_id, post_id, seller_id, date\)\s+VALUES \(\$4,\$1,\$2,\$3\),\(\$5,\$1,\$2,\$3\)\s+ON CONFLICT DO NOTHING`).
+		WithArgs(7, 2, date, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := s.AppendTimeline([]int{1, 3}, domain.Post{PostID: 7, UserID: 2, Date: date}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_TimelinePosts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cols := []string{"id", "user_id", "date", "date_str", "product_id", "product_name", "type", "brand", "color", "notes", "image_url", "category", "price", "has_promo", "discount", "promo_starts_at", "promo_ends_at", "currency"}
+	mock.ExpectQuery(`FROM posts\s+WHERE id IN \(SELECT post_id FROM timeline WHERE user_id = \$2 AND date >= \$3\)\s+AND LOWER\(brand\) IN \(\$1\)`).
+		WithArgs("nike", 1, since).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(5, 2, since, "01-01-2026", 1, "Tênis", "calcado", "Nike", "Preto", "", "", 1, 100.0, true, 10.0, nil, nil, "BRL"))
+
+	posts, err := s.TimelinePosts(1, since, domain.PostFilter{Brands: []string{"Nike"}})
+	if err != nil || len(posts) != 1 || posts[0].FinalPrice != 90_00 {
+		t.Fatalf("unexpected: %v %+v", err, posts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_TimelineMaintenance(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`INSERT INTO timeline \(user_id, post_id, seller_id, date\)\s+SELECT \$1, id, user_id, date FROM posts\s+WHERE user_id = \$2 AND date >= \$3`).
+		WithArgs(1, 2, since).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM timeline WHERE user_id = $1 AND seller_id = $2`)).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM timeline WHERE date < $1`)).
+		WithArgs(since).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectQuery(`SELECT seller_id FROM follows\s+GROUP BY seller_id\s+HAVING COUNT\(\*\) >= \$1`).
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"seller_id"}).AddRow(2).AddRow(9))
+
+	if err := s.BackfillTimeline(1, 2, since); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	if err := s.PruneTimeline(1, 2); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if n, err := s.ExpireTimeline(since); err != nil || n != 4 {
+		t.Fatalf("expire: %d %v", n, err)
+	}
+	ids, err := s.PopularSellers(100)
+	if err != nil || len(ids) != 2 || ids[1] != 9 {
+		t.Fatalf("popular: %v %v", ids, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_PulledSellers(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO timeline_pulled (seller_id) VALUES ($1) ON CONFLICT DO NOTHING`)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT seller_id FROM timeline_pulled ORDER BY seller_id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"seller_id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM timeline_pulled WHERE seller_id = $1`)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := s.SetSellerPulled(2, true); err != nil {
+		t.Fatalf("mark: %v", err)
+	}
+	ids, err := s.PulledSellers()
+	if err != nil || len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("pulled: %v %v", ids, err)
+	}
+	if err := s.SetSellerPulled(2, false); err != nil {
+		t.Fatalf("unmark: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+// referencias a um upload: posts usando a imagem, contas usando como avatar e
+// itens de pedidos (copia do produto na compra)
+const uploadUnreferenced = `
+	NOT EXISTS (SELECT 1 FROM posts p WHERE p.image_url = u.url)
+	AND NOT EXISTS (SELECT 1 FROM users a WHERE a.avatar_url = u.url)
+	AND NOT EXISTS (SELECT 1 FROM order_items i WHERE i.image_url = u.url)`
+
+const uploadRefCount = `
+	(SELECT COUNT(*) FROM posts p WHERE p.image_url = u.url) +
+	(SELECT COUNT(*) FROM users a WHERE a.avatar_url = u.url) +
+	(SELECT COUNT(*) FROM order_items i WHERE i.image_url = u.url)`
+
+func (s *SQLStore) RecordUpload(u domain.Upload) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var hash any
+	if u.Hash != "" {
+		hash = u.Hash
+	}
+	// em conflito mantem o primeiro dono; renova created_at e limpa a marca da coleta
+	if _, err := tx.Exec(`
+		INSERT INTO uploads (id, owner_id, url, blob_keys, size, created_at, hash)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		ON CONFLICT (id) DO UPDATE
+		SET url=EXCLUDED.url, blob_keys=EXCLUDED.blob_keys, size=EXCLUDED.size,
+			created_at=EXCLUDED.created_at, hash=EXCLUDED.hash, unreferenced_since=NULL
+	`, u.ID, u.OwnerID, u.URL, strings.Join(u.Keys, ","), u.Size, u.CreatedAt, hash); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO upload_owners (upload_id, user_id, created_at)
+		VALUES ($1,$2,$3)
+		ON CONFLICT DO NOTHING
+	`, u.ID, u.OwnerID, u.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) getUploadWhere(where string, arg any) (domain.Upload, bool) {
+	var u domain.Upload
+	var keys string
+	err := s.db.QueryRow(`
+		SELECT u.id, COALESCE(u.hash, ''), u.owner_id, u.url, u.blob_keys, u.size, u.created_at,`+uploadRefCount+`
+		FROM uploads u
+		WHERE `+where, arg).Scan(&u.ID, &u.Hash, &u.OwnerID, &u.URL, &keys, &u.Size, &u.CreatedAt, &u.RefCount)
+	if err != nil {
+		return domain.Upload{}, false
+	}
+	u.Keys = splitKeys(keys)
+	return u, true
+}
+
+func (s *SQLStore) GetUpload(id string) (domain.Upload, bool) {
+	return s.getUploadWhere(`u.id=$1`, id)
+}
+
+func (s *SQLStore) GetUploadByHash(hash string) (domain.Upload, bool) {
+	return s.getUploadWhere(`u.hash=$1`, hash)
+}
+
+func (s *SQLStore) ReuseUpload(id string, ownerID int, at time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// a condicao segura a corrida com a coleta: ou o reaproveitamento limpa a marca
+	// antes (e o ClaimUnreferencedUpload falha), ou a coleta chegou primeiro e aqui falha
+	res, err := tx.Exec(`
+		UPDATE uploads SET created_at=$2, unreferenced_since=NULL
+		WHERE id=$1 AND collecting_since IS NULL
+	`, id, at)
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if aff == 0 {
+		var exists int
+		err := tx.QueryRow(`SELECT 1 FROM uploads WHERE id=$1`, id).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUploadNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return ErrUploadCollecting
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO upload_owners (upload_id, user_id, created_at)
+		VALUES ($1,$2,$3)
+		ON CONFLICT DO NOTHING
+	`, id, ownerID, at); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) UploadsByOwner(userID int) ([]domain.Upload, error) {
+	rows, err := s.db.Query(`
+		SELECT u.id, COALESCE(u.hash, ''), u.owner_id, u.url, u.blob_keys, u.size, u.created_at,`+uploadRefCount+`
+		FROM upload_owners o
+		JOIN uploads u ON u.id = o.upload_id
+		WHERE o.user_id=$1
+		ORDER BY u.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []domain.Upload{}
+	for rows.Next() {
+		var u domain.Upload
+		var keys string
+		if err := rows.Scan(&u.ID, &u.Hash, &u.OwnerID, &u.URL, &keys, &u.Size, &u.CreatedAt, &u.RefCount); err != nil {
+			return nil, err
+		}
+		u.Keys = splitKeys(keys)
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) MarkUnreferencedUploads(at time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`UPDATE uploads u SET unreferenced_since = $1 WHERE u.unreferenced_since IS NULL AND `+uploadUnreferenced, at.UTC()); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE uploads u SET unreferenced_since = NULL WHERE u.unreferenced_since IS NOT NULL AND u.collecting_since IS NULL AND NOT (` + uploadUnreferenced + `)`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) UnreferencedUploads(olderThan time.Time) ([]domain.Upload, error) {
+	rows, err := s.db.Query(`
+		SELECT u.id, COALESCE(u.hash, ''), u.owner_id, u.url, u.blob_keys, u.size, u.created_at, u.unreferenced_since, u.collecting_since
+		FROM uploads u
+		WHERE u.collecting_since IS NOT NULL OR (u.unreferenced_since < $1 AND `+uploadUnreferenced+`)
+		ORDER BY u.unreferenced_since
+	`, olderThan.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []domain.Upload{}
+	for rows.Next() {
+		var u domain.Upload
+		var keys string
+		if err := rows.Scan(&u.ID, &u.Hash, &u.OwnerID, &u.URL, &keys, &u.Size, &u.CreatedAt, &u.UnreferencedSince, &u.CollectingSince); err != nil {
+			return nil, err
+		}
+		u.Keys = splitKeys(keys)
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) ClaimUnreferencedUpload(id string, olderThan, at time.Time) error {
+	// as checagens vao no proprio UPDATE: sem janela entre checar e marcar
+	res, err := s.db.Exec(`
+		UPDATE uploads u SET collecting_since = COALESCE(u.collecting_since, $3)
+		WHERE u.id=$1 AND (u.collecting_since IS NOT NULL OR (u.unreferenced_since < $2 AND `+uploadUnreferenced+`))
+	`, id, olderThan.UTC(), at.UTC())
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if aff > 0 {
+		return nil
+	}
+	var exists int
+	err = s.db.QueryRow(`SELECT 1 FROM uploads WHERE id=$1`, id).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUploadNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return ErrUploadInUse
+}
+
+func (s *SQLStore) DeleteCollectedUpload(id string) error {
+	// upload_owners cai junto pelo ON DELETE CASCADE
+	res, err := s.db.Exec(`DELETE FROM uploads WHERE id=$1 AND collecting_since IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	aff, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if aff == 0 {
+		return ErrUploadNotFound
+	}
+	return nil
+}
+
+func splitKeys(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
+func (s *SQLStore) UploadParts(uploadID string) ([]domain.UploadPart, error) {
+	rows, err := s.db.Query(`
+		SELECT upload_id, start_at, size, blob_key, created_at
+		FROM upload_parts
+		WHERE upload_id = $1
+		ORDER BY start_at`, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.UploadPart{}
+	for rows.Next() {
+		var p domain.UploadPart
+		if err := rows.Scan(&p.UploadID, &p.Start, &p.Size, &p.Key, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// AddUploadPart confere o offset no próprio INSERT; dois pedaços no mesmo offset
+// (retries em instâncias diferentes) esbarram na chave primária.
+func (s *SQLStore) AddUploadPart(p domain.UploadPart) (bool, error) {
+	res, err := s.db.Exec(`
+		INSERT INTO upload_parts (upload_id, start_at, size, blob_key, created_at)
+		SELECT $1, $2, $3, $4, $5
+		WHERE (SELECT COALESCE(MAX(start_at + size), 0) FROM upload_parts WHERE upload_id = $1) = $2
+		ON CONFLICT DO NOTHING`, p.UploadID, p.Start, p.Size, p.Key, p.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (s *SQLStore) DeleteUploadParts(uploadID string) error {
+	_, err := s.db.Exec(`DELETE FROM upload_parts WHERE upload_id = $1`, uploadID)
+	return err
+}
+
+func (s *SQLStore) StaleUploadParts(before time.Time) ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT upload_id FROM upload_parts
+		GROUP BY upload_id
+		HAVING MAX(created_at) < $1
+		ORDER BY upload_id`, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,230 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_RecordUpload(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO uploads (id, owner_id, url, blob_keys, size, created_at, hash)`)).
+		WithArgs("images/h_large.jpg", 1, "/static/images/h_large.jpg", "images/h_thumb.jpg,images/h_large.jpg", int64(99), now, "h").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO upload_owners (upload_id, user_id, created_at)`)).
+		WithArgs("images/h_large.jpg", 1, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := s.RecordUpload(domain.Upload{
+		ID: "images/h_large.jpg", Hash: "h", OwnerID: 1, URL: "/static/images/h_large.jpg",
+		Keys: []string{"images/h_thumb.jpg", "images/h_large.jpg"}, Size: 99, CreatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_GetUpload_WithRefCount(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	cols := []string{"id", "hash", "owner_id", "url", "blob_keys", "size", "created_at", "refs"}
+	mock.ExpectQuery(`SELECT u.id, COALESCE\(u.hash, ''\), u.owner_id, .*FROM uploads u\s+WHERE u.id=\$1`).
+		WithArgs("images/h_large.jpg").
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow("images/h_large.jpg", "h", 1, "/static/images/h_large.jpg", "k1,k2", int64(5), time.Now(), 2))
+
+	u, ok := s.GetUpload("images/h_large.jpg")
+	if !ok || u.RefCount != 2 || len(u.Keys) != 2 || u.Hash != "h" {
+		t.Fatalf("unexpected upload: %+v ok=%v", u, ok)
+	}
+
+	mock.ExpectQuery(`FROM uploads u\s+WHERE u.hash=\$1`).
+		WithArgs("nope").
+		WillReturnRows(sqlmock.NewRows(cols))
+	if _, ok := s.GetUploadByHash("nope"); ok {
+		t.Fatalf("expected ok=false")
+	}
+}
+
+func TestSQLStore_UploadsByOwner(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM upload_owners o\s+JOIN uploads u ON u.id = o.upload_id\s+WHERE o.user_id=\$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hash", "owner_id", "url", "blob_keys", "size", "created_at", "refs"}).
+			AddRow("images/a_large.png", "a", 1, "/static/images/a_large.png", "k", int64(5), time.Now(), 0).
+			AddRow("images/b_large.png", "b", 3, "/static/images/b_large.png", "k", int64(7), time.Now(), 1))
+
+	out, err := s.UploadsByOwner(3)
+	if err != nil || len(out) != 2 || out[1].Size != 7 {
+		t.Fatalf("unexpected uploads: %+v %v", out, err)
+	}
+}
+
+func TestSQLStore_UnreferencedUploads(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	cutoff := time.Now().Add(-time.Hour)
+	mock.ExpectQuery(`FROM uploads u\s+WHERE u.collecting_since IS NOT NULL OR \(u.unreferenced_since < \$1 AND\s+NOT EXISTS .*order_items`).
+		WithArgs(cutoff.UTC()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hash", "owner_id", "url", "blob_keys", "size", "created_at", "unreferenced_since", "collecting_since"}).
+			AddRow("images/b_large.jpg", "b", 1, "/static/images/b_large.jpg", "images/b_large.jpg", int64(7), cutoff.Add(-48*time.Hour), cutoff.Add(-time.Hour), nil))
+
+	out, err := s.UnreferencedUploads(cutoff)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(out) != 1 || out[0].Size != 7 || out[0].UnreferencedSince == nil {
+		t.Fatalf("unexpected orphans: %+v", out)
+	}
+}
+
+func TestSQLStore_ClaimUnreferencedUpload(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	cutoff := time.Now().UTC()
+	at := cutoff.Add(time.Minute)
+	claim := `UPDATE uploads u SET collecting_since = COALESCE\(u.collecting_since, \$3\)\s+WHERE u.id=\$1 AND \(u.collecting_since IS NOT NULL OR \(u.unreferenced_since < \$2 AND`
+
+	// marcado
+	mock.ExpectExec(claim).
+		WithArgs("a", cutoff, at).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := s.ClaimUnreferencedUpload("a", cutoff, at); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	// ainda referenciado (ou renovado)
+	mock.ExpectExec(claim).
+		WithArgs("b", cutoff, at).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM uploads WHERE id=$1`)).
+		WithArgs("b").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	if err := s.ClaimUnreferencedUpload("b", cutoff, at); err != ErrUploadInUse {
+		t.Fatalf("expected ErrUploadInUse, got %v", err)
+	}
+
+	// arquivos apagados: sai o registro
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM uploads WHERE id=$1 AND collecting_since IS NOT NULL`)).
+		WithArgs("a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := s.DeleteCollectedUpload("a"); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_ReuseUpload(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reuse := `UPDATE uploads SET created_at=\$2, unreferenced_since=NULL\s+WHERE id=\$1 AND collecting_since IS NULL`
+
+	mock.ExpectBegin()
+	mock.ExpectExec(reuse).WithArgs("a", at).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO upload_owners`).WithArgs("a", 3, at).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := s.ReuseUpload("a", 3, at); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	// a coleta já marcou: nada de dono novo
+	mock.ExpectBegin()
+	mock.ExpectExec(reuse).WithArgs("b", at).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM uploads WHERE id=$1`)).
+		WithArgs("b").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	mock.ExpectRollback()
+	if err := s.ReuseUpload("b", 3, at); err != ErrUploadCollecting {
+		t.Fatalf("expected ErrUploadCollecting, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_MarkUnreferencedUploads(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE uploads u SET unreferenced_since = \$1 WHERE u.unreferenced_since IS NULL AND\s+NOT EXISTS`).
+		WithArgs(at).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE uploads u SET unreferenced_since = NULL WHERE u.unreferenced_since IS NOT NULL AND u.collecting_since IS NULL AND NOT \(`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := s.MarkUnreferencedUploads(at); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_UploadParts(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	add := `INSERT INTO upload_parts (upload_id, start_at, size, blob_key, created_at)`
+	mock.ExpectExec(regexp.QuoteMeta(add)+`.*WHERE \(SELECT COALESCE\(MAX\(start_at \+ size\), 0\) FROM upload_parts WHERE upload_id = \$1\) = \$2\s+ON CONFLICT DO NOTHING`).
+		WithArgs("ab", int64(0), int64(5), "staging/ab/0-x", at).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// offset que não confere (ou outro pedaço no mesmo offset): nada gravado
+	mock.ExpectExec(regexp.QuoteMeta(add)).
+		WithArgs("ab", int64(0), int64(5), "staging/ab/0-y", at).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM upload_parts`)).
+		WithArgs("ab").
+		WillReturnRows(sqlmock.NewRows([]string{"upload_id", "start_at", "size", "blob_key", "created_at"}).AddRow("ab", 0, 5, "staging/ab/0-x", at))
+	mock.ExpectQuery(regexp.QuoteMeta(`HAVING MAX(created_at) < $1`)).
+		WithArgs(at).
+		WillReturnRows(sqlmock.NewRows([]string{"upload_id"}).AddRow("ab"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM upload_parts WHERE upload_id = $1`)).
+		WithArgs("ab").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if ok, err := s.AddUploadPart(domain.UploadPart{UploadID: "ab", Size: 5, Key: "staging/ab/0-x", CreatedAt: at}); err != nil || !ok {
+		t.Fatalf("expected part added, got %v %v", ok, err)
+	}
+	if ok, err := s.AddUploadPart(domain.UploadPart{UploadID: "ab", Size: 5, Key: "staging/ab/0-y", CreatedAt: at}); err != nil || ok {
+		t.Fatalf("expected offset conflict, got %v %v", ok, err)
+	}
+	parts, err := s.UploadParts("ab")
+	if err != nil || len(parts) != 1 || parts[0].Size != 5 || parts[0].Key != "staging/ab/0-x" {
+		t.Fatalf("unexpected parts %+v %v", parts, err)
+	}
+	if ids, err := s.StaleUploadParts(at); err != nil || len(ids) != 1 || ids[0] != "ab" {
+		t.Fatalf("unexpected stale %v %v", ids, err)
+	}
+	if err := s.DeleteUploadParts("ab"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"socialmeli/internal/domain"
+)
+
+// sqlUserName é a mesma expressão do índice idx_users_name_trgm.
+const sqlUserName = `immutable_unaccent(LOWER(name))`
+
+// userSearchWhere exige que cada termo seja prefixo de alguma palavra do nome.
+// Os termos vêm de domain.SearchTerms (só [a-z0-9]), sem curingas do LIKE.
+func userSearchWhere(terms []string, sellersOnly bool) (string, []any) {
+	conds := make([]string, 0, len(terms)+1)
+	args := make([]any, 0, len(terms))
+	for _, t := range terms {
+		args = append(args, t)
+		n := len(args)
+		conds = append(conds, fmt.Sprintf("(%s LIKE $%d || '%%' OR %s LIKE '%% ' || $%d || '%%')", sqlUserName, n, sqlUserName, n))
+	}
+	if sellersOnly {
+		conds = append(conds, "is_seller = true")
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func (s *SQLStore) SearchUsers(terms []string, sellersOnly bool, limit, offset int) ([]domain.UserSummary, int, error) {
+	where, args := userSearchWhere(terms, sellersOnly)
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM users WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 || offset >= total {
+		return []domain.UserSummary{}, total, nil
+	}
+
+	n := len(args)
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT id, name, is_seller, avatar_url
+		FROM users
+		WHERE %s
+		ORDER BY (%s LIKE $1 || '%%') DESC, LOWER(name), id
+		LIMIT $%d OFFSET $%d
+	`, where, sqlUserName, n+1, n+2), append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := []domain.UserSummary{}
+	for rows.Next() {
+		var u domain.UserSummary
+		var avatar sql.NullString
+		if err := rows.Scan(&u.ID, &u.Name, &u.IsSeller, &avatar); err != nil {
+			return nil, 0, err
+		}
+		u.AvatarURL = avatar.String
+		out = append(out, u)
+	}
+	return out, total, rows.Err()
+}
@@ -0,0 +1,27 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_SearchUsers(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE \(immutable_unaccent\(LOWER\(name\)\) LIKE \$1 \|\| '%' OR .*\) AND is_seller = true`).
+		WithArgs("jo").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT id, name, is_seller, avatar_url\s+FROM users.*LIMIT \$2 OFFSET \$3`).
+		WithArgs("jo", 5, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_seller", "avatar_url"}).AddRow(2, "João", true, nil))
+
+	users, total, err := s.SearchUsers([]string{"jo"}, true, 5, 0)
+	if err != nil || total != 1 || len(users) != 1 || users[0].Name != "João" || users[0].AvatarURL != "" {
+		t.Fatalf("unexpected: %v %d %+v", err, total, users)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"socialmeli/internal/domain"
+)
+
+const webhookColumns = `id, user_id, url, events, secret, created_at`
+
+func scanWebhook(row interface{ Scan(...any) error }) (domain.Webhook, error) {
+	var w domain.Webhook
+	var events string
+	if err := row.Scan(&w.ID, &w.UserID, &w.URL, &events, &w.Secret, &w.CreatedAt); err != nil {
+		return domain.Webhook{}, err
+	}
+	w.Events = strings.Split(events, ",")
+	return w, nil
+}
+
+func (s *SQLStore) CreateWebhook(w domain.Webhook) (domain.Webhook, error) {
+	if _, ok := s.GetUser(w.UserID); !ok {
+		return domain.Webhook{}, ErrUserNotFound
+	}
+	row := s.db.QueryRow(`
+		INSERT INTO webhooks (user_id, url, events, secret)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+webhookColumns, w.UserID, w.URL, strings.Join(w.Events, ","), w.Secret)
+	return scanWebhook(row)
+}
+
+func (s *SQLStore) GetWebhook(id int) (domain.Webhook, bool) {
+	w, err := scanWebhook(s.db.QueryRow(`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	return w, err == nil
+}
+
+func (s *SQLStore) WebhooksByUser(userID int) ([]domain.Webhook, error) {
+	rows, err := s.db.Query(`SELECT `+webhookColumns+` FROM webhooks WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLStore) DeleteWebhook(userID, id int) error {
+	res, err := s.db.Exec(`DELETE FROM webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) AddWebhookDeliveries(ds []domain.WebhookDelivery) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	rows := make([]string, len(ds))
+	args := make([]any, 0, len(ds)*5)
+	for i, d := range ds {
+		b := len(args)
+		rows[i] = fmt.Sprintf("($%d,$%d,$%d,$%d,$%d)", b+1, b+2, b+3, b+4, b+5)
+		args = append(args, d.WebhookID, d.Event, string(d.Payload), d.Status, d.NextAttemptAt)
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at)
+		VALUES `+strings.Join(rows, ","), args...)
+	return err
+}
+
+const deliveryColumns = `id, webhook_id, event, payload, status, attempts, last_status, last_error, next_attempt_at, created_at, updated_at`
+
+func scanDelivery(row interface{ Scan(...any) error }) (domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	var payload string
+	err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &payload, &d.Status, &d.Attempts, &d.LastStatus, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt)
+	d.Payload = []byte(payload)
+	return d, err
+}
+
+func (s *SQLStore) scanDeliveries(rows *sql.Rows) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+	out := []domain.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// ClaimWebhookDeliveries usa SKIP LOCKED: instâncias concorrentes pegam entregas diferentes.
+func (s *SQLStore) ClaimWebhookDeliveries(now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := s.db.Query(`
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.scanDeliveries(rows)
+}
+
+func (s *SQLStore) UpdateWebhookDelivery(d domain.WebhookDelivery) error {
+	res, err := s.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, last_status = $4, last_error = $5, next_attempt_at = $6, updated_at = NOW()
+		WHERE id = $1
+	`, d.ID, d.Status, d.Attempts, d.LastStatus, d.LastError, d.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) GetWebhookDelivery(id int) (domain.WebhookDelivery, bool) {
+	d, err := scanDelivery(s.db.QueryRow(`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	return d, err == nil
+}
+
+func (s *SQLStore) WebhookDeliveries(webhookID int, status string, limit, offset int) ([]domain.WebhookDelivery, int, error) {
+	where := `webhook_id = $1`
+	args := []any{webhookID}
+	if status != "" {
+		where += ` AND status = $2`
+		args = append(args, status)
+	}
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	n := len(args)
+	rows, err := s.db.Query(`
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE `+where+`
+		ORDER BY id DESC
+		LIMIT $`+fmt.Sprint(n+1)+` OFFSET $`+fmt.Sprint(n+2), append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	out, err := s.scanDeliveries(rows)
+	return out, total, err
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"socialmeli/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSQLStore_WebhooksByUser(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, url, events, secret, created_at FROM webhooks WHERE user_id = $1 ORDER BY id`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url", "events", "secret", "created_at"}).
+			AddRow(1, 2, "https://x", "follower.new,post.created", "s", at))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM webhooks WHERE id = $1 AND user_id = $2`)).
+		WithArgs(5, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ws, err := s.WebhooksByUser(2)
+	if err != nil || len(ws) != 1 || len(ws[0].Events) != 2 || !ws[0].Subscribes(domain.WebhookPostCreated) {
+		t.Fatalf("unexpected: %+v %v", ws, err)
+	}
+	if err := s.DeleteWebhook(2, 5); err != ErrWebhookNotFound {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_ClaimWebhookDeliveries(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	lease := now.Add(time.Minute)
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at = \$2\s+WHERE id IN \(\s+SELECT id FROM webhook_deliveries\s+WHERE status = 'pending' AND next_attempt_at <= \$1\s+ORDER BY next_attempt_at, id\s+LIMIT \$3\s+FOR UPDATE SKIP LOCKED\s+\)\s+RETURNING`).
+		WithArgs(now, lease, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event", "payload", "status", "attempts", "last_status", "last_error", "next_attempt_at", "created_at", "updated_at"}).
+			AddRow(7, 1, "post.created", `{"event":"post.created"}`, "pending", 2, 500, "HTTP 500", lease, now, now))
+
+	ds, err := s.ClaimWebhookDeliveries(now, lease, 50)
+	if err != nil || len(ds) != 1 || ds[0].Attempts != 2 || string(ds[0].Payload) != `{"event":"post.created"}` {
+		t.Fatalf("unexpected: %+v %v", ds, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSQLStore_WebhookDeliveriesDeadLetter(t *testing.T) {
+	s, mock, cleanup := newSQLStoreWithMock(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1 AND status = $2`)).
+		WithArgs(1, "dead").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`FROM webhook_deliveries\s+WHERE webhook_id = \$1 AND status = \$2\s+ORDER BY id DESC\s+LIMIT \$3 OFFSET \$4`).
+		WithArgs(1, "dead", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if ds, total, err := s.WebhookDeliveries(1, domain.DeliveryDead, 20, 0); err != nil || total != 0 || len(ds) != 0 {
+		t.Fatalf("unexpected: %+v %d %v", ds, total, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
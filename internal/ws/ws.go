@@ -0,0 +1,385 @@
+// Package ws implementa o necessário do WebSocket (RFC 6455) para o canal de
+// notificações: handshake no servidor, cliente (usado nos testes), mensagens de
+// texto/binárias com fragmentação e os frames de controle (ping, pong, close).
+// Extensões e subprotocolos não são suportados.
+package ws
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Opcodes.
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// Códigos de fechamento usados aqui.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseNoStatus        = 1005
+	CloseInvalidPayload  = 1007
+	ClosePolicyViolation = 1008
+	CloseTooBig          = 1009
+	CloseTryAgainLater   = 1013
+)
+
+// DefaultMaxMessageSize limita mensagens recebidas (somando fragmentos).
+const DefaultMaxMessageSize = 64 << 10
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	ErrBadHandshake   = errors.New("ws: handshake inválido")
+	ErrProtocol       = errors.New("ws: violação de protocolo")
+	ErrMessageTooBig  = errors.New("ws: mensagem grande demais")
+	ErrInvalidPayload = errors.New("ws: texto não é UTF-8")
+)
+
+// CloseError é devolvido por ReadMessage quando o outro lado fecha a conexão.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("ws: conexão fechada (%d %s)", e.Code, e.Reason)
+}
+
+// AcceptKey calcula o Sec-WebSocket-Accept para a chave do cliente.
+func AcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsUpgrade diz se a requisição pede upgrade para WebSocket.
+func IsUpgrade(r *http.Request) bool {
+	return headerHasToken(r.Header, "Connection", "upgrade") && headerHasToken(r.Header, "Upgrade", "websocket")
+}
+
+// Upgrade valida o handshake, assume a conexão TCP e responde 101.
+// Em caso de erro a resposta HTTP já foi escrita.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet || !IsUpgrade(r) {
+		http.Error(w, "upgrade para websocket esperado", http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "versão de websocket não suportada", http.StatusUpgradeRequired)
+		return nil, ErrBadHandshake
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if raw, err := base64.StdEncoding.DecodeString(key); err != nil || len(raw) != 16 {
+		http.Error(w, "Sec-WebSocket-Key inválida", http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "conexão não suporta websocket", http.StatusInternalServerError)
+		return nil, ErrBadHandshake
+	}
+	netConn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// nada pode ter ficado no buffer de leitura antes do handshake terminar
+	if brw.Reader.Buffered() > 0 {
+		netConn.Close()
+		return nil, ErrBadHandshake
+	}
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n"
+	if _, err := netConn.Write([]byte(resp)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return newConn(netConn, brw.Reader, false), nil
+}
+
+// Dial abre uma conexão cliente. Aceita ws:// e http:// (ex.: URL do httptest).
+func Dial(rawURL string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch u.Scheme {
+	case "ws", "http":
+		u.Scheme = "http"
+	default:
+		return nil, nil, fmt.Errorf("ws: esquema não suportado: %s", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "80")
+	}
+	netConn, err := net.DialTimeout("tcp", host, 10*time.Second)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		netConn.Close()
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{Method: http.MethodGet, URL: u, Host: u.Host, Header: http.Header{}}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(netConn); err != nil {
+		netConn.Close()
+		return nil, nil, err
+	}
+
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		netConn.Close()
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != AcceptKey(key) {
+		netConn.Close()
+		return nil, resp, ErrBadHandshake
+	}
+	return newConn(netConn, br, true), resp, nil
+}
+
+// Conn é uma conexão WebSocket. Leituras devem vir de uma goroutine só;
+// escritas podem ser concorrentes.
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // o cliente mascara o que envia; o servidor não
+
+	// MaxMessageSize limita o tamanho das mensagens recebidas.
+	MaxMessageSize int
+
+	wmu       sync.Mutex
+	closeSent bool
+}
+
+func newConn(c net.Conn, br *bufio.Reader, client bool) *Conn {
+	return &Conn{conn: c, br: br, client: client, MaxMessageSize: DefaultMaxMessageSize}
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error  { return c.conn.SetReadDeadline(t) }
+func (c *Conn) SetWriteDeadline(t time.Time) error { return c.conn.SetWriteDeadline(t) }
+
+// Close fecha a conexão TCP sem handshake de fechamento (ver WriteClose).
+func (c *Conn) Close() error { return c.conn.Close() }
+
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	if op == OpClose {
+		c.closeSent = true
+	}
+
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | op
+	n := len(payload)
+	switch {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if c.client {
+		header[1] |= 0x80
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		header = append(header, mask[:]...)
+		masked := make([]byte, n)
+		for i := range payload {
+			masked[i] = payload[i] ^ mask[i%4]
+		}
+		payload = masked
+	}
+	_, err := c.conn.Write(append(header, payload...))
+	return err
+}
+
+// WriteMessage envia uma mensagem OpText ou OpBinary num frame só.
+func (c *Conn) WriteMessage(op byte, data []byte) error {
+	if op != OpText && op != OpBinary {
+		return ErrProtocol
+	}
+	return c.writeFrame(op, data)
+}
+
+func (c *Conn) WriteJSON(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(OpText, b)
+}
+
+func (c *Conn) Ping(data []byte) error { return c.writeFrame(OpPing, data) }
+
+// WriteClose inicia (ou responde) o fechamento. Depois dele nada mais é enviado.
+func (c *Conn) WriteClose(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	return c.writeFrame(OpClose, append(payload, reason...))
+}
+
+// fail fecha por erro do outro lado e devolve err.
+func (c *Conn) fail(code int, err error) error {
+	_ = c.WriteClose(code, "")
+	return err
+}
+
+// ReadMessage devolve a próxima mensagem de dados (OpText ou OpBinary), juntando
+// fragmentos. Ping é respondido e pong ignorado aqui dentro; um close do outro
+// lado é respondido e vira *CloseError.
+func (c *Conn) ReadMessage() (byte, []byte, error) {
+	var (
+		msgOp   byte
+		msg     []byte
+		started bool
+	)
+	for {
+		var h [2]byte
+		if _, err := io.ReadFull(c.br, h[:]); err != nil {
+			return 0, nil, err
+		}
+		fin := h[0]&0x80 != 0
+		op := h[0] & 0x0F
+		masked := h[1]&0x80 != 0
+		if h[0]&0x70 != 0 || masked == c.client {
+			return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+		}
+
+		length := uint64(h[1] & 0x7F)
+		switch length {
+		case 126:
+			var b [2]byte
+			if _, err := io.ReadFull(c.br, b[:]); err != nil {
+				return 0, nil, err
+			}
+			length = uint64(binary.BigEndian.Uint16(b[:]))
+		case 127:
+			var b [8]byte
+			if _, err := io.ReadFull(c.br, b[:]); err != nil {
+				return 0, nil, err
+			}
+			length = binary.BigEndian.Uint64(b[:])
+		}
+
+		control := op >= OpClose
+		if control && (!fin || length > 125) {
+			return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+		}
+		if !control && uint64(len(msg))+length > uint64(c.MaxMessageSize) {
+			return 0, nil, c.fail(CloseTooBig, ErrMessageTooBig)
+		}
+
+		var mask [4]byte
+		if masked {
+			if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+				return 0, nil, err
+			}
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			return 0, nil, err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+
+		switch op {
+		case OpPing:
+			if err := c.writeFrame(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+		case OpPong:
+		case OpClose:
+			ce := &CloseError{Code: CloseNoStatus}
+			if len(payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(payload))
+				ce.Reason = string(payload[2:])
+			}
+			reply := ce.Code
+			if reply == CloseNoStatus {
+				reply = CloseNormal
+			}
+			_ = c.WriteClose(reply, "")
+			return 0, nil, ce
+		case OpText, OpBinary:
+			if started {
+				return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+			}
+			started, msgOp, msg = true, op, payload
+		case OpContinuation:
+			if !started {
+				return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+			}
+			msg = append(msg, payload...)
+		default:
+			return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+		}
+
+		if started && fin && !control {
+			if msgOp == OpText && !utf8.Valid(msg) {
+				return 0, nil, c.fail(CloseInvalidPayload, ErrInvalidPayload)
+			}
+			return msgOp, msg, nil
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package ws
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// exemplo da RFC 6455, seção 1.3
+	if got := AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected accept key %q", got)
+	}
+}
+
+// echoServer devolve cada mensagem recebida e reporta o erro que encerrou a leitura.
+func echoServer(t *testing.T, maxSize int) (*httptest.Server, <-chan error) {
+	t.Helper()
+	done := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		if maxSize > 0 {
+			c.MaxMessageSize = maxSize
+		}
+		for {
+			op, data, err := c.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			if err := c.WriteMessage(op, data); err != nil {
+				done <- err
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, done
+}
+
+func TestEchoAndClose(t *testing.T) {
+	srv, done := echoServer(t, 1<<20)
+	c, _, err := Dial(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+	c.MaxMessageSize = 1 << 20
+	c.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	// tamanhos nas três codificações de comprimento (7, 16 e 64 bits)
+	for _, msg := range []string{"olá", strings.Repeat("y", 300), strings.Repeat("x", 70000)} {
+		if err := c.WriteMessage(OpText, []byte(msg)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		op, data, err := c.ReadMessage()
+		if err != nil || op != OpText || string(data) != msg {
+			t.Fatalf("echo mismatch: op=%d len=%d err=%v", op, len(data), err)
+		}
+	}
+
+	// ping no meio não atrapalha a leitura
+	if err := c.Ping([]byte("p")); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if err := c.WriteJSON(map[string]int{"a": 1}); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	if _, data, err := c.ReadMessage(); err != nil || string(data) != `{"a":1}` {
+		t.Fatalf("unexpected %q %v", data, err)
+	}
+
+	if err := c.WriteClose(CloseNormal, "tchau"); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	var ce *CloseError
+	if _, _, err := c.ReadMessage(); !errors.As(err, &ce) || ce.Code != CloseNormal {
+		t.Fatalf("expected close echo, got %v", err)
+	}
+	if err := <-done; !errors.As(err, &ce) || ce.Reason != "tchau" {
+		t.Fatalf("server expected close with reason, got %v", err)
+	}
+}
+
+func TestFragmentedMessage(t *testing.T) {
+	srv, _ := echoServer(t, 0)
+	c, _, err := Dial(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	// "he" + ping intercalado + "llo"
+	if err := c.writeRaw(OpText, []byte("he")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := c.writeFrame(OpPing, nil); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if err := c.writeRaw(0x80|OpContinuation, []byte("llo")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, data, err := c.ReadMessage(); err != nil || string(data) != "hello" {
+		t.Fatalf("expected reassembled message, got %q %v", data, err)
+	}
+}
+
+func TestServerRejectsBadFrames(t *testing.T) {
+	srv, done := echoServer(t, 16)
+	c, _, err := Dial(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	if err := c.WriteMessage(OpText, []byte(strings.Repeat("z", 17))); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var ce *CloseError
+	if _, _, err := c.ReadMessage(); !errors.As(err, &ce) || ce.Code != CloseTooBig {
+		t.Fatalf("expected 1009, got %v", err)
+	}
+	if err := <-done; !errors.Is(err, ErrMessageTooBig) {
+		t.Fatalf("unexpected server error %v", err)
+	}
+
+	// frame sem máscara vindo do cliente
+	c2, _, err := Dial(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c2.Close()
+	c2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	c2.client = false
+	_ = c2.writeFrame(OpText, []byte("a"))
+	c2.client = true
+	if _, _, err := c2.ReadMessage(); !errors.As(err, &ce) || ce.Code != CloseProtocolError {
+		t.Fatalf("expected 1002, got %v", err)
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	srv, _ := echoServer(t, 0)
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// writeRaw escreve um frame mascarado com o primeiro byte dado (para testar fragmentos).
+func (c *Conn) writeRaw(b0 byte, payload []byte) error {
+	frame := []byte{b0, 0x80 | byte(len(payload)), 1, 2, 3, 4}
+	for i, b := range payload {
+		frame = append(frame, b^frame[2+i%4])
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}